@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gnolang/tlin/internal/workspace"
+	"go.uber.org/zap"
+)
+
+func runCacheCommand(logger *zap.Logger, args []string) int {
+	if len(args) != 1 || args[0] != "clear" {
+		fmt.Println("usage: tlin cache clear")
+		return 1
+	}
+
+	dir, err := workspace.CacheDir()
+	if err != nil {
+		logger.Error("Error locating the cache", zap.Error(err))
+		return 1
+	}
+	if err := workspace.ClearCache(dir); err != nil {
+		logger.Error("Error clearing the cache", zap.String("dir", dir), zap.Error(err))
+		return 1
+	}
+	fmt.Printf("removed %s\n", dir)
+	return 0
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/rpc"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint"
+	"go.uber.org/zap"
+)
+
+// defaultDaemonSocket is the socket `tlin daemon` listens on, one per user.
+var defaultDaemonSocket = filepath.Join(os.TempDir(), fmt.Sprintf("tlin-%d.sock", os.Getuid()))
+
+// LintRequest asks the daemon to lint paths, relative to Dir.
+type LintRequest struct {
+	Dir           string
+	Configuration string
+	Paths         []string
+}
+
+// LintReply holds the issues found by the daemon.
+type LintReply struct {
+	Issues []tt.Issue
+}
+
+// Daemon serves lint requests with an engine kept in memory, so that rules
+// are built, and standard packages imported, once for all the runs.
+type Daemon struct {
+	logger        *zap.Logger
+	engine        lint.LintEngine
+	dir           string
+	configuration string
+}
+
+// Lint lints the paths of req. Requests made from another directory or with
+// another configuration are refused, the client then lints on its own.
+func (d *Daemon) Lint(req LintRequest, reply *LintReply) error {
+	if req.Dir != d.dir || req.Configuration != d.configuration {
+		return fmt.Errorf("daemon serves %s with %s", d.dir, d.configuration)
+	}
+	issues, err := lint.ProcessFiles(context.Background(), d.logger, d.engine, req.Paths, lint.ProcessFile)
+	if err != nil {
+		return err
+	}
+	reply.Issues = issues
+	return nil
+}
+
+func runDaemonCommand(logger *zap.Logger, args []string) int {
+	flagSet := flag.NewFlagSet("tlin daemon", flag.ExitOnError)
+	socket := flagSet.String("socket", defaultDaemonSocket, "Path of the unix socket to listen on")
+	configurationPath := flagSet.String("c", defaultConfigurationPath, "Path to the linter configuration file")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() != 0 {
+		fmt.Println("usage: tlin daemon [-socket path] [-c config]")
+		return 1
+	}
+
+	daemon, err := newDaemon(logger, *configurationPath)
+	if err != nil {
+		logger.Error("Failed to initialize lint engine", zap.Error(err))
+		return 1
+	}
+
+	listener, err := listenDaemon(*socket)
+	if err != nil {
+		logger.Error("Failed to listen", zap.String("socket", *socket), zap.Error(err))
+		return 1
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	fmt.Printf("tlin daemon listening on %s\n", *socket)
+	if err := serveDaemon(listener, daemon); err != nil {
+		logger.Error("Daemon stopped", zap.Error(err))
+		return 1
+	}
+	return 0
+}
+
+func newDaemon(logger *zap.Logger, configurationPath string) (*Daemon, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	configuration, err := filepath.Abs(configurationPath)
+	if err != nil {
+		return nil, err
+	}
+	engine, err := lint.New(".", nil, configurationPath)
+	if err != nil {
+		return nil, err
+	}
+	var linter lint.LintEngine = engine
+	if configurationPath == defaultConfigurationPath {
+		linter = lint.NewModuleEngine(engine, configurationPath, nil)
+	}
+	return &Daemon{logger: logger, engine: linter, dir: dir, configuration: configuration}, nil
+}
+
+// listenDaemon listens on socket, replacing the socket of a daemon that did
+// not stop cleanly.
+func listenDaemon(socket string) (net.Listener, error) {
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("a daemon is already listening on %s", socket)
+	}
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return net.Listen("unix", socket)
+}
+
+// serveDaemon serves the requests made on listener until it is closed.
+func serveDaemon(listener net.Listener, daemon *Daemon) error {
+	server := rpc.NewServer()
+	if err := server.Register(daemon); err != nil {
+		return err
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go server.ServeConn(conn)
+	}
+}
+
+// lintWithDaemon lints paths with the daemon listening on socket.
+func lintWithDaemon(socket, configurationPath string, paths []string) ([]tt.Issue, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	configuration, err := filepath.Abs(configurationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := rpc.Dial("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var reply LintReply
+	req := LintRequest{Dir: dir, Configuration: configuration, Paths: paths}
+	if err := client.Call("Daemon.Lint", req, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Issues, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDaemon(t *testing.T) {
+	t.Parallel()
+	// unix socket paths are limited to about a hundred bytes.
+	dir, err := os.MkdirTemp("", "tlin")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "d.sock")
+
+	testFile := filepath.Join(dir, "test.go")
+	require.NoError(t, os.WriteFile(testFile, []byte(sliceRangeIssueExample), 0o644))
+	expected := []tt.Issue{{Rule: "simplify-slice-range", Filename: testFile, Message: "unnecessary use of len() in slice expression"}}
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	configuration, err := filepath.Abs(".tlin.yaml")
+	require.NoError(t, err)
+	daemon := &Daemon{
+		logger:        zap.NewNop(),
+		engine:        setupMockEngine(expected, testFile),
+		dir:           cwd,
+		configuration: configuration,
+	}
+
+	listener, err := listenDaemon(socket)
+	require.NoError(t, err)
+	done := make(chan error)
+	go func() { done <- serveDaemon(listener, daemon) }()
+
+	_, err = listenDaemon(socket)
+	assert.ErrorContains(t, err, "already listening")
+
+	issues, err := lintWithDaemon(socket, ".tlin.yaml", []string{testFile})
+	require.NoError(t, err)
+	assert.Equal(t, expected, issues)
+
+	_, err = lintWithDaemon(socket, "other.yaml", []string{testFile})
+	assert.ErrorContains(t, err, "daemon serves")
+
+	require.NoError(t, listener.Close())
+	require.NoError(t, <-done)
+
+	_, err = lintWithDaemon(socket, ".tlin.yaml", []string{testFile})
+	assert.Error(t, err, "no daemon listens once it is stopped")
+}
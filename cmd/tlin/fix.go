@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/tlin/internal"
+	"github.com/gnolang/tlin/internal/fixer"
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint"
+	"go.uber.org/zap"
+)
+
+func runFixCommand(logger *zap.Logger, args []string) int {
+	flagSet := flag.NewFlagSet("tlin fix", flag.ExitOnError)
+	rules := flagSet.String("rule", "", "Comma-separated list of the rules whose fixes are applied")
+	configurationPath := flagSet.String("c", defaultConfigurationPath, "Path to the linter configuration file. By default, the "+defaultConfigurationPath+" at the root of a module applies to its files")
+	dryRun := flagSet.Bool("dry-run", false, "Show fixes without applying them")
+	confidence := flagSet.Float64("confidence", defaultConfidenceThreshold, "Confidence threshold for auto-fixing (0.0 to 1.0)")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() == 0 || *rules == "" {
+		fmt.Println("usage: tlin fix -rule <rule>[,<rule>...] <paths>")
+		return 1
+	}
+
+	engine, err := lint.New(".", nil, *configurationPath)
+	if err != nil {
+		logger.Error("Failed to initialize lint engine", zap.Error(err))
+		return 1
+	}
+
+	var names []string
+	for _, name := range strings.Split(*rules, ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	// only the selected rules run, so every issue found belongs to them.
+	if err := engine.EnableOnly(names...); err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+	var linter lint.LintEngine = engine
+	if *configurationPath == defaultConfigurationPath {
+		linter = lint.NewModuleEngine(engine, *configurationPath, func(e *internal.Engine) error {
+			return e.EnableOnly(names...)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	issues, err := lint.ProcessFiles(ctx, logger, linter, flagSet.Args(), lint.ProcessFile)
+	if err != nil {
+		logger.Error("Error processing files", zap.Error(err))
+		return 1
+	}
+
+	fix := fixer.New(*dryRun, *confidence)
+	policyFor := fixPolicyFor(*configurationPath)
+
+	files, byFile := groupIssuesByFile(issues)
+	failed := false
+	for _, filename := range files {
+		fix.Policy = policyFor(filename)
+		if err := fix.Fix(filename, byFile[filename]); err != nil {
+			logger.Error("Error fixing issues", zap.String("file", filename), zap.Error(err))
+			failed = true
+		}
+	}
+	if *dryRun {
+		return 0
+	}
+
+	// lint the fixed files again to show what is left to do by hand.
+	remaining, err := lint.ProcessFiles(ctx, logger, linter, files, lint.ProcessFile)
+	if err != nil {
+		logger.Error("Error verifying fixed files", zap.Error(err))
+		return 1
+	}
+	fmt.Printf("%d issues found in %d files, %d remaining after fixing\n", len(issues), len(files), len(remaining))
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// runFixFromReport applies the fixes of a JSON report produced by `tlin -json`,
+// possibly filtered by a review tool. Files that changed since the report was
+// made are skipped. policyFor returns the fix policy of each file.
+func runFixFromReport(logger *zap.Logger, reportPath string, dryRun bool, confidenceThreshold float64, policyFor func(filename string) fixer.Policy) {
+	report, err := fixer.ReadReport(reportPath)
+	if err != nil {
+		logger.Error("Error reading report", zap.String("path", reportPath), zap.Error(err))
+		os.Exit(1)
+	}
+
+	fix := fixer.New(dryRun, confidenceThreshold)
+
+	files := make([]string, 0, len(report))
+	for filename := range report {
+		files = append(files, filename)
+	}
+	sort.Strings(files)
+
+	failed := false
+	for _, filename := range files {
+		if err := fixer.CheckReport(filename, report[filename]); err != nil {
+			logger.Error("Skipping file", zap.String("file", filename), zap.Error(err))
+			failed = true
+			continue
+		}
+		fix.Policy = policyFor(filename)
+		if err := fix.Fix(filename, report[filename]); err != nil {
+			logger.Error("Error fixing issues", zap.String("file", filename), zap.Error(err))
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// fixPolicyFor returns the fix policy of each file: the one of the
+// configuration of its module with the default configuration path, as the
+// rules, or else the one of configurationPath.
+func fixPolicyFor(configurationPath string) func(filename string) fixer.Policy {
+	if configurationPath == defaultConfigurationPath {
+		return lint.NewModuleFixPolicy(configurationPath).For
+	}
+	policy := lint.NewFixPolicy(configurationPath)
+	return func(string) fixer.Policy { return policy }
+}
+
+// groupIssuesByFile returns the files with issues, sorted, and their issues.
+func groupIssuesByFile(issues []tt.Issue) ([]string, map[string][]tt.Issue) {
+	byFile := make(map[string][]tt.Issue)
+	for _, issue := range issues {
+		byFile[issue.Filename] = append(byFile[issue.Filename], issue)
+	}
+
+	files := make([]string, 0, len(byFile))
+	for filename := range byFile {
+		files = append(files, filename)
+	}
+	sort.Strings(files)
+	return files, byFile
+}
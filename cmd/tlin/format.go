@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gnolang/tlin/formatter"
+	"github.com/gnolang/tlin/lint"
+)
+
+// newFormatOptions applies the theme and loads the issue template selected by
+// the flags, or by the configuration file when the flags are not set.
+func newFormatOptions(config Config) (formatter.Options, error) {
+	opts := formatter.Options{Context: config.ContextLines, VerboseNotes: config.VerboseNotes, Width: config.Width}
+	if opts.Width == 0 {
+		opts.Width = terminalWidth()
+	}
+	switch config.Format {
+	case "", "text", "json":
+	case "line":
+		if config.Template != "" {
+			return opts, fmt.Errorf("-format line and -template cannot be used together")
+		}
+		// the line format is meant for tools and ignores the configuration.
+		opts.Template, _ = formatter.LoadTemplate("line")
+		return opts, nil
+	default:
+		return opts, fmt.Errorf("unknown format %q", config.Format)
+	}
+
+	format := lint.NewFormatConfig(config.ConfigurationPath)
+
+	themeName := config.Theme
+	if themeName == "" {
+		themeName = format.Theme
+	}
+	if themeName != "" {
+		// themes of the configuration take precedence over the built-in ones.
+		theme, ok := formatter.BuiltinTheme(themeName)
+		if colors, found := format.Themes[themeName]; found {
+			theme, ok = colors, true
+		}
+		if !ok {
+			return opts, fmt.Errorf("unknown theme %q", themeName)
+		}
+		if err := formatter.ApplyTheme(theme); err != nil {
+			return opts, fmt.Errorf("theme %q: %w", themeName, err)
+		}
+	}
+
+	templateName := config.Template
+	if templateName == "" && format.Template != "" {
+		templateName = format.Template
+		// template files of the configuration are relative to it.
+		if !formatter.IsBuiltinTemplate(templateName) && !filepath.IsAbs(templateName) {
+			templateName = filepath.Join(filepath.Dir(config.ConfigurationPath), templateName)
+		}
+	}
+	if templateName != "" {
+		tmpl, err := formatter.LoadTemplate(templateName)
+		if err != nil {
+			return opts, err
+		}
+		opts.Template = tmpl
+	}
+	return opts, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/tlin/internal"
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+func runInitCommand(logger *zap.Logger, args []string) int {
+	flagSet := flag.NewFlagSet("tlin init", flag.ExitOnError)
+	fromScan := flagSet.Bool("from-scan", false, "Lint the given paths and relax the rules reporting many issues")
+	configurationPath := flagSet.String("c", ".tlin.yaml", "Path of the configuration file to create")
+	downgradeAbove := flagSet.Int("downgrade-above", 10, "With -from-scan, downgrade to INFO the rules reporting more issues than this")
+	disableAbove := flagSet.Int("disable-above", 50, "With -from-scan, turn off the rules reporting more issues than this")
+	if err := flagSet.Parse(args); err != nil || (*fromScan && flagSet.NArg() == 0) {
+		fmt.Println("usage: tlin init [-from-scan <paths>]")
+		return 1
+	}
+
+	if !*fromScan {
+		if err := initConfigurationFile(*configurationPath); err != nil {
+			logger.Error("Error initializing config file", zap.Error(err))
+			return 1
+		}
+		return 0
+	}
+
+	// scan with the default rules, whatever the current configuration says.
+	engine, err := internal.NewEngine(".", nil, nil)
+	if err != nil {
+		logger.Error("Failed to initialize lint engine", zap.Error(err))
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var paths []string
+	for _, path := range flagSet.Args() {
+		// `./...` lints the directory recursively, as directories always are.
+		path = strings.TrimSuffix(path, "...")
+		if path == "" {
+			path = "."
+		}
+		paths = append(paths, path)
+	}
+	issues, err := lint.ProcessFiles(ctx, logger, engine, paths, lint.ProcessFile)
+	if err != nil {
+		logger.Error("Error processing files", zap.Error(err))
+		return 1
+	}
+
+	d, err := configFromScan(engine, issues, *downgradeAbove, *disableAbove)
+	if err != nil {
+		logger.Error("Error generating config file", zap.Error(err))
+		return 1
+	}
+	if err := os.WriteFile(*configurationPath, d, 0o644); err != nil {
+		logger.Error("Error writing config file", zap.Error(err))
+		return 1
+	}
+	fmt.Printf("%d issues found, configuration written to %s\n", len(issues), *configurationPath)
+	return 0
+}
+
+// configFromScan generates a configuration turning off the rules of engine
+// reporting more than disableAbove issues, and downgrading to INFO those
+// reporting more than downgradeAbove. Each relaxed rule is commented with its
+// number of issues.
+func configFromScan(engine *internal.Engine, issues []tt.Issue, downgradeAbove, disableAbove int) ([]byte, error) {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.ConfigName()]++
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := &yaml.Node{Kind: yaml.MappingNode}
+	for _, name := range names {
+		severity, ok := engine.RuleSeverity(name)
+		if !ok {
+			// findings of golangci-lint and internal errors are not rules.
+			continue
+		}
+
+		n := counts[name]
+		switch {
+		case n > disableAbove:
+			severity = tt.SeverityOff
+		case n > downgradeAbove && severity < tt.SeverityInfo:
+			severity = tt.SeverityInfo
+		default:
+			continue
+		}
+		rules.Content = append(rules.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: name, LineComment: fmt.Sprintf("%d issues", n)},
+			&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Value: "severity"},
+				{Kind: yaml.ScalarNode, Value: severity.String(), Style: yaml.DoubleQuotedStyle},
+			}},
+		)
+	}
+
+	doc := &yaml.Node{
+		Kind: yaml.DocumentNode,
+		HeadComment: strings.Join([]string{
+			fmt.Sprintf("Generated by `tlin init -from-scan` from %d issues.", len(issues)),
+			fmt.Sprintf("Rules reporting more than %d issues are turned off, and rules", disableAbove),
+			fmt.Sprintf("reporting more than %d are downgraded to INFO. Other rules keep their", downgradeAbove),
+			"default severity. Restore the relaxed rules as their issues get fixed.",
+		}, "\n"),
+		Content: []*yaml.Node{{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "name"},
+			{Kind: yaml.ScalarNode, Value: "tlin"},
+			{Kind: yaml.ScalarNode, Value: "rules"},
+			rules,
+		}}},
+	}
+	return yaml.Marshal(doc)
+}
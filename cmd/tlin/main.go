@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gnolang/tlin/filter"
 	"github.com/gnolang/tlin/formatter"
 	"github.com/gnolang/tlin/internal"
 	"github.com/gnolang/tlin/internal/analysis/cfg"
@@ -26,29 +27,69 @@ import (
 const (
 	defaultTimeout             = 5 * time.Minute
 	defaultConfidenceThreshold = 0.75
+	defaultConfigurationPath   = lint.ModuleConfigName
 )
 
 type Config struct {
 	IgnoreRules          string
+	Include              string
+	Exclude              string
+	EnableOnly           string
 	FuncName             string
 	Output               string
 	ConfigurationPath    string
+	FixFromJSON          string
+	FixReport            string
+	Format               string
+	Theme                string
+	Template             string
+	Workspace            string
+	Summary              string
+	Filter               string
+	Socket               string
 	Paths                []string
 	Timeout              time.Duration
 	CyclomaticThreshold  int
+	Parallelism          int
+	ContextLines         int
+	Width                int
 	ConfidenceThreshold  float64
 	CyclomaticComplexity bool
 	CFGAnalysis          bool
 	AutoFix              bool
 	DryRun               bool
+	Daemon               bool
 	JsonOutput           bool
+	Verbose              bool
+	VerboseNotes         bool
 	Init                 bool
 }
 
+// subcommands maps the first CLI argument to a command with its own flags.
+// Each command returns the process exit code.
+var subcommands = map[string]func(logger *zap.Logger, args []string) int{
+	"selftest": runSelfTestCommand,
+	"init":     runInitCommand,
+	"fix":      runFixCommand,
+	"rename":   runRenameCommand,
+	"why-not":  runWhyNotCommand,
+	"rules":    runRulesCommand,
+	"daemon":   runDaemonCommand,
+	"cache":    runCacheCommand,
+}
+
 func main() {
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
 
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			code := cmd(logger, os.Args[2:])
+			logger.Sync()
+			os.Exit(code)
+		}
+	}
+
 	config := parseFlags(os.Args[1:])
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
@@ -63,16 +104,43 @@ func main() {
 		return
 	}
 
+	formatOptions, err := newFormatOptions(config)
+	if err != nil {
+		logger.Fatal("Invalid output format", zap.Error(err))
+	}
+	exitPolicy := lint.NewExitPolicy(config.ConfigurationPath)
+	var issueFilter *filter.Filter
+	if config.Filter != "" {
+		if issueFilter, err = filter.Parse(config.Filter); err != nil {
+			logger.Fatal("Invalid -filter", zap.Error(err))
+		}
+	}
+
+	if config.Daemon && daemonCanServe(config) {
+		served := false
+		runWithTimeout(ctx, func() {
+			served = runDaemonLintProcess(logger, config, formatOptions, exitPolicy, issueFilter)
+		})
+		if served {
+			return
+		}
+	}
+
 	engine, err := lint.New(".", nil, config.ConfigurationPath)
 	if err != nil {
 		logger.Fatal("Failed to initialize lint engine", zap.Error(err))
 	}
 
-	if config.IgnoreRules != "" {
-		rules := strings.Split(config.IgnoreRules, ",")
-		for _, rule := range rules {
-			engine.IgnoreRule(strings.TrimSpace(rule))
-		}
+	if err := configureEngine(engine, config); err != nil {
+		logger.Fatal("Invalid engine options", zap.Error(err))
+	}
+
+	var linter workspaceEngine = engine
+	if config.ConfigurationPath == defaultConfigurationPath {
+		// modules of the checkout may come with their own configuration.
+		linter = lint.NewModuleEngine(engine, config.ConfigurationPath, func(e *internal.Engine) error {
+			return configureEngine(e, config)
+		})
 	}
 
 	if config.CFGAnalysis {
@@ -81,19 +149,67 @@ func main() {
 		})
 	} else if config.CyclomaticComplexity {
 		runWithTimeout(ctx, func() {
-			runCyclomaticComplexityAnalysis(ctx, logger, config.Paths, config.CyclomaticThreshold, config.JsonOutput, config.Output)
+			runCyclomaticComplexityAnalysis(ctx, logger, config.Paths, config.CyclomaticThreshold, config.JsonOutput, config.Output, config.ConfidenceThreshold, formatOptions, exitPolicy, issueFilter)
+		})
+	} else if config.FixFromJSON != "" {
+		runWithTimeout(ctx, func() {
+			runFixFromReport(logger, config.FixFromJSON, config.DryRun, config.ConfidenceThreshold, fixPolicyFor(config.ConfigurationPath))
+		})
+	} else if config.Workspace != "" {
+		runWithTimeout(ctx, func() {
+			runWorkspace(ctx, logger, linter, config.Workspace, config.JsonOutput, config.Output, config.Summary, config.ConfidenceThreshold, formatOptions, exitPolicy, issueFilter)
 		})
 	} else if config.AutoFix {
 		runWithTimeout(ctx, func() {
-			runAutoFix(ctx, logger, engine, config.Paths, config.DryRun, config.ConfidenceThreshold)
+			runAutoFix(ctx, logger, linter, config.Paths, config.DryRun, config.ConfidenceThreshold, fixPolicyFor(config.ConfigurationPath), config.FixReport)
 		})
 	} else {
 		runWithTimeout(ctx, func() {
-			runNormalLintProcess(ctx, logger, engine, config.Paths, config.JsonOutput, config.Output)
+			runNormalLintProcess(ctx, logger, linter, config.Paths, config.JsonOutput, config.Output, config.ConfidenceThreshold, formatOptions, exitPolicy, issueFilter)
 		})
 	}
 }
 
+// configureEngine applies the options of the command line to engine.
+func configureEngine(engine *internal.Engine, config Config) error {
+	engine.SetVerbose(config.Verbose)
+	engine.SetParallelism(config.Parallelism)
+
+	if config.EnableOnly != "" {
+		var rules []string
+		for _, rule := range strings.Split(config.EnableOnly, ",") {
+			rules = append(rules, strings.TrimSpace(rule))
+		}
+		if err := engine.EnableOnly(rules...); err != nil {
+			return fmt.Errorf("invalid -enable-only: %w", err)
+		}
+	}
+
+	if config.IgnoreRules != "" {
+		rules := strings.Split(config.IgnoreRules, ",")
+		for _, rule := range rules {
+			engine.IgnoreRule(strings.TrimSpace(rule))
+		}
+	}
+
+	if config.Include != "" {
+		for _, pattern := range strings.Split(config.Include, ",") {
+			if err := engine.IncludePath(strings.TrimSpace(pattern)); err != nil {
+				return fmt.Errorf("invalid -include: %w", err)
+			}
+		}
+	}
+
+	if config.Exclude != "" {
+		for _, pattern := range strings.Split(config.Exclude, ",") {
+			if err := engine.IgnorePath(strings.TrimSpace(pattern)); err != nil {
+				return fmt.Errorf("invalid -exclude: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
 func parseFlags(args []string) Config {
 	flagSet := flag.NewFlagSet("tlin", flag.ExitOnError)
 	config := Config{}
@@ -102,15 +218,33 @@ func parseFlags(args []string) Config {
 	flagSet.BoolVar(&config.CyclomaticComplexity, "cyclo", false, "Run cyclomatic complexity analysis")
 	flagSet.IntVar(&config.CyclomaticThreshold, "threshold", 10, "Cyclomatic complexity threshold")
 	flagSet.StringVar(&config.IgnoreRules, "ignore", "", "Comma-separated list of lint rules to ignore")
+	flagSet.StringVar(&config.Include, "include", "", "Comma-separated list of glob patterns of the files to lint in directories, e.g. '**/*.gno'")
+	flagSet.StringVar(&config.Exclude, "exclude", "", "Comma-separated list of glob patterns of the paths to skip in directories, e.g. 'vendor/**'")
+	flagSet.StringVar(&config.EnableOnly, "enable-only", "", "Comma-separated list of the only lint rules to run")
 	flagSet.BoolVar(&config.CFGAnalysis, "cfg", false, "Run control flow graph analysis")
 	flagSet.StringVar(&config.FuncName, "func", "", "Function name for CFG analysis")
 	flagSet.BoolVar(&config.AutoFix, "fix", false, "Automatically fix issues")
+	flagSet.StringVar(&config.FixReport, "fix-report", "", "Write the fixes applied by -fix to a Markdown file, or JSON if the path ends with .json")
+	flagSet.StringVar(&config.FixFromJSON, "fix-from-json", "", "Apply the fixes of a JSON report produced with -json")
+	flagSet.StringVar(&config.Workspace, "workspace", "", "Lint the gno packages below a directory in dependency order")
+	flagSet.StringVar(&config.Summary, "summary", "", "With -workspace, write the number of issues by package and rule as JSON to a file")
+	flagSet.StringVar(&config.Filter, "filter", "", "Only report the issues matching an expression, e.g. 'rule==\"nested-if\" && severity>=warning && file~\"contracts/\"'")
 	flagSet.StringVar(&config.Output, "o", "", "Output path")
 	flagSet.BoolVar(&config.DryRun, "dry-run", false, "Run in dry-run mode (show fixes without applying them)")
+	flagSet.BoolVar(&config.Daemon, "daemon", false, "Lint with the daemon started by tlin daemon, or in process when it is not running")
+	flagSet.StringVar(&config.Socket, "socket", defaultDaemonSocket, "Path of the unix socket of the daemon")
 	flagSet.BoolVar(&config.JsonOutput, "json", false, "Output issues in JSON format")
+	flagSet.StringVar(&config.Format, "format", "text", "Output format: text, line (one issue per line, without code) or json")
+	flagSet.IntVar(&config.ContextLines, "context", 0, "Number of lines of code shown before and after each issue")
+	flagSet.IntVar(&config.Width, "width", 0, "Wrap messages and notes at this many columns (default: the width of the terminal, none when the output is not a terminal)")
+	flagSet.StringVar(&config.Theme, "theme", "", "Color theme of the output: default, mono, or a theme of the configuration file")
+	flagSet.StringVar(&config.Template, "template", "", "Issue template: compact, or the path of a text/template file")
 	flagSet.Float64Var(&config.ConfidenceThreshold, "confidence", defaultConfidenceThreshold, "Confidence threshold for auto-fixing (0.0 to 1.0)")
+	flagSet.IntVar(&config.Parallelism, "parallel", 0, "Maximum number of rules running at the same time on a file (default: GOMAXPROCS)")
+	flagSet.BoolVar(&config.Verbose, "verbose", false, "Include stack traces when a rule panics")
+	flagSet.BoolVar(&config.VerboseNotes, "verbose-notes", false, "Print the note of every issue, instead of referring to the same note printed for a previous issue of the rule in the file")
 	flagSet.BoolVar(&config.Init, "init", false, "Initialize a new linter configuration file")
-	flagSet.StringVar(&config.ConfigurationPath, "c", ".tlin.yaml", "Path to the linter configuration file")
+	flagSet.StringVar(&config.ConfigurationPath, "c", defaultConfigurationPath, "Path to the linter configuration file. By default, the "+defaultConfigurationPath+" at the root of a module applies to its files")
 
 	err := flagSet.Parse(args)
 	if err != nil {
@@ -119,7 +253,10 @@ func parseFlags(args []string) Config {
 	}
 
 	config.Paths = flagSet.Args()
-	if !config.Init && len(config.Paths) == 0 {
+	if config.Format == "json" {
+		config.JsonOutput = true
+	}
+	if !config.Init && config.FixFromJSON == "" && config.Workspace == "" && len(config.Paths) == 0 {
 		fmt.Println("error: Please provide file or directory paths")
 		os.Exit(1)
 	}
@@ -143,21 +280,48 @@ func runWithTimeout(ctx context.Context, f func()) {
 	}
 }
 
-func runNormalLintProcess(ctx context.Context, logger *zap.Logger, engine lint.LintEngine, paths []string, isJson bool, jsonOutput string) {
+func runNormalLintProcess(ctx context.Context, logger *zap.Logger, engine lint.LintEngine, paths []string, isJson bool, jsonOutput string, minConfidence float64, formatOptions formatter.Options, exitPolicy lint.ExitPolicy, issueFilter *filter.Filter) {
 	issues, err := lint.ProcessFiles(ctx, logger, engine, paths, lint.ProcessFile)
 	if err != nil {
 		logger.Error("Error processing files", zap.Error(err))
 		os.Exit(1)
 	}
+	issues = issueFilter.Apply(issues)
 
-	printIssues(logger, issues, isJson, jsonOutput)
+	printIssues(logger, issues, isJson, jsonOutput, minConfidence, formatOptions)
 
-	if len(issues) > 0 {
-		os.Exit(1)
+	if code := exitPolicy.ExitCode(issues); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// daemonCanServe reports whether the run only lints paths with the engine as
+// configured by the configuration file, which the daemon keeps in memory.
+func daemonCanServe(config Config) bool {
+	return !config.CFGAnalysis && !config.CyclomaticComplexity && config.FixFromJSON == "" &&
+		config.Workspace == "" && !config.AutoFix && config.EnableOnly == "" &&
+		config.IgnoreRules == "" && config.Include == "" && config.Exclude == ""
+}
+
+// runDaemonLintProcess lints the paths with the daemon. It returns false,
+// without printing anything, when the daemon could not serve the request.
+func runDaemonLintProcess(logger *zap.Logger, config Config, formatOptions formatter.Options, exitPolicy lint.ExitPolicy, issueFilter *filter.Filter) bool {
+	issues, err := lintWithDaemon(config.Socket, config.ConfigurationPath, config.Paths)
+	if err != nil {
+		logger.Warn("Daemon unavailable, linting in process", zap.Error(err))
+		return false
 	}
+	issues = issueFilter.Apply(issues)
+
+	printIssues(logger, issues, config.JsonOutput, config.Output, config.ConfidenceThreshold, formatOptions)
+
+	if code := exitPolicy.ExitCode(issues); code != 0 {
+		os.Exit(code)
+	}
+	return true
 }
 
-func runCyclomaticComplexityAnalysis(ctx context.Context, logger *zap.Logger, paths []string, threshold int, isJson bool, jsonOutput string) {
+func runCyclomaticComplexityAnalysis(ctx context.Context, logger *zap.Logger, paths []string, threshold int, isJson bool, jsonOutput string, minConfidence float64, formatOptions formatter.Options, exitPolicy lint.ExitPolicy, issueFilter *filter.Filter) {
 	issues, err := lint.ProcessFiles(ctx, logger, nil, paths, func(_ lint.LintEngine, path string) ([]tt.Issue, error) {
 		return lint.ProcessCyclomaticComplexity(path, threshold)
 	})
@@ -165,11 +329,12 @@ func runCyclomaticComplexityAnalysis(ctx context.Context, logger *zap.Logger, pa
 		logger.Error("Error processing files for cyclomatic complexity", zap.Error(err))
 		os.Exit(1)
 	}
+	issues = issueFilter.Apply(issues)
 
-	printIssues(logger, issues, isJson, jsonOutput)
+	printIssues(logger, issues, isJson, jsonOutput, minConfidence, formatOptions)
 
-	if len(issues) > 0 {
-		os.Exit(1)
+	if code := exitPolicy.ExitCode(issues); code != 0 {
+		os.Exit(code)
 	}
 }
 
@@ -209,7 +374,7 @@ func runCFGAnalysis(_ context.Context, logger *zap.Logger, paths []string, funcN
 	}
 }
 
-func runAutoFix(ctx context.Context, logger *zap.Logger, engine lint.LintEngine, paths []string, dryRun bool, confidenceThreshold float64) {
+func runAutoFix(ctx context.Context, logger *zap.Logger, engine lint.LintEngine, paths []string, dryRun bool, confidenceThreshold float64, policyFor func(filename string) fixer.Policy, reportPath string) {
 	fix := fixer.New(dryRun, confidenceThreshold)
 
 	for _, path := range paths {
@@ -219,11 +384,18 @@ func runAutoFix(ctx context.Context, logger *zap.Logger, engine lint.LintEngine,
 			continue
 		}
 
+		fix.Policy = policyFor(path)
 		err = fix.Fix(path, issues)
 		if err != nil {
 			logger.Error("error fixing issues", zap.String("path", path), zap.Error(err))
 		}
 	}
+
+	if reportPath != "" {
+		if err := fixer.WriteChangelog(reportPath, fix.Changes); err != nil {
+			logger.Error("error writing fix report", zap.String("path", reportPath), zap.Error(err))
+		}
+	}
 }
 
 func initConfigurationFile(configurationPath string) error {
@@ -256,7 +428,7 @@ func initConfigurationFile(configurationPath string) error {
 	return nil
 }
 
-func printIssues(logger *zap.Logger, issues []tt.Issue, isJson bool, jsonOutput string) {
+func printIssues(logger *zap.Logger, issues []tt.Issue, isJson bool, jsonOutput string, minConfidence float64, formatOptions formatter.Options) {
 	issuesByFile := make(map[string][]tt.Issue)
 	for _, issue := range issues {
 		issuesByFile[issue.Filename] = append(issuesByFile[issue.Filename], issue)
@@ -276,10 +448,27 @@ func printIssues(logger *zap.Logger, issues []tt.Issue, isJson bool, jsonOutput
 				logger.Error("Error reading source file", zap.String("file", filename), zap.Error(err))
 				continue
 			}
-			output := formatter.GenerateFormattedIssue(fileIssues, sourceCode)
+			output := formatter.GenerateFormattedIssueWithOptions(fileIssues, sourceCode, formatOptions)
+			if formatOptions.Template != "" {
+				// custom templates separate the issues themselves.
+				fmt.Print(output)
+				continue
+			}
 			fmt.Println(output)
 		}
 	} else {
+		for filename, fileIssues := range issuesByFile {
+			content, err := os.ReadFile(filename)
+			if err != nil {
+				logger.Error("Error reading source file", zap.String("file", filename), zap.Error(err))
+				continue
+			}
+			hash := fixer.ContentHash(content)
+			for i := range fileIssues {
+				fileIssues[i].FileHash = hash
+			}
+			fixer.AttachEdits(filename, content, fileIssues, minConfidence)
+		}
 		d, err := json.Marshal(issuesByFile)
 		if err != nil {
 			logger.Error("Error marshalling issues to JSON", zap.Error(err))
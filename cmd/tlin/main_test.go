@@ -14,10 +14,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gnolang/tlin/formatter"
+	"github.com/gnolang/tlin/internal"
+	"github.com/gnolang/tlin/internal/fixer"
 	tt "github.com/gnolang/tlin/internal/types"
 	"github.com/gnolang/tlin/lint"
+	"github.com/gnolang/tlin/lint/ruletest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
@@ -94,6 +99,16 @@ func TestParseFlags(t *testing.T) {
 				ConfigurationPath:   ".tlin.yaml",
 			},
 		},
+		{
+			name: "JSON format",
+			args: []string{"-format", "json", "file.go"},
+			expected: Config{
+				Paths:               []string{"file.go"},
+				JsonOutput:          true,
+				ConfidenceThreshold: defaultConfidenceThreshold,
+				ConfigurationPath:   ".tlin.yaml",
+			},
+		},
 		{
 			name: "Output",
 			args: []string{"-o", "output.svg", "file.go"},
@@ -104,6 +119,16 @@ func TestParseFlags(t *testing.T) {
 				ConfigurationPath:   ".tlin.yaml",
 			},
 		},
+		{
+			name: "EnableOnly",
+			args: []string{"-enable-only", "useless-break,defer-issues", "file.go"},
+			expected: Config{
+				Paths:               []string{"file.go"},
+				EnableOnly:          "useless-break,defer-issues",
+				ConfidenceThreshold: defaultConfidenceThreshold,
+				ConfigurationPath:   ".tlin.yaml",
+			},
+		},
 		{
 			name: "Configuration File",
 			args: []string{"-c", "config.yaml", "file.go"},
@@ -127,6 +152,7 @@ func TestParseFlags(t *testing.T) {
 			assert.Equal(t, tt.expected.Paths, config.Paths)
 			assert.Equal(t, tt.expected.JsonOutput, config.JsonOutput)
 			assert.Equal(t, tt.expected.Output, config.Output)
+			assert.Equal(t, tt.expected.EnableOnly, config.EnableOnly)
 			assert.Equal(t, tt.expected.ConfigurationPath, config.ConfigurationPath)
 		})
 	}
@@ -257,7 +283,7 @@ func TestRunAutoFix(t *testing.T) {
 			Rule:       "simplify-slice-range",
 			Filename:   testFile,
 			Message:    "unnecessary use of len() in slice expression, can be simplified",
-			Start:      token.Position{Line: 5, Column: 5},
+			Start:      token.Position{Line: 5, Column: 2},
 			End:        token.Position{Line: 5, Column: 24},
 			Suggestion: "_ = slice[:]",
 			Confidence: 0.9,
@@ -265,9 +291,10 @@ func TestRunAutoFix(t *testing.T) {
 	}
 
 	mockEngine := setupMockEngine(expectedIssues, testFile)
+	reportPath := filepath.Join(tempDir, "FIXES.md")
 
 	output := captureOutput(t, func() {
-		runAutoFix(ctx, logger, mockEngine, []string{testFile}, false, 0.8)
+		runAutoFix(ctx, logger, mockEngine, []string{testFile}, false, 0.8, func(string) fixer.Policy { return fixer.Policy{} }, reportPath)
 	})
 
 	content, err := os.ReadFile(testFile)
@@ -283,12 +310,16 @@ func main() {
 	assert.Equal(t, expectedContent, string(content))
 	assert.Contains(t, output, "Fixed issues in")
 
+	report, err := os.ReadFile(reportPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(report), "## simplify-slice-range")
+
 	// dry run test
 	err = os.WriteFile(testFile, []byte(sliceRangeIssueExample), 0o644)
 	assert.NoError(t, err)
 
 	output = captureOutput(t, func() {
-		runAutoFix(ctx, logger, mockEngine, []string{testFile}, true, 0.8)
+		runAutoFix(ctx, logger, mockEngine, []string{testFile}, true, 0.8, func(string) fixer.Policy { return fixer.Policy{} }, "")
 	})
 
 	content, err = os.ReadFile(testFile)
@@ -329,6 +360,7 @@ func TestRunJsonOutput(t *testing.T) {
 				assert.Equal(t, 5, issue.End.Line)
 				assert.Equal(t, 24, issue.End.Column)
 				assert.Equal(t, tt.SeverityError, issue.Severity)
+				assert.Equal(t, fixer.ContentHash([]byte(sliceRangeIssueExample)), issue.FileHash)
 			}
 
 			return
@@ -362,7 +394,7 @@ func TestRunJsonOutput(t *testing.T) {
 	mockEngine := setupMockEngine(expectedIssues, testFile)
 
 	jsonOutput := filepath.Join(tempDir, "output.json")
-	runNormalLintProcess(ctx, logger, mockEngine, []string{testFile}, true, jsonOutput)
+	runNormalLintProcess(ctx, logger, mockEngine, []string{testFile}, true, jsonOutput, defaultConfidenceThreshold, formatter.Options{}, lint.NewExitPolicy(""), nil)
 }
 
 func createTempFileWithContent(t *testing.T, content string) string {
@@ -391,3 +423,315 @@ func captureOutput(t *testing.T, f func()) string {
 	io.Copy(&buf, r)
 	return buf.String()
 }
+
+func TestParseRenameTarget(t *testing.T) {
+	t.Parallel()
+
+	opts, err := parseRenameTarget("coins.OldName", "NewName")
+	assert.NoError(t, err)
+	assert.Equal(t, "coins", opts.Package)
+	assert.Equal(t, "OldName", opts.From)
+	assert.Equal(t, "NewName", opts.To)
+
+	for _, from := range []string{"OldName", ".OldName", "coins."} {
+		_, err := parseRenameTarget(from, "NewName")
+		assert.Error(t, err, from)
+	}
+}
+
+func TestParseWhyNotTarget(t *testing.T) {
+	t.Parallel()
+
+	filename, line, err := parseWhyNotTarget("r/demo/foo.gno:42")
+	assert.NoError(t, err)
+	assert.Equal(t, "r/demo/foo.gno", filename)
+	assert.Equal(t, 42, line)
+
+	for _, target := range []string{"foo.gno", ":42", "foo.gno:", "foo.gno:0", "foo.gno:x"} {
+		_, _, err := parseWhyNotTarget(target)
+		assert.Error(t, err, target)
+	}
+}
+
+func TestRunFixCommand(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+
+	path := filepath.Join(tempDir, "errors.gno")
+	src := `package foo
+
+import "errors"
+
+const ErrNotFound = errors.New("not found")
+
+func f(x int) int {
+	if x > 0 {
+		return 1
+	} else {
+		return 2
+	}
+}
+`
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+	code := runFixCommand(zap.NewNop(), []string{"-rule", "const-error-declaration", "-c", filepath.Join(tempDir, "none.yaml"), tempDir})
+	assert.Equal(t, 0, code)
+
+	fixed, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(fixed), `var ErrNotFound = errors.New("not found")`)
+	assert.Contains(t, string(fixed), "} else {", "fixes of other rules must not be applied")
+}
+
+func TestRunFixCommandSimplifiesSlices(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+
+	src, err := os.ReadFile(filepath.Join("..", "..", "testdata", "slice0.gno"))
+	require.NoError(t, err)
+	path := filepath.Join(tempDir, "slice0.gno")
+	require.NoError(t, os.WriteFile(path, src, 0o644))
+
+	code := runFixCommand(zap.NewNop(), []string{"-rule", "simplify-slice-range", "-c", filepath.Join(tempDir, "none.yaml"), tempDir})
+	assert.Equal(t, 0, code)
+
+	fixed, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(fixed), "len(slice)")
+	assert.Contains(t, string(fixed), "_ = slice[1:]")
+	assert.Contains(t, string(fixed), "_ = slice[:]")
+	assert.Contains(t, string(fixed), "_ = slice[a:]")
+}
+
+func TestRunFixCommandWithModules(t *testing.T) {
+	t.Parallel()
+	src := "package foo\n\nfunc f(s []int) []int {\n\treturn s[:len(s)]\n}\n"
+	files := map[string]string{
+		"app/gno.mod":           "module gno.land/p/demo/app\n",
+		"app/foo.gno":           src,
+		"app/nested/gno.mod":    "module gno.land/p/demo/app/nested\n",
+		"app/nested/.tlin.yaml": "rules:\n  simplify-slice-range:\n    fixable: false\n",
+		"app/nested/foo.gno":    src,
+	}
+	root := ruletest.WriteFiles(t, files)
+
+	// without -c, the configuration of the module of each file applies.
+	code := runFixCommand(zap.NewNop(), []string{"-rule", "simplify-slice-range", root})
+	assert.Equal(t, 0, code)
+
+	fixed, err := os.ReadFile(filepath.Join(root, "app/foo.gno"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(fixed), "len(s)")
+
+	nested, err := os.ReadFile(filepath.Join(root, "app/nested/foo.gno"))
+	require.NoError(t, err)
+	assert.Equal(t, src, string(nested), "fixable: false of the nested module applies to its files")
+}
+
+func TestGroupIssuesByFile(t *testing.T) {
+	t.Parallel()
+
+	files, byFile := groupIssuesByFile([]tt.Issue{
+		{Filename: "b.gno", Rule: "r1"},
+		{Filename: "a.gno", Rule: "r1"},
+		{Filename: "b.gno", Rule: "r2"},
+	})
+	assert.Equal(t, []string{"a.gno", "b.gno"}, files)
+	assert.Len(t, byFile["b.gno"], 2)
+}
+
+func TestLintWorkspace(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"r/app/gno.mod": "module gno.land/r/demo/app\n",
+		"r/app/app.gno": `package app
+
+import "gno.land/p/demo/coins"
+
+func Init() {
+	coins.Mint()
+}
+`,
+		"p/coins/gno.mod": "module gno.land/p/demo/coins\n",
+		"p/coins/coins.gno": `package coins
+
+// Mint creates coins.
+//
+// Deprecated: use NewCoins instead.
+func Mint() {}
+
+// NewCoins creates coins.
+func NewCoins() {}
+`,
+	}
+	root := ruletest.WriteFiles(t, files)
+
+	engine, err := lint.New(root, nil, filepath.Join(root, "none.yaml"))
+	require.NoError(t, err)
+	// deprecations are registered while linting, leave out the other rules.
+	require.NoError(t, engine.EnableOnly("useless-break"))
+
+	issues, err := lintWorkspace(context.Background(), zap.NewNop(), engine, root, nil)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "deprecated", issues[0].Rule)
+	assert.Equal(t, filepath.Join(root, "r/app/app.gno"), issues[0].Filename)
+	assert.Equal(t, "gno.land/r/demo/app", issues[0].Package)
+	assert.Equal(t, "Use of deprecated function. please use coins.NewCoins instead.", issues[0].Message)
+}
+
+func TestLintWorkspaceMissingExport(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"r/app/gno.mod": "module gno.land/r/demo/app\n",
+		"r/app/app.gno": `package app
+
+import "gno.land/p/demo/coins"
+
+func Init() {
+	coins.NewCoins()
+	coins.Mint()
+}
+`,
+		"p/coins/gno.mod": "module gno.land/p/demo/coins\n",
+		"p/coins/coins.gno": `package coins
+
+// NewCoins creates coins.
+func NewCoins() {}
+`,
+	}
+	root := ruletest.WriteFiles(t, files)
+
+	engine, err := lint.New(root, nil, filepath.Join(root, "none.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, engine.EnableOnly("useless-break"))
+
+	issues, err := lintWorkspace(context.Background(), zap.NewNop(), engine, root, nil)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "missing-export", issues[0].Rule)
+	assert.Equal(t, filepath.Join(root, "r/app/app.gno"), issues[0].Filename)
+	assert.Equal(t, 5, issues[0].Start.Line)
+	assert.Equal(t, "gno.land/r/demo/app", issues[0].Package)
+	assert.Equal(t, "Init calls gno.land/p/demo/coins.Mint, which its package does not export", issues[0].Message)
+}
+
+func TestConfigFromScan(t *testing.T) {
+	t.Parallel()
+	engine, err := internal.NewEngine(".", nil, nil)
+	require.NoError(t, err)
+
+	var issues []tt.Issue
+	add := func(rule string, n int) {
+		for i := 0; i < n; i++ {
+			issues = append(issues, tt.Issue{Rule: rule})
+		}
+	}
+	add("magic-number", 60)  // INFO by default, turned off
+	add("useless-break", 20) // downgraded
+	add("early-return-opportunity", 3)
+	add("golangci-lint:errcheck", 80)
+
+	// early-return-opportunity reports its issues as early-return.
+	path := filepath.Join(t.TempDir(), "a.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n\nfunc f(x int) int {\n\tif x > 0 {\n\t\treturn 1\n\t} else {\n\t\treturn 2\n\t}\n}\n"), 0o644))
+	scanned, err := engine.Run(path)
+	require.NoError(t, err)
+	for _, issue := range scanned {
+		if issue.Rule == "early-return" {
+			for i := 0; i < 50; i++ {
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	d, err := configFromScan(engine, issues, 10, 50)
+	require.NoError(t, err)
+	assert.Contains(t, string(d), "# Generated by `tlin init -from-scan` from 213 issues.")
+	assert.Contains(t, string(d), "magic-number: # 60 issues")
+	assert.Contains(t, string(d), "early-return-opportunity: # 53 issues")
+
+	var config lint.Config
+	require.NoError(t, yaml.Unmarshal(d, &config))
+	assert.Equal(t, "tlin", config.Name)
+	assert.Equal(t, map[string]tt.ConfigRule{
+		"early-return-opportunity": {Severity: tt.SeverityOff},
+		"magic-number":             {Severity: tt.SeverityOff},
+		"useless-break":            {Severity: tt.SeverityInfo},
+	}, config.Rules)
+
+	// the generated configuration loads.
+	_, err = internal.NewEngine(".", nil, config.Rules)
+	require.NoError(t, err)
+}
+
+func TestNewFormatOptions(t *testing.T) {
+	// not parallel: themes change the styles of the formatter.
+	t.Cleanup(func() { _ = formatter.ApplyTheme(nil) })
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".tlin.yaml")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "issue.tmpl"), []byte("{{.Rule}}\n"), 0o644))
+	require.NoError(t, os.WriteFile(configPath, []byte(`format:
+  theme: corporate
+  template: issue.tmpl
+  themes:
+    corporate:
+      error: "magenta,bold"
+`), 0o644))
+
+	// the template is found next to the configuration.
+	opts, err := newFormatOptions(Config{ConfigurationPath: configPath, ContextLines: 2})
+	require.NoError(t, err)
+	assert.Equal(t, formatter.Options{Context: 2, Template: "{{.Rule}}\n"}, opts)
+
+	// flags take precedence.
+	opts, err = newFormatOptions(Config{ConfigurationPath: configPath, Theme: "mono", Template: "compact"})
+	require.NoError(t, err)
+	assert.Contains(t, opts.Template, ".Filename")
+
+	_, err = newFormatOptions(Config{ConfigurationPath: configPath, Theme: "unknown"})
+	assert.Error(t, err)
+
+	// the line format ignores the configuration.
+	opts, err = newFormatOptions(Config{ConfigurationPath: configPath, Format: "line"})
+	require.NoError(t, err)
+	assert.Contains(t, opts.Template, "{{lower .Severity}}")
+
+	_, err = newFormatOptions(Config{ConfigurationPath: configPath, Format: "line", Template: "compact"})
+	assert.Error(t, err)
+	_, err = newFormatOptions(Config{ConfigurationPath: configPath, Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestWriteRules(t *testing.T) {
+	t.Parallel()
+	minimum := 1
+	rules := []internal.RuleDescription{
+		{Name: "file-length", Severity: "WARNING", Parameters: []internal.RuleParameter{
+			{Name: "max-lines", Type: "integer", Minimum: &minimum, Default: 1000, Description: "Maximum number of lines of a file."},
+		}},
+		{Name: "useless-break", Severity: "WARNING"},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, writeRules(&text, rules, false))
+	assert.Equal(t, `file-length                    WARNING
+    max-lines (integer >= 1) = 1000: Maximum number of lines of a file.
+useless-break                  WARNING
+`, text.String())
+
+	var out bytes.Buffer
+	require.NoError(t, writeRules(&out, rules, true))
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 2)
+	params := decoded[0]["parameters"].([]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"name":        "max-lines",
+		"type":        "integer",
+		"minimum":     float64(1),
+		"default":     float64(1000),
+		"description": "Maximum number of lines of a file.",
+	}, params[0])
+	assert.NotContains(t, decoded[1], "parameters")
+}
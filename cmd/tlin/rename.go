@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/rename"
+	"go.uber.org/zap"
+)
+
+func runRenameCommand(logger *zap.Logger, args []string) int {
+	flagSet := flag.NewFlagSet("tlin rename", flag.ExitOnError)
+	from := flagSet.String("from", "", "Symbol to rename, qualified by its package name (e.g. pkg.OldName)")
+	to := flagSet.String("to", "", "New name of the symbol")
+	dryRun := flagSet.Bool("dry-run", false, "Show the files that would change without writing them")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() == 0 || *to == "" {
+		fmt.Println("usage: tlin rename -from pkg.OldName -to NewName <paths>")
+		return 1
+	}
+
+	opts, err := parseRenameTarget(*from, *to)
+	if err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+
+	result, err := rename.Plan(flagSet.Args(), opts)
+	if err != nil {
+		logger.Error("Error planning rename", zap.Error(err))
+		return 1
+	}
+
+	files := make([]string, 0, len(result.Files))
+	for filename := range result.Files {
+		files = append(files, filename)
+	}
+	sort.Strings(files)
+
+	if *dryRun {
+		for _, filename := range files {
+			fmt.Printf("Would update %s\n", filename)
+		}
+		return 0
+	}
+
+	if err := result.Apply(); err != nil {
+		logger.Error("Error applying rename", zap.Error(err))
+		return 1
+	}
+	fmt.Printf("Renamed %d references in %d files\n", result.References, len(files))
+	return 0
+}
+
+func parseRenameTarget(from, to string) (rename.Options, error) {
+	idx := strings.LastIndex(from, ".")
+	if idx <= 0 || idx == len(from)-1 {
+		return rename.Options{}, fmt.Errorf("-from must be of the form pkg.Name, got %q", from)
+	}
+	return rename.Options{Package: from[:idx], From: from[idx+1:], To: to}, nil
+}
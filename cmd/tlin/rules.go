@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gnolang/tlin/internal"
+	"go.uber.org/zap"
+)
+
+func runRulesCommand(logger *zap.Logger, args []string) int {
+	flagSet := flag.NewFlagSet("tlin rules", flag.ExitOnError)
+	asJSON := flagSet.Bool("json", false, "Print the rules and their parameter schemas in JSON")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() != 0 {
+		fmt.Println("usage: tlin rules [-json]")
+		return 1
+	}
+
+	if err := writeRules(os.Stdout, internal.DescribeRules(), *asJSON); err != nil {
+		logger.Error("Error listing rules", zap.Error(err))
+		return 1
+	}
+	return 0
+}
+
+// writeRules prints the rules with their default severity and parameters.
+func writeRules(w io.Writer, rules []internal.RuleDescription, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rules)
+	}
+
+	for _, rule := range rules {
+		if _, err := fmt.Fprintf(w, "%-30s %s\n", rule.Name, rule.Severity); err != nil {
+			return err
+		}
+		for _, p := range rule.Parameters {
+			line := fmt.Sprintf("    %s (%s)", p.Name, parameterType(p))
+			if p.Default != nil {
+				d, err := json.Marshal(p.Default)
+				if err != nil {
+					return err
+				}
+				line += " = " + string(d)
+			}
+			if _, err := fmt.Fprintf(w, "%s: %s\n", line, p.Description); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parameterType(p internal.RuleParameter) string {
+	switch {
+	case p.Type == "array":
+		return "array of " + p.Items
+	case len(p.Enum) > 0:
+		return strings.Join(p.Enum, "|")
+	case p.Minimum != nil:
+		return fmt.Sprintf("%s >= %d", p.Type, *p.Minimum)
+	}
+	return p.Type
+}
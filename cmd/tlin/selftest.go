@@ -0,0 +1,205 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gnolang/tlin/internal"
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint"
+	"go.uber.org/zap"
+)
+
+const defaultSelfTestFileTimeout = 30 * time.Second
+
+type selfTestFailureKind string
+
+const (
+	selfTestError   selfTestFailureKind = "error"
+	selfTestPanic   selfTestFailureKind = "panic"
+	selfTestTimeout selfTestFailureKind = "timeout"
+)
+
+type selfTestFailure struct {
+	Kind   selfTestFailureKind
+	File   string
+	Detail string
+}
+
+// selfTestReport summarizes a lint run over a whole source tree.
+type selfTestReport struct {
+	RuleIssues map[string]int // number of issues reported by each rule
+	RuleFiles  map[string]int // number of files with at least one issue of each rule
+	Failures   []selfTestFailure
+	Files      int
+	Duration   time.Duration
+}
+
+// Failed reports whether the run hit a panic or a timeout.
+// Plain errors (e.g. unparsable files) are reported but tolerated.
+func (r *selfTestReport) Failed() bool {
+	for _, f := range r.Failures {
+		if f.Kind != selfTestError {
+			return true
+		}
+	}
+	return false
+}
+
+func runSelfTestCommand(logger *zap.Logger, args []string) int {
+	flagSet := flag.NewFlagSet("tlin selftest", flag.ExitOnError)
+	fileTimeout := flagSet.Duration("file-timeout", defaultSelfTestFileTimeout, "Maximum time spent linting a single file")
+	configurationPath := flagSet.String("c", defaultConfigurationPath, "Path to the linter configuration file. By default, the "+defaultConfigurationPath+" at the root of a module applies to its files")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() != 1 {
+		fmt.Println("usage: tlin selftest [flags] <path-to-gno-repo>")
+		return 1
+	}
+
+	newEngine := func() (lint.LintEngine, error) {
+		engine, err := lint.New(".", nil, *configurationPath)
+		if err != nil {
+			return nil, err
+		}
+		if *configurationPath != defaultConfigurationPath {
+			return engine, nil
+		}
+		// modules of the tree may come with their own configuration.
+		return lint.NewModuleEngine(engine, *configurationPath, nil), nil
+	}
+	report, err := runSelfTest(newEngine, flagSet.Arg(0), *fileTimeout)
+	if err != nil {
+		logger.Error("Error running self test", zap.Error(err))
+		return 1
+	}
+
+	report.Print(os.Stdout)
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
+
+// runSelfTest lints every .gno file below root, recording panics, timeouts
+// and per-rule hit counts instead of stopping at the first problem.
+//
+// A run that times out keeps going in the background with the engine it
+// was given, so the next files are linted with a new engine from newEngine.
+func runSelfTest(newEngine func() (lint.LintEngine, error), root string, fileTimeout time.Duration) (*selfTestReport, error) {
+	report := &selfTestReport{
+		RuleIssues: make(map[string]int),
+		RuleFiles:  make(map[string]int),
+	}
+	start := time.Now()
+
+	engine, err := newEngine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize lint engine: %w", err)
+	}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".gno" {
+			return nil
+		}
+
+		report.Files++
+		issues, failure := lintFileGuarded(engine, path, fileTimeout)
+		if failure != nil {
+			report.Failures = append(report.Failures, *failure)
+			if failure.Kind == selfTestTimeout {
+				if engine, err = newEngine(); err != nil {
+					return fmt.Errorf("failed to initialize lint engine: %w", err)
+				}
+			}
+			return nil
+		}
+
+		seen := make(map[string]bool)
+		for _, issue := range issues {
+			report.RuleIssues[issue.Rule]++
+			if !seen[issue.Rule] {
+				seen[issue.Rule] = true
+				report.RuleFiles[issue.Rule]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking %s: %w", root, err)
+	}
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+func lintFileGuarded(engine lint.LintEngine, path string, timeout time.Duration) ([]tt.Issue, *selfTestFailure) {
+	type result struct {
+		issues  []tt.Issue
+		failure *selfTestFailure
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{failure: &selfTestFailure{Kind: selfTestPanic, File: path, Detail: fmt.Sprint(r)}}
+			}
+		}()
+		issues, err := engine.Run(path)
+		if err != nil {
+			done <- result{failure: &selfTestFailure{Kind: selfTestError, File: path, Detail: err.Error()}}
+			return
+		}
+		// the engine recovers from panics of a rule and reports them as issues.
+		for _, issue := range issues {
+			if issue.Rule == internal.InternalErrorRule {
+				done <- result{failure: &selfTestFailure{Kind: selfTestPanic, File: path, Detail: issue.Message}}
+				return
+			}
+		}
+		done <- result{issues: issues}
+	}()
+
+	select {
+	case r := <-done:
+		return r.issues, r.failure
+	case <-time.After(timeout):
+		return nil, &selfTestFailure{Kind: selfTestTimeout, File: path, Detail: fmt.Sprintf("exceeded %s", timeout)}
+	}
+}
+
+// Print writes a human-readable summary of the report.
+func (r *selfTestReport) Print(w io.Writer) {
+	fmt.Fprintf(w, "linted %d files in %s\n", r.Files, r.Duration.Round(time.Millisecond))
+
+	rules := make([]string, 0, len(r.RuleIssues))
+	for rule := range r.RuleIssues {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	if len(rules) > 0 {
+		fmt.Fprintf(w, "\n%-32s %8s %8s %9s\n", "rule", "issues", "files", "hit rate")
+		for _, rule := range rules {
+			rate := 0.0
+			if r.Files > 0 {
+				rate = float64(r.RuleFiles[rule]) / float64(r.Files) * 100
+			}
+			fmt.Fprintf(w, "%-32s %8d %8d %8.1f%%\n", rule, r.RuleIssues[rule], r.RuleFiles[rule], rate)
+		}
+	}
+
+	if len(r.Failures) > 0 {
+		fmt.Fprintf(w, "\n%d failures:\n", len(r.Failures))
+		for _, f := range r.Failures {
+			fmt.Fprintf(w, "  %s: %s: %s\n", f.Kind, f.File, f.Detail)
+		}
+	}
+}
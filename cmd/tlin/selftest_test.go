@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gnolang/tlin/internal"
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"a.gno":          "package a",
+		"sub/b.gno":      "package b",
+		"sub/c.gno":      "package c",
+		"sub/ignored.go": "package c",
+	}
+	root := ruletest.WriteFiles(t, files)
+
+	engine := new(mockLintEngine)
+	engine.On("Run", filepath.Join(root, "a.gno")).Return([]tt.Issue{
+		{Rule: "useless-break"},
+		{Rule: "useless-break"},
+		{Rule: "emit-format"},
+	}, nil)
+	engine.On("Run", filepath.Join(root, "sub/b.gno")).Return([]tt.Issue{}, errors.New("parse error"))
+	engine.On("Run", filepath.Join(root, "sub/c.gno")).Return([]tt.Issue{{Rule: "useless-break"}}, nil)
+
+	report, err := runSelfTest(engineOf(engine), root, time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Files)
+	assert.Equal(t, 3, report.RuleIssues["useless-break"])
+	assert.Equal(t, 2, report.RuleFiles["useless-break"])
+	assert.Equal(t, 1, report.RuleFiles["emit-format"])
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, selfTestError, report.Failures[0].Kind)
+	assert.False(t, report.Failed(), "plain errors should not fail the self test")
+
+	var buf bytes.Buffer
+	report.Print(&buf)
+	assert.Contains(t, buf.String(), "linted 3 files")
+	assert.Contains(t, buf.String(), "useless-break")
+	assert.Contains(t, buf.String(), "66.7%")
+}
+
+func TestRunSelfTestPanicsAndTimeouts(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+
+	panicking := filepath.Join(root, "panic.gno")
+	recovered := filepath.Join(root, "recovered.gno")
+	slow := filepath.Join(root, "slow.gno")
+	after := filepath.Join(root, "z.gno")
+	for _, path := range []string{panicking, recovered, slow, after} {
+		require.NoError(t, os.WriteFile(path, []byte("package p"), 0o644))
+	}
+
+	engine := new(mockLintEngine)
+	engine.On("Run", panicking).Run(func(mock.Arguments) { panic("boom") }).Return([]tt.Issue{}, nil)
+	engine.On("Run", recovered).Return([]tt.Issue{{Rule: internal.InternalErrorRule, Message: "rule x panicked: boom"}}, nil)
+	engine.On("Run", slow).After(500*time.Millisecond).Return([]tt.Issue{}, nil)
+
+	// the files after a timeout are linted with a new engine, while the slow
+	// run still uses the first one.
+	fresh := new(mockLintEngine)
+	fresh.On("Run", after).Return([]tt.Issue{}, nil)
+
+	engines := []*mockLintEngine{engine, fresh}
+	newEngine := func() (lint.LintEngine, error) {
+		e := engines[0]
+		engines = engines[1:]
+		return e, nil
+	}
+
+	report, err := runSelfTest(newEngine, root, 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, engines)
+	fresh.AssertCalled(t, "Run", after)
+	engine.AssertNotCalled(t, "Run", after)
+
+	require.Len(t, report.Failures, 3)
+	var kinds []selfTestFailureKind
+	for _, f := range report.Failures {
+		kinds = append(kinds, f.Kind)
+	}
+	assert.ElementsMatch(t, []selfTestFailureKind{selfTestPanic, selfTestPanic, selfTestTimeout}, kinds)
+	assert.True(t, report.Failed())
+}
+
+func engineOf(engine lint.LintEngine) func() (lint.LintEngine, error) {
+	return func() (lint.LintEngine, error) { return engine, nil }
+}
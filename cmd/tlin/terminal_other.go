@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package main
+
+// terminalWidth returns 0: the width of the terminal is not detected on this
+// platform, and -width sets it instead.
+func terminalWidth() int {
+	return 0
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/tlin/lint"
+	"go.uber.org/zap"
+)
+
+func runWhyNotCommand(logger *zap.Logger, args []string) int {
+	flagSet := flag.NewFlagSet("tlin why-not", flag.ExitOnError)
+	rule := flagSet.String("rule", "", "Name of the rule to explain")
+	configurationPath := flagSet.String("c", defaultConfigurationPath, "Path to the linter configuration file. By default, the "+defaultConfigurationPath+" at the root of a module applies to its files")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() != 1 || *rule == "" {
+		fmt.Println("usage: tlin why-not -rule <rule> <file>:<line>")
+		return 1
+	}
+
+	filename, line, err := parseWhyNotTarget(flagSet.Arg(0))
+	if err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+
+	engine, err := lint.New(".", nil, *configurationPath)
+	if err != nil {
+		logger.Error("Failed to initialize lint engine", zap.Error(err))
+		return 1
+	}
+
+	if *configurationPath == defaultConfigurationPath {
+		// the file is explained with the configuration of its module.
+		if engine, err = lint.NewModuleEngine(engine, *configurationPath, nil).EngineFor(filename); err != nil {
+			logger.Error("Failed to initialize lint engine", zap.Error(err))
+			return 1
+		}
+	}
+
+	explanation, err := engine.WhyNot(*rule, filename, line)
+	if err != nil {
+		logger.Error("Error explaining rule", zap.Error(err))
+		return 1
+	}
+
+	if len(explanation.Issues) > 0 {
+		fmt.Printf("%s:%d: %s reports this line:\n", filename, line, *rule)
+		for _, issue := range explanation.Issues {
+			fmt.Printf("  - %s\n", issue.Message)
+		}
+	}
+	if len(explanation.Reasons) > 0 {
+		fmt.Printf("%s:%d: %s does not report this line:\n", filename, line, *rule)
+		for _, reason := range explanation.Reasons {
+			fmt.Printf("  - %s\n", reason)
+		}
+	}
+	return 0
+}
+
+func parseWhyNotTarget(target string) (string, int, error) {
+	idx := strings.LastIndex(target, ":")
+	if idx <= 0 {
+		return "", 0, fmt.Errorf("target must be of the form file:line, got %q", target)
+	}
+	line, err := strconv.Atoi(target[idx+1:])
+	if err != nil || line <= 0 {
+		return "", 0, fmt.Errorf("invalid line number in %q", target)
+	}
+	return target[:idx], line, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gnolang/tlin/filter"
+	"github.com/gnolang/tlin/formatter"
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/internal/workspace"
+	"github.com/gnolang/tlin/lint"
+	"go.uber.org/zap"
+)
+
+// missingExportRule reports calls to functions of workspace packages that do
+// not export them.
+const missingExportRule = "missing-export"
+
+// workspaceEngine is an engine facts of the workspace packages are
+// registered with.
+type workspaceEngine interface {
+	lint.LintEngine
+	RegisterDeprecatedFunc(pkgPath, funcName, alternative string)
+}
+
+func runWorkspace(ctx context.Context, logger *zap.Logger, engine workspaceEngine, root string, isJson bool, jsonOutput, summaryOutput string, minConfidence float64, formatOptions formatter.Options, exitPolicy lint.ExitPolicy, issueFilter *filter.Filter) {
+	var cache *workspace.FactsCache
+	if dir, err := workspace.CacheDir(); err == nil {
+		cache = workspace.NewFactsCache(dir)
+	} else {
+		logger.Warn("Facts of the packages are not cached", zap.Error(err))
+	}
+
+	issues, err := lintWorkspace(ctx, logger, engine, root, cache)
+	if err != nil {
+		logger.Error("Error linting workspace", zap.Error(err))
+		os.Exit(1)
+	}
+	issues = issueFilter.Apply(issues)
+
+	if isJson {
+		printIssues(logger, issues, isJson, jsonOutput, minConfidence, formatOptions)
+	} else {
+		printWorkspaceIssues(logger, issues, formatOptions)
+	}
+
+	if summaryOutput != "" {
+		if err := writeWorkspaceSummary(summaryOutput, issues); err != nil {
+			logger.Error("Error writing workspace summary", zap.Error(err))
+			os.Exit(1)
+		}
+	}
+
+	if code := exitPolicy.ExitCode(issues); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// lintWorkspace lints the gno packages below root so that each package is
+// linted after the packages it imports, once their facts are registered in
+// the engine. Facts are read from cache, when not nil, for the packages that
+// did not change since they were stored.
+//
+// The calls of each package to functions that the workspace packages they
+// belong to do not export are reported as missing-export issues.
+func lintWorkspace(ctx context.Context, logger *zap.Logger, engine workspaceEngine, root string, cache *workspace.FactsCache) ([]tt.Issue, error) {
+	pkgs, err := workspace.Discover(root)
+	if err != nil {
+		return nil, err
+	}
+	ordered, err := workspace.Order(pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []tt.Issue
+	exports := make(map[string]map[string]bool, len(ordered))
+	for _, pkg := range ordered {
+		collect := workspace.CollectFacts
+		if cache != nil {
+			collect = cache.Facts
+		}
+		facts, err := collect(pkg)
+		if err != nil {
+			return nil, fmt.Errorf("error analyzing %s: %w", pkg.Path, err)
+		}
+		for _, d := range facts.Deprecated {
+			engine.RegisterDeprecatedFunc(d.Package, d.Function, d.Alternative)
+		}
+		// the packages imported come first, with their exports known.
+		exports[pkg.Path] = facts.ExportedFuncs()
+		missing, err := missingCallIssues(pkg, workspace.MissingCalls(pkg.Path, facts, exports))
+		if err != nil {
+			return nil, fmt.Errorf("error analyzing %s: %w", pkg.Path, err)
+		}
+		issues = append(issues, missing...)
+
+		if len(pkg.Files) == 0 {
+			continue
+		}
+		pkgIssues, err := lint.ProcessFiles(ctx, logger, engine, pkg.Files, lint.ProcessFile)
+		if err != nil {
+			return nil, fmt.Errorf("error linting %s: %w", pkg.Path, err)
+		}
+		for i := range pkgIssues {
+			pkgIssues[i].Package = pkg.Path
+		}
+		issues = append(issues, pkgIssues...)
+	}
+	return issues, nil
+}
+
+// missingCallIssues reports calls, at the declaration of their caller in pkg.
+func missingCallIssues(pkg *workspace.Package, calls []workspace.MissingCall) ([]tt.Issue, error) {
+	var issues []tt.Issue
+	for _, call := range calls {
+		pos, ok, err := workspace.FuncPosition(pkg, call.Caller)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		issues = append(issues, tt.Issue{
+			Rule:       missingExportRule,
+			Filename:   pos.Filename,
+			Message:    fmt.Sprintf("%s calls %s, which its package does not export", call.Caller, call.Callee),
+			Note:       "the function was removed, renamed or unexported in its package. call the function replacing it, or restore it.",
+			Start:      pos,
+			End:        pos,
+			Confidence: 1,
+			Severity:   tt.SeverityError,
+			Package:    pkg.Path,
+		})
+	}
+	return issues, nil
+}
+
+// printWorkspaceIssues prints the issues of each package under its path and
+// number of issues.
+func printWorkspaceIssues(logger *zap.Logger, issues []tt.Issue, formatOptions formatter.Options) {
+	paths, byPackage := workspace.GroupByPackage(issues)
+	for _, path := range paths {
+		fmt.Printf("%s: %d issue(s)\n\n", path, len(byPackage[path]))
+		printIssues(logger, byPackage[path], false, "", 0, formatOptions)
+	}
+}
+
+// writeWorkspaceSummary writes the number of issues by package and rule as JSON.
+func writeWorkspaceSummary(path string, issues []tt.Issue) error {
+	d, err := json.MarshalIndent(workspace.Summarize(issues), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(d, '\n'), 0o644)
+}
@@ -0,0 +1,248 @@
+// Package filter implements a small expression language selecting lint
+// issues, such as
+//
+//	rule == "early-return-opportunity" && severity >= warning && file ~ "contracts/"
+//
+// A comparison has a field on its left and a value on its right. The fields
+// are rule, category, file, message, note, package and cost, compared as
+// strings, severity, ordered from info to error, and line and confidence,
+// compared as numbers. Strings are compared with == and !=, or matched
+// against a regular expression with ~ and !~. Severities and numbers are also
+// ordered with <, <=, > and >=.
+//
+// Values are double-quoted or back-quoted strings, or bare words such as
+// warning or 10. Comparisons are combined with &&, || and !, and grouped with
+// parentheses.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// Filter is a parsed filter expression.
+type Filter struct {
+	src  string
+	expr node
+}
+
+// Parse parses a filter expression.
+func Parse(src string) (*Filter, error) {
+	p := &parser{lexer: lexer{src: src}}
+	p.next()
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Filter{src: src, expr: expr}, nil
+}
+
+// Match reports whether issue is selected by f. A nil filter selects all
+// the issues.
+func (f *Filter) Match(issue tt.Issue) bool {
+	return f == nil || f.expr.eval(issue)
+}
+
+// Apply returns the issues selected by f.
+func (f *Filter) Apply(issues []tt.Issue) []tt.Issue {
+	if f == nil {
+		return issues
+	}
+	var selected []tt.Issue
+	for _, issue := range issues {
+		if f.Match(issue) {
+			selected = append(selected, issue)
+		}
+	}
+	return selected
+}
+
+func (f *Filter) String() string {
+	return f.src
+}
+
+type node interface {
+	eval(issue tt.Issue) bool
+}
+
+type andNode struct{ x, y node }
+
+func (n andNode) eval(issue tt.Issue) bool { return n.x.eval(issue) && n.y.eval(issue) }
+
+type orNode struct{ x, y node }
+
+func (n orNode) eval(issue tt.Issue) bool { return n.x.eval(issue) || n.y.eval(issue) }
+
+type notNode struct{ x node }
+
+func (n notNode) eval(issue tt.Issue) bool { return !n.x.eval(issue) }
+
+type fieldKind int
+
+const (
+	stringField fieldKind = iota
+	severityField
+	numberField
+)
+
+var fieldKinds = map[string]fieldKind{
+	"rule":       stringField,
+	"category":   stringField,
+	"file":       stringField,
+	"message":    stringField,
+	"note":       stringField,
+	"package":    stringField,
+	"cost":       stringField,
+	"severity":   severityField,
+	"line":       numberField,
+	"confidence": numberField,
+}
+
+func stringValue(field string, issue tt.Issue) string {
+	switch field {
+	case "rule":
+		return issue.Rule
+	case "category":
+		return issue.Category
+	case "file":
+		return issue.Filename
+	case "message":
+		return issue.Message
+	case "note":
+		return issue.Note
+	case "package":
+		return issue.Package
+	case "cost":
+		return string(issue.Cost)
+	}
+	return ""
+}
+
+func numberValue(field string, issue tt.Issue) float64 {
+	switch field {
+	case "line":
+		return float64(issue.Start.Line)
+	case "confidence":
+		return issue.Confidence
+	case "severity":
+		return severityRank(issue.Severity)
+	}
+	return 0
+}
+
+// severityRank orders the severities from the least to the most severe,
+// unlike their values.
+func severityRank(s tt.Severity) float64 {
+	return float64(tt.SeverityOff - s)
+}
+
+type stringCmp struct {
+	field string
+	op    string
+	value string
+}
+
+func (n stringCmp) eval(issue tt.Issue) bool {
+	equal := stringValue(n.field, issue) == n.value
+	if n.op == "!=" {
+		return !equal
+	}
+	return equal
+}
+
+type regexpCmp struct {
+	field  string
+	negate bool
+	re     *regexp.Regexp
+}
+
+func (n regexpCmp) eval(issue tt.Issue) bool {
+	return n.re.MatchString(stringValue(n.field, issue)) != n.negate
+}
+
+type numberCmp struct {
+	field string
+	op    string
+	value float64
+}
+
+func (n numberCmp) eval(issue tt.Issue) bool {
+	v := numberValue(n.field, issue)
+	switch n.op {
+	case "==":
+		return v == n.value
+	case "!=":
+		return v != n.value
+	case "<":
+		return v < n.value
+	case "<=":
+		return v <= n.value
+	case ">":
+		return v > n.value
+	case ">=":
+		return v >= n.value
+	}
+	return false
+}
+
+// comparison builds the comparison of field with value by op.
+func comparison(field, op, value string) (node, error) {
+	kind, ok := fieldKinds[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+
+	switch kind {
+	case stringField:
+		switch op {
+		case "==", "!=":
+			return stringCmp{field: field, op: op, value: value}, nil
+		case "~", "!~":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %w", value, err)
+			}
+			return regexpCmp{field: field, negate: op == "!~", re: re}, nil
+		}
+		return nil, fmt.Errorf("%s is a string, compare it with ==, !=, ~ or !~", field)
+
+	case severityField:
+		severity, ok := parseSeverity(value)
+		if !ok {
+			return nil, fmt.Errorf("unknown severity %q, expected error, warning or info", value)
+		}
+		if op == "~" || op == "!~" {
+			return nil, fmt.Errorf("%s cannot be matched with %s", field, op)
+		}
+		return numberCmp{field: field, op: op, value: severityRank(severity)}, nil
+
+	default:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is a number, got %q", field, value)
+		}
+		if op == "~" || op == "!~" {
+			return nil, fmt.Errorf("%s cannot be matched with %s", field, op)
+		}
+		return numberCmp{field: field, op: op, value: number}, nil
+	}
+}
+
+func parseSeverity(s string) (tt.Severity, bool) {
+	switch strings.ToLower(s) {
+	case "error":
+		return tt.SeverityError, true
+	case "warning":
+		return tt.SeverityWarning, true
+	case "info":
+		return tt.SeverityInfo, true
+	}
+	return 0, false
+}
@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterMatch(t *testing.T) {
+	t.Parallel()
+	issue := tt.Issue{
+		Rule:       "early-return-opportunity",
+		Category:   "style",
+		Filename:   "contracts/bank/bank.gno",
+		Message:    "can be simplified using early return",
+		Start:      token.Position{Line: 12},
+		Confidence: 0.8,
+		Severity:   tt.SeverityWarning,
+	}
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{`rule=="early-return-opportunity" && severity>=warning && file~"contracts/"`, true},
+		{`rule == early-return-opportunity`, true},
+		{`rule != "early-return-opportunity"`, false},
+		{`severity >= error`, false},
+		{`severity > info`, true},
+		{`severity == WARNING`, true},
+		{`severity < warning`, false},
+		{`file !~ "_test\\.gno$"`, true},
+		{"message ~ `early\\s+return`", true},
+		{`line > 10 && line <= 12`, true},
+		{`confidence < 0.5`, false},
+		{`package == ""`, true},
+		{`!(category == style) || rule ~ "^early"`, true},
+		{`category == style && (line < 5 || line > 20)`, false},
+		{`rule == "nested-if" || rule == "early-return-opportunity" && severity == error`, false},
+	}
+	for _, tc := range tests {
+		f, err := Parse(tc.expr)
+		require.NoError(t, err, tc.expr)
+		assert.Equal(t, tc.expected, f.Match(issue), tc.expr)
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	t.Parallel()
+	issues := []tt.Issue{
+		{Rule: "a", Severity: tt.SeverityError},
+		{Rule: "b", Severity: tt.SeverityInfo},
+		{Rule: "c", Severity: tt.SeverityWarning},
+	}
+
+	f, err := Parse("severity >= warning")
+	require.NoError(t, err)
+	assert.Equal(t, []tt.Issue{issues[0], issues[2]}, f.Apply(issues))
+
+	var none *Filter
+	assert.Equal(t, issues, none.Apply(issues))
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{``, "position 0: expected a field, got end of the filter"},
+		{`rule`, "position 4: expected a comparison operator after rule, got end of the filter"},
+		{`rule ==`, "position 7: expected a value after ==, got end of the filter"},
+		{`owner == bob`, `position 0: unknown field "owner"`},
+		{`rule < b`, "position 0: rule is a string, compare it with ==, !=, ~ or !~"},
+		{`severity >= fatal`, `position 0: unknown severity "fatal", expected error, warning or info`},
+		{`line == ten`, `position 0: line is a number, got "ten"`},
+		{`file ~ "("`, "position 0: invalid regular expression"},
+		{`rule == "a`, "position 8: unterminated string"},
+		{`(rule == a`, "position 10: expected ), got end of the filter"},
+		{`rule == a b`, `position 10: unexpected "b"`},
+		{`rule == a & b`, `position 10: unexpected character '&'`},
+	}
+	for _, tc := range tests {
+		_, err := Parse(tc.expr)
+		require.Error(t, err, tc.expr)
+		assert.Contains(t, err.Error(), tc.err, tc.expr)
+	}
+}
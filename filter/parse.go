@@ -0,0 +1,226 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type lexemeKind int
+
+const (
+	tokEOF lexemeKind = iota
+	tokWord
+	tokString
+	tokOp
+)
+
+type lexeme struct {
+	kind lexemeKind
+	text string // the word, the unquoted string or the operator
+	pos  int    // byte offset in the expression
+}
+
+func (t lexeme) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of the filter"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// operators are tried in order, so that the longest one wins.
+var operators = []string{"&&", "||", "==", "!=", "!~", "<=", ">=", "<", ">", "~", "!", "(", ")"}
+
+var comparisonOps = map[string]bool{"==": true, "!=": true, "~": true, "!~": true, "<": true, "<=": true, ">": true, ">=": true}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (lexeme, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(rune(l.src[l.pos])) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos == len(l.src) {
+		return lexeme{kind: tokEOF, pos: start}, nil
+	}
+
+	rest := l.src[l.pos:]
+	if rest[0] == '"' || rest[0] == '`' {
+		end := closingQuote(rest)
+		if end < 0 {
+			return lexeme{}, fmt.Errorf("position %d: unterminated string", start)
+		}
+		quoted := rest[:end+1]
+		text, err := strconv.Unquote(quoted)
+		if err != nil {
+			return lexeme{}, fmt.Errorf("position %d: invalid string %s", start, quoted)
+		}
+		l.pos += len(quoted)
+		return lexeme{kind: tokString, text: text, pos: start}, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			l.pos += len(op)
+			return lexeme{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return !isWordRune(r)
+	})
+	if end == 0 {
+		return lexeme{}, fmt.Errorf("position %d: unexpected character %q", start, rest[0])
+	}
+	if end < 0 {
+		end = len(rest)
+	}
+	l.pos += end
+	return lexeme{kind: tokWord, text: rest[:end], pos: start}, nil
+}
+
+// closingQuote returns the index of the quote closing the string s starts
+// with, or -1. Double-quoted strings may escape quotes with backslashes.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && s[0] == '"':
+			i++
+		case s[i] == s[0]:
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-./", r)
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" or ")" | comparison
+//	comparison = word op ( word | string )
+type parser struct {
+	lexer lexer
+	tok   lexeme
+	err   error
+}
+
+func (p *parser) next() {
+	if p.err != nil {
+		return
+	}
+	p.tok, p.err = p.lexer.next()
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	if p.err != nil {
+		return p.err
+	}
+	return fmt.Errorf("position %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) isOp(op string) bool {
+	return p.err == nil && p.tok.kind == tokOp && p.tok.text == op
+}
+
+func (p *parser) parseOr() (node, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = orNode{x, y}
+	}
+	return x, p.err
+}
+
+func (p *parser) parseAnd() (node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = andNode{x, y}
+	}
+	return x, p.err
+}
+
+func (p *parser) parseUnary() (node, error) {
+	switch {
+	case p.isOp("!"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	case p.isOp("("):
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOp(")") {
+			return nil, p.errorf("expected ), got %s", p.tok)
+		}
+		p.next()
+		return x, p.err
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != tokWord {
+		return nil, p.errorf("expected a field, got %s", p.tok)
+	}
+	field := p.tok
+	p.next()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	op := p.tok
+	if op.kind != tokOp || !comparisonOps[op.text] {
+		return nil, p.errorf("expected a comparison operator after %s, got %s", field.text, p.tok)
+	}
+	p.next()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != tokWord && p.tok.kind != tokString {
+		return nil, p.errorf("expected a value after %s, got %s", op.text, p.tok)
+	}
+	value := p.tok
+	p.next()
+
+	n, err := comparison(field.text, op.text, value.text)
+	if err != nil {
+		return nil, fmt.Errorf("position %d: %w", field.pos, err)
+	}
+	return n, p.err
+}
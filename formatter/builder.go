@@ -11,6 +11,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/gnolang/tlin/internal"
 	tt "github.com/gnolang/tlin/internal/types"
+	"golang.org/x/text/width"
 )
 
 const tabWidth = 8
@@ -59,15 +60,76 @@ func getIssueFormatter(rule string) issueFormatter {
 // GenerateFormattedIssue formats a slice of issues into a human-readable string.
 // It uses the appropriate formatter for each issue based on its rule.
 func GenerateFormattedIssue(issues []tt.Issue, snippet *internal.SourceCode) string {
+	return GenerateFormattedIssueWithContext(issues, snippet, 0)
+}
+
+// GenerateFormattedIssueWithContext is like GenerateFormattedIssue, but also
+// shows up to context lines of code before and after each issue.
+func GenerateFormattedIssueWithContext(issues []tt.Issue, snippet *internal.SourceCode, context int) string {
+	return GenerateFormattedIssueWithOptions(issues, snippet, Options{Context: context})
+}
+
+// Options customizes the output of GenerateFormattedIssueWithOptions.
+type Options struct {
+	// Context is the number of lines of code shown before and after each issue.
+	Context int
+	// Template replaces the templates of all rules when set. It is executed
+	// with an IssueData, see LoadTemplate.
+	Template string
+	// VerboseNotes prints the note of every issue. Otherwise, a note already
+	// printed for an issue of the same rule is replaced with a reference to
+	// the line of that issue.
+	VerboseNotes bool
+	// Width is the number of columns messages and notes are wrapped at,
+	// between words. Zero leaves them unwrapped, as do custom templates.
+	Width int
+}
+
+// GenerateFormattedIssueWithOptions is like GenerateFormattedIssue, with the
+// output customized by opts.
+func GenerateFormattedIssueWithOptions(issues []tt.Issue, snippet *internal.SourceCode, opts Options) string {
+	if !opts.VerboseNotes {
+		issues = dedupNotes(issues)
+	}
+
 	var builder strings.Builder
 	for _, issue := range issues {
-		formatter := getIssueFormatter(issue.Rule)
-		formattedIssue := buildIssue(issue, snippet, formatter)
+		var formatter issueFormatter = customFormatter(opts.Template)
+		if opts.Template == "" {
+			formatter = getIssueFormatter(issue.Rule)
+		}
+		formattedIssue := buildIssue(issue, snippet, formatter, opts.Context, opts.Width)
 		builder.WriteString(formattedIssue)
 	}
 	return builder.String()
 }
 
+// dedupNotes returns issues where the notes identical to the note of a
+// previous issue of the same rule refer to it instead, when the reference is
+// shorter. issues is left untouched.
+func dedupNotes(issues []tt.Issue) []tt.Issue {
+	type ruleNote struct{ rule, note string }
+	firstLine := make(map[ruleNote]int)
+
+	deduped := make([]tt.Issue, len(issues))
+	copy(deduped, issues)
+	for i, issue := range deduped {
+		if issue.Note == "" {
+			continue
+		}
+		key := ruleNote{issue.Rule, issue.Note}
+		line, seen := firstLine[key]
+		if !seen {
+			firstLine[key] = issue.Start.Line
+			continue
+		}
+		if ref := fmt.Sprintf("same as the note of line %d", line); len(ref) < len(issue.Note) {
+			deduped[i].Note = ref
+		}
+	}
+	return deduped
+}
+
 /***** Issue Formatter Builder *****/
 
 type IssueData struct {
@@ -86,6 +148,9 @@ type IssueData struct {
 	Note            string
 	SnippetLines    []string
 	CommonIndent    string
+	Context         int // lines of code shown before and after the issue
+
+	RelatedLocations []tt.Location
 }
 
 var funcMap = template.FuncMap{
@@ -97,6 +162,10 @@ var funcMap = template.FuncMap{
 	"message":             message,
 	"warning":             warning,
 	"complexityInfo":      complexityInfo,
+	"related":             related,
+	"style":               style,
+	"severity":            severityStyle,
+	"lower":               strings.ToLower,
 }
 
 var templateCache sync.Map
@@ -111,17 +180,26 @@ func getCachedTemplate(tmplStr string) *template.Template {
 	return newTmpl
 }
 
-func buildIssue(issue tt.Issue, snippet *internal.SourceCode, formatter issueFormatter) string {
+func buildIssue(issue tt.Issue, snippet *internal.SourceCode, formatter issueFormatter, context, width int) string {
 	startLine := issue.Start.Line
 	endLine := issue.End.Line
-	maxLineNumWidth := calculateMaxLineNumWidth(endLine)
-	padding := strings.Repeat(" ", maxLineNumWidth+1)
+	lastLine := endLine
 
 	var commonIndent string
 	if startLine-1 < 0 || endLine > len(snippet.Lines) || startLine > endLine {
 		commonIndent = ""
+		context = 0
 	} else {
-		commonIndent = findCommonIndent(snippet.Lines[startLine-1 : endLine])
+		var firstLine int
+		firstLine, lastLine = contextRange(startLine, endLine, context, len(snippet.Lines))
+		commonIndent = findCommonIndent(snippet.Lines[firstLine-1 : lastLine])
+	}
+	maxLineNumWidth := calculateMaxLineNumWidth(lastLine)
+	padding := strings.Repeat(" ", maxLineNumWidth+1)
+	if _, custom := formatter.(customFormatter); !custom && width > 0 {
+		// continuation lines line up with the text after "= " and "= note: ".
+		issue.Message = wrapText(issue.Message, width, len(padding)+len("= "))
+		issue.Note = wrapText(issue.Note, width, len(padding)+len("= note: "))
 	}
 
 	data := IssueData{
@@ -140,6 +218,9 @@ func buildIssue(issue tt.Issue, snippet *internal.SourceCode, formatter issueFor
 		Padding:         padding,
 		CommonIndent:    commonIndent,
 		SnippetLines:    snippet.Lines,
+		Context:         context,
+
+		RelatedLocations: issue.RelatedLocations,
 	}
 
 	issueTemplate := formatter.IssueTemplate()
@@ -149,7 +230,23 @@ func buildIssue(issue tt.Issue, snippet *internal.SourceCode, formatter issueFor
 	if err := tmpl.Execute(&buf, data); err != nil {
 		return fmt.Sprintf("Error formatting issue: %v", err)
 	}
-	return buf.String()
+
+	// custom templates decide for themselves what to show.
+	if _, ok := formatter.(customFormatter); ok {
+		return buf.String()
+	}
+	if issue.Cost == tt.CostUnknown && issue.DocURL == "" {
+		return buf.String()
+	}
+	// cost and link go right before the blank line separating issues.
+	out := strings.TrimSuffix(buf.String(), "\n")
+	if issue.Cost != tt.CostUnknown {
+		out += costHint(issue.Cost, padding)
+	}
+	if issue.DocURL != "" {
+		out += docLink(issue.DocURL, padding)
+	}
+	return out + "\n"
 }
 
 // utils functions used in the text templates
@@ -174,11 +271,11 @@ func header(rule string, severity string, maxLineNumWidth int, filename string,
 	return endString
 }
 
-func codeSnippet(snippetLines []string, startLine int, endLine int, maxLineNumWidth int, commonIndent string, padding string) string {
+func codeSnippet(snippetLines []string, startLine int, endLine int, maxLineNumWidth int, commonIndent string, padding string, context int) string {
 	var endString string
 	endString = lineStyle.Sprintf("%s|\n", padding)
 
-	for i := startLine; i <= endLine; i++ {
+	for i := startLine - context; i <= endLine; i++ {
 		if i-1 < 0 || i-1 >= len(snippetLines) {
 			continue
 		}
@@ -194,7 +291,7 @@ func codeSnippet(snippetLines []string, startLine int, endLine int, maxLineNumWi
 	return endString
 }
 
-func underlineAndMessage(message string, padding string, startLine int, endLine int, startColumn int, endColumn int, snippetLines []string, commonIndent string, note string) string {
+func underlineAndMessage(message string, padding string, startLine int, endLine int, startColumn int, endColumn int, snippetLines []string, commonIndent string, note string, context int, maxLineNumWidth int) string {
 	var endString string
 	endString = lineStyle.Sprintf("%s| ", padding)
 
@@ -217,6 +314,10 @@ func underlineAndMessage(message string, padding string, startLine int, endLine
 
 	endString += fmt.Sprint(strings.Repeat(" ", underlineStart))
 	endString += messageStyle.Sprintf("%s\n", strings.Repeat("^", underlineLength))
+	for i := endLine + 1; i <= endLine+context && i <= len(snippetLines); i++ {
+		endString += lineStyle.Sprintf("%*d | ", maxLineNumWidth, i)
+		endString += noStyle.Sprintf("%s\n", strings.TrimPrefix(snippetLines[i-1], commonIndent))
+	}
 	endString += lineStyle.Sprintf("%s|\n", padding)
 
 	endString += lineStyle.Sprintf("%s= ", padding)
@@ -265,6 +366,50 @@ func note(note string, padding string, suggestion string) string {
 	return endString
 }
 
+// related renders the secondary locations attached to an issue.
+// Like note, the trailing newline is left to the suggestion block when it follows.
+func related(locations []tt.Location, padding string, note string, suggestion string) string {
+	if len(locations) == 0 {
+		return ""
+	}
+
+	var endString string
+	if note != "" && suggestion != "" {
+		endString += "\n"
+	}
+
+	arrowPadding := strings.Repeat(" ", len(padding)-1)
+	for i, loc := range locations {
+		if i > 0 {
+			endString += "\n"
+		}
+		endString += lineStyle.Sprintf("%s= ", padding)
+		endString += noStyle.Sprintf("related: %s\n", loc.Message)
+		endString += lineStyle.Sprintf("%s--> ", arrowPadding)
+		endString += fileStyle.Sprintf("%s:%d:%d", loc.Filename, loc.Start.Line, loc.Start.Column)
+	}
+
+	if suggestion == "" {
+		endString += "\n"
+	}
+	return endString
+}
+
+// costHint renders the estimated cost category of the flagged code.
+func costHint(cost tt.CostCategory, padding string) string {
+	return lineStyle.Sprintf("%s= ", padding) + noStyle.Sprintf("cost: %s\n", cost)
+}
+
+// docLink renders the documentation URL of the rule. Terminals that support
+// colors get an OSC 8 hyperlink, others the plain URL.
+func docLink(url string, padding string) string {
+	text := url
+	if !color.NoColor {
+		text = "\x1b]8;;" + url + "\x1b\\" + url + "\x1b]8;;\x1b\\"
+	}
+	return lineStyle.Sprintf("%s= ", padding) + noStyle.Sprintf("docs: ") + text + "\n"
+}
+
 func isValidLineRange(startLine int, endLine int, snippetLines []string) bool {
 	return startLine > 0 &&
 		endLine > 0 &&
@@ -273,30 +418,93 @@ func isValidLineRange(startLine int, endLine int, snippetLines []string) bool {
 		endLine <= len(snippetLines)
 }
 
+// contextRange returns the first and last lines shown for an issue spanning
+// startLine to endLine, with up to context lines around it.
+func contextRange(startLine, endLine, context, lineCount int) (int, int) {
+	first := max(startLine-context, 1)
+	last := min(endLine+context, lineCount)
+	return first, last
+}
+
 func calculateMaxLineNumWidth(endLine int) int {
 	return len(fmt.Sprintf("%d", endLine))
 }
 
-// calculateVisualColumn calculates the visual column position
-// in a string. taking into account tab characters.
+// calculateVisualColumn calculates the 0-based visual column of the byte
+// column in line, as displayed by a terminal: tabs advance to the next tab
+// stop, and characters take the number of cells given by runeWidth.
 func calculateVisualColumn(line string, column int) int {
-	if !strings.ContainsRune(line, '\t') || column <= 1 {
-		return column - 1 // adjust to 0-based index
-	}
-
-	// calculate visual column only if there is a tab character
 	visualColumn := 0
 	for i, ch := range line {
-		if i+1 == column {
-			break
+		if i+1 >= column {
+			return visualColumn
 		}
 		if ch == '\t' {
 			visualColumn += tabWidth - (visualColumn % tabWidth)
 		} else {
-			visualColumn++
+			visualColumn += runeWidth(ch)
+		}
+	}
+	// columns past the end of the line, e.g. the end of an issue at the end
+	// of the line, are counted as single cells.
+	return visualColumn + column - 1 - len(line)
+}
+
+// runeWidth returns the number of terminal cells taken by r: two for wide
+// characters such as CJK ideographs, none for combining marks and format
+// characters, one otherwise.
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// minWrapWidth is the narrowest column wrapText fills, below which the text
+// is left unwrapped rather than split into a word per line.
+const minWrapWidth = 20
+
+// wrapText wraps text between words so that its lines, starting at column
+// indent, fit in width columns. Lines after the first are indented with
+// indent spaces. Words wider than the space left are not split.
+func wrapText(text string, width, indent int) string {
+	if text == "" || width-indent < minWrapWidth {
+		return text
+	}
+	prefix := strings.Repeat(" ", indent)
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line, lineWidth := "", 0
+		for _, word := range strings.Fields(paragraph) {
+			w := stringWidth(word)
+			if line != "" && indent+lineWidth+1+w > width {
+				lines = append(lines, line)
+				line, lineWidth = "", 0
+			}
+			if line != "" {
+				line += " "
+				lineWidth++
+			}
+			line += word
+			lineWidth += w
 		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"+prefix)
+}
+
+// stringWidth returns the number of terminal cells taken by s.
+func stringWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
 	}
-	return visualColumn
+	return n
 }
 
 // findCommonIndent finds the common indent in the code snippet.
@@ -2,11 +2,16 @@ package formatter
 
 import (
 	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/fatih/color"
 	"github.com/gnolang/tlin/internal"
 	tt "github.com/gnolang/tlin/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatIssuesWithArrows(t *testing.T) {
@@ -290,3 +295,461 @@ func TestFindCommonIndent(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatIssueWithRelatedLocations(t *testing.T) {
+	t.Parallel()
+	code := &internal.SourceCode{
+		Lines: []string{
+			"package main",
+			"",
+			"func main() {",
+			"    x := 0",
+			"    println(1 / x)",
+			"}",
+		},
+	}
+
+	issues := []tt.Issue{
+		{
+			Rule:     "division-by-zero",
+			Filename: "test.go",
+			Start:    token.Position{Line: 5, Column: 13},
+			End:      token.Position{Line: 5, Column: 17},
+			Message:  "possible division by zero",
+			RelatedLocations: []tt.Location{
+				{
+					Filename: "test.go",
+					Message:  "divisor set to zero here",
+					Start:    token.Position{Line: 4, Column: 5},
+					End:      token.Position{Line: 4, Column: 11},
+				},
+			},
+		},
+	}
+
+	expected := `error: division-by-zero
+ --> test.go:5:13
+  |
+5 | println(1 / x)
+  |         ^^^^^
+  |
+  = possible division by zero
+  = related: divisor set to zero here
+ --> test.go:4:5
+
+`
+
+	result := GenerateFormattedIssue(issues, code)
+	assert.Equal(t, expected, result)
+
+	issues[0].Note = "the divisor is never reassigned"
+	issues[0].Suggestion = "if x != 0 {"
+
+	expected = `error: division-by-zero
+ --> test.go:5:13
+  |
+5 | println(1 / x)
+  |         ^^^^^
+  |
+  = possible division by zero
+  = note: the divisor is never reassigned
+  = related: divisor set to zero here
+ --> test.go:4:5
+suggestion:
+  |
+5 | if x != 0 {
+  |
+
+`
+
+	result = GenerateFormattedIssue(issues, code)
+	assert.Equal(t, expected, result)
+}
+
+func TestFormatIssueWithDocURL(t *testing.T) {
+	t.Parallel()
+	code := &internal.SourceCode{
+		Lines: []string{
+			"package main",
+			"",
+			"func main() {",
+			"    x := 1",
+			"}",
+		},
+	}
+
+	issues := []tt.Issue{
+		{
+			Rule:     "useless-break",
+			Filename: "test.go",
+			Start:    token.Position{Line: 4, Column: 5},
+			End:      token.Position{Line: 4, Column: 6},
+			Message:  "x declared but not used",
+			DocURL:   "https://example.com/rules/useless-break",
+		},
+	}
+
+	// colors are disabled when not writing to a terminal, so the plain URL is rendered.
+	expected := `error: useless-break
+ --> test.go:4:5
+  |
+4 | x := 1
+  | ^^
+  |
+  = x declared but not used
+  = docs: https://example.com/rules/useless-break
+
+`
+
+	result := GenerateFormattedIssue(issues, code)
+	assert.Equal(t, expected, result)
+}
+
+func TestFormatIssueWithCost(t *testing.T) {
+	t.Parallel()
+	code := &internal.SourceCode{
+		Lines: []string{
+			"package main",
+			"",
+			"func main() {",
+			"    s += x",
+			"}",
+		},
+	}
+
+	issues := []tt.Issue{
+		{
+			Rule:     "gas-hint",
+			Filename: "test.gno",
+			Start:    token.Position{Line: 4, Column: 5},
+			End:      token.Position{Line: 4, Column: 10},
+			Message:  "string concatenation in a loop",
+			Severity: tt.SeverityInfo,
+			Cost:     tt.CostQuadratic,
+		},
+	}
+
+	expected := `info: gas-hint
+ --> test.gno:4:5
+  |
+4 | s += x
+  | ^^^^^^
+  |
+  = string concatenation in a loop
+  = cost: quadratic
+
+`
+
+	result := GenerateFormattedIssue(issues, code)
+	assert.Equal(t, expected, result)
+}
+
+func TestCalculateVisualColumn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		line   string
+		column int
+		want   int
+	}{
+		{name: "ascii", line: "x := 1", column: 3, want: 2},
+		{name: "tab stop", line: "\tx := 1", column: 2, want: 8},
+		{name: "cjk identifier", line: "名前 := 1", column: 8, want: 5},
+		{name: "after cjk string", line: `s := "日本語"; x`, column: 19, want: 15},
+		{name: "combining mark", line: "café := 1", column: 8, want: 5},
+		{name: "fullwidth", line: "ＡＢ := 1", column: 7, want: 4},
+		{name: "past the end", line: "名", column: 5, want: 3},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, calculateVisualColumn(tc.line, tc.column))
+		})
+	}
+}
+
+func TestFormatIssueWithCJKIdentifiers(t *testing.T) {
+	t.Parallel()
+	code := &internal.SourceCode{
+		Lines: []string{
+			"package main",
+			"",
+			"func main() {",
+			"\t名前 := \"値\"; 数 := 1",
+			"}",
+		},
+	}
+
+	issues := []tt.Issue{
+		{
+			Rule:     "unused-variable",
+			Filename: "test.go",
+			Start:    token.Position{Line: 4, Column: 19},
+			End:      token.Position{Line: 4, Column: 22},
+			Message:  "数 declared but not used",
+		},
+	}
+
+	expected := `error: unused-variable
+ --> test.go:4:19
+  |
+4 | 名前 := "値"; 数 := 1
+  |               ^^^
+  |
+  = 数 declared but not used
+
+`
+
+	assert.Equal(t, expected, GenerateFormattedIssue(issues, code))
+}
+
+func TestFormatIssueWithContext(t *testing.T) {
+	t.Parallel()
+	code := &internal.SourceCode{
+		Lines: []string{
+			"package main",
+			"",
+			"func main() {",
+			"    x := 1",
+			"    if true {}",
+			"}",
+		},
+	}
+
+	issues := []tt.Issue{
+		{
+			Rule:     "unused-variable",
+			Filename: "test.go",
+			Start:    token.Position{Line: 4, Column: 5},
+			End:      token.Position{Line: 4, Column: 6},
+			Message:  "x declared but not used",
+		},
+		{
+			Rule:     "empty-if",
+			Filename: "test.go",
+			Start:    token.Position{Line: 5, Column: 5},
+			End:      token.Position{Line: 5, Column: 13},
+			Message:  "empty branch",
+		},
+	}
+
+	// context lines stop at the end of the file.
+	expected := `error: unused-variable
+ --> test.go:4:5
+  |
+3 | func main() {
+4 |     x := 1
+  |     ^^
+5 |     if true {}
+  |
+  = x declared but not used
+
+error: empty-if
+ --> test.go:5:5
+  |
+4 |     x := 1
+5 |     if true {}
+  |     ^^^^^^^^^
+6 | }
+  |
+  = empty branch
+
+`
+
+	result := GenerateFormattedIssueWithContext(issues, code, 1)
+	assert.Equal(t, expected, result)
+
+	// a context going past both ends of the file shows the whole file.
+	result = GenerateFormattedIssueWithContext(issues[:1], code, 10)
+	assert.Contains(t, result, "1 | package main\n")
+	assert.Contains(t, result, "6 | }\n")
+	assert.NotContains(t, result, "7 |")
+}
+
+func TestFormatIssuesWithCompactTemplate(t *testing.T) {
+	t.Parallel()
+	code := &internal.SourceCode{
+		Lines: []string{
+			"package main",
+			"",
+			"func main() {",
+			"    x := 1",
+			"}",
+		},
+	}
+	issues := []tt.Issue{
+		{
+			Rule:     "unused-variable",
+			Filename: "test.go",
+			Start:    token.Position{Line: 4, Column: 5},
+			End:      token.Position{Line: 4, Column: 6},
+			Message:  "x declared but not used",
+			Severity: tt.SeverityWarning,
+			Cost:     tt.CostQuadratic,
+		},
+	}
+
+	tmpl, err := LoadTemplate("compact")
+	assert.NoError(t, err)
+
+	expected := "test.go:4:5: warning: x declared but not used [unused-variable]\n"
+	assert.Equal(t, expected, GenerateFormattedIssueWithOptions(issues, code, Options{Template: tmpl}))
+}
+
+func TestFormatIssuesWithLineTemplate(t *testing.T) {
+	t.Parallel()
+	code := &internal.SourceCode{Lines: []string{"package main", "", "func main() {", "    x := 1", "}"}}
+	issues := []tt.Issue{
+		{
+			Rule:     "unused-variable",
+			Filename: "main.go",
+			Start:    token.Position{Line: 4, Column: 5},
+			End:      token.Position{Line: 4, Column: 6},
+			Message:  "x declared but not used",
+			Note:     "remove it",
+			Severity: tt.SeverityError,
+		},
+		{
+			Rule:     "magic-number",
+			Filename: "main.go",
+			Start:    token.Position{Line: 4, Column: 10},
+			End:      token.Position{Line: 4, Column: 11},
+			Message:  "magic number 1",
+			Severity: tt.SeverityInfo,
+		},
+	}
+
+	tmpl, err := LoadTemplate("line")
+	require.NoError(t, err)
+
+	expected := `main.go:4:5: error unused-variable: x declared but not used
+main.go:4:10: info magic-number: magic number 1
+`
+	assert.Equal(t, expected, GenerateFormattedIssueWithOptions(issues, code, Options{Template: tmpl}))
+}
+
+func TestFormatIssuesDedupNotes(t *testing.T) {
+	t.Parallel()
+	long := "slicing up to the length of the slice is the same as omitting the high bound"
+	issues := []tt.Issue{
+		{Rule: "simplify-slice-range", Start: token.Position{Line: 3}, Note: long},
+		{Rule: "simplify-slice-range", Start: token.Position{Line: 7}, Note: long},
+		{Rule: "other-rule", Start: token.Position{Line: 8}, Note: long},
+		{Rule: "simplify-slice-range", Start: token.Position{Line: 9}, Note: "short"},
+		{Rule: "simplify-slice-range", Start: token.Position{Line: 10}, Note: "short"},
+	}
+	tmpl := "{{.Rule}}:{{.StartLine}} {{.Note}}\n"
+
+	expected := `simplify-slice-range:3 ` + long + `
+simplify-slice-range:7 same as the note of line 3
+other-rule:8 ` + long + `
+simplify-slice-range:9 short
+simplify-slice-range:10 short
+`
+	code := &internal.SourceCode{}
+	assert.Equal(t, expected, GenerateFormattedIssueWithOptions(issues, code, Options{Template: tmpl}))
+	assert.Equal(t, long, issues[1].Note, "the issues are left untouched")
+
+	verbose := GenerateFormattedIssueWithOptions(issues, code, Options{Template: tmpl, VerboseNotes: true})
+	assert.Equal(t, 3, strings.Count(verbose, long))
+}
+
+func TestFormatIssuesWidth(t *testing.T) {
+	t.Parallel()
+	code := &internal.SourceCode{
+		Lines: []string{
+			"package main",
+			"",
+			"func main() {",
+			"    x := 1",
+			"}",
+		},
+	}
+	issues := []tt.Issue{{
+		Rule:     "unused-variable",
+		Filename: "test.go",
+		Start:    token.Position{Line: 4, Column: 5},
+		End:      token.Position{Line: 4, Column: 6},
+		Message:  "x is declared but never used anywhere in the function",
+		Note:     "remove the declaration, or use the blank identifier when the value is needed for its side effects",
+	}}
+
+	expected := `error: unused-variable
+ --> test.go:4:5
+  |
+4 | x := 1
+  | ^^
+  |
+  = x is declared but never used
+    anywhere in the function
+  = note: remove the declaration, or
+          use the blank identifier
+          when the value is needed for
+          its side effects
+
+`
+	assert.Equal(t, expected, GenerateFormattedIssueWithOptions(issues, code, Options{Width: 38}))
+	assert.NotContains(t, GenerateFormattedIssueWithOptions(issues, code, Options{}), "\n    anywhere")
+
+	// custom templates are not wrapped.
+	compact, err := LoadTemplate("compact")
+	require.NoError(t, err)
+	assert.Contains(t, GenerateFormattedIssueWithOptions(issues, code, Options{Template: compact, Width: 38}), issues[0].Message)
+}
+
+func TestWrapText(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		text     string
+		width    int
+		expected string
+	}{
+		{"short message", 40, "short message"},
+		{"wrap this message between its words", 24, "wrap this message\n    between its words"},
+		{"a verylongwordthatdoesnotfitonasingleline here", 24, "a\n    verylongwordthatdoesnotfitonasingleline\n    here"},
+		{"first paragraph\nsecond one", 40, "first paragraph\n    second one"},
+		{"漢字漢字漢字 漢字漢字漢字", 24, "漢字漢字漢字\n    漢字漢字漢字"},
+		{"too narrow to be wrapped at all", 10, "too narrow to be wrapped at all"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, wrapText(tc.text, tc.width, 4), tc.text)
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "issue.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{.Rule}} {{style \"file\" .Filename}}\n"), 0o644))
+	tmpl, err := LoadTemplate(path)
+	require.NoError(t, err)
+	assert.Equal(t, "empty-if test.go\n", GenerateFormattedIssueWithOptions([]tt.Issue{{Rule: "empty-if", Filename: "test.go"}}, &internal.SourceCode{}, Options{Template: tmpl}))
+
+	invalid := filepath.Join(dir, "invalid.tmpl")
+	require.NoError(t, os.WriteFile(invalid, []byte("{{unknown .Rule}}"), 0o644))
+	_, err = LoadTemplate(invalid)
+	assert.Error(t, err)
+
+	_, err = LoadTemplate(filepath.Join(dir, "missing.tmpl"))
+	assert.Error(t, err)
+}
+
+func TestApplyTheme(t *testing.T) {
+	// not parallel: themes change the styles of every test.
+	t.Cleanup(func() { _ = ApplyTheme(nil) })
+
+	require.NoError(t, ApplyTheme(Theme{"error": "magenta, underline"}))
+	assert.True(t, errorStyle.Equals(color.New(color.FgMagenta, color.Underline)))
+	assert.True(t, warningStyle.Equals(color.New(color.FgHiYellow, color.Bold)))
+
+	mono, ok := BuiltinTheme("mono")
+	require.True(t, ok)
+	require.NoError(t, ApplyTheme(mono))
+	assert.True(t, errorStyle.Equals(color.New(color.Bold)))
+
+	assert.Error(t, ApplyTheme(Theme{"error": "purple"}))
+	assert.Error(t, ApplyTheme(Theme{"title": "red"}))
+}
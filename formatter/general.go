@@ -4,12 +4,13 @@ type GeneralIssueFormatter struct{}
 
 func (f *GeneralIssueFormatter) IssueTemplate() string {
 	return `{{header .Rule .Severity .MaxLineNumWidth .Filename .StartLine .StartColumn -}}
-{{snippet .SnippetLines .StartLine .EndLine .MaxLineNumWidth .CommonIndent .Padding -}}
-{{underlineAndMessage .Message .Padding .StartLine .EndLine .StartColumn .EndColumn .SnippetLines .CommonIndent .Note}}
+{{snippet .SnippetLines .StartLine .EndLine .MaxLineNumWidth .CommonIndent .Padding .Context -}}
+{{underlineAndMessage .Message .Padding .StartLine .EndLine .StartColumn .EndColumn .SnippetLines .CommonIndent .Note .Context .MaxLineNumWidth}}
 
 {{- if .Note }}
 {{note .Note .Padding .Suggestion}}
 {{- end }}
+{{- if .RelatedLocations }}{{related .RelatedLocations .Padding .Note .Suggestion}}{{ end }}
 
 {{- if .Suggestion }}
 {{suggestion .Suggestion .Padding .MaxLineNumWidth .StartLine}}
@@ -4,8 +4,8 @@ type SliceBoundsCheckFormatter struct{}
 
 func (f *SliceBoundsCheckFormatter) IssueTemplate() string {
 	return `{{header .Rule .Severity .MaxLineNumWidth .Filename .StartLine .StartColumn -}}
-{{snippet .SnippetLines .StartLine .EndLine .MaxLineNumWidth .CommonIndent .Padding -}}
-{{underlineAndMessage .Message .Padding .StartLine .EndLine .StartColumn .EndColumn .SnippetLines .CommonIndent .Note}}
+{{snippet .SnippetLines .StartLine .EndLine .MaxLineNumWidth .CommonIndent .Padding .Context -}}
+{{underlineAndMessage .Message .Padding .StartLine .EndLine .StartColumn .EndColumn .SnippetLines .CommonIndent .Note .Context .MaxLineNumWidth}}
 {{warning .Category -}}
 `
 }
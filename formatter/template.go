@@ -0,0 +1,55 @@
+package formatter
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// compactTemplate prints each issue on a single line.
+const compactTemplate = `{{style "file" (printf "%s:%d:%d" .Filename .StartLine .StartColumn)}}: {{severity .Severity}}: {{.Message}} {{style "rule" (printf "[%s]" .Rule)}}
+`
+
+// lineTemplate prints each issue on a single line without colors, in the
+// `file:line:col: message` form understood by editors such as vim (quickfix)
+// and emacs (compilation mode).
+const lineTemplate = `{{.Filename}}:{{.StartLine}}:{{.StartColumn}}: {{lower .Severity}} {{.Rule}}: {{.Message}}
+`
+
+var builtinTemplates = map[string]string{
+	"compact": compactTemplate,
+	"line":    lineTemplate,
+}
+
+// IsBuiltinTemplate reports whether name is the name of a built-in template.
+func IsBuiltinTemplate(name string) bool {
+	_, ok := builtinTemplates[name]
+	return ok
+}
+
+// customFormatter formats every issue with a user-supplied template.
+type customFormatter string
+
+func (f customFormatter) IssueTemplate() string {
+	return string(f)
+}
+
+// LoadTemplate returns the built-in issue template called name, or the
+// template read from the file name otherwise, after checking that it parses.
+//
+// Templates are executed with an IssueData and can use the functions of the
+// rule templates, as well as `style`, which renders a string with the style of
+// a theme element, and `severity`, which renders a severity.
+func LoadTemplate(name string) (string, error) {
+	if tmpl, ok := builtinTemplates[name]; ok {
+		return tmpl, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("reading issue template: %w", err)
+	}
+	if _, err := template.New("issue").Funcs(funcMap).Parse(string(data)); err != nil {
+		return "", fmt.Errorf("parsing issue template %s: %w", name, err)
+	}
+	return string(data), nil
+}
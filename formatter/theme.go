@@ -0,0 +1,134 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Theme maps the elements of the output (error, warning, rule, file, line,
+// message, suggestion and text) to comma-separated color attributes, e.g.
+// "magenta,bold". Elements left out keep their default style.
+type Theme map[string]string
+
+// builtinThemes are the themes selectable by name without a configuration.
+var builtinThemes = map[string]Theme{
+	"default": {},
+	"mono": {
+		"error":      "bold",
+		"warning":    "bold",
+		"rule":       "bold",
+		"file":       "bold",
+		"line":       "faint",
+		"message":    "bold",
+		"suggestion": "bold",
+		"text":       "reset",
+	},
+}
+
+// BuiltinTheme returns the built-in theme called name.
+func BuiltinTheme(name string) (Theme, bool) {
+	theme, ok := builtinThemes[name]
+	return theme, ok
+}
+
+var defaultStyles = map[string][]color.Attribute{
+	"error":      {color.FgRed, color.Bold},
+	"warning":    {color.FgHiYellow, color.Bold},
+	"rule":       {color.FgYellow, color.Bold},
+	"file":       {color.FgCyan, color.Bold},
+	"line":       {color.FgHiBlue, color.Bold},
+	"message":    {color.FgRed, color.Bold},
+	"suggestion": {color.FgGreen, color.Bold},
+	"text":       {color.FgWhite},
+}
+
+var colorAttributes = map[string]color.Attribute{
+	"reset":      color.Reset,
+	"bold":       color.Bold,
+	"faint":      color.Faint,
+	"italic":     color.Italic,
+	"underline":  color.Underline,
+	"black":      color.FgBlack,
+	"red":        color.FgRed,
+	"green":      color.FgGreen,
+	"yellow":     color.FgYellow,
+	"blue":       color.FgBlue,
+	"magenta":    color.FgMagenta,
+	"cyan":       color.FgCyan,
+	"white":      color.FgWhite,
+	"hi-black":   color.FgHiBlack,
+	"hi-red":     color.FgHiRed,
+	"hi-green":   color.FgHiGreen,
+	"hi-yellow":  color.FgHiYellow,
+	"hi-blue":    color.FgHiBlue,
+	"hi-magenta": color.FgHiMagenta,
+	"hi-cyan":    color.FgHiCyan,
+	"hi-white":   color.FgHiWhite,
+}
+
+// ApplyTheme sets the colors of the output. It is not safe to call while
+// issues are being formatted.
+func ApplyTheme(theme Theme) error {
+	styles := make(map[string]*color.Color, len(defaultStyles))
+	for element, attrs := range defaultStyles {
+		styles[element] = color.New(attrs...)
+	}
+	for element, spec := range theme {
+		if _, ok := defaultStyles[element]; !ok {
+			return fmt.Errorf("unknown theme element %q", element)
+		}
+		var attrs []color.Attribute
+		for _, name := range strings.Split(spec, ",") {
+			attr, ok := colorAttributes[strings.TrimSpace(name)]
+			if !ok {
+				return fmt.Errorf("unknown color %q for theme element %q", name, element)
+			}
+			attrs = append(attrs, attr)
+		}
+		styles[element] = color.New(attrs...)
+	}
+
+	errorStyle = styles["error"]
+	warningStyle = styles["warning"]
+	ruleStyle = styles["rule"]
+	fileStyle = styles["file"]
+	lineStyle = styles["line"]
+	messageStyle = styles["message"]
+	suggestionStyle = styles["suggestion"]
+	noStyle = styles["text"]
+	return nil
+}
+
+// style renders s with the style of a theme element, for custom templates.
+func style(element string, s string) string {
+	switch element {
+	case "error":
+		return errorStyle.Sprint(s)
+	case "warning":
+		return warningStyle.Sprint(s)
+	case "rule":
+		return ruleStyle.Sprint(s)
+	case "file":
+		return fileStyle.Sprint(s)
+	case "line":
+		return lineStyle.Sprint(s)
+	case "message":
+		return messageStyle.Sprint(s)
+	case "suggestion":
+		return suggestionStyle.Sprint(s)
+	}
+	return noStyle.Sprint(s)
+}
+
+// severityStyle renders a severity with the style header gives it.
+func severityStyle(severity string) string {
+	switch severity {
+	case "ERROR":
+		return errorStyle.Sprint("error")
+	case "WARNING":
+		return warningStyle.Sprint("warning")
+	}
+	return messageStyle.Sprint(strings.ToLower(severity))
+}
@@ -39,6 +39,7 @@ func (b *builder) Build(s []ast.Stmt) *CFG {
 		Entry:  b.entry,
 		Exit:   b.exit,
 		Defers: b.defers,
+		index:  newIndex(b.blocks),
 	}
 }
 
@@ -0,0 +1,120 @@
+package cfg
+
+import (
+	"go/ast"
+	"go/token"
+	"sync"
+)
+
+// Cache keeps the CFGs of the functions of files between two versions of
+// them, as the daemon and editors lint a file again after each edit. Only the
+// functions whose source changed are built again, the CFGs of the others are
+// carried over to the statements of the new version. It is safe for
+// concurrent use.
+type Cache struct {
+	mu    sync.Mutex
+	files map[string]map[string]cachedFunc // function sources by filename
+}
+
+type cachedFunc struct {
+	decl *ast.FuncDecl
+	cfg  *CFG
+}
+
+// NewCache returns an empty cache.
+func NewCache() *Cache {
+	return &Cache{files: make(map[string]map[string]cachedFunc)}
+}
+
+// Update returns the CFGs of the functions of file, parsed from src, and the
+// functions whose CFG was built rather than reused, in source order. The CFGs
+// of the previous version of filename given to Update are dropped.
+func (c *Cache) Update(filename string, src []byte, fset *token.FileSet, file *ast.File) (map[*ast.FuncDecl]*CFG, []*ast.FuncDecl) {
+	c.mu.Lock()
+	previous := c.files[filename]
+	c.mu.Unlock()
+
+	tf := fset.File(file.Pos())
+	cfgs := make(map[*ast.FuncDecl]*CFG)
+	current := make(map[string]cachedFunc)
+	var rebuilt []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		// functions are identified by their source, so that those moved
+		// by an edit elsewhere in the file are still found.
+		key := string(src[tf.Offset(fn.Pos()):tf.Offset(fn.End())])
+
+		var g *CFG
+		if cached, ok := previous[key]; ok {
+			g = cached.cfg.remap(cached.decl, fn)
+		}
+		if g == nil {
+			g = FromFunc(fn)
+			rebuilt = append(rebuilt, fn)
+		}
+		cfgs[fn] = g
+		current[key] = cachedFunc{decl: fn, cfg: g}
+	}
+
+	c.mu.Lock()
+	c.files[filename] = current
+	c.mu.Unlock()
+	return cfgs, rebuilt
+}
+
+// Forget drops the CFGs of filename, once it is closed or deleted.
+func (c *Cache) Forget(filename string) {
+	c.mu.Lock()
+	delete(c.files, filename)
+	c.mu.Unlock()
+}
+
+// remap returns a copy of c, built for the function from, over the
+// statements of to, whose source is the same. It returns nil if the
+// statements of the functions do not match.
+func (c *CFG) remap(from, to *ast.FuncDecl) *CFG {
+	old, stmts := funcStmts(from), funcStmts(to)
+	if len(old) != len(stmts) {
+		return nil
+	}
+	m := map[ast.Stmt]ast.Stmt{c.Entry: c.Entry, c.Exit: c.Exit}
+	for i, s := range old {
+		m[s] = stmts[i]
+	}
+	translate := func(list []ast.Stmt) []ast.Stmt {
+		out := make([]ast.Stmt, len(list))
+		for i, s := range list {
+			out[i] = m[s]
+		}
+		return out
+	}
+
+	g := &CFG{
+		Entry:  c.Entry,
+		Exit:   c.Exit,
+		blocks: make(map[ast.Stmt]*block, len(c.blocks)),
+		index:  translate(c.index),
+	}
+	for s, b := range c.blocks {
+		g.blocks[m[s]] = &block{stmt: m[s], preds: translate(b.preds), succs: translate(b.succs)}
+	}
+	for _, d := range c.Defers {
+		g.Defers = append(g.Defers, m[d].(*ast.DeferStmt))
+	}
+	return g
+}
+
+// funcStmts returns the statements of fn in depth-first order.
+func funcStmts(fn *ast.FuncDecl) []ast.Stmt {
+	var stmts []ast.Stmt
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if s, ok := n.(ast.Stmt); ok {
+			stmts = append(stmts, s)
+		}
+		return true
+	})
+	return stmts
+}
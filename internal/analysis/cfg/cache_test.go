@@ -0,0 +1,111 @@
+package cfg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheUpdate(t *testing.T) {
+	t.Parallel()
+	v1 := `package main
+
+func a(x int) int {
+	defer println("a")
+	for i := 0; i < x; i++ {
+		if i%2 == 0 {
+			continue
+		}
+		x--
+	}
+	return x
+}
+
+func b() {
+	println("b")
+}
+
+func c(s string) {
+	switch s {
+	case "x":
+		fallthrough
+	case "y":
+		println(s)
+	}
+}
+`
+	// b changes, and a and c move down.
+	v2 := strings.Replace(v1, "package main\n", "package main\n\nvar n int\n", 1)
+	v2 = strings.Replace(v2, `println("b")`, `println("b", n)`, 1)
+
+	cache := NewCache()
+	parse := func(src string) (*token.FileSet, *ast.File) {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "main.go", src, 0)
+		require.NoError(t, err)
+		return fset, file
+	}
+
+	fset, file := parse(v1)
+	cfgs, rebuilt := cache.Update("main.go", []byte(v1), fset, file)
+	assert.Len(t, cfgs, 3)
+	assert.Equal(t, []string{"a", "b", "c"}, funcNames(rebuilt))
+
+	fset, file = parse(v2)
+	cfgs, rebuilt = cache.Update("main.go", []byte(v2), fset, file)
+	assert.Len(t, cfgs, 3)
+	assert.Equal(t, []string{"b"}, funcNames(rebuilt))
+
+	for fn, g := range cfgs {
+		assert.Equal(t, edges(FromFunc(fn), fn), edges(g, fn), fn.Name.Name)
+		for _, s := range g.Blocks() {
+			if s != g.Entry && s != g.Exit {
+				assert.Same(t, s, g.BlockAt(s.Pos()), fn.Name.Name)
+			}
+		}
+	}
+	a := file.Decls[1].(*ast.FuncDecl)
+	require.Len(t, cfgs[a].Defers, 1)
+	assert.Same(t, a.Body.List[0], cfgs[a].Defers[0])
+
+	cache.Forget("main.go")
+	_, rebuilt = cache.Update("main.go", []byte(v2), fset, file)
+	assert.Len(t, rebuilt, 3)
+}
+
+func funcNames(fns []*ast.FuncDecl) []string {
+	var names []string
+	for _, fn := range fns {
+		names = append(names, fn.Name.Name)
+	}
+	return names
+}
+
+// edges returns the edges of g, with statements given by their offset from
+// the start of fn.
+func edges(g *CFG, fn *ast.FuncDecl) []string {
+	name := func(s ast.Stmt) string {
+		switch s {
+		case g.Entry:
+			return "ENTRY"
+		case g.Exit:
+			return "EXIT"
+		}
+		return fmt.Sprint(s.Pos() - fn.Pos())
+	}
+	var out []string
+	for _, from := range g.Blocks() {
+		for _, to := range g.Succs(from) {
+			out = append(out, name(from)+" -> "+name(to))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
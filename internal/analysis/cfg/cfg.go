@@ -31,6 +31,8 @@ type CFG struct {
 	blocks map[ast.Stmt]*block
 	// All defers found in CFG, disjoint from blocks. May be flowed to after Exit.
 	Defers []*ast.DeferStmt
+	// Statements of the blocks sorted by position, excluding Entry and Exit.
+	index []ast.Stmt
 }
 
 type block struct {
@@ -100,6 +102,36 @@ func (c *CFG) Blocks() []ast.Stmt {
 	return blocks
 }
 
+// BlockAt returns the innermost statement of the CFG whose source range
+// contains pos, or nil if there is none. Preds and Succs can then be queried
+// on the result.
+func (c *CFG) BlockAt(pos token.Pos) ast.Stmt {
+	// nested statements start after their parent, so the innermost one
+	// is the last statement starting before pos that still contains it.
+	i := sort.Search(len(c.index), func(i int) bool {
+		return c.index[i].Pos() > pos
+	})
+	for i--; i >= 0; i-- {
+		if s := c.index[i]; pos < s.End() {
+			return s
+		}
+	}
+	return nil
+}
+
+// newIndex returns the statements of blocks sorted by position, excluding
+// the Entry and Exit sentinels.
+func newIndex(blocks map[ast.Stmt]*block) []ast.Stmt {
+	index := make([]ast.Stmt, 0, len(blocks))
+	for s := range blocks {
+		if s.Pos() > token.NoPos {
+			index = append(index, s)
+		}
+	}
+	sort.Sort(stmtSlice(index))
+	return index
+}
+
 // type for sorting statements by their starting positions in the source code
 type stmtSlice []ast.Stmt
 
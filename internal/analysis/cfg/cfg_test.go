@@ -528,6 +528,35 @@ func TestIfNoElse(t *testing.T) {
 	c.expectPreds(t, END, 3)
 }
 
+func TestBlockAt(t *testing.T) {
+	t.Parallel()
+	c := getWrapper(t, `
+  package main
+
+  func foo(c int) {
+    //START
+    if c > 0 && true { // 1
+      println("here") // 2
+    }
+    print("there") // 3
+    //END
+  }
+  `)
+	at := func(s int, offset token.Pos) ast.Stmt {
+		return c.cfg.BlockAt(c.exp[s].Pos() + offset)
+	}
+
+	assert.Equal(t, c.exp[1], at(1, 0))
+	assert.Equal(t, c.exp[1], at(1, 4), "the condition belongs to the if statement")
+	assert.Equal(t, c.exp[2], at(2, 3), "the innermost statement is returned")
+	assert.Equal(t, c.exp[3], at(3, 0))
+	assert.Nil(t, c.cfg.BlockAt(c.exp[1].Pos()-1))
+	assert.Nil(t, c.cfg.BlockAt(c.exp[3].End()))
+
+	// the result can be used to query the neighbours of a line.
+	assert.ElementsMatch(t, []ast.Stmt{c.exp[1], c.exp[2]}, c.cfg.Preds(at(3, 0)))
+}
+
 func TestIfElseIf(t *testing.T) {
 	t.Parallel()
 	c := getWrapper(t, `
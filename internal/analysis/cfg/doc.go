@@ -25,4 +25,5 @@
 //  1. CFG Construction: Generate a CFG from AST (Abstract Syntax Tree) nodes.
 //  2. Use the `FromFunc` or `Build` methods to construct a CFG from the AST.
 //  3. Analyze the CFG using provided methods or traverse it from custom analysis.
+//  4. Keep the CFGs of a file across edits with a `Cache`, which only rebuilds the functions that changed.
 package cfg
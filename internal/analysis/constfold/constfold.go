@@ -0,0 +1,195 @@
+package constfold
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"github.com/gnolang/tlin/internal/analysis/lattice"
+)
+
+// maxShift bounds the shift counts folded, to keep results of a reasonable size.
+const maxShift = 512
+
+// Eval returns the constant value of expr, or an unknown value when expr is
+// not constant.
+//
+// info and env may be nil. Identifiers missing from info are looked up in
+// env, and are constant when their interval holds a single value.
+func Eval(expr ast.Expr, info *types.Info, env lattice.Env[lattice.Interval]) constant.Value {
+	return eval(expr, info, func(name string) constant.Value {
+		if iv, ok := env[name]; ok && iv.IsConst() {
+			return constant.MakeInt64(iv.Lo)
+		}
+		return constant.MakeUnknown()
+	})
+}
+
+// EvalValues is like Eval, with an environment of integers and booleans:
+// identifiers are also constant when their boolean holds a single value.
+func EvalValues(expr ast.Expr, info *types.Info, env lattice.Env[lattice.Value]) constant.Value {
+	return eval(expr, info, func(name string) constant.Value {
+		v, ok := env[name]
+		if !ok {
+			return constant.MakeUnknown()
+		}
+		if b, ok := v.Bool.Value(); ok {
+			return constant.MakeBool(b)
+		}
+		if v.Int.IsConst() {
+			return constant.MakeInt64(v.Int.Lo)
+		}
+		return constant.MakeUnknown()
+	})
+}
+
+func eval(expr ast.Expr, info *types.Info, lookup func(name string) constant.Value) constant.Value {
+	if info != nil {
+		if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+			return tv.Value
+		}
+	}
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(e.Value, e.Kind, 0)
+	case *ast.ParenExpr:
+		return eval(e.X, info, lookup)
+	case *ast.Ident:
+		return evalIdent(e, info, lookup)
+	case *ast.UnaryExpr:
+		return unaryOp(e.Op, eval(e.X, info, lookup))
+	case *ast.BinaryExpr:
+		return BinaryOp(eval(e.X, info, lookup), e.Op, eval(e.Y, info, lookup))
+	}
+	return constant.MakeUnknown()
+}
+
+func evalIdent(id *ast.Ident, info *types.Info, lookup func(name string) constant.Value) constant.Value {
+	if info != nil {
+		if c, ok := info.Uses[id].(*types.Const); ok {
+			return c.Val()
+		}
+	}
+	if info == nil {
+		switch id.Name {
+		case "true":
+			return constant.MakeBool(true)
+		case "false":
+			return constant.MakeBool(false)
+		}
+	}
+	return lookup(id.Name)
+}
+
+func unaryOp(op token.Token, x constant.Value) constant.Value {
+	switch {
+	case op == token.NOT && x.Kind() == constant.Bool,
+		(op == token.ADD || op == token.SUB) && isNumeric(x),
+		op == token.XOR && x.Kind() == constant.Int:
+		return constant.UnaryOp(op, x, 0)
+	}
+	return constant.MakeUnknown()
+}
+
+// BinaryOp folds x op y, returning an unknown value when an operand is
+// unknown or when the operation is not valid for them.
+func BinaryOp(x constant.Value, op token.Token, y constant.Value) constant.Value {
+	unknown := constant.MakeUnknown()
+	if !IsKnown(x) || !IsKnown(y) {
+		return unknown
+	}
+
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if !isComparable(x, op, y) {
+			return unknown
+		}
+		return constant.MakeBool(constant.Compare(x, op, y))
+
+	case token.LAND, token.LOR:
+		if x.Kind() != constant.Bool || y.Kind() != constant.Bool {
+			return unknown
+		}
+		return constant.BinaryOp(x, op, y)
+
+	case token.SHL, token.SHR:
+		n, ok := constant.Uint64Val(y)
+		if x.Kind() != constant.Int || y.Kind() != constant.Int || !ok || n > maxShift {
+			return unknown
+		}
+		return constant.Shift(x, op, uint(n))
+
+	case token.ADD:
+		if x.Kind() == constant.String && y.Kind() == constant.String {
+			return constant.BinaryOp(x, op, y)
+		}
+		fallthrough
+	case token.SUB, token.MUL:
+		if !isNumeric(x) || !isNumeric(y) {
+			return unknown
+		}
+		return constant.BinaryOp(x, op, y)
+
+	case token.QUO:
+		if !isNumeric(x) || !isNumeric(y) || IsZero(y) {
+			return unknown
+		}
+		if x.Kind() == constant.Int && y.Kind() == constant.Int {
+			op = token.QUO_ASSIGN // integer division
+		}
+		return constant.BinaryOp(x, op, y)
+
+	case token.REM, token.AND, token.OR, token.XOR, token.AND_NOT:
+		if x.Kind() != constant.Int || y.Kind() != constant.Int || (op == token.REM && IsZero(y)) {
+			return unknown
+		}
+		return constant.BinaryOp(x, op, y)
+	}
+	return unknown
+}
+
+// isComparable reports whether x op y is a valid comparison: complex numbers
+// and booleans only support == and !=.
+func isComparable(x constant.Value, op token.Token, y constant.Value) bool {
+	equality := op == token.EQL || op == token.NEQ
+	switch {
+	case isNumeric(x) && isNumeric(y):
+		return equality || (x.Kind() != constant.Complex && y.Kind() != constant.Complex)
+	case x.Kind() == constant.String && y.Kind() == constant.String:
+		return true
+	case x.Kind() == constant.Bool && y.Kind() == constant.Bool:
+		return equality
+	}
+	return false
+}
+
+// IsKnown reports whether v is a constant value.
+func IsKnown(v constant.Value) bool {
+	return v != nil && v.Kind() != constant.Unknown
+}
+
+// IsZero reports whether v is a numeric constant equal to zero.
+func IsZero(v constant.Value) bool {
+	return isNumeric(v) && constant.Sign(v) == 0
+}
+
+// Bool returns the value of a boolean constant. ok is false when v is not one.
+func Bool(v constant.Value) (value, ok bool) {
+	if v == nil || v.Kind() != constant.Bool {
+		return false, false
+	}
+	return constant.BoolVal(v), true
+}
+
+func isNumeric(v constant.Value) bool {
+	if v == nil {
+		return false
+	}
+	switch v.Kind() {
+	case constant.Int, constant.Float, constant.Complex:
+		return true
+	}
+	return false
+}
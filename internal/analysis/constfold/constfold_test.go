@@ -0,0 +1,146 @@
+package constfold
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/gnolang/tlin/internal/analysis/lattice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+	env := lattice.Env[lattice.Interval]{
+		"n":    lattice.Const(4),
+		"size": lattice.Range(0, 10),
+	}
+
+	tests := []struct {
+		expr     string
+		expected string // ExactString of the value, "" for unknown
+	}{
+		{"1 + 2*3", "7"},
+		{"7 / 2", "3"},
+		{"7.0 / 2", "7/2"},
+		{"7 % 3", "1"},
+		{"1 << 10", "1024"},
+		{"-(2 - 5)", "3"},
+		{`"tl" + "in"`, `"tlin"`},
+		{"3 > 2 && !false", "true"},
+		{"n * 2", "8"},
+		{"(n - 4) == 0", "true"},
+		{"size + 1", ""},
+		{"x + 1", ""},
+		{"1 / 0", ""},
+		{"n % (n - 4)", ""},
+		{`"a" + 1`, ""},
+		{"true < false", ""},
+		{"1 << 100000", ""},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+			expr, err := parser.ParseExpr(tc.expr)
+			require.NoError(t, err)
+
+			v := Eval(expr, nil, env)
+			if tc.expected == "" {
+				assert.False(t, IsKnown(v), "got %s", v)
+				return
+			}
+			require.True(t, IsKnown(v))
+			assert.Equal(t, tc.expected, v.ExactString())
+		})
+	}
+}
+
+func TestEvalValues(t *testing.T) {
+	t.Parallel()
+	env := lattice.Env[lattice.Value]{
+		"done":  lattice.BoolValue(false),
+		"n":     lattice.IntValue(lattice.Const(3)),
+		"ready": lattice.ValueDomain{}.Top(),
+	}
+
+	tests := []struct {
+		expr     string
+		expected string // ExactString of the value, "" for unknown
+	}{
+		{"done", "false"},
+		{"!done && n > 2", "true"},
+		{"n + 1", "4"},
+		{"done || ready", ""},
+		{"ready", ""},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+			expr, err := parser.ParseExpr(tc.expr)
+			require.NoError(t, err)
+
+			v := EvalValues(expr, nil, env)
+			if tc.expected == "" {
+				assert.False(t, IsKnown(v), "got %s", v)
+				return
+			}
+			require.True(t, IsKnown(v))
+			assert.Equal(t, tc.expected, v.ExactString())
+		})
+	}
+}
+
+func TestEvalWithTypeInfo(t *testing.T) {
+	t.Parallel()
+	src := `package p
+
+const limit = 1 << 4
+
+var count int
+
+var a = limit - 1
+var b = count - limit
+var c = limit == 16
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	_, err = (&types.Config{Importer: importer.Default()}).Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	values := make(map[string]constant.Value)
+	for _, decl := range file.Decls {
+		spec := decl.(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+		if len(spec.Values) > 0 {
+			values[spec.Names[0].Name] = Eval(spec.Values[0], info, nil)
+		}
+	}
+
+	assert.Equal(t, "15", values["a"].ExactString())
+	assert.False(t, IsKnown(values["b"]))
+	ok, known := Bool(values["c"])
+	assert.True(t, known)
+	assert.True(t, ok)
+}
+
+func TestIsZero(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsZero(constant.MakeInt64(0)))
+	assert.True(t, IsZero(constant.MakeFloat64(0)))
+	assert.False(t, IsZero(constant.MakeInt64(3)))
+	assert.False(t, IsZero(constant.MakeString("")))
+	assert.False(t, IsZero(constant.MakeUnknown()))
+}
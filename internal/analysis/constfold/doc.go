@@ -0,0 +1,12 @@
+// Package constfold evaluates expressions to constant values, so that rules
+// reasoning about constants (zero divisors, always-true conditions, switch
+// cases) share the same semantics.
+//
+// Values come, in order, from the constants recorded by the type checker,
+// from literals and from the integer intervals, or the booleans with
+// EvalValues, of a lattice environment that hold a single value. Operators
+// are then folded with go/constant, following the rules of Go: integer
+// operands use integer division, and operations that would fail at compile
+// time, such as a division by zero or mismatched operand kinds, yield an
+// unknown value instead of panicking.
+package constfold
@@ -0,0 +1,130 @@
+package lattice
+
+// Bool is an abstract boolean: no value, true, false, or either of them.
+type Bool uint8
+
+const (
+	BoolBottom Bool = iota
+	BoolTrue
+	BoolFalse
+	BoolTop
+)
+
+// BoolConst returns the abstract boolean holding only b.
+func BoolConst(b bool) Bool {
+	if b {
+		return BoolTrue
+	}
+	return BoolFalse
+}
+
+// Value returns the only possible value of b. ok is false when b is
+// bottom or top.
+func (b Bool) Value() (value, ok bool) {
+	switch b {
+	case BoolTrue:
+		return true, true
+	case BoolFalse:
+		return false, true
+	}
+	return false, false
+}
+
+func (b Bool) String() string {
+	switch b {
+	case BoolBottom:
+		return "⊥"
+	case BoolTrue:
+		return "true"
+	case BoolFalse:
+		return "false"
+	}
+	return "⊤"
+}
+
+// BoolDomain implements Domain for Bool values. Its chains are short, so
+// widening is a plain join.
+type BoolDomain struct{}
+
+func (BoolDomain) Bottom() Bool { return BoolBottom }
+func (BoolDomain) Top() Bool    { return BoolTop }
+
+func (BoolDomain) Join(a, b Bool) Bool {
+	switch {
+	case a == BoolBottom:
+		return b
+	case b == BoolBottom, a == b:
+		return a
+	}
+	return BoolTop
+}
+
+func (BoolDomain) Meet(a, b Bool) Bool {
+	switch {
+	case a == BoolTop:
+		return b
+	case b == BoolTop, a == b:
+		return a
+	}
+	return BoolBottom
+}
+
+func (d BoolDomain) Widen(prev, next Bool) Bool { return d.Join(prev, next) }
+
+func (BoolDomain) Equal(a, b Bool) bool { return a == b }
+
+// Value is the abstract value of a variable that holds an integer or a
+// boolean, the product of the Interval and Bool domains. The component that
+// does not apply to the variable is left to Top.
+type Value struct {
+	Int  Interval
+	Bool Bool
+}
+
+// IntValue returns the value of an integer in i.
+func IntValue(i Interval) Value {
+	return Value{Int: i, Bool: BoolTop}
+}
+
+// BoolValue returns the value of a boolean b.
+func BoolValue(b bool) Value {
+	return Value{Int: IntervalDomain{}.Top(), Bool: BoolConst(b)}
+}
+
+func (v Value) String() string {
+	switch {
+	case v.Bool == BoolTop:
+		return v.Int.String()
+	case v.Int.IsTop():
+		return v.Bool.String()
+	}
+	return "(" + v.Int.String() + ", " + v.Bool.String() + ")"
+}
+
+// ValueDomain implements Domain for Value, applying the Interval and Bool
+// domains to each component.
+type ValueDomain struct{}
+
+func (ValueDomain) Bottom() Value {
+	return Value{Int: IntervalDomain{}.Bottom(), Bool: BoolBottom}
+}
+
+func (ValueDomain) Top() Value {
+	return Value{Int: IntervalDomain{}.Top(), Bool: BoolTop}
+}
+
+func (ValueDomain) Join(a, b Value) Value {
+	return Value{Int: IntervalDomain{}.Join(a.Int, b.Int), Bool: BoolDomain{}.Join(a.Bool, b.Bool)}
+}
+
+func (ValueDomain) Meet(a, b Value) Value {
+	return Value{Int: IntervalDomain{}.Meet(a.Int, b.Int), Bool: BoolDomain{}.Meet(a.Bool, b.Bool)}
+}
+
+func (ValueDomain) Widen(prev, next Value) Value {
+	return Value{Int: IntervalDomain{}.Widen(prev.Int, next.Int), Bool: BoolDomain{}.Widen(prev.Bool, next.Bool)}
+}
+
+func (ValueDomain) Equal(a, b Value) bool {
+	return IntervalDomain{}.Equal(a.Int, b.Int) && a.Bool == b.Bool
+}
@@ -0,0 +1,18 @@
+// Package lattice provides abstract domains and a forward dataflow solver
+// for the control flow graphs built by the cfg package.
+//
+// Analyses are parameterized by a Domain, which supplies the lattice
+// operations (join, meet and widening). The solver applies widening at
+// blocks that are visited repeatedly, so loop-heavy code converges while
+// straight-line code keeps the precision of the underlying domain.
+// ForwardBranches also narrows the state along the edges of branches, such
+// as the body of a loop, where its condition holds.
+//
+// The Interval domain tracks the range of values an integer expression may
+// take. It is precise for constants and degrades gracefully to Top when
+// the analysis cannot bound a value.
+//
+// The Bool domain tracks whether a boolean is known to be true or false, and
+// Value combines it with the Interval domain for environments holding both
+// integers and booleans.
+package lattice
@@ -0,0 +1,113 @@
+package lattice
+
+// Domain defines the operations of an abstract domain over values of type T.
+type Domain[T any] interface {
+	// Bottom returns the least element, representing no possible value.
+	Bottom() T
+	// Top returns the greatest element, representing any possible value.
+	Top() T
+	// Join returns the least upper bound of a and b.
+	Join(a, b T) T
+	// Meet returns the greatest lower bound of a and b.
+	Meet(a, b T) T
+	// Widen extrapolates from prev to next so that ascending chains terminate.
+	Widen(prev, next T) T
+	// Equal reports whether a and b describe the same set of values.
+	Equal(a, b T) bool
+}
+
+// Env maps variable names to abstract values.
+// A missing entry means the variable is unconstrained (Top).
+type Env[T any] map[string]T
+
+// EnvDomain lifts a value domain to environments, applying every
+// operation pointwise.
+type EnvDomain[T any] struct {
+	Values Domain[T]
+}
+
+func (d EnvDomain[T]) Bottom() Env[T] {
+	return nil
+}
+
+func (d EnvDomain[T]) Top() Env[T] {
+	return Env[T]{}
+}
+
+// Join keeps only the variables known in both environments,
+// since a missing entry already means Top.
+func (d EnvDomain[T]) Join(a, b Env[T]) Env[T] {
+	if a == nil {
+		return b.clone()
+	}
+	if b == nil {
+		return a.clone()
+	}
+	out := make(Env[T], len(a))
+	for name, av := range a {
+		if bv, ok := b[name]; ok {
+			out[name] = d.Values.Join(av, bv)
+		}
+	}
+	return out
+}
+
+func (d EnvDomain[T]) Meet(a, b Env[T]) Env[T] {
+	if a == nil || b == nil {
+		return nil
+	}
+	out := a.clone()
+	for name, bv := range b {
+		if av, ok := out[name]; ok {
+			out[name] = d.Values.Meet(av, bv)
+		} else {
+			out[name] = bv
+		}
+	}
+	return out
+}
+
+func (d EnvDomain[T]) Widen(prev, next Env[T]) Env[T] {
+	if prev == nil {
+		return next.clone()
+	}
+	if next == nil {
+		return prev.clone()
+	}
+	out := make(Env[T], len(prev))
+	for name, pv := range prev {
+		if nv, ok := next[name]; ok {
+			out[name] = d.Values.Widen(pv, nv)
+		}
+	}
+	return out
+}
+
+func (d EnvDomain[T]) Equal(a, b Env[T]) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for name, av := range a {
+		bv, ok := b[name]
+		if !ok || !d.Values.Equal(av, bv) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e Env[T]) clone() Env[T] {
+	if e == nil {
+		return nil
+	}
+	out := make(Env[T], len(e))
+	for k, v := range e {
+		out[k] = v
+	}
+	return out
+}
+
+// Clone returns a shallow copy of the environment.
+func (e Env[T]) Clone() Env[T] {
+	return e.clone()
+}
@@ -0,0 +1,209 @@
+package lattice
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// NegInf and PosInf are used as unbounded interval ends.
+	NegInf int64 = math.MinInt64
+	PosInf int64 = math.MaxInt64
+)
+
+// Interval is the closed integer range [Lo, Hi].
+// An empty interval is the bottom element of the domain.
+type Interval struct {
+	Lo, Hi int64
+	empty  bool
+}
+
+// Const returns the interval containing only n.
+func Const(n int64) Interval {
+	return Interval{Lo: n, Hi: n}
+}
+
+// Range returns the interval [lo, hi], or an empty interval if lo > hi.
+func Range(lo, hi int64) Interval {
+	if lo > hi {
+		return Interval{empty: true}
+	}
+	return Interval{Lo: lo, Hi: hi}
+}
+
+// IsBottom reports whether the interval contains no value.
+func (i Interval) IsBottom() bool { return i.empty }
+
+// IsTop reports whether the interval is unbounded on both ends.
+func (i Interval) IsTop() bool { return !i.empty && i.Lo == NegInf && i.Hi == PosInf }
+
+// IsConst reports whether the interval holds exactly one value.
+func (i Interval) IsConst() bool { return !i.empty && i.Lo == i.Hi }
+
+// Contains reports whether n is a possible value.
+func (i Interval) Contains(n int64) bool {
+	return !i.empty && i.Lo <= n && n <= i.Hi
+}
+
+// MayBeZero reports whether zero is a possible value.
+func (i Interval) MayBeZero() bool { return i.Contains(0) }
+
+// IsZero reports whether zero is the only possible value.
+func (i Interval) IsZero() bool { return i.IsConst() && i.Lo == 0 }
+
+func (i Interval) String() string {
+	if i.empty {
+		return "⊥"
+	}
+	return fmt.Sprintf("[%s, %s]", boundString(i.Lo), boundString(i.Hi))
+}
+
+func boundString(b int64) string {
+	switch b {
+	case NegInf:
+		return "-inf"
+	case PosInf:
+		return "+inf"
+	}
+	return fmt.Sprint(b)
+}
+
+// Add returns the interval of all possible sums.
+func (i Interval) Add(o Interval) Interval {
+	if i.empty || o.empty {
+		return Interval{empty: true}
+	}
+	hi := PosInf
+	if i.Hi != PosInf && o.Hi != PosInf {
+		hi = satAdd(i.Hi, o.Hi)
+	}
+	return Interval{Lo: satAdd(i.Lo, o.Lo), Hi: hi}
+}
+
+// Sub returns the interval of all possible differences.
+func (i Interval) Sub(o Interval) Interval {
+	return i.Add(o.Neg())
+}
+
+// Neg returns the interval of negated values.
+func (i Interval) Neg() Interval {
+	if i.empty {
+		return i
+	}
+	return Interval{Lo: satNeg(i.Hi), Hi: satNeg(i.Lo)}
+}
+
+// Mul returns the interval of all possible products.
+func (i Interval) Mul(o Interval) Interval {
+	if i.empty || o.empty {
+		return Interval{empty: true}
+	}
+	products := [4]int64{
+		satMul(i.Lo, o.Lo), satMul(i.Lo, o.Hi),
+		satMul(i.Hi, o.Lo), satMul(i.Hi, o.Hi),
+	}
+	out := Interval{Lo: products[0], Hi: products[0]}
+	for _, p := range products[1:] {
+		out.Lo = min(out.Lo, p)
+		out.Hi = max(out.Hi, p)
+	}
+	return out
+}
+
+// IntervalDomain implements Domain for Interval values.
+type IntervalDomain struct{}
+
+func (IntervalDomain) Bottom() Interval { return Interval{empty: true} }
+func (IntervalDomain) Top() Interval    { return Interval{Lo: NegInf, Hi: PosInf} }
+
+func (IntervalDomain) Join(a, b Interval) Interval {
+	if a.empty {
+		return b
+	}
+	if b.empty {
+		return a
+	}
+	return Interval{Lo: min(a.Lo, b.Lo), Hi: max(a.Hi, b.Hi)}
+}
+
+func (IntervalDomain) Meet(a, b Interval) Interval {
+	if a.empty || b.empty {
+		return Interval{empty: true}
+	}
+	return Range(max(a.Lo, b.Lo), min(a.Hi, b.Hi))
+}
+
+// Widen pushes every bound that is still moving to infinity.
+func (IntervalDomain) Widen(prev, next Interval) Interval {
+	if prev.empty {
+		return next
+	}
+	if next.empty {
+		return prev
+	}
+	out := prev
+	if next.Lo < prev.Lo {
+		out.Lo = NegInf
+	}
+	if next.Hi > prev.Hi {
+		out.Hi = PosInf
+	}
+	return out
+}
+
+func (IntervalDomain) Equal(a, b Interval) bool {
+	if a.empty || b.empty {
+		return a.empty == b.empty
+	}
+	return a.Lo == b.Lo && a.Hi == b.Hi
+}
+
+// saturating arithmetic keeps infinite bounds infinite.
+
+func satAdd(a, b int64) int64 {
+	switch {
+	case a == NegInf || b == NegInf:
+		return NegInf
+	case a == PosInf || b == PosInf:
+		return PosInf
+	}
+	s := a + b
+	if a > 0 && b > 0 && s < 0 {
+		return PosInf
+	}
+	if a < 0 && b < 0 && s >= 0 {
+		return NegInf
+	}
+	return s
+}
+
+func satNeg(a int64) int64 {
+	switch a {
+	case NegInf:
+		return PosInf
+	case PosInf:
+		return NegInf
+	}
+	return -a
+}
+
+func satMul(a, b int64) int64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	neg := (a < 0) != (b < 0)
+	if a == NegInf || a == PosInf || b == NegInf || b == PosInf {
+		if neg {
+			return NegInf
+		}
+		return PosInf
+	}
+	p := a * b
+	if p/b != a {
+		if neg {
+			return NegInf
+		}
+		return PosInf
+	}
+	return p
+}
@@ -0,0 +1,193 @@
+package lattice
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntervalDomain(t *testing.T) {
+	t.Parallel()
+	d := IntervalDomain{}
+
+	tests := []struct {
+		name     string
+		got      Interval
+		expected Interval
+	}{
+		{"join constants", d.Join(Const(1), Const(5)), Range(1, 5)},
+		{"join with bottom", d.Join(d.Bottom(), Const(3)), Const(3)},
+		{"meet overlapping", d.Meet(Range(0, 10), Range(5, 20)), Range(5, 10)},
+		{"meet disjoint", d.Meet(Range(0, 1), Range(5, 6)), d.Bottom()},
+		{"widen growing upper bound", d.Widen(Range(0, 1), Range(0, 2)), Range(0, PosInf)},
+		{"widen growing lower bound", d.Widen(Range(0, 1), Range(-1, 1)), Range(NegInf, 1)},
+		{"widen stable", d.Widen(Range(0, 1), Range(0, 1)), Range(0, 1)},
+		{"add", Range(1, 2).Add(Range(10, 20)), Range(11, 22)},
+		{"add saturates", Range(0, PosInf).Add(Const(1)), Range(1, PosInf)},
+		{"sub", Const(5).Sub(Range(1, 2)), Range(3, 4)},
+		{"mul mixed signs", Range(-2, 3).Mul(Range(4, 5)), Range(-10, 15)},
+		{"mul by unbounded", Range(1, 2).Mul(Range(1, PosInf)), Range(1, PosInf)},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.True(t, d.Equal(tc.expected, tc.got), "expected %s, got %s", tc.expected, tc.got)
+		})
+	}
+}
+
+func TestBoolDomain(t *testing.T) {
+	t.Parallel()
+	d := BoolDomain{}
+	assert.Equal(t, BoolTrue, d.Join(d.Bottom(), BoolTrue))
+	assert.Equal(t, BoolTop, d.Join(BoolTrue, BoolFalse))
+	assert.Equal(t, BoolFalse, d.Meet(BoolTop, BoolFalse))
+	assert.Equal(t, BoolBottom, d.Meet(BoolTrue, BoolFalse))
+
+	v, ok := BoolConst(false).Value()
+	assert.True(t, ok)
+	assert.False(t, v)
+	_, ok = BoolTop.Value()
+	assert.False(t, ok)
+}
+
+func TestValueDomain(t *testing.T) {
+	t.Parallel()
+	d := ValueDomain{}
+	assert.True(t, d.Equal(BoolValue(true), d.Join(BoolValue(true), BoolValue(true))))
+	assert.True(t, d.Equal(d.Top(), d.Join(BoolValue(true), BoolValue(false))))
+	assert.True(t, d.Equal(IntValue(Range(1, PosInf)), d.Widen(IntValue(Const(1)), IntValue(Range(1, 2)))))
+	assert.Equal(t, "false", BoolValue(false).String())
+	assert.Equal(t, "[0, 5]", IntValue(Range(0, 5)).String())
+}
+
+func TestIntervalPredicates(t *testing.T) {
+	t.Parallel()
+	assert.True(t, Const(0).IsZero())
+	assert.True(t, Range(-1, 1).MayBeZero())
+	assert.False(t, Range(1, 10).MayBeZero())
+	assert.True(t, IntervalDomain{}.Top().IsTop())
+	assert.True(t, Range(2, 1).IsBottom())
+	assert.Equal(t, "[-inf, 3]", Range(NegInf, 3).String())
+}
+
+func TestForwardConvergesOnLoops(t *testing.T) {
+	t.Parallel()
+	src := `package main
+
+func f() {
+	i := 0
+	n := 10
+	for i < n {
+		i = i + 1
+	}
+	done := i
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, 0)
+	require.NoError(t, err)
+
+	fn := file.Decls[0].(*ast.FuncDecl)
+	g := cfg.FromFunc(fn)
+
+	d := EnvDomain[Interval]{Values: IntervalDomain{}}
+	states := Forward[Env[Interval]](g, d, d.Top(), func(stmt ast.Stmt, in Env[Interval]) Env[Interval] {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok {
+			return in
+		}
+		out := in.Clone()
+		for i, lhs := range assign.Lhs {
+			out[lhs.(*ast.Ident).Name] = evalInterval(assign.Rhs[i], in)
+		}
+		return out
+	})
+
+	var doneStmt ast.Stmt
+	for _, stmt := range fn.Body.List {
+		if assign, ok := stmt.(*ast.AssignStmt); ok && assign.Lhs[0].(*ast.Ident).Name == "done" {
+			doneStmt = stmt
+		}
+	}
+	require.NotNil(t, doneStmt)
+
+	state := states[doneStmt]
+	assert.Equal(t, Const(10), state["n"])
+	assert.Equal(t, Range(0, PosInf), state["i"])
+}
+
+func TestForwardBranchesRefinesLoopCondition(t *testing.T) {
+	t.Parallel()
+	src := `package main
+
+func f() {
+	i := 0
+	for i < 10 {
+		body := i
+		i = i + 1
+	}
+	done := i
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, 0)
+	require.NoError(t, err)
+
+	fn := file.Decls[0].(*ast.FuncDecl)
+	g := cfg.FromFunc(fn)
+	loop := fn.Body.List[1].(*ast.ForStmt)
+
+	d := EnvDomain[Interval]{Values: IntervalDomain{}}
+	states := ForwardBranches[Env[Interval]](g, d, d.Top(), func(stmt ast.Stmt, in Env[Interval]) Env[Interval] {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok {
+			return in
+		}
+		out := in.Clone()
+		out[assign.Lhs[0].(*ast.Ident).Name] = evalInterval(assign.Rhs[0], in)
+		return out
+	}, func(from, to ast.Stmt, out Env[Interval]) Env[Interval] {
+		if from != loop {
+			return out
+		}
+		bound := Range(NegInf, 9)
+		if to != loop.Body.List[0] {
+			bound = Range(10, PosInf)
+		}
+		refined := out.Clone()
+		refined["i"] = IntervalDomain{}.Meet(out["i"], bound)
+		return refined
+	})
+
+	assert.Equal(t, Range(0, 9), states[loop.Body.List[0]]["i"])
+	assert.Equal(t, Range(10, PosInf), states[fn.Body.List[2]]["i"])
+}
+
+func evalInterval(expr ast.Expr, env Env[Interval]) Interval {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		n, err := strconv.ParseInt(e.Value, 10, 64)
+		if err != nil {
+			return IntervalDomain{}.Top()
+		}
+		return Const(n)
+	case *ast.Ident:
+		if v, ok := env[e.Name]; ok {
+			return v
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return evalInterval(e.X, env).Add(evalInterval(e.Y, env))
+		}
+	}
+	return IntervalDomain{}.Top()
+}
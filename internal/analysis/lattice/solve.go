@@ -0,0 +1,87 @@
+package lattice
+
+import (
+	"go/ast"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+)
+
+// widenAfter is the number of times a block may be updated with plain joins
+// before the solver switches to widening for that block.
+const widenAfter = 2
+
+// Transfer computes the state after executing stmt from the state before it.
+type Transfer[T any] func(stmt ast.Stmt, in T) T
+
+// Branch refines the state flowing from a statement to one of its
+// successors, such as the state entering the body of an if statement, where
+// its condition holds.
+type Branch[T any] func(from, to ast.Stmt, out T) T
+
+// Forward runs a forward dataflow analysis over g with the given domain and
+// returns the state holding at the entry of every statement in the graph.
+//
+// The transfer function is never called for the Entry and Exit sentinels.
+func Forward[T any](g *cfg.CFG, d Domain[T], entry T, transfer Transfer[T]) map[ast.Stmt]T {
+	return ForwardBranches(g, d, entry, transfer, nil)
+}
+
+// ForwardBranches is like Forward, but passes the state flowing along every
+// edge of g through branch, when it is not nil. branch must only narrow the
+// state, so that the analysis still terminates.
+func ForwardBranches[T any](g *cfg.CFG, d Domain[T], entry T, transfer Transfer[T], branch Branch[T]) map[ast.Stmt]T {
+	in := map[ast.Stmt]T{g.Entry: entry}
+	visits := make(map[ast.Stmt]int)
+
+	out := func(s ast.Stmt) T {
+		state, ok := in[s]
+		if !ok {
+			return d.Bottom()
+		}
+		if s == g.Entry || s == g.Exit {
+			return state
+		}
+		return transfer(s, state)
+	}
+
+	worklist := []ast.Stmt{g.Entry}
+	queued := map[ast.Stmt]bool{g.Entry: true}
+
+	for len(worklist) > 0 {
+		s := worklist[0]
+		worklist = worklist[1:]
+		queued[s] = false
+
+		state := out(s)
+		for _, succ := range g.Succs(s) {
+			prev, seen := in[succ]
+			next := state
+			if branch != nil {
+				next = branch(s, succ, state)
+			}
+			if seen {
+				next = d.Join(prev, next)
+				if visits[succ] >= widenAfter {
+					next = d.Widen(prev, next)
+					// widening drops the bounds the branch put on the
+					// state, which are put back.
+					if branch != nil {
+						next = branch(s, succ, next)
+					}
+				}
+				if d.Equal(prev, next) {
+					continue
+				}
+			}
+
+			in[succ] = next
+			visits[succ]++
+			if !queued[succ] {
+				worklist = append(worklist, succ)
+				queued[succ] = true
+			}
+		}
+	}
+
+	return in
+}
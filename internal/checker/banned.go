@@ -0,0 +1,144 @@
+package checker
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// BannedCall describes a call matching a registered ban.
+type BannedCall struct {
+	Pattern  string
+	Package  string
+	Function string
+	Message  string
+	Start    token.Position
+	End      token.Position
+}
+
+type ban struct {
+	pattern string
+	message string
+}
+
+// BannedCallChecker finds calls to functions matching selector patterns
+// such as `os.Exit` or `unsafe.*`.
+type BannedCallChecker struct {
+	// banned maps package paths to function names (or "*") and their ban.
+	banned map[string]map[string]ban
+}
+
+// NewBannedCallChecker creates a new BannedCallChecker
+func NewBannedCallChecker() *BannedCallChecker {
+	return &BannedCallChecker{
+		banned: make(map[string]map[string]ban),
+	}
+}
+
+// Register bans the functions matching pattern.
+//
+// The pattern is an import path followed by a function name, or by `*` to
+// ban every function of the package, e.g. `gno.land/p/demo/ufmt.Println`.
+func (b *BannedCallChecker) Register(pattern, message string) error {
+	pkgPath, funcName, err := splitPattern(pattern)
+	if err != nil {
+		return err
+	}
+	if _, ok := b.banned[pkgPath]; !ok {
+		b.banned[pkgPath] = make(map[string]ban)
+	}
+	b.banned[pkgPath][funcName] = ban{pattern: pattern, message: message}
+	return nil
+}
+
+func splitPattern(pattern string) (pkgPath, funcName string, err error) {
+	idx := strings.LastIndex(pattern, ".")
+	if idx <= 0 || idx == len(pattern)-1 {
+		return "", "", fmt.Errorf("invalid banned call pattern %q: expected pkg.Func or pkg.*", pattern)
+	}
+	pkgPath, funcName = pattern[:idx], pattern[idx+1:]
+	if funcName != "*" && !token.IsIdentifier(funcName) {
+		return "", "", fmt.Errorf("invalid banned call pattern %q: %q is not an identifier", pattern, funcName)
+	}
+	return pkgPath, funcName, nil
+}
+
+// Empty reports whether no pattern has been registered.
+func (b *BannedCallChecker) Empty() bool {
+	return len(b.banned) == 0
+}
+
+// Check checks an AST node for banned calls
+func (b *BannedCallChecker) Check(node *ast.File, fset *token.FileSet) ([]BannedCall, error) {
+	aliases := make(map[string]string)
+	for _, imp := range node.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, fmt.Errorf("error unquoting import path: %w", err)
+		}
+		if _, ok := b.banned[path]; !ok {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		aliases[name] = path
+	}
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+
+	var found []BannedCall
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		var pkgPath, funcName string
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			ident, ok := fun.X.(*ast.Ident)
+			// a resolved identifier is a local variable shadowing the import.
+			if !ok || ident.Obj != nil {
+				return true
+			}
+			if pkgPath, ok = aliases[ident.Name]; !ok {
+				return true
+			}
+			funcName = fun.Sel.Name
+		case *ast.Ident:
+			if fun.Obj != nil {
+				return true
+			}
+			if pkgPath, ok = aliases["."]; !ok {
+				return true
+			}
+			funcName = fun.Name
+		default:
+			return true
+		}
+
+		funcs := b.banned[pkgPath]
+		match, ok := funcs[funcName]
+		if !ok {
+			if match, ok = funcs["*"]; !ok {
+				return true
+			}
+		}
+		found = append(found, BannedCall{
+			Pattern:  match.pattern,
+			Package:  pkgPath,
+			Function: funcName,
+			Message:  match.message,
+			Start:    fset.Position(call.Pos()),
+			End:      fset.Position(call.End()),
+		})
+		return true
+	})
+
+	return found, nil
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBannedCallChecker(t *testing.T) {
+	t.Parallel()
+	src := `
+package main
+
+import (
+	"os"
+	u "unsafe"
+	"gno.land/p/demo/ufmt"
+)
+
+func main() {
+	os.Exit(1)
+	os.Getenv("HOME")
+	_ = u.Sizeof(0)
+	ufmt.Println("hi")
+}
+
+func shadowed(os fakeOS) {
+	os.Exit(1)
+}
+`
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "example.go", src, 0)
+	require.NoError(t, err)
+
+	checker := NewBannedCallChecker()
+	require.NoError(t, checker.Register("os.Exit", "terminates the process"))
+	require.NoError(t, checker.Register("unsafe.*", "unsafe is not allowed"))
+	require.NoError(t, checker.Register("gno.land/p/demo/ufmt.Println", "use ufmt.Sprintf"))
+
+	found, err := checker.Check(node, fset)
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+
+	assert.Equal(t, "os.Exit", found[0].Pattern)
+	assert.Equal(t, 11, found[0].Start.Line)
+	assert.Equal(t, "unsafe.*", found[1].Pattern)
+	assert.Equal(t, "Sizeof", found[1].Function)
+	assert.Equal(t, "gno.land/p/demo/ufmt", found[2].Package)
+	assert.Equal(t, "use ufmt.Sprintf", found[2].Message)
+}
+
+func TestBannedCallCheckerInvalidPattern(t *testing.T) {
+	t.Parallel()
+	checker := NewBannedCallChecker()
+
+	for _, pattern := range []string{"Exit", ".Exit", "os.", "os.1Exit"} {
+		assert.Error(t, checker.Register(pattern, ""), pattern)
+	}
+	assert.True(t, checker.Empty())
+}
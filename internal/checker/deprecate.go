@@ -51,6 +51,11 @@ func (d *DeprecatedFuncChecker) Register(pkgName, funcName, alternative string)
 	d.deprecatedFuncs[pkgName][funcName] = alternative
 }
 
+// HasPackage reports whether a deprecated function of pkgPath is registered.
+func (d *DeprecatedFuncChecker) HasPackage(pkgPath string) bool {
+	return len(d.deprecatedFuncs[pkgPath]) > 0
+}
+
 // Check checks an AST node for deprecated functions
 func (d *DeprecatedFuncChecker) Check(filename string, node *ast.File, fset *token.FileSet) ([]DeprecatedFunc, error) {
 	packageAliases, err := d.getPackageAliases(node)
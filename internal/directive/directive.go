@@ -0,0 +1,105 @@
+// Package directive parses the comments that steer tlin: //nolint comments
+// and //tlin: annotations.
+package directive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// AnnotationPrefix is the name of the annotation comments, e.g. //tlin:pure.
+const AnnotationPrefix = "tlin"
+
+// Parse parses a comment of the form //<name> or //<name>:<args> and returns
+// its trimmed arguments. ok is false when text is another comment.
+func Parse(text, name string) (args string, ok bool, err error) {
+	rest, found := strings.CutPrefix(text, "//"+name)
+	if !found {
+		return "", false, nil
+	}
+	if rest == "" {
+		return "", true, nil
+	}
+	if rest[0] != ':' {
+		// a longer word, e.g. //nolintfoo.
+		return "", false, nil
+	}
+	args = strings.TrimSpace(rest[1:])
+	if args == "" {
+		return "", true, fmt.Errorf("invalid %s comment: no arguments after colon", name)
+	}
+	return args, true, nil
+}
+
+// Annotations holds the //tlin:<key>[=<value>] annotations of a file.
+// Annotations written before the package clause apply to the whole file,
+// those in the doc comment of a function apply to that function.
+type Annotations struct {
+	file  map[string]string
+	funcs map[*ast.FuncDecl]map[string]string
+}
+
+// ParseAnnotations collects the annotations of f.
+func ParseAnnotations(f *ast.File, fset *token.FileSet) *Annotations {
+	a := &Annotations{
+		file:  make(map[string]string),
+		funcs: make(map[*ast.FuncDecl]map[string]string),
+	}
+	packageLine := fset.Position(f.Package).Line
+	for _, cg := range f.Comments {
+		if fset.Position(cg.Pos()).Line < packageLine {
+			parseGroup(cg, a.file)
+		}
+	}
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc != nil {
+			values := make(map[string]string)
+			parseGroup(fn.Doc, values)
+			if len(values) > 0 {
+				a.funcs[fn] = values
+			}
+		}
+	}
+	return a
+}
+
+// parseGroup adds the annotations of cg to values. Malformed ones are ignored.
+func parseGroup(cg *ast.CommentGroup, values map[string]string) {
+	for _, c := range cg.List {
+		args, ok, err := Parse(c.Text, AnnotationPrefix)
+		if !ok || err != nil || args == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(args, "=")
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+}
+
+// Lookup returns the value of the annotation key of fn, or of the file when
+// fn is not annotated with key. fn may be nil.
+func (a *Annotations) Lookup(fn *ast.FuncDecl, key string) (string, bool) {
+	if value, ok := a.funcs[fn][key]; ok {
+		return value, true
+	}
+	value, ok := a.file[key]
+	return value, ok
+}
+
+// Has reports whether fn or the file is annotated with key.
+func (a *Annotations) Has(fn *ast.FuncDecl, key string) bool {
+	_, ok := a.Lookup(fn, key)
+	return ok
+}
+
+// Funcs returns the functions annotated with key, ignoring file annotations.
+func (a *Annotations) Funcs(key string) []*ast.FuncDecl {
+	var funcs []*ast.FuncDecl
+	for fn, values := range a.funcs {
+		if _, ok := values[key]; ok {
+			funcs = append(funcs, fn)
+		}
+	}
+	return funcs
+}
@@ -0,0 +1,75 @@
+package directive
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		text    string
+		args    string
+		ok      bool
+		wantErr bool
+	}{
+		{text: "//nolint", ok: true},
+		{text: "//nolint:a, b", args: "a, b", ok: true},
+		{text: "//nolint:", ok: true, wantErr: true},
+		{text: "//nolintfoo"},
+		{text: "// nolint"},
+		{text: "//tlin:max-complexity=20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+			args, ok, err := Parse(tt.text, "nolint")
+			assert.Equal(t, tt.args, args)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestParseAnnotations(t *testing.T) {
+	t.Parallel()
+	src := `//tlin:max-complexity=15
+
+package main
+
+// Add adds.
+//
+//tlin:pure
+//tlin:max-complexity = 20
+func Add(a, b int) int { return a + b }
+
+func Sub(a, b int) int { return a - b }
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	require.NoError(t, err)
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range f.Decls {
+		fn := decl.(*ast.FuncDecl)
+		funcs[fn.Name.Name] = fn
+	}
+
+	a := ParseAnnotations(f, fset)
+
+	value, ok := a.Lookup(funcs["Add"], "max-complexity")
+	assert.True(t, ok)
+	assert.Equal(t, "20", value)
+	value, ok = a.Lookup(funcs["Sub"], "max-complexity")
+	assert.True(t, ok)
+	assert.Equal(t, "15", value)
+
+	assert.True(t, a.Has(funcs["Add"], "pure"))
+	assert.False(t, a.Has(funcs["Sub"], "pure"))
+	assert.False(t, a.Has(nil, "pure"))
+	assert.Equal(t, []*ast.FuncDecl{funcs["Add"]}, a.Funcs("pure"))
+}
@@ -2,12 +2,17 @@ package internal
 
 import (
 	"fmt"
+	"go/ast"
 	"go/token"
 	"os"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/gnolang/tlin/internal/ignore"
 	"github.com/gnolang/tlin/internal/lints"
 	"github.com/gnolang/tlin/internal/nolint"
 	tt "github.com/gnolang/tlin/internal/types"
@@ -17,14 +22,23 @@ import (
 // TODO: use symbol table
 type Engine struct {
 	ignoredRules map[string]bool
-	nolintMgr    *nolint.Manager
 	rules        map[string]LintRule
+	confidence   map[string]float64 // configured suggestion confidence per rule
+	ignoredPaths *ignore.Matcher
+	includePaths *ignore.Matcher // files walked, all of them if nil
+	verbose      bool            // include stack traces in internal-error issues
+	parallelism  int             // rules run concurrently on a file, GOMAXPROCS if <= 0
+
+	timingsMu sync.Mutex
+	timings   map[string]time.Duration // accumulated run time per rule
 }
 
 // NewEngine creates a new lint engine.
 func NewEngine(rootDir string, source []byte, rules map[string]tt.ConfigRule) (*Engine, error) {
 	engine := &Engine{}
-	engine.applyRules(rules)
+	if err := engine.applyRules(rules); err != nil {
+		return nil, err
+	}
 
 	return engine, nil
 }
@@ -39,6 +53,8 @@ type ruleMap map[string]ruleConstructor
 var allRuleConstructors = ruleMap{
 	"golangci-lint":               NewGolangciLintRule,
 	"early-return-opportunity":    NewEarlyReturnOpportunityRule,
+	"early-continue":              NewEarlyContinueRule,
+	"nested-if":                   NewNestedIfRule,
 	"simplify-slice-range":        NewSimplifySliceExprRule,
 	"unnecessary-type-conversion": NewUnnecessaryConversionRule,
 	"emit-format":                 NewEmitFormatRule,
@@ -48,10 +64,46 @@ var allRuleConstructors = ruleMap{
 	"useless-break":               NewUselessBreakRule,
 	"defer-issues":                NewDeferRule,
 	"const-error-declaration":     NewConstErrorDeclarationRule,
+	"sentinel-error":              NewSentinelErrorRule,
+	"discarded-error":             NewDiscardedErrorRule,
+	"error-string-match":          NewErrorStringMatchRule,
+	"append-result-ignored":       NewAppendResultIgnoredRule,
+	"avl-tree-misuse":             NewAVLTreeMisuseRule,
+	"no-floats-in-realm":          NewNoFloatsInRealmRule,
+	"float-equality":              NewFloatEqualityRule,
+	"map-format-comparison":       NewMapFormatComparisonRule,
+	"map-range-order":             NewMapRangeOrderRule,
+	"misplaced-test-fatal":        NewMisplacedTestFatalRule,
+	"unbounded-recursion":         NewUnboundedRecursionRule,
+	"busy-wait":                   NewBusyWaitRule,
+	"unbuffered-send":             NewUnbufferedSendRule,
+	"off-by-one":                  NewOffByOneRule,
+	"constant-condition":          NewConstantConditionRule,
+	"unrestricted-setter":         NewUnrestrictedSetterRule,
+	"unbounded-input":             NewUnboundedInputRule,
+	"unused-struct-field":         NewUnusedStructFieldRule,
+	"unused-parameter":            NewUnusedParameterRule,
+	"shadowed-err":                NewShadowedErrRule,
+	"mixed-receivers":             NewMixedReceiversRule,
+	"receiver-name":               NewReceiverNameRule,
+	"function-length":             NewFunctionLengthRule,
+	"file-length":                 NewFileLengthRule,
+	"forbidden-import":            NewForbiddenImportRule,
+	"magic-number":                NewMagicNumberRule,
+	"type-switch-default":         NewTypeSwitchDefaultRule,
+	"exhaustive-switch":           NewExhaustiveSwitchRule,
+	"duplicate-case-body":         NewDuplicateCaseBodyRule,
+	"shadowed-predeclared":        NewShadowedPredeclaredRule,
+	"shadowed-import":             NewShadowedImportRule,
+	"suspicious-assignment":       NewSuspiciousAssignmentRule,
+	"struct-tag":                  NewStructTagRule,
+	"gas-hint":                    NewGasHintRule,
+	"reentrancy":                  NewReentrancyRule,
 }
 
-func (e *Engine) applyRules(rules map[string]tt.ConfigRule) {
+func (e *Engine) applyRules(rules map[string]tt.ConfigRule) error {
 	e.rules = make(map[string]LintRule)
+	e.confidence = make(map[string]float64)
 	e.registerDefaultRules()
 
 	// Iterate over the rules and apply severity
@@ -63,16 +115,39 @@ func (e *Engine) applyRules(rules map[string]tt.ConfigRule) {
 				// Unknown rule, continue to the next one
 				continue
 			}
-			newRule := newRuleCstr()
-			newRule.SetSeverity(rule.Severity)
-			e.rules[key] = newRule
+			r = newRuleCstr()
+			r.SetSeverity(rule.Severity)
+			e.rules[key] = r
 		} else {
 			if rule.Severity == tt.SeverityOff {
 				e.IgnoreRule(key)
 			}
 			r.SetSeverity(rule.Severity)
 		}
+
+		if rule.Confidence != nil {
+			if *rule.Confidence < 0 || *rule.Confidence > 1 {
+				return fmt.Errorf("rule %s: confidence must be between 0.0 and 1.0, got %v", key, *rule.Confidence)
+			}
+			e.confidence[key] = *rule.Confidence
+		}
+
+		if rule.Data != nil {
+			cr, ok := r.(ConfigurableRule)
+			if !ok {
+				return fmt.Errorf("rule %s: the rule takes no data", key)
+			}
+			// the errors of the rule come first, being more specific.
+			if err := cr.SetData(rule.Data); err != nil {
+				return fmt.Errorf("rule %s: %w", key, err)
+			}
+			if err := validateRuleData(rule.Data, cr.Parameters()); err != nil {
+				return fmt.Errorf("rule %s: %w", key, err)
+			}
+		}
 	}
+
+	return nil
 }
 
 func (e *Engine) registerDefaultRules() {
@@ -92,6 +167,16 @@ func (e *Engine) findRule(name string) LintRule {
 	return nil
 }
 
+// RuleSeverity returns the severity of the rule named name, and whether the
+// rule is enabled.
+func (e *Engine) RuleSeverity(name string) (tt.Severity, bool) {
+	r := e.findRule(name)
+	if r == nil {
+		return tt.SeverityOff, false
+	}
+	return r.Severity(), true
+}
+
 // Run applies all lint rules to the given file and returns a slice of Issues.
 func (e *Engine) Run(filename string) ([]tt.Issue, error) {
 	if strings.HasSuffix(filename, ".mod") {
@@ -104,42 +189,57 @@ func (e *Engine) Run(filename string) ([]tt.Issue, error) {
 	}
 	defer e.cleanupTemp(tempFile)
 
-	node, fset, err := lints.ParseFile(tempFile, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing file: %w", err)
-	}
-
-	e.nolintMgr = nolint.ParseComments(node, fset)
+	return e.runFile(filename, tempFile)
+}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
+// LintSource applies all lint rules, including the ones working on files
+// such as golangci-lint, to src as if it were the content of filename.
+// The file does not have to exist, and is left untouched when it does.
+// Issues are reported against filename.
+func (e *Engine) LintSource(filename string, src []byte) ([]tt.Issue, error) {
+	dir := filepath.Dir(filename)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		// still lint the snippet when the directory is gone,
+		// only package-level analysis loses the sibling files.
+		tempDir, err := os.MkdirTemp("", "tlin")
+		if err != nil {
+			return nil, fmt.Errorf("error creating temp dir: %w", err)
+		}
+		defer os.RemoveAll(tempDir)
+		if err := copyModuleFile(filepath.Dir(filename), tempDir); err != nil {
+			return nil, err
+		}
+		dir = tempDir
+	}
 
-	var allIssues []tt.Issue
-	for _, rule := range e.rules {
-		wg.Add(1)
-		go func(r LintRule) {
-			defer wg.Done()
-			if e.ignoredRules[r.Name()] {
-				return
-			}
-			issues, err := r.Check(tempFile, node, fset)
-			if err != nil {
-				return
-			}
+	tempFile, err := writeTempGoFile(dir, filename, src)
+	if err != nil {
+		return nil, err
+	}
+	defer e.cleanupTemp(tempFile)
 
-			nolinted := e.filterNolintIssues(issues)
+	return e.runFile(filename, tempFile)
+}
 
-			mu.Lock()
-			allIssues = append(allIssues, nolinted...)
-			mu.Unlock()
-		}(rule)
+// runFile lints tempFile, the Go source to analyze for filename.
+func (e *Engine) runFile(filename, tempFile string) ([]tt.Issue, error) {
+	node, fset, err := lints.ParseFile(tempFile, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
 	}
-	wg.Wait()
 
-	// map issues back to .gno file if necessary
-	if strings.HasSuffix(filename, ".gno") {
+	nolintMgr := nolint.ParseComments(node, fset)
+	allIssues := e.runRules(filename, tempFile, node, fset, nolintMgr)
+
+	// map issues back to the original file if necessary
+	if tempFile != filename {
 		for i := range allIssues {
 			allIssues[i].Filename = filename
+			for j := range allIssues[i].RelatedLocations {
+				if allIssues[i].RelatedLocations[j].Filename == tempFile {
+					allIssues[i].RelatedLocations[j].Filename = filename
+				}
+			}
 		}
 	}
 
@@ -153,25 +253,58 @@ func (e *Engine) RunSource(source []byte) ([]tt.Issue, error) {
 		return nil, fmt.Errorf("error parsing content: %w", err)
 	}
 
-	e.nolintMgr = nolint.ParseComments(node, fset)
+	nolintMgr := nolint.ParseComments(node, fset)
+
+	// rules scoped to some files cannot tell whether the source is one of them.
+	return e.runRules("", "", node, fset, nolintMgr), nil
+}
+
+// runRules runs the enabled rules on a parsed file, at most
+// e.parallelism of them at a time. Rules scoped to some files are skipped
+// when filename is not one of them; checkName is the name the rules see.
+// Issues silenced by the nolint comments of nolintMgr are left out.
+func (e *Engine) runRules(filename, checkName string, node *ast.File, fset *token.FileSet, nolintMgr *nolint.Manager) []tt.Issue {
+	limit := e.parallelism
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	sem := make(chan struct{}, limit)
+
+	defer lints.ShareTypeInfo(node)()
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
 	var allIssues []tt.Issue
 	for _, rule := range e.rules {
+		if e.ignoredRules[rule.Name()] {
+			continue
+		}
+		if fr, ok := rule.(FileScopedRule); ok && filename != "" && !fr.AppliesTo(filename) {
+			continue
+		}
+
 		wg.Add(1)
+		sem <- struct{}{}
 		go func(r LintRule) {
-			defer wg.Done()
-			if e.ignoredRules[r.Name()] {
-				return
-			}
-			issues, err := r.Check("", node, fset)
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			start := time.Now()
+			issues, err := e.checkRule(r, checkName, node, fset)
+			e.recordTiming(r.Name(), time.Since(start))
 			if err != nil {
 				return
 			}
+			attachConfigRule(issues, r.Name())
+			attachDocURL(issues, r.Name())
+			e.attachConfidence(issues, r.Name())
 
-			nolinted := e.filterNolintIssues(issues)
+			nolinted := filterNolintIssues(nolintMgr, issues)
+			for i := range nolinted {
+				nolinted[i].AddAction(tt.ActionSuppressible)
+			}
 
 			mu.Lock()
 			allIssues = append(allIssues, nolinted...)
@@ -180,7 +313,59 @@ func (e *Engine) RunSource(source []byte) ([]tt.Issue, error) {
 	}
 	wg.Wait()
 
-	return allIssues, nil
+	return allIssues
+}
+
+// attachConfigRule records the registered name of the rule on the issues
+// it reported under another name, such as early-return for
+// early-return-opportunity.
+func attachConfigRule(issues []tt.Issue, rule string) {
+	for i := range issues {
+		if issues[i].Rule != rule && issues[i].Rule != InternalErrorRule {
+			issues[i].ConfigRule = rule
+		}
+	}
+}
+
+// SetParallelism sets how many rules may run at the same time on a file.
+// Zero or less, the default, uses GOMAXPROCS; 1 runs the rules one after
+// the other.
+func (e *Engine) SetParallelism(n int) {
+	e.parallelism = n
+}
+
+// InternalErrorRule is the rule of the issues reporting a rule that panicked.
+const InternalErrorRule = "internal-error"
+
+// checkRule runs r on a file. A panic in the rule is reported as an
+// internal-error issue instead of the issues of the rule, so that the
+// other rules and files are still linted.
+func (e *Engine) checkRule(r LintRule, filename string, node *ast.File, fset *token.FileSet) (issues []tt.Issue, err error) {
+	defer func() {
+		p := recover()
+		if p == nil {
+			return
+		}
+		issue := tt.Issue{
+			Rule:     InternalErrorRule,
+			Filename: filename,
+			Start:    token.Position{Filename: filename, Line: 1, Column: 1},
+			End:      token.Position{Filename: filename, Line: 1, Column: 1},
+			Message:  fmt.Sprintf("rule %s panicked: %v", r.Name(), p),
+			Note:     "this is a bug in tlin, the rule was skipped for this file. please report it with the file content.",
+			Severity: tt.SeverityError,
+		}
+		if e.verbose {
+			issue.Note += "\n" + string(debug.Stack())
+		}
+		issues, err = []tt.Issue{issue}, nil
+	}()
+	return r.Check(filename, node, fset)
+}
+
+// SetVerbose makes internal-error issues include the stack trace of the panic.
+func (e *Engine) SetVerbose(verbose bool) {
+	e.verbose = verbose
 }
 
 func (e *Engine) IgnoreRule(rule string) {
@@ -190,6 +375,134 @@ func (e *Engine) IgnoreRule(rule string) {
 	e.ignoredRules[rule] = true
 }
 
+// EnableOnly restricts the engine to the given rules. Rules that are off by
+// default or in the configuration are enabled when listed, so that a single
+// rule can be trialed across a repository.
+func (e *Engine) EnableOnly(names ...string) error {
+	rules := make(map[string]LintRule, len(names))
+	for _, name := range names {
+		r := e.findRule(name)
+		if r == nil {
+			newRuleCstr, ok := allRuleConstructors[name]
+			if !ok {
+				return fmt.Errorf("unknown rule %q", name)
+			}
+			r = newRuleCstr()
+		}
+		if r.Severity() == tt.SeverityOff {
+			r.SetSeverity(tt.SeverityWarning)
+		}
+		rules[name] = r
+		delete(e.ignoredRules, name)
+	}
+	e.rules = rules
+	return nil
+}
+
+// IgnorePath excludes the paths matching a gitignore-style pattern,
+// relative to the current directory, from directory walks.
+func (e *Engine) IgnorePath(pattern string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if e.ignoredPaths == nil {
+		e.ignoredPaths = ignore.New()
+	}
+	e.ignoredPaths.Add(wd, pattern)
+	return nil
+}
+
+// IsPathIgnored reports whether path matches a pattern added with IgnorePath.
+func (e *Engine) IsPathIgnored(path string, isDir bool) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return e.ignoredPaths.Match(abs, isDir)
+}
+
+// IncludePath restricts the files found by directory walks to those matching
+// one of the gitignore-style patterns added, relative to the current
+// directory. Without patterns, every file is included.
+func (e *Engine) IncludePath(pattern string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if e.includePaths == nil {
+		e.includePaths = ignore.New()
+	}
+	e.includePaths.Add(wd, pattern)
+	return nil
+}
+
+// IsPathIncluded reports whether the file at path matches a pattern added
+// with IncludePath, or whether no pattern was added.
+func (e *Engine) IsPathIncluded(path string) bool {
+	if e.includePaths == nil {
+		return true
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return e.includePaths.Match(abs, false)
+}
+
+// RegisterBannedCall reports every call matching pattern with the given
+// message and severity. Patterns are an import path followed by a function
+// name or `*`, e.g. `os.Exit` or `unsafe.*`.
+//
+// Bans must be registered before the engine starts linting.
+func (e *Engine) RegisterBannedCall(pattern, message string, severity tt.Severity) error {
+	rule, ok := e.rules["banned-call"].(*BannedCallRule)
+	if !ok {
+		rule = newBannedCallRule()
+	}
+	if err := rule.bans.Register(pattern, message); err != nil {
+		return err
+	}
+	rule.severities[pattern] = severity
+	e.rules[rule.Name()] = rule
+	return nil
+}
+
+// RegisterDeprecatedFunc reports calls to funcName of the package imported
+// as pkgPath, suggesting alternative when it is not empty.
+//
+// Deprecations must be registered before the files calling them are linted.
+func (e *Engine) RegisterDeprecatedFunc(pkgPath, funcName, alternative string) {
+	rule, ok := e.rules["deprecated"].(*DeprecatedFuncRule)
+	if !ok {
+		rule = newDeprecatedFuncRule()
+	}
+	rule.deprecated.Register(pkgPath, funcName, alternative)
+	e.rules[rule.Name()] = rule
+}
+
+// RuleTimings returns the time spent in each rule since the engine was created.
+func (e *Engine) RuleTimings() map[string]time.Duration {
+	e.timingsMu.Lock()
+	defer e.timingsMu.Unlock()
+
+	timings := make(map[string]time.Duration, len(e.timings))
+	for rule, d := range e.timings {
+		timings[rule] = d
+	}
+	return timings
+}
+
+func (e *Engine) recordTiming(rule string, d time.Duration) {
+	e.timingsMu.Lock()
+	defer e.timingsMu.Unlock()
+
+	if e.timings == nil {
+		e.timings = make(map[string]time.Duration)
+	}
+	e.timings[rule] += d
+}
+
 func (e *Engine) prepareFile(filename string) (string, error) {
 	if strings.HasSuffix(filename, ".gno") {
 		return createTempGoFile(filename)
@@ -208,6 +521,7 @@ func (e *Engine) runModCheck(filename string) ([]tt.Issue, error) {
 			if err != nil {
 				return nil, fmt.Errorf("error checking .mod file: %w", err)
 			}
+			attachDocURL(issues, rule.Name())
 			allIssues = append(allIssues, issues...)
 		}
 	}
@@ -220,9 +534,9 @@ func (e *Engine) cleanupTemp(temp string) {
 	}
 }
 
-// filterNolintIssues filters issues based on nolint comments.
-func (e *Engine) filterNolintIssues(issues []tt.Issue) []tt.Issue {
-	if e.nolintMgr == nil {
+// filterNolintIssues filters issues based on the nolint comments of mgr.
+func filterNolintIssues(mgr *nolint.Manager, issues []tt.Issue) []tt.Issue {
+	if mgr == nil {
 		return issues
 	}
 	filtered := make([]tt.Issue, 0, len(issues))
@@ -231,7 +545,7 @@ func (e *Engine) filterNolintIssues(issues []tt.Issue) []tt.Issue {
 			Filename: issue.Filename,
 			Line:     issue.Start.Line,
 		}
-		if !e.nolintMgr.IsNolint(pos, issue.Rule) {
+		if !mgr.IsNolint(pos, issue.Rule) {
 			filtered = append(filtered, issue)
 		}
 	}
@@ -247,8 +561,60 @@ func createTempGoFile(gnoFile string) (string, error) {
 		return "", fmt.Errorf("error reading .gno file: %w", err)
 	}
 
-	dir := filepath.Dir(gnoFile)
-	tempFile, err := os.CreateTemp(dir, "temp_*.go")
+	return writeTempGoFile(filepath.Dir(gnoFile), gnoFile, content)
+}
+
+// copyModuleFile copies the gno.mod of the module dir belongs to into
+// tempDir, so that rules resolving the module of a file, such as the ones
+// scoped to realms, see the snippet as part of it. dir does not have to
+// exist, the module is searched from its closest existing parent.
+func copyModuleFile(dir, tempDir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	for {
+		if _, err := os.Stat(abs); err == nil {
+			break
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil
+		}
+		abs = parent
+	}
+
+	root, ok := lints.ModuleRoot(abs)
+	if !ok {
+		return nil
+	}
+	content, err := os.ReadFile(filepath.Join(root, "gno.mod"))
+	if err != nil {
+		// a go module, or a gno.mod that cannot be read: the snippet is
+		// linted without it.
+		return nil
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "gno.mod"), content, 0o644); err != nil {
+		return fmt.Errorf("error writing gno.mod to temp dir: %w", err)
+	}
+	return nil
+}
+
+// writeTempGoFile writes content to a temporary .go file in dir,
+// standing for the source file filename.
+func writeTempGoFile(dir, filename string, content []byte) (string, error) {
+	// keep the test suffix so that package-level analysis can tell
+	// test files apart from regular sources.
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+	pattern := "temp_*.go"
+	switch {
+	case strings.HasSuffix(base, "_filetest"):
+		pattern = "temp_*_filetest.go"
+	case strings.HasSuffix(base, "_test"):
+		pattern = "temp_*_test.go"
+	}
+
+	tempFile, err := os.CreateTemp(dir, pattern)
 	if err != nil {
 		return "", fmt.Errorf("error creating temp file: %w", err)
 	}
@@ -1,11 +1,18 @@
 package internal
 
 import (
+	"fmt"
+	"go/ast"
+	"go/token"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gnolang/tlin/internal/types"
 	"github.com/stretchr/testify/assert"
@@ -198,3 +205,767 @@ func createTempDir(tb testing.TB, prefix string) string {
 	tb.Cleanup(func() { os.RemoveAll(tempDir) })
 	return tempDir
 }
+
+func TestNewEngineRuleData(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	engine, err := NewEngine(tempDir, nil, map[string]types.ConfigRule{
+		"no-floats-in-realm": {
+			Severity: types.SeverityError,
+			Data:     map[string]interface{}{"allow": []interface{}{"Render", "Display"}},
+		},
+	})
+	require.NoError(t, err)
+
+	rule, ok := engine.rules["no-floats-in-realm"].(*NoFloatsInRealmRule)
+	require.True(t, ok)
+	assert.Equal(t, []string{"Render", "Display"}, rule.allow)
+
+	_, err = NewEngine(tempDir, nil, map[string]types.ConfigRule{
+		"no-floats-in-realm": {Data: map[string]interface{}{"allow": 42}},
+	})
+	assert.Error(t, err)
+}
+
+func TestEngine_StructTagNaming(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	_, err := NewEngine(tempDir, nil, map[string]types.ConfigRule{
+		"struct-tag": {Data: map[string]interface{}{"naming": "snake"}},
+	})
+	assert.NoError(t, err)
+
+	_, err = NewEngine(tempDir, nil, map[string]types.ConfigRule{
+		"struct-tag": {Data: map[string]interface{}{"naming": "screaming"}},
+	})
+	assert.ErrorContains(t, err, "unknown naming convention")
+}
+
+func TestNewEngineRuleDataValidation(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	tests := []struct {
+		name string
+		rule string
+		data interface{}
+		err  string
+	}{
+		{"valid", "function-length", map[string]interface{}{"max-lines": 40, "overrides": []interface{}{map[string]interface{}{"path": "gen/", "max-lines": 400}}}, ""},
+		{"unknown parameter", "function-length", map[string]interface{}{"max-line": 40}, `unknown parameter "max-line", expected one of max-lines, max-statements, overrides`},
+		{"not a mapping", "magic-number", "42", "invalid rule data"},
+		{"wrong type", "file-length", map[string]interface{}{"max-lines": "many"}, "cannot unmarshal"},
+		{"below minimum", "function-length", map[string]interface{}{"max-statements": 0}, "parameter max-statements: must be at least 1, got 0"},
+		{"invalid override", "file-length", map[string]interface{}{"overrides": []interface{}{map[string]interface{}{"path": "gen/", "max-lines": -1}}}, "override gen/: max-lines must be at least 1, got -1"},
+		{"list elements", "receiver-name", map[string]interface{}{"banned": []interface{}{[]interface{}{"me"}}}, "cannot unmarshal"},
+		{"avl method", "avl-tree-misuse", map[string]interface{}{"methods": map[string]interface{}{"GetOr": map[string]interface{}{"found": 1}}}, ""},
+		{"avl negative index", "avl-tree-misuse", map[string]interface{}{"methods": map[string]interface{}{"GetOr": map[string]interface{}{"found": -1}}}, "method GetOr: negative index -1"},
+		{"boolean", "map-range-order", map[string]interface{}{"autofix": true}, ""},
+		{"rule without data", "useless-break", map[string]interface{}{"strict": true}, "the rule takes no data"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewEngine(tempDir, nil, map[string]types.ConfigRule{
+				tt.rule: {Data: tt.data},
+			})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestDescribeRules(t *testing.T) {
+	t.Parallel()
+	rules := DescribeRules()
+	require.Len(t, rules, len(allRuleConstructors))
+	assert.True(t, sort.SliceIsSorted(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name }))
+
+	var length RuleDescription
+	for _, rule := range rules {
+		if rule.Name == "function-length" {
+			length = rule
+		}
+	}
+	assert.Equal(t, "WARNING", length.Severity)
+	require.Len(t, length.Parameters, 3)
+	assert.Equal(t, "max-lines", length.Parameters[0].Name)
+	assert.Equal(t, defaultMaxFunctionLines, length.Parameters[0].Default)
+	assert.Equal(t, 1, *length.Parameters[0].Minimum)
+}
+
+func TestEngine_RegisterBannedCall(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	engine, err := NewEngine(tempDir, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.RegisterBannedCall("os.Exit", "do not terminate the process", types.SeverityError))
+	require.NoError(t, engine.RegisterBannedCall("unsafe.*", "", types.SeverityWarning))
+	assert.Error(t, engine.RegisterBannedCall("Exit", "", types.SeverityError))
+
+	issues, err := engine.RunSource([]byte(`package main
+
+import (
+	"os"
+	"unsafe"
+)
+
+func main() {
+	_ = unsafe.Sizeof(0)
+	os.Exit(1)
+}
+`))
+	require.NoError(t, err)
+
+	var banned []types.Issue
+	for _, issue := range issues {
+		if issue.Rule == "banned-call" {
+			banned = append(banned, issue)
+		}
+	}
+	require.Len(t, banned, 2)
+	assert.Equal(t, "call to banned function unsafe.Sizeof", banned[0].Message)
+	assert.Equal(t, types.SeverityWarning, banned[0].Severity)
+	assert.Equal(t, "call to banned function os.Exit: do not terminate the process", banned[1].Message)
+	assert.Equal(t, types.SeverityError, banned[1].Severity)
+}
+
+func TestBannedCallRule_SetSeverity(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	engine, err := NewEngine(tempDir, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.RegisterBannedCall("os.Exit", "", types.SeverityError))
+	engine.findRule("banned-call").SetSeverity(types.SeverityInfo)
+	require.NoError(t, engine.RegisterBannedCall("unsafe.*", "", types.SeverityWarning))
+
+	issues, err := engine.RunSource([]byte(`package main
+
+import (
+	"os"
+	"unsafe"
+)
+
+func main() {
+	_ = unsafe.Sizeof(0)
+	os.Exit(1)
+}
+`))
+	require.NoError(t, err)
+
+	severities := make(map[string]types.Severity)
+	for _, issue := range issues {
+		if issue.Rule == "banned-call" {
+			severities[issue.Message] = issue.Severity
+		}
+	}
+	assert.Equal(t, map[string]types.Severity{
+		"call to banned function os.Exit":       types.SeverityInfo,
+		"call to banned function unsafe.Sizeof": types.SeverityWarning,
+	}, severities)
+}
+
+func TestEngine_RegisterDeprecatedFunc(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	engine, err := NewEngine(tempDir, nil, nil)
+	require.NoError(t, err)
+
+	engine.RegisterDeprecatedFunc("gno.land/p/demo/coins", "Mint", "coins.NewCoins")
+	engine.RegisterDeprecatedFunc("gno.land/p/demo/coins", "Reset", "")
+
+	issues, err := engine.RunSource([]byte(`package main
+
+import "gno.land/p/demo/coins"
+
+func main() {
+	coins.Mint()
+	coins.Reset()
+	coins.NewCoins()
+}
+`))
+	require.NoError(t, err)
+
+	var deprecated []types.Issue
+	for _, issue := range issues {
+		if issue.Rule == "deprecated" {
+			deprecated = append(deprecated, issue)
+		}
+	}
+	require.Len(t, deprecated, 2)
+	assert.Equal(t, "Use of deprecated function. please use coins.NewCoins instead.", deprecated[0].Message)
+	assert.Equal(t, "Use of deprecated function. please remove it.", deprecated[1].Message)
+}
+
+func TestEngine_MixedGoAndGnoPackage(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	files := map[string]string{
+		"gno.mod": "module gno.land/r/demo/mixed\n",
+		"realm.gno": `package mixed
+
+var ratio = 0.5
+
+func f() error { return nil }
+
+func Check() error {
+	var err error
+	if err := f(); err != nil {
+		println(err)
+	}
+	return err
+}
+`,
+		"realm_test.go": `package mixed
+
+var tolerance = 0.01
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0o644))
+	}
+
+	engine, err := NewEngine(tempDir, nil, nil)
+	require.NoError(t, err)
+
+	gnoIssues, err := engine.Run(filepath.Join(tempDir, "realm.gno"))
+	require.NoError(t, err)
+
+	rules := make(map[string]types.Issue)
+	for _, issue := range gnoIssues {
+		rules[issue.Rule] = issue
+	}
+	require.Contains(t, rules, "no-floats-in-realm")
+	require.Contains(t, rules, "shadowed-err")
+	for _, loc := range rules["shadowed-err"].RelatedLocations {
+		assert.Equal(t, filepath.Join(tempDir, "realm.gno"), loc.Filename, "related locations must point to the .gno file")
+	}
+
+	testIssues, err := engine.Run(filepath.Join(tempDir, "realm_test.go"))
+	require.NoError(t, err)
+	for _, issue := range testIssues {
+		assert.NotEqual(t, "no-floats-in-realm", issue.Rule, "realm rules do not apply to test harnesses")
+	}
+}
+
+func TestEngine_AttachesDocURL(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine("", nil, nil)
+	require.NoError(t, err)
+
+	issues, err := engine.RunSource([]byte(`package main
+
+func main() {
+	for {
+		break
+	}
+	switch 1 {
+	case 1:
+		break
+	}
+}
+`))
+	require.NoError(t, err)
+
+	found := false
+	for _, issue := range issues {
+		if issue.Rule == "useless-break" {
+			found = true
+			assert.Equal(t, RuleDocURL("useless-break"), issue.DocURL)
+		}
+	}
+	assert.True(t, found)
+	assert.Empty(t, RuleDocURL("unknown-rule"))
+}
+
+func TestLengthRuleOverrides(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	engine, err := NewEngine(tempDir, nil, map[string]types.ConfigRule{
+		"function-length": {
+			Severity: types.SeverityWarning,
+			Data: map[string]interface{}{
+				"max-lines": 3,
+				"overrides": []interface{}{
+					map[string]interface{}{"path": "legacy", "max-lines": 100},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	src := "package foo\n\nfunc f() {\n\ta := 1\n\ta++\n\t_ = a\n}\n"
+	for dir, expected := range map[string]int{"current": 1, "legacy": 0} {
+		path := filepath.Join(tempDir, dir, "foo.gno")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+		issues, err := engine.Run(path)
+		require.NoError(t, err)
+
+		count := 0
+		for _, issue := range issues {
+			if issue.Rule == "function-length" {
+				count++
+			}
+		}
+		assert.Equal(t, expected, count, dir)
+	}
+}
+
+func TestEngine_RuleConfidence(t *testing.T) {
+	t.Parallel()
+	src := []byte(`package main
+
+func main() {
+	s := []int{1}
+	append(s, 2)
+}
+`)
+	confidenceOf := func(issues []types.Issue) float64 {
+		for _, issue := range issues {
+			if issue.Rule == "append-result-ignored" {
+				return issue.Confidence
+			}
+		}
+		t.Fatal("append-result-ignored not reported")
+		return 0
+	}
+
+	engine, err := NewEngine("", nil, nil)
+	require.NoError(t, err)
+	issues, err := engine.RunSource(src)
+	require.NoError(t, err)
+	assert.Equal(t, RuleConfidence("append-result-ignored"), confidenceOf(issues))
+
+	low := 0.5
+	engine, err = NewEngine("", nil, map[string]types.ConfigRule{
+		"append-result-ignored": {Severity: types.SeverityWarning, Confidence: &low},
+	})
+	require.NoError(t, err)
+	issues, err = engine.RunSource(src)
+	require.NoError(t, err)
+	assert.Equal(t, low, confidenceOf(issues))
+
+	invalid := 1.5
+	_, err = NewEngine("", nil, map[string]types.ConfigRule{
+		"append-result-ignored": {Confidence: &invalid},
+	})
+	assert.Error(t, err)
+}
+
+func TestEngine_SuggestionsHaveConfidence(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte(`package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+)
+
+func name(c Color) string {
+	switch c {
+	case Red:
+		return "red"
+	}
+	return ""
+}
+
+type T struct{ n int }
+
+func (t *T) Get() int  { return t.n }
+func (self *T) Set(n int) { self.n = n }
+
+func load() error {
+	if err := fmt.Errorf("x"); err != nil {
+		return errors.New("load failed")
+	}
+	return nil
+}
+
+func title(s string) string {
+	return strings.Title(s)
+}
+`), 0o644))
+
+	engine, err := NewEngine("", nil, map[string]types.ConfigRule{
+		"exhaustive-switch": {Severity: types.SeverityWarning},
+	})
+	require.NoError(t, err)
+	engine.RegisterDeprecatedFunc("strings", "Title", "cases.Title")
+
+	issues, err := engine.Run(path)
+	require.NoError(t, err)
+
+	suggested := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Suggestion == "" {
+			continue
+		}
+		suggested[issue.ConfigName()] = true
+		assert.Positive(t, issue.Confidence, issue.ConfigName())
+	}
+	for _, rule := range []string{"receiver-name", "discarded-error", "exhaustive-switch", "deprecated"} {
+		assert.True(t, suggested[rule], rule)
+	}
+}
+
+func TestEngine_ConcurrentRunsKeepTheirNolintComments(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	src := "package main\n\nfunc f(s []int) []int {\n\treturn s[:len(s)]%s\n}\n"
+	silenced := filepath.Join(dir, "silenced.go")
+	reported := filepath.Join(dir, "reported.go")
+	require.NoError(t, os.WriteFile(silenced, []byte(fmt.Sprintf(src, " //nolint:simplify-slice-range")), 0o644))
+	require.NoError(t, os.WriteFile(reported, []byte(fmt.Sprintf(src, "")), 0o644))
+
+	engine, err := NewEngine(dir, nil, nil)
+	require.NoError(t, err)
+
+	count := func(path string) int {
+		issues, err := engine.Run(path)
+		if err != nil {
+			t.Error(err)
+		}
+		n := 0
+		for _, issue := range issues {
+			if issue.Rule == "simplify-slice-range" {
+				n++
+			}
+		}
+		return n
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, 0, count(silenced))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, 1, count(reported))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEngine_SuppressibleAction(t *testing.T) {
+	t.Parallel()
+	engine, err := NewEngine("", nil, nil)
+	require.NoError(t, err)
+	issues, err := engine.RunSource([]byte(`package main
+
+func main() {
+	s := []int{1}
+	append(s, 2)
+}
+`))
+	require.NoError(t, err)
+	require.NotEmpty(t, issues)
+	for _, issue := range issues {
+		assert.True(t, issue.HasAction(types.ActionSuppressible), issue.Rule)
+	}
+}
+
+func TestEngine_LintSource(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	onDisk := "package main\n\nfunc main() {}\n"
+	filename := filepath.Join(tempDir, "main.gno")
+	require.NoError(t, os.WriteFile(filename, []byte(onDisk), 0o644))
+
+	engine, err := NewEngine(tempDir, nil, nil)
+	require.NoError(t, err)
+
+	src := []byte(`package main
+
+func main() {
+	s := []int{1}
+	_ = s[:len(s)]
+}
+`)
+	for _, name := range []string{filename, filepath.Join(tempDir, "missing", "main.gno")} {
+		issues, err := engine.LintSource(name, src)
+		require.NoError(t, err)
+
+		found := false
+		for _, issue := range issues {
+			assert.Equal(t, name, issue.Filename)
+			if issue.Rule == "simplify-slice-range" {
+				found = true
+			}
+		}
+		assert.True(t, found, name)
+	}
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, onDisk, string(content), "the linted file must be left untouched")
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files must be removed")
+}
+
+func TestEngine_LintSourceModule(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "gno.mod"), []byte("module gno.land/r/demo/app\n"), 0o644))
+
+	engine, err := NewEngine(tempDir, nil, nil)
+	require.NoError(t, err)
+
+	src := []byte(`package app
+
+var ratio float64 = 0.5
+`)
+	// the directory is gone, the snippet still belongs to the realm.
+	issues, err := engine.LintSource(filepath.Join(tempDir, "missing", "app.gno"), src)
+	require.NoError(t, err)
+
+	found := false
+	for _, issue := range issues {
+		if issue.Rule == "no-floats-in-realm" {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestEngine_WhyNot(t *testing.T) {
+	t.Parallel()
+	tempDir := createTempDir(t, "engine_test")
+
+	filename := filepath.Join(tempDir, "foo.gno")
+	require.NoError(t, os.WriteFile(filename, []byte(`package foo
+
+func f(x int) int {
+	if x > 0 {
+		x++
+	} else {
+		x--
+	}
+	if x > 10 {
+		return x
+	}
+	if x < 0 {
+		return 0
+	} else {
+		return x
+	}
+}
+`), 0o644))
+
+	engine, err := NewEngine(tempDir, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		line    int
+		reason  string
+		flagged bool
+	}{
+		{line: 4, reason: "the if body does not terminate with return, break, continue, goto, panic or os.Exit"},
+		{line: 9, reason: "the if statement has no else branch"},
+		{line: 2, reason: "no if statement starts on this line"},
+		{line: 12, flagged: true},
+	}
+	for _, tc := range tests {
+		explanation, err := engine.WhyNot("early-return-opportunity", filename, tc.line)
+		require.NoError(t, err)
+		if tc.flagged {
+			assert.Len(t, explanation.Issues, 1)
+			assert.Empty(t, explanation.Reasons)
+			continue
+		}
+		assert.Empty(t, explanation.Issues)
+		assert.Equal(t, []string{tc.reason}, explanation.Reasons, "line %d", tc.line)
+	}
+
+	_, err = engine.WhyNot("useless-break", filename, 4)
+	assert.Error(t, err)
+}
+
+func TestEngine_EnableOnly(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine("", nil, map[string]types.ConfigRule{
+		"useless-break": {Severity: types.SeverityOff},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, engine.EnableOnly("useless-break", "magic-number"))
+	assert.Len(t, engine.rules, 2)
+	assert.False(t, engine.ignoredRules["useless-break"])
+	assert.NotEqual(t, types.SeverityOff, engine.rules["useless-break"].Severity())
+
+	issues, err := engine.RunSource([]byte(`package main
+
+func main() {
+	switch 1 {
+	case 1:
+		break
+	}
+	var err error
+	if err := f(); err != nil {
+	}
+	_ = err
+}
+`))
+	require.NoError(t, err)
+	require.NotEmpty(t, issues)
+	for _, issue := range issues {
+		assert.Equal(t, "useless-break", issue.Rule)
+	}
+
+	assert.Error(t, engine.EnableOnly("no-such-rule"))
+}
+
+func TestEngine_IgnorePath(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine("", nil, nil)
+	require.NoError(t, err)
+	assert.False(t, engine.IsPathIgnored("testdata/foo.gno", false))
+
+	require.NoError(t, engine.IgnorePath("testdata/"))
+	assert.True(t, engine.IsPathIgnored("testdata", true))
+	assert.True(t, engine.IsPathIgnored("testdata/foo.gno", false))
+	assert.False(t, engine.IsPathIgnored("lints/foo.gno", false))
+}
+
+func TestEngine_IncludePath(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine("", nil, nil)
+	require.NoError(t, err)
+	assert.True(t, engine.IsPathIncluded("lints/foo.go"))
+
+	require.NoError(t, engine.IncludePath("**/*.gno"))
+	assert.True(t, engine.IsPathIncluded("testdata/foo.gno"))
+	assert.False(t, engine.IsPathIncluded("lints/foo.go"))
+}
+
+// panickingRule fails on every file.
+type panickingRule struct{ UselessBreakRule }
+
+func (r *panickingRule) Check(string, *ast.File, *token.FileSet) ([]types.Issue, error) {
+	panic("boom")
+}
+
+func (r *panickingRule) Name() string { return "panicking" }
+
+func TestEngine_RulePanic(t *testing.T) {
+	t.Parallel()
+
+	engine := &Engine{rules: map[string]LintRule{
+		"panicking":     &panickingRule{},
+		"useless-break": NewUselessBreakRule(),
+	}}
+	src := []byte("package main\n\nfunc main() {\n\tfor {\n\t\tbreak\n\t}\n\tswitch 1 {\n\tcase 1:\n\t\tbreak\n\t}\n}\n")
+
+	issues, err := engine.RunSource(src)
+	require.NoError(t, err)
+
+	rules := make(map[string]types.Issue)
+	for _, issue := range issues {
+		rules[issue.Rule] = issue
+	}
+	require.Contains(t, rules, "useless-break", "other rules still run")
+	require.Contains(t, rules, InternalErrorRule)
+	assert.Equal(t, "rule panicking panicked: boom", rules[InternalErrorRule].Message)
+	assert.NotContains(t, rules[InternalErrorRule].Note, "goroutine")
+
+	engine.SetVerbose(true)
+	issues, err = engine.RunSource(src)
+	require.NoError(t, err)
+	for _, issue := range issues {
+		if issue.Rule == InternalErrorRule {
+			assert.Contains(t, issue.Note, "goroutine", "verbose mode includes the stack trace")
+		}
+	}
+}
+
+// concurrencyRule records how many instances run at the same time.
+type concurrencyRule struct {
+	UselessBreakRule
+	name            string
+	running, maxRun *atomic.Int32
+}
+
+func (r *concurrencyRule) Check(string, *ast.File, *token.FileSet) ([]types.Issue, error) {
+	n := r.running.Add(1)
+	defer r.running.Add(-1)
+	for {
+		m := r.maxRun.Load()
+		if n <= m || r.maxRun.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return nil, nil
+}
+
+func (r *concurrencyRule) Name() string { return r.name }
+
+func TestEngine_SetParallelism(t *testing.T) {
+	t.Parallel()
+
+	for _, limit := range []int{1, 3} {
+		var running, maxRun atomic.Int32
+		engine := &Engine{rules: make(map[string]LintRule)}
+		for i := 0; i < 8; i++ {
+			name := fmt.Sprintf("rule-%d", i)
+			engine.rules[name] = &concurrencyRule{name: name, running: &running, maxRun: &maxRun}
+		}
+		engine.SetParallelism(limit)
+
+		_, err := engine.RunSource([]byte("package main\n"))
+		require.NoError(t, err)
+		assert.LessOrEqual(t, maxRun.Load(), int32(limit))
+		assert.Len(t, engine.RuleTimings(), 8, "every rule runs")
+	}
+}
+
+func TestGolangciLintRule_Unavailable(t *testing.T) {
+	// not parallel: the rule looks golangci-lint up in PATH.
+	t.Setenv("PATH", t.TempDir())
+
+	rule := NewGolangciLintRule()
+	issues, err := rule.Check("a.go", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "golangci-lint", issues[0].Rule)
+	assert.Equal(t, types.SeverityWarning, issues[0].Severity)
+	assert.Contains(t, issues[0].Message, "golangci-lint checks are disabled: golangci-lint was not found in PATH")
+
+	// the rule is disabled for the next files, without reporting it again.
+	issues, err = rule.Check("b.go", nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
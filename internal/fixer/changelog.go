@@ -0,0 +1,92 @@
+package fixer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// Change is a fix applied to a file, kept so that automated modifications
+// can be reviewed after the fact.
+type Change struct {
+	Rule     string `json:"rule"`
+	Filename string `json:"filename"`
+	Message  string `json:"message"`
+	Before   string `json:"before"`
+	After    string `json:"after"`
+	Line     int    `json:"line"`
+}
+
+func newChange(filename string, content []byte, edit Edit, issue tt.Issue) Change {
+	return Change{
+		Rule:     issue.Rule,
+		Filename: filename,
+		Message:  issue.Message,
+		Line:     issue.Start.Line,
+		Before:   string(content[edit.Start:edit.End]),
+		After:    edit.NewText,
+	}
+}
+
+// WriteChangelog writes changes to path, as JSON if its extension is .json
+// and as Markdown otherwise. Changes are grouped by rule, then sorted by file
+// and line.
+func WriteChangelog(path string, changes []Change) error {
+	sorted := append([]Change(nil), changes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+
+	var content []byte
+	if filepath.Ext(path) == ".json" {
+		byRule := make(map[string][]Change)
+		for _, c := range sorted {
+			byRule[c.Rule] = append(byRule[c.Rule], c)
+		}
+		d, err := json.MarshalIndent(byRule, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling changes: %w", err)
+		}
+		content = append(d, '\n')
+	} else {
+		content = []byte(markdownChangelog(sorted))
+	}
+
+	if err := os.WriteFile(path, content, defaultFilePermissions); err != nil {
+		return fmt.Errorf("error writing changelog: %w", err)
+	}
+	return nil
+}
+
+func markdownChangelog(changes []Change) string {
+	var b strings.Builder
+	b.WriteString("# Fixes\n")
+	if len(changes) == 0 {
+		b.WriteString("\nNo fix was applied.\n")
+		return b.String()
+	}
+
+	rule := ""
+	for _, c := range changes {
+		if c.Rule != rule {
+			rule = c.Rule
+			fmt.Fprintf(&b, "\n## %s\n", rule)
+		}
+		fmt.Fprintf(&b, "\n### %s:%d\n\n%s\n", c.Filename, c.Line, c.Message)
+		fmt.Fprintf(&b, "\nBefore:\n\n```go\n%s\n```\n", c.Before)
+		fmt.Fprintf(&b, "\nAfter:\n\n```go\n%s\n```\n", c.After)
+	}
+	return b.String()
+}
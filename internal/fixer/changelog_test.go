@@ -0,0 +1,61 @@
+package fixer
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteChangelog(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(file, []byte(`package main
+
+func main() {
+	slice := []int{1, 2, 3}
+	_ = slice[:len(slice)]
+}
+`), 0o644))
+
+	fix := New(false, confidenceThreshold)
+	require.NoError(t, fix.Fix(file, []tt.Issue{{
+		Rule:       "simplify-slice-range",
+		Message:    "unnecessary use of len() in slice expression, can be simplified",
+		Start:      token.Position{Offset: 47, Line: 5, Column: 2},
+		End:        token.Position{Offset: 69, Line: 5, Column: 24},
+		Suggestion: "_ = slice[:]",
+		Confidence: 0.9,
+	}}))
+	require.Len(t, fix.Changes, 1)
+	assert.Equal(t, Change{
+		Rule:     "simplify-slice-range",
+		Filename: file,
+		Message:  "unnecessary use of len() in slice expression, can be simplified",
+		Before:   "_ = slice[:len(slice)]",
+		After:    "_ = slice[:]",
+		Line:     5,
+	}, fix.Changes[0])
+
+	mdPath := filepath.Join(dir, "FIXES.md")
+	require.NoError(t, WriteChangelog(mdPath, fix.Changes))
+	md, err := os.ReadFile(mdPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "## simplify-slice-range\n\n### "+file+":5\n")
+	assert.Contains(t, string(md), "Before:\n\n```go\n_ = slice[:len(slice)]\n```")
+	assert.Contains(t, string(md), "After:\n\n```go\n_ = slice[:]\n```")
+
+	jsonPath := filepath.Join(dir, "fixes.json")
+	require.NoError(t, WriteChangelog(jsonPath, fix.Changes))
+	data, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	var byRule map[string][]Change
+	require.NoError(t, json.Unmarshal(data, &byRule))
+	assert.Equal(t, fix.Changes, byRule["simplify-slice-range"])
+}
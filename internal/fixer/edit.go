@@ -0,0 +1,106 @@
+package fixer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Edit replaces the bytes in [Start, End) of a file with NewText.
+type Edit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+// ApplyEdits applies non-overlapping edits to content and returns the result.
+func ApplyEdits(content []byte, edits []Edit) ([]byte, error) {
+	sorted := make([]Edit, len(edits))
+	copy(sorted, edits)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	out := make([]byte, 0, len(content))
+	last := 0
+	for _, e := range sorted {
+		if e.Start < last || e.End < e.Start || e.End > len(content) {
+			return nil, fmt.Errorf("invalid or overlapping edit at offset %d", e.Start)
+		}
+		out = append(out, content[last:e.Start]...)
+		out = append(out, e.NewText...)
+		last = e.End
+	}
+	out = append(out, content[last:]...)
+
+	return out, nil
+}
+
+// WriteFilesAtomically writes every file or none of them.
+//
+// Contents are first written to temporary files next to their targets, which
+// are then renamed over the originals. If a rename fails, the files that were
+// already replaced are restored. Existing files keep their permissions.
+func WriteFilesAtomically(files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	temps := make(map[string]string, len(names))
+	originals := make(map[string][]byte, len(names))
+	perms := make(map[string]os.FileMode, len(names))
+	cleanup := func() {
+		for _, tmp := range temps {
+			os.Remove(tmp)
+		}
+	}
+
+	for _, name := range names {
+		original, err := os.ReadFile(name)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			cleanup()
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		originals[name] = original
+		perm := os.FileMode(defaultFilePermissions)
+		if info, err := os.Stat(name); err == nil {
+			perm = info.Mode().Perm()
+		}
+		perms[name] = perm
+
+		tmp, err := os.CreateTemp(filepath.Dir(name), ".tlin-*")
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		temps[name] = tmp.Name()
+		_, err = tmp.Write(files[name])
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Chmod(tmp.Name(), perm)
+		}
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+
+	for i, name := range names {
+		if err := os.Rename(temps[name], name); err != nil {
+			for _, done := range names[:i] {
+				os.WriteFile(done, originals[done], perms[done])
+			}
+			cleanup()
+			return fmt.Errorf("failed to replace %s: %w", name, err)
+		}
+		delete(temps, name)
+	}
+
+	return nil
+}
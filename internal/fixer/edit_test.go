@@ -0,0 +1,56 @@
+package fixer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEdits(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("var foo = foo + 1")
+	got, err := ApplyEdits(content, []Edit{
+		{Start: 10, End: 13, NewText: "bar"},
+		{Start: 4, End: 7, NewText: "bar"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "var bar = bar + 1", string(got))
+
+	_, err = ApplyEdits(content, []Edit{
+		{Start: 4, End: 8, NewText: "x"},
+		{Start: 6, End: 9, NewText: "y"},
+	})
+	assert.Error(t, err)
+}
+
+func TestWriteFilesAtomically(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	a := filepath.Join(dir, "a.gno")
+	b := filepath.Join(dir, "b.gno")
+	require.NoError(t, os.WriteFile(a, []byte("old a"), 0o600))
+
+	require.NoError(t, WriteFilesAtomically(map[string][]byte{
+		a: []byte("new a"),
+		b: []byte("new b"),
+	}))
+
+	got, err := os.ReadFile(a)
+	require.NoError(t, err)
+	assert.Equal(t, "new a", string(got))
+	info, err := os.Stat(a)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "permissions of existing files are kept")
+	got, err = os.ReadFile(b)
+	require.NoError(t, err)
+	assert.Equal(t, "new b", string(got))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "temporary files should be removed")
+}
@@ -17,13 +17,35 @@ const (
 	defaultFilePermissions = 0o644
 )
 
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
 // Fixer handles the fixing of issues in Gno code files.
 type Fixer struct {
-	buffer        bytes.Buffer
+	buffer bytes.Buffer
+	Policy Policy
+	// Changes records the fixes applied, or that would be applied in dry-run mode.
+	Changes       []Change
 	MinConfidence float64
 	DryRun        bool
 }
 
+// Policy decides which rules may have their suggestions applied.
+// The zero value allows every rule.
+type Policy struct {
+	// Allowed restricts fixes to the listed rules. nil allows every rule.
+	Allowed map[string]bool
+	// Disabled lists rules whose fixes are never applied.
+	Disabled map[string]bool
+}
+
+// Allows reports whether fixes of the given rule may be applied.
+func (p Policy) Allows(rule string) bool {
+	if p.Disabled[rule] {
+		return false
+	}
+	return p.Allowed == nil || p.Allowed[rule]
+}
+
 // New creates a new Fixer instance.
 func New(dryRun bool, threshold float64) *Fixer {
 	return &Fixer{
@@ -33,30 +55,67 @@ func New(dryRun bool, threshold float64) *Fixer {
 }
 
 // Fix applies fixes to the given file based on the provided issues.
+//
+// Each suggestion replaces the bytes between the start and end positions of
+// its issue. Issues are applied from the end of the file to its start, and an
+// issue overlapping one already applied is skipped.
 func (f *Fixer) Fix(filename string, issues []tt.Issue) error {
-	content, err := os.ReadFile(filename)
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	raw, err := os.ReadFile(filename)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	lines := strings.Split(string(content), "\n")
-	sortIssuesByEndOffset(issues)
+	style := detectFileStyle(info.Mode().Perm(), raw)
+	content := style.normalize(raw)
+	lines := lineOffsets(content)
 
+	var edits []Edit
+	var fixable []tt.Issue
 	for _, issue := range issues {
-		if issue.Confidence < f.MinConfidence {
+		if issue.Suggestion == "" || issue.Confidence < f.MinConfidence || !f.Policy.Allows(issue.ConfigName()) {
+			continue
+		}
+		edit, ok := editFor(content, lines, style, issue)
+		if !ok {
+			continue
+		}
+		edits = append(edits, edit)
+		fixable = append(fixable, issue)
+	}
+	sortEditsFromEnd(edits, fixable)
+
+	// edits are applied from the end, so the offsets of the next ones stay valid.
+	limit := len(content)
+	for i, edit := range edits {
+		if edit.End > limit {
+			continue
+		}
+		fixed, err := ApplyEdits(content, []Edit{edit})
+		if err != nil {
+			continue
+		}
+		issue := fixable[i]
+		if c := issue.Confidence * verify(filename, fixed); c == 0 || c < f.MinConfidence {
 			continue
 		}
 
+		f.Changes = append(f.Changes, newChange(filename, content, edit, issue))
+		// an overlapping edit would be skipped once this one is applied.
+		limit = edit.Start
 		if f.DryRun {
 			f.printDryRunInfo(filename, issue)
 			continue
 		}
 
-		lines = f.applyFix(lines, issue)
+		content = fixed
 	}
 
 	if !f.DryRun {
-		if err := f.writeFixedContent(filename, lines); err != nil {
+		if err := f.writeFixedContent(filename, content, style); err != nil {
 			return err
 		}
 		fmt.Printf("Fixed issues in %s\n", filename)
@@ -70,27 +129,73 @@ func (f *Fixer) printDryRunInfo(filename string, issue tt.Issue) {
 	fmt.Printf("Suggestion:\n%s\n", issue.Suggestion)
 }
 
-func (f *Fixer) applyFix(lines []string, issue tt.Issue) []string {
-	startLine := issue.Start.Line - 1
-	endLine := issue.End.Line - 1
-
-	indent := extractIndent(lines[startLine])
-	suggestion := applyIndent(issue.Suggestion, indent, issue.Start)
+// editFor converts the range of issue to byte offsets in content, whose
+// lines start at the given offsets. Lines and columns are used rather than
+// the offset of the positions, which does not account for the line endings
+// and BOM removed from content.
+func editFor(content []byte, lines []int, style fileStyle, issue tt.Issue) (Edit, bool) {
+	offset := func(pos token.Position) (int, bool) {
+		if pos.Line < 1 || pos.Line > len(lines) || pos.Column < 1 {
+			return 0, false
+		}
+		column := pos.Column
+		if style.bom && pos.Line == 1 {
+			// the parser counts the BOM in the columns of the first line.
+			column -= len(utf8BOM)
+		}
+		lineEnd := len(content)
+		if pos.Line < len(lines) {
+			lineEnd = lines[pos.Line] - 1
+		}
+		off := lines[pos.Line-1] + column - 1
+		return off, column >= 1 && off <= lineEnd
+	}
 
-	return append(lines[:startLine], append([]string{suggestion}, lines[endLine+1:]...)...)
+	start, ok := offset(issue.Start)
+	if !ok {
+		return Edit{}, false
+	}
+	end, ok := offset(issue.End)
+	if !ok || end < start {
+		return Edit{}, false
+	}
+	// the text before the start of the issue is kept, indentation included.
+	return Edit{Start: start, End: end, NewText: strings.TrimLeft(issue.Suggestion, " \t")}, true
 }
 
-func (f *Fixer) writeFixedContent(filename string, lines []string) error {
-	f.buffer.Reset()
-	for i, line := range lines {
-		f.buffer.WriteString(line)
-		if i < len(lines)-1 {
-			f.buffer.WriteByte('\n')
+// lineOffsets returns the offset of the first byte of each line of content.
+func lineOffsets(content []byte) []int {
+	offsets := []int{0}
+	for i, b := range content {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
 		}
 	}
+	return offsets
+}
+
+// sortEditsFromEnd sorts edits, and the issues they come from, by
+// decreasing start offset.
+func sortEditsFromEnd(edits []Edit, issues []tt.Issue) {
+	idx := make([]int, len(edits))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return edits[idx[a]].Start > edits[idx[b]].Start
+	})
+	sortedEdits := make([]Edit, len(edits))
+	sortedIssues := make([]tt.Issue, len(issues))
+	for i, j := range idx {
+		sortedEdits[i], sortedIssues[i] = edits[j], issues[j]
+	}
+	copy(edits, sortedEdits)
+	copy(issues, sortedIssues)
+}
 
+func (f *Fixer) writeFixedContent(filename string, content []byte, style fileStyle) error {
 	fset := token.NewFileSet()
-	astFile, err := parser.ParseFile(fset, filename, f.buffer.Bytes(), parser.ParseComments)
+	astFile, err := parser.ParseFile(fset, filename, content, parser.ParseComments)
 	if err != nil {
 		return fmt.Errorf("failed to parse file: %w", err)
 	}
@@ -100,47 +205,56 @@ func (f *Fixer) writeFixedContent(filename string, lines []string) error {
 		return fmt.Errorf("failed to format file: %w", err)
 	}
 
-	if err := os.WriteFile(filename, f.buffer.Bytes(), defaultFilePermissions); err != nil {
+	if err := os.WriteFile(filename, style.restore(f.buffer.Bytes()), style.perm); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
 	return nil
 }
 
-// sorts the issues by the end offset of the issue.
-// By doing this, we ensure that the issues are applied in the correct order.
-func sortIssuesByEndOffset(issues []tt.Issue) {
-	sort.Slice(issues, func(i, j int) bool {
-		return issues[i].End.Offset > issues[j].End.Offset
-	})
+// fileStyle records the properties of a file that must survive a rewrite,
+// so that fixing a Windows-authored file does not produce a noisy diff.
+type fileStyle struct {
+	perm os.FileMode
+	bom  bool
+	crlf bool
 }
 
-// extractIndent extracts the indentation from the first line of the issue.
-func extractIndent(line string) string {
-	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+func detectFileStyle(perm os.FileMode, content []byte) fileStyle {
+	return fileStyle{
+		perm: perm,
+		bom:  bytes.HasPrefix(content, utf8BOM),
+		crlf: bytes.Contains(content, []byte("\r\n")),
+	}
 }
 
-// applyIndent applies the indentation to the suggestion.
-func applyIndent(content, indent string, start token.Position) string {
-	lines := strings.Split(content, "\n")
-	sugLines := strings.Split(indent, "\n")
-	offset := getOffset(lines, start.Line-1)
-
-	for i := range sugLines {
-		sugLines[i] = strings.Repeat(" ", offset) + sugLines[i]
+// normalize strips the BOM and converts CRLF to LF. Line numbers are unchanged.
+func (s fileStyle) normalize(content []byte) []byte {
+	content = bytes.TrimPrefix(content, utf8BOM)
+	if s.crlf {
+		content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
 	}
+	return content
+}
 
-	for i := 0; i < len(sugLines) && start.Line-1+i < len(lines); i++ {
-		lines[start.Line-1+i] = sugLines[i]
+// restore converts normalized content back to the original style.
+func (s fileStyle) restore(content []byte) []byte {
+	if s.crlf {
+		content = bytes.ReplaceAll(content, []byte("\n"), []byte("\r\n"))
 	}
-
-	return strings.Join(lines, "\n")
+	if s.bom {
+		content = append(append([]byte(nil), utf8BOM...), content...)
+	}
+	return content
 }
 
-// getOffset calculates the offset of the indentation from the first line of the issue.
-func getOffset(lines []string, lineIndex int) int {
-	if lineIndex < 0 || lineIndex >= len(lines) {
+// verify scores the result of applying a fix: 1 when the fixed file still
+// parses, 0 otherwise. The score is multiplied with the confidence of the
+// rule, so that a broken suggestion is never applied whatever the rule claims.
+func verify(filename string, content []byte) float64 {
+	fset := token.NewFileSet()
+	if _, err := parser.ParseFile(fset, filename, content, 0); err != nil {
 		return 0
 	}
-	return len(lines[lineIndex]) - len(strings.TrimLeft(lines[lineIndex], " \t"))
+	return 1
 }
@@ -1,7 +1,10 @@
 package fixer
 
 import (
+	"go/ast"
+	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"testing"
@@ -34,7 +37,7 @@ func main() {
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
 					Start:      token.Position{Line: 5, Column: 5},
-					End:        token.Position{Line: 5, Column: 24},
+					End:        token.Position{Line: 5, Column: 27},
 					Suggestion: "_ = slice[:]",
 					Confidence: 0.9,
 				},
@@ -60,13 +63,39 @@ func main() {
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
 					Start:      token.Position{Line: 5, Column: 5},
-					End:        token.Position{Line: 5, Column: 24},
+					End:        token.Position{Line: 5, Column: 27},
 					Suggestion: "_ = slice[:]",
 					Confidence: 0.3,
 				},
 			},
 			expected: `package main
 
+func main() {
+	slice := []int{1, 2, 3}
+	_ = slice[:len(slice)]
+}
+`,
+		},
+		{
+			name: "Don't Fix - Suggestion breaks the file",
+			input: `package main
+
+func main() {
+    slice := []int{1, 2, 3}
+    _ = slice[:len(slice)]
+}`,
+			issues: []tt.Issue{
+				{
+					Rule:       "simplify-slice-range",
+					Message:    "unnecessary use of len() in slice expression, can be simplified",
+					Start:      token.Position{Line: 5, Column: 5},
+					End:        token.Position{Line: 5, Column: 27},
+					Suggestion: "_ = slice[:",
+					Confidence: 1.0,
+				},
+			},
+			expected: `package main
+
 func main() {
 	slice := []int{1, 2, 3}
 	_ = slice[:len(slice)]
@@ -88,7 +117,7 @@ func main() {
 				{
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
-					Start:      token.Position{Line: 5, Column: 5},
+					Start:      token.Position{Line: 5, Column: 2},
 					End:        token.Position{Line: 5, Column: 26},
 					Suggestion: "_ = slice1[:]",
 					Confidence: 0.9,
@@ -96,7 +125,7 @@ func main() {
 				{
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
-					Start:      token.Position{Line: 8, Column: 5},
+					Start:      token.Position{Line: 8, Column: 2},
 					End:        token.Position{Line: 8, Column: 26},
 					Suggestion: "_ = slice2[:]",
 					Confidence: 0.9,
@@ -111,6 +140,32 @@ func main() {
 	slice2 := []string{"a", "b", "c"}
 	_ = slice2[:]
 }
+`,
+		},
+		{
+			name: "Fix - Multi-line suggestion near the top of the file",
+			input: `package main
+func f() {
+	len := 3
+	println(len)
+	println(len)
+}`,
+			issues: []tt.Issue{
+				{
+					Rule:       "shadowed-predeclared",
+					Start:      token.Position{Line: 3, Column: 2},
+					End:        token.Position{Line: 5, Column: 14},
+					Suggestion: "length := 3\n\tprintln(length)\n\tprintln(length)",
+					Confidence: 0.9,
+				},
+			},
+			expected: `package main
+
+func f() {
+	length := 3
+	println(length)
+	println(length)
+}
 `,
 		},
 		{
@@ -128,7 +183,7 @@ func main() {
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
 					Start:      token.Position{Line: 6, Column: 3},
-					End:        token.Position{Line: 6, Column: 22},
+					End:        token.Position{Line: 6, Column: 25},
 					Suggestion: "_ = slice[:]",
 					Confidence: 0.9,
 				},
@@ -156,7 +211,7 @@ func main() {
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
 					Start:      token.Position{Line: 5, Column: 5},
-					End:        token.Position{Line: 5, Column: 24},
+					End:        token.Position{Line: 5, Column: 27},
 					Suggestion: "_ = slice[:]",
 					Confidence: 0.9,
 				},
@@ -186,7 +241,7 @@ func main() {
 					Rule:    "emit-format",
 					Message: "Consider formatting std.Emit call for better readability",
 					Start:   token.Position{Line: 8, Column: 5},
-					End:     token.Position{Line: 9, Column: 44},
+					End:     token.Position{Line: 9, Column: 45},
 					Suggestion: `std.Emit(
     "OwnershipChange",
     "newOwner", newOwner,
@@ -238,6 +293,97 @@ func runTestCase(t *testing.T, input string, issues []tt.Issue, expected string,
 	assert.Equal(t, expected, string(content))
 }
 
+func TestFixerDryRunSkipsOverlappingEdits(t *testing.T) {
+	t.Parallel()
+	input := `package main
+
+func main() {
+    slice := []int{1, 2, 3}
+    _ = slice[:len(slice)]
+}`
+	for _, dryRun := range []bool{false, true} {
+		_, testFile, cleanup := setupTestFile(t, input)
+		defer cleanup()
+
+		fixer := New(dryRun, confidenceThreshold)
+		err := fixer.Fix(testFile, []tt.Issue{{
+			Rule:       "simplify-slice-range",
+			Filename:   testFile,
+			Start:      token.Position{Line: 5, Column: 5},
+			End:        token.Position{Line: 5, Column: 27},
+			Suggestion: "_ = slice[:]",
+			Confidence: 0.9,
+		}, {
+			Rule:       "simplify-slice-range",
+			Filename:   testFile,
+			Start:      token.Position{Line: 5, Column: 9},
+			End:        token.Position{Line: 5, Column: 27},
+			Suggestion: "slice[:]",
+			Confidence: 0.9,
+		}})
+		require.NoError(t, err)
+		assert.Len(t, fixer.Changes, 1, "dry run: %v", dryRun)
+	}
+}
+
+func TestFixerPolicy(t *testing.T) {
+	t.Parallel()
+	input := `package main
+
+func main() {
+    slice := []int{1, 2, 3}
+    _ = slice[:len(slice)]
+}`
+	_, testFile, cleanup := setupTestFile(t, input)
+	defer cleanup()
+
+	fixer := New(false, confidenceThreshold)
+	fixer.Policy = Policy{Disabled: map[string]bool{"simplify-slice-range": true}}
+	err := fixer.Fix(testFile, []tt.Issue{{
+		Rule:       "simplify-slice-range",
+		Filename:   testFile,
+		Start:      token.Position{Line: 5, Column: 5},
+		End:        token.Position{Line: 5, Column: 24},
+		Suggestion: "_ = slice[:]",
+		Confidence: 0.9,
+	}})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "slice[:len(slice)]", "disabled rule must not be fixed")
+
+	assert.True(t, Policy{}.Allows("any-rule"))
+	assert.False(t, Policy{Allowed: map[string]bool{"a": true}}.Allows("b"))
+}
+
+func TestFixerPreservesFileStyle(t *testing.T) {
+	t.Parallel()
+	input := "\xEF\xBB\xBFpackage main\r\n\r\nfunc main() {\r\n\tslice := []int{1, 2, 3}\r\n\t_ = slice[:len(slice)]\r\n}\r\n"
+	_, testFile, cleanup := setupTestFile(t, input)
+	defer cleanup()
+	require.NoError(t, os.Chmod(testFile, 0o600))
+
+	fixer := New(false, confidenceThreshold)
+	err := fixer.Fix(testFile, []tt.Issue{{
+		Rule:       "simplify-slice-range",
+		Filename:   testFile,
+		Start:      token.Position{Line: 5, Column: 2},
+		End:        token.Position{Line: 5, Column: 24},
+		Suggestion: "_ = slice[:]",
+		Confidence: 0.9,
+	}})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, "\xEF\xBB\xBFpackage main\r\n\r\nfunc main() {\r\n\tslice := []int{1, 2, 3}\r\n\t_ = slice[:]\r\n}\r\n", string(content))
+
+	info, err := os.Stat(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
 func setupTestFile(t *testing.T, content string) (string, string, func()) {
 	t.Helper()
 	tmpDir, err := os.MkdirTemp("", "autofixer-test")
@@ -273,7 +419,7 @@ func main() {
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
 					Start:      token.Position{Line: 5, Column: 5},
-					End:        token.Position{Line: 5, Column: 24},
+					End:        token.Position{Line: 5, Column: 27},
 					Suggestion: "_ = slice[:]",
 					Confidence: 0.9,
 				},
@@ -294,7 +440,7 @@ func main() {
 				{
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
-					Start:      token.Position{Line: 5, Column: 5},
+					Start:      token.Position{Line: 5, Column: 2},
 					End:        token.Position{Line: 5, Column: 26},
 					Suggestion: "_ = slice1[:]",
 					Confidence: 0.9,
@@ -302,7 +448,7 @@ func main() {
 				{
 					Rule:       "simplify-slice-range",
 					Message:    "unnecessary use of len() in slice expression, can be simplified",
-					Start:      token.Position{Line: 8, Column: 5},
+					Start:      token.Position{Line: 8, Column: 2},
 					End:        token.Position{Line: 8, Column: 26},
 					Suggestion: "_ = slice2[:]",
 					Confidence: 0.9,
@@ -339,3 +485,82 @@ func main() {
 		})
 	}
 }
+
+func TestFixUnicodeSources(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "multi-byte identifiers before the range",
+			input:    "package main\n\nfunc main() {\n\tnúmeros := []int{1, 2}\n\t_ = números[:len(números)]\n}\n",
+			expected: "package main\n\nfunc main() {\n\tnúmeros := []int{1, 2}\n\t_ = números[:]\n}\n",
+		},
+		{
+			name:     "several ranges on a line with emoji",
+			input:    "package main\n\nfunc main() {\n\ts := []string{\"🚀\", \"🌕\"}\n\tprintln(\"🚀🚀\", len(s[:len(s)]), len(s[1:len(s)]))\n}\n",
+			expected: "package main\n\nfunc main() {\n\ts := []string{\"🚀\", \"🌕\"}\n\tprintln(\"🚀🚀\", len(s[:]), len(s[1:]))\n}\n",
+		},
+		{
+			name:     "trailing comment kept",
+			input:    "package main\n\nfunc main() {\n\ts := []int{}\n\t_ = s[:len(s)] // 終わり\n}\n",
+			expected: "package main\n\nfunc main() {\n\ts := []int{}\n\t_ = s[:] // 終わり\n}\n",
+		},
+		{
+			name:     "BOM and CRLF with a range on the first line",
+			input:    "\xEF\xBB\xBFpackage main; var s = []int{}; var _ = s[:len(s)]\r\n",
+			expected: "\xEF\xBB\xBFpackage main\r\n\r\nvar s = []int{}\r\nvar _ = s[:]\r\n",
+		},
+		{
+			name:     "spaces and tabs mixed",
+			input:    "package main\n\nfunc main() {\n  \ts := \"ß\"\n\t  _ = s[:len(s)]\n}\n",
+			expected: "package main\n\nfunc main() {\n\ts := \"ß\"\n\t_ = s[:]\n}\n",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, testFile, cleanup := setupTestFile(t, tc.input)
+			defer cleanup()
+
+			fixer := New(false, confidenceThreshold)
+			require.NoError(t, fixer.Fix(testFile, sliceIssues(t, testFile)))
+
+			content, err := os.ReadFile(testFile)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(content))
+		})
+	}
+}
+
+// sliceIssues reports every slice expression of the file ending in len(),
+// with the positions given by the parser.
+func sliceIssues(t *testing.T, filename string) []tt.Issue {
+	t.Helper()
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, filename, nil, 0)
+	require.NoError(t, err)
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		se, ok := n.(*ast.SliceExpr)
+		if !ok || se.High == nil {
+			return true
+		}
+		issues = append(issues, tt.Issue{
+			Rule:       "simplify-slice-range",
+			Filename:   filename,
+			Start:      fset.Position(se.Pos()),
+			End:        fset.Position(se.End()),
+			Suggestion: types.ExprString(&ast.SliceExpr{X: se.X, Low: se.Low}),
+			Confidence: 0.9,
+		})
+		return true
+	})
+	require.NotEmpty(t, issues)
+	return issues
+}
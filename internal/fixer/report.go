@@ -0,0 +1,101 @@
+package fixer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// ContentHash returns the hash identifying a version of a file in JSON reports.
+func ContentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// ReadReport reads a JSON report produced by `tlin -json`, which maps file
+// names to their issues.
+func ReadReport(path string) (map[string][]tt.Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report map[string][]tt.Issue
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid report: %w", err)
+	}
+	for filename, issues := range report {
+		for i := range issues {
+			issues[i].Filename = filename
+			issues[i].Start.Filename = filename
+			issues[i].End.Filename = filename
+		}
+	}
+	return report, nil
+}
+
+// CheckReport verifies that filename still has the content its issues were
+// reported on, so that their positions are still valid.
+func CheckReport(filename string, issues []tt.Issue) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	hash := ContentHash(content)
+	for _, issue := range issues {
+		if issue.FileHash == "" {
+			return fmt.Errorf("the report has no hash for %s", filename)
+		}
+		if issue.FileHash != hash {
+			return fmt.Errorf("%s changed since the report was made", filename)
+		}
+	}
+	return nil
+}
+
+// AttachEdits sets the Fix of the issues of filename carrying a suggestion,
+// with offsets in content, the file as stored. Each edit is verified on its
+// own by parsing the file it produces.
+//
+// Issues whose edit is verified, with a confidence of at least
+// minConfidence, are marked autofixable, and the other issues carrying a
+// suggestion as needing a review.
+func AttachEdits(filename string, content []byte, issues []tt.Issue, minConfidence float64) {
+	lines := lineOffsets(content)
+	crlf := bytes.Contains(content, []byte("\r\n"))
+	for i, issue := range issues {
+		if issue.Suggestion == "" {
+			continue
+		}
+		// positions count the BOM like any other byte, so no style is applied.
+		edit, ok := editFor(content, lines, fileStyle{}, issue)
+		if !ok {
+			issues[i].AddAction(tt.ActionNeedsReview)
+			continue
+		}
+		if crlf {
+			edit.NewText = strings.ReplaceAll(edit.NewText, "\n", "\r\n")
+		}
+		fixed, err := ApplyEdits(content, []Edit{edit})
+		issues[i].Fix = &tt.FixEdit{
+			Filename:   filename,
+			Start:      edit.Start,
+			End:        edit.End,
+			NewText:    edit.NewText,
+			Confidence: issue.Confidence,
+			Verified:   err == nil && verify(filename, fixed) == 1,
+		}
+		if issues[i].Fix.Verified && issue.Confidence > 0 && issue.Confidence >= minConfidence {
+			issues[i].AddAction(tt.ActionAutofixable)
+		} else {
+			issues[i].AddAction(tt.ActionNeedsReview)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package fixer
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReport(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	src := []byte(`package main
+
+func main() {
+	slice := []int{1, 2, 3}
+	_ = slice[:len(slice)]
+}
+`)
+	file := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(file, src, 0o644))
+
+	data, err := json.Marshal(map[string][]tt.Issue{
+		file: {{
+			Rule:       "simplify-slice-range",
+			Start:      token.Position{Offset: 47, Line: 5, Column: 2},
+			End:        token.Position{Offset: 69, Line: 5, Column: 24},
+			Suggestion: "_ = slice[:]",
+			Confidence: 0.9,
+			FileHash:   ContentHash(src),
+		}},
+	})
+	require.NoError(t, err)
+	reportPath := filepath.Join(dir, "report.json")
+	require.NoError(t, os.WriteFile(reportPath, data, 0o644))
+
+	report, err := ReadReport(reportPath)
+	require.NoError(t, err)
+	require.Len(t, report[file], 1)
+	assert.Equal(t, file, report[file][0].Filename)
+	assert.Equal(t, 5, report[file][0].Start.Line)
+
+	require.NoError(t, CheckReport(file, report[file]))
+	require.NoError(t, New(false, confidenceThreshold).Fix(file, report[file]))
+
+	fixed, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(fixed), "_ = slice[:]")
+
+	assert.ErrorContains(t, CheckReport(file, report[file]), "changed since the report was made")
+
+	report[file][0].FileHash = ""
+	assert.ErrorContains(t, CheckReport(file, report[file]), "no hash")
+}
+
+func TestAttachEdits(t *testing.T) {
+	t.Parallel()
+	content := []byte("\xEF\xBB\xBFpackage main\r\n\r\nfunc main() {\r\n\tslice := []int{1, 2, 3}\r\n\t_ = slice[:len(slice)]\r\n}\r\n")
+	at := func(line, column int) token.Position { return token.Position{Line: line, Column: column} }
+	issues := []tt.Issue{
+		{Rule: "simplify-slice-range", Start: at(5, 6), End: at(5, 24), Suggestion: "slice[:]", Confidence: 0.9},
+		{Rule: "broken", Start: at(5, 2), End: at(5, 3), Suggestion: "func {", Confidence: 0.5},
+		{Rule: "no-suggestion", Start: at(1, 4), End: at(1, 11)},
+		{Rule: "bom", Start: at(1, 12), End: at(1, 16), Suggestion: "app"},
+	}
+
+	AttachEdits("main.go", content, issues, 0.75)
+
+	fix := issues[0].Fix
+	require.NotNil(t, fix)
+	assert.Equal(t, "slice[:len(slice)]", string(content[fix.Start:fix.End]))
+	assert.Equal(t, tt.FixEdit{Filename: "main.go", Start: fix.Start, End: fix.End, NewText: "slice[:]", Confidence: 0.9, Verified: true}, *fix)
+
+	require.NotNil(t, issues[1].Fix)
+	assert.False(t, issues[1].Fix.Verified)
+	assert.Nil(t, issues[2].Fix)
+	assert.Equal(t, "main", string(content[issues[3].Fix.Start:issues[3].Fix.End]))
+
+	assert.Equal(t, []tt.Action{tt.ActionAutofixable}, issues[0].Actions)
+	assert.Equal(t, []tt.Action{tt.ActionNeedsReview}, issues[1].Actions)
+	assert.Empty(t, issues[2].Actions)
+	// verified, but no confidence to be applied by -fix.
+	assert.Equal(t, []tt.Action{tt.ActionNeedsReview}, issues[3].Actions)
+
+	d, err := json.Marshal(&issues[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(d), `"fix":{"filename":"main.go","new_text":"slice[:]"`)
+	assert.Contains(t, string(d), `"actions":["autofixable"]`)
+}
@@ -0,0 +1,148 @@
+// Package ignore matches paths against gitignore-style patterns.
+package ignore
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the name of the files holding ignore patterns.
+const FileName = ".tlinignore"
+
+// Matcher holds ignore patterns collected from several directories.
+// As with gitignore, the last matching pattern wins, and a path inside an
+// ignored directory stays ignored whatever the patterns say about it.
+type Matcher struct {
+	patterns []pattern
+}
+
+type pattern struct {
+	base     string   // directory the pattern is relative to
+	segments []string // pattern split on "/"
+	negate   bool
+	dirOnly  bool
+	anchored bool // matches from base rather than at any depth
+}
+
+// New creates an empty matcher.
+func New() *Matcher {
+	return &Matcher{}
+}
+
+// Add adds a pattern relative to the directory base.
+// Blank patterns and comments starting with `#` are skipped.
+func (m *Matcher) Add(base, line string) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	p := pattern{base: filepath.Clean(base)}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return
+	}
+
+	p.segments = strings.Split(line, "/")
+	m.patterns = append(m.patterns, p)
+}
+
+// LoadFile adds the patterns of the ignore file in dir, if any.
+func (m *Matcher) LoadFile(dir string) error {
+	f, err := os.Open(filepath.Join(dir, FileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m.Add(dir, scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// Match reports whether path is ignored. isDir tells whether path is a directory.
+func (m *Matcher) Match(path string, isDir bool) bool {
+	if m == nil || len(m.patterns) == 0 {
+		return false
+	}
+	path = filepath.Clean(path)
+
+	// a path is ignored when itself or one of its parents is.
+	dirs := []string{path}
+	for dir := filepath.Dir(path); dir != path; dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+		path = dir
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if m.matchOne(dirs[i], i > 0 || isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Matcher) matchOne(p string, isDir bool) bool {
+	ignored := false
+	for _, pat := range m.patterns {
+		rel, err := filepath.Rel(pat.base, p)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if pat.dirOnly && !isDir {
+			continue
+		}
+		segments := strings.Split(filepath.ToSlash(rel), "/")
+		if !pat.anchored {
+			// patterns without a slash match the name at any depth.
+			segments = segments[len(segments)-1:]
+		}
+		if matchSegments(pat.segments, segments) {
+			ignored = !pat.negate
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches path segments against pattern segments,
+// where `**` matches any number of segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], segments[0])
+	return err == nil && ok && matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,85 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher(t *testing.T) {
+	t.Parallel()
+	root := filepath.FromSlash("/repo")
+
+	m := New()
+	for _, line := range []string{
+		"# generated code",
+		"",
+		"*.pb.gno",
+		"vendor/",
+		"/build",
+		"docs/**/examples",
+		"testdata/*",
+		"!testdata/keep.gno",
+		`\#hash.gno`,
+	} {
+		m.Add(root, line)
+	}
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{path: "a/b/types.pb.gno", ignored: true},
+		{path: "a/b/types.gno", ignored: false},
+		{path: "vendor", isDir: true, ignored: true},
+		{path: "p/vendor/lib.gno", ignored: true},
+		{path: "vendor", ignored: false}, // dir-only pattern
+		{path: "build/out.gno", ignored: true},
+		{path: "p/build/out.gno", ignored: false}, // anchored at the root
+		{path: "docs/examples/a.gno", ignored: true},
+		{path: "docs/x/y/examples/a.gno", ignored: true},
+		{path: "docs/x/a.gno", ignored: false},
+		{path: "testdata/skip.gno", ignored: true},
+		{path: "testdata/keep.gno", ignored: false},
+		{path: "#hash.gno", ignored: true},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.ignored, m.Match(filepath.Join(root, filepath.FromSlash(tc.path)), tc.isDir), tc.path)
+	}
+
+	assert.False(t, m.Match(filepath.FromSlash("/elsewhere/types.pb.gno"), false), "patterns only apply below their directory")
+}
+
+func TestMatcherIgnoredParent(t *testing.T) {
+	t.Parallel()
+	root := filepath.FromSlash("/repo")
+
+	m := New()
+	m.Add(root, "gen/")
+	m.Add(root, "!gen/keep.gno")
+
+	assert.True(t, m.Match(filepath.Join(root, "gen", "keep.gno"), false), "files of an ignored directory cannot be re-included")
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.MkdirAll(sub, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("*.gen.gno\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(sub, FileName), []byte("local.gno\n!keep.gen.gno\n"), 0o644))
+
+	m := New()
+	require.NoError(t, m.LoadFile(dir))
+	require.NoError(t, m.LoadFile(sub))
+	require.NoError(t, m.LoadFile(filepath.Join(dir, "missing")))
+
+	assert.True(t, m.Match(filepath.Join(dir, "a.gen.gno"), false))
+	assert.True(t, m.Match(filepath.Join(sub, "local.gno"), false))
+	assert.False(t, m.Match(filepath.Join(dir, "local.gno"), false), "sub patterns do not apply to the parent")
+	assert.False(t, m.Match(filepath.Join(sub, "keep.gen.gno"), false))
+}
@@ -0,0 +1,66 @@
+package lints
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectIgnoredAppendResult detects calls to the built-in append function
+// whose result is not assigned back to anything.
+//
+// append may allocate a new backing array, so discarding its result silently
+// drops the appended elements. An explicit `_ = append(...)` is considered
+// intentional and is not reported.
+func DetectIgnoredAppendResult(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	var issues []tt.Issue
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		exprStmt, ok := n.(*ast.ExprStmt)
+		if !ok {
+			return true
+		}
+
+		call, ok := exprStmt.X.(*ast.CallExpr)
+		if !ok || !isBuiltinAppend(call) {
+			return true
+		}
+
+		issue := tt.Issue{
+			Rule:     "append-result-ignored",
+			Filename: filename,
+			Start:    fset.Position(exprStmt.Pos()),
+			End:      fset.Position(exprStmt.End()),
+			Message:  "result of append is not used",
+			Note:     "append may return a new slice. the appended elements are lost unless the result is assigned back.",
+			Severity: severity,
+		}
+
+		// only suggest a fix when the destination is obvious.
+		if len(call.Args) > 0 {
+			if ident, ok := call.Args[0].(*ast.Ident); ok && ident.Name != "_" {
+				var buf bytes.Buffer
+				if err := format.Node(&buf, fset, call); err == nil {
+					issue.Suggestion = ident.Name + " = " + buf.String()
+				}
+			}
+		}
+
+		issues = append(issues, issue)
+		return true
+	})
+
+	return issues, nil
+}
+
+func isBuiltinAppend(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || ident.Name != "append" {
+		return false
+	}
+	// a locally declared function named append shadows the builtin.
+	return ident.Obj == nil
+}
@@ -0,0 +1,96 @@
+package lints
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectIgnoredAppendResult(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		code        string
+		suggestions []string
+	}{
+		{
+			name: "append result assigned",
+			code: `
+package main
+
+func main() {
+	var s []int
+	s = append(s, 1)
+	_ = s
+}`,
+		},
+		{
+			name: "append result explicitly discarded",
+			code: `
+package main
+
+func main() {
+	var s []int
+	_ = append(s, 1)
+}`,
+		},
+		{
+			name: "append result ignored",
+			code: `
+package main
+
+func main() {
+	var s []int
+	append(s, 1, 2)
+}`,
+			suggestions: []string{"s = append(s, 1, 2)"},
+		},
+		{
+			name: "append result ignored with non-identifier destination",
+			code: `
+package main
+
+type T struct{ items []int }
+
+func (t *T) add(v int) {
+	append(t.items, v)
+}`,
+			suggestions: []string{""},
+		},
+		{
+			name: "shadowed append",
+			code: `
+package main
+
+func append(s []int, v int) {}
+
+func main() {
+	var s []int
+	append(s, 1)
+}`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "test.go", tc.code, parser.ParseComments)
+			require.NoError(t, err)
+
+			issues, err := DetectIgnoredAppendResult("test.go", node, fset, tt.SeverityError)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.suggestions))
+
+			for i, issue := range issues {
+				assert.Equal(t, "append-result-ignored", issue.Rule)
+				assert.Equal(t, tc.suggestions[i], issue.Suggestion)
+			}
+		})
+	}
+}
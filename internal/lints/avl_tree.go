@@ -0,0 +1,288 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// AVLMethod describes the behavior of a method of avl.Tree that the
+// avl-tree-misuse rule relies on. Indexes are nil when they do not apply.
+type AVLMethod struct {
+	// Found is the index of the result telling whether the key was found.
+	Found *int `yaml:"found"`
+	// Callback is the index of the argument called for each entry.
+	Callback *int `yaml:"callback"`
+	// Value is the index of the argument stored in the tree.
+	Value *int `yaml:"value"`
+	// Mutates reports whether the method modifies the tree.
+	Mutates bool `yaml:"mutates"`
+}
+
+// AVLModel describes avl.Tree: the import paths of its packages and its
+// methods, by name.
+type AVLModel struct {
+	Packages []string
+	Methods  map[string]AVLMethod
+}
+
+func intPtr(i int) *int { return &i }
+
+// DefaultAVLModel returns the model of the avl.Tree API of gno.land/p/demo/avl.
+func DefaultAVLModel() AVLModel {
+	return AVLModel{
+		Packages: []string{"gno.land/p/demo/avl", "gno.land/p/nt/avl"},
+		Methods: map[string]AVLMethod{
+			"Get":                    {Found: intPtr(1)},
+			"Remove":                 {Found: intPtr(1), Mutates: true},
+			"Set":                    {Value: intPtr(1), Mutates: true},
+			"Iterate":                {Callback: intPtr(2)},
+			"ReverseIterate":         {Callback: intPtr(2)},
+			"IterateByOffset":        {Callback: intPtr(2)},
+			"ReverseIterateByOffset": {Callback: intPtr(2)},
+		},
+	}
+}
+
+// DetectAVLTreeMisuse reports common mistakes with avl.Tree:
+//   - the found result of Get or Remove discarded while the value is used,
+//     which is then nil for missing keys;
+//   - the tree modified from the callback iterating over it;
+//   - in realm files, channels and unsafe pointers stored in a tree, which
+//     cannot be persisted.
+//
+// Trees are recognized by their declared type, avl.Tree or *avl.Tree, or by
+// their initialization with avl.NewTree or an avl.Tree literal, as the avl
+// package itself is usually not available for type checking.
+func DetectAVLTreeMisuse(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, model AVLModel) ([]tt.Issue, error) {
+	avlNames := importNames(node, model.Packages)
+	if len(avlNames) == 0 {
+		return nil, nil
+	}
+	info := packageTypeInfo(filename, node, fset)
+	trees := treeObjects(node, info, avlNames)
+	realm := IsRealmFile(filename)
+
+	// treeCall returns the method of the tree called by expr, if any.
+	treeCall := func(expr ast.Expr) (*ast.CallExpr, *ast.SelectorExpr, AVLMethod, bool) {
+		call, ok := unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return nil, nil, AVLMethod{}, false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil, nil, AVLMethod{}, false
+		}
+		method, ok := model.Methods[sel.Sel.Name]
+		if !ok || !trees[objectOfExpr(sel.X, info)] {
+			return nil, nil, AVLMethod{}, false
+		}
+		return call, sel, method, true
+	}
+
+	var issues []tt.Issue
+	report := func(n ast.Node, message, note string) {
+		issues = append(issues, tt.Issue{
+			Rule:     "avl-tree-misuse",
+			Filename: filename,
+			Start:    fset.Position(n.Pos()),
+			End:      fset.Position(n.End()),
+			Message:  message,
+			Note:     note,
+			Severity: severity,
+		})
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Rhs) != 1 {
+				return true
+			}
+			call, sel, method, ok := treeCall(n.Rhs[0])
+			if !ok || method.Found == nil || *method.Found >= len(n.Lhs) || !isBlank(n.Lhs[*method.Found]) {
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				if i != *method.Found && !isBlank(lhs) {
+					report(call, fmt.Sprintf("the found result of %s is discarded", sel.Sel.Name),
+						"the value is nil when the key is missing. check the found result before using the value.")
+					break
+				}
+			}
+
+		case *ast.CallExpr:
+			_, sel, method, ok := treeCall(n)
+			if !ok {
+				return true
+			}
+			if method.Callback != nil && *method.Callback < len(n.Args) {
+				if lit, ok := n.Args[*method.Callback].(*ast.FuncLit); ok {
+					tree := objectOfExpr(sel.X, info)
+					ast.Inspect(lit.Body, func(inner ast.Node) bool {
+						expr, ok := inner.(ast.Expr)
+						if !ok {
+							return true
+						}
+						mut, mutSel, m, ok := treeCall(expr)
+						if ok && m.Mutates && objectOfExpr(mutSel.X, info) == tree {
+							report(mut, fmt.Sprintf("%s modifies the tree while %s iterates over it", mutSel.Sel.Name, sel.Sel.Name),
+								"modifying a tree during its iteration can skip or revisit entries. collect the keys in the callback and modify the tree after the iteration.")
+						}
+						return true
+					})
+				}
+			}
+			if realm && method.Value != nil && *method.Value < len(n.Args) {
+				if kind := unpersistable(n.Args[*method.Value], info); kind != "" {
+					report(n.Args[*method.Value], fmt.Sprintf("%s stored in an avl.Tree", kind),
+						"realm state is persisted between transactions, and "+kind+"s cannot be. store the data they give access to instead.")
+				}
+			}
+		}
+		return true
+	})
+
+	return issues, nil
+}
+
+// importNames returns the names under which node imports the packages of paths.
+func importNames(node *ast.File, paths []string) map[string]bool {
+	names := make(map[string]bool)
+	for _, imp := range node.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		for _, p := range paths {
+			if path != p {
+				continue
+			}
+			name := path[strings.LastIndex(path, "/")+1:]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// treeObjects returns the variables, parameters and struct fields holding an
+// avl.Tree, avlNames being the names of the avl package in the file.
+func treeObjects(node *ast.File, info *types.Info, avlNames map[string]bool) map[types.Object]bool {
+	isTreeType := func(expr ast.Expr) bool {
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Tree" {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		return ok && avlNames[pkg.Name]
+	}
+	isTreeValue := func(expr ast.Expr) bool {
+		expr = unparen(expr)
+		if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+			expr = u.X
+		}
+		switch e := expr.(type) {
+		case *ast.CompositeLit:
+			return isTreeType(e.Type)
+		case *ast.CallExpr:
+			sel, ok := e.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "NewTree" {
+				return false
+			}
+			pkg, ok := sel.X.(*ast.Ident)
+			return ok && avlNames[pkg.Name]
+		}
+		return false
+	}
+
+	trees := make(map[types.Object]bool)
+	add := func(names []*ast.Ident) {
+		for _, name := range names {
+			if obj := info.Defs[name]; obj != nil {
+				trees[obj] = true
+			}
+		}
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field: // struct fields and parameters
+			if isTreeType(n.Type) {
+				add(n.Names)
+			}
+		case *ast.ValueSpec:
+			if n.Type != nil && isTreeType(n.Type) {
+				add(n.Names)
+				return true
+			}
+			for i, value := range n.Values {
+				if i < len(n.Names) && isTreeValue(value) {
+					add(n.Names[i : i+1])
+				}
+			}
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE || len(n.Lhs) != len(n.Rhs) {
+				return true
+			}
+			for i, rhs := range n.Rhs {
+				if id, ok := n.Lhs[i].(*ast.Ident); ok && isTreeValue(rhs) {
+					add([]*ast.Ident{id})
+				}
+			}
+		}
+		return true
+	})
+	return trees
+}
+
+// objectOfExpr returns the variable or field denoted by expr, an identifier
+// or a field selection, or nil.
+func objectOfExpr(expr ast.Expr, info *types.Info) types.Object {
+	switch e := unparen(expr).(type) {
+	case *ast.Ident:
+		return info.ObjectOf(e)
+	case *ast.SelectorExpr:
+		return info.ObjectOf(e.Sel)
+	}
+	return nil
+}
+
+func isBlank(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// unpersistable returns the kind of value of expr that realm state cannot
+// hold, "channel" or "unsafe pointer", or "" for other values.
+func unpersistable(expr ast.Expr, info *types.Info) string {
+	if call, ok := unparen(expr).(*ast.CallExpr); ok {
+		if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "make" && len(call.Args) > 0 {
+			if _, ok := call.Args[0].(*ast.ChanType); ok {
+				return "channel"
+			}
+		}
+	}
+	tv, ok := info.Types[expr]
+	if !ok || tv.Type == nil {
+		return ""
+	}
+	switch t := tv.Type.Underlying().(type) {
+	case *types.Chan:
+		return "channel"
+	case *types.Basic:
+		if t.Kind() == types.UnsafePointer {
+			return "unsafe pointer"
+		}
+	}
+	return ""
+}
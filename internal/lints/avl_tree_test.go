@@ -0,0 +1,101 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectAVLTreeMisuse(t *testing.T) {
+	t.Parallel()
+	src := `package registry
+
+import (
+	"gno.land/p/demo/avl"
+)
+
+type Registry struct {
+	names *avl.Tree
+}
+
+var (
+	users   avl.Tree
+	reg     = Registry{names: avl.NewTree()}
+	pending = make(chan string)
+)
+
+func Name(addr string) string {
+	v, _ := reg.names.Get(addr) // want "the found result of Get is discarded"
+	return v.(string)
+}
+
+func Exists(addr string) bool {
+	_, found := users.Get(addr)
+	return found
+}
+
+func Drop(addr string) {
+	users.Remove(addr)
+	old, _ := users.Remove(addr) // want "the found result of Remove is discarded"
+	_ = old
+}
+
+func Prune() {
+	users.Iterate("", "", func(key string, value any) bool {
+		if value == nil {
+			users.Remove(key) // want "Remove modifies the tree while Iterate iterates over it"
+		}
+		reg.names.Set(key, value)
+		return false
+	})
+}
+
+func Queue(addr string) {
+	local := avl.NewTree()
+	local.Set(addr, pending) // want "channel stored in an avl.Tree"
+	users.Set(addr, make(chan int)) // want "channel stored in an avl.Tree"
+	users.Set(addr, addr)
+}
+`
+	model := DefaultAVLModel()
+	ruletest.RunFiles(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectAVLTreeMisuse(filename, node, fset, tt.SeverityWarning, model)
+	}, map[string]string{"gno.land/r/demo/registry/registry.gno": src})
+}
+
+func TestDetectAVLTreeMisuseCustomModel(t *testing.T) {
+	t.Parallel()
+	src := `package cache
+
+import (
+	tree "gno.land/p/custom/avl"
+)
+
+var entries = tree.NewTree()
+
+func Lookup(key string) any {
+	v, _, _ := entries.Find(key) // want "the found result of Find is discarded"
+	v2, _ := entries.Get(key)
+	_ = v2
+	return v
+}
+
+func Fill(ch chan int) {
+	entries.Put("ch", ch)
+}
+`
+	model := AVLModel{
+		Packages: []string{"gno.land/p/custom/avl"},
+		Methods: map[string]AVLMethod{
+			"Find": {Found: intPtr(2)},
+			"Put":  {Value: intPtr(1), Mutates: true},
+		},
+	}
+	// not a realm: stored values are not checked.
+	ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectAVLTreeMisuse(filename, node, fset, tt.SeverityWarning, model)
+	}, "cache.gno", src)
+}
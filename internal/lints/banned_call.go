@@ -0,0 +1,47 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/gnolang/tlin/internal/checker"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectBannedCalls reports calls matching the patterns registered in bans.
+// severities holds the severity of each pattern.
+func DetectBannedCalls(
+	filename string,
+	node *ast.File,
+	fset *token.FileSet,
+	bans *checker.BannedCallChecker,
+	severities map[string]tt.Severity,
+) ([]tt.Issue, error) {
+	found, err := bans.Check(node, fset)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]tt.Issue, 0, len(found))
+	for _, call := range found {
+		severity := severities[call.Pattern]
+		if severity == tt.SeverityOff {
+			continue
+		}
+		message := fmt.Sprintf("call to banned function %s.%s", call.Package, call.Function)
+		if call.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, call.Message)
+		}
+		issues = append(issues, tt.Issue{
+			Rule:     "banned-call",
+			Filename: filename,
+			Start:    call.Start,
+			End:      call.End,
+			Message:  message,
+			Severity: severity,
+		})
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,146 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectBusyWaits reports for loops spinning on their conditions: every
+// statement of the loop, as found in the control flow graph, only tests a
+// condition or jumps, without changing any state, calling a function or
+// blocking on a channel. Calls in the conditions, such as l.next() == ' ',
+// count as progress, since the function may advance the state they test;
+// builtins such as len and conversions do not. Such loops never end unless something else changes
+// the conditions, which in a realm cannot happen while the loop runs.
+//
+// Loops with a post statement, such as i++, make progress and are skipped,
+// as are range loops.
+func DetectBusyWaits(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	check := func(g *cfg.CFG) {
+		for _, s := range g.Blocks() {
+			loop, ok := s.(*ast.ForStmt)
+			if !ok || !isBusyWait(g, loop, info) {
+				continue
+			}
+			issues = append(issues, tt.Issue{
+				Rule:     "busy-wait",
+				Filename: filename,
+				Start:    fset.Position(loop.Pos()),
+				End:      fset.Position(loop.Body.Lbrace + 1),
+				Message:  "busy-wait loop: the loop only polls its conditions",
+				Note:     "nothing in the loop changes the conditions it tests, calls a function nor blocks, so it spins until they change elsewhere. in a realm, nothing else runs meanwhile and the transaction runs out of gas. wait on a channel, or make the loop progress towards its end.",
+				Severity: severity,
+			})
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				check(cfg.FromFunc(n))
+			}
+		case *ast.FuncLit:
+			check(cfg.FromStmts(n.Body.List))
+		}
+		return true
+	})
+
+	// the graphs list their blocks in no particular order.
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Start.Offset < issues[j].Start.Offset
+	})
+	return issues, nil
+}
+
+// isBusyWait reports whether every statement of loop only polls.
+func isBusyWait(g *cfg.CFG, loop *ast.ForStmt, info *types.Info) bool {
+	for _, s := range loopStatements(g, loop) {
+		if !isPolling(s, info) {
+			return false
+		}
+	}
+	return true
+}
+
+// loopStatements returns the statements of g on a path from loop back to
+// itself, including loop.
+func loopStatements(g *cfg.CFG, loop ast.Stmt) []ast.Stmt {
+	forward := map[ast.Stmt]bool{loop: true}
+	queue := []ast.Stmt{loop}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, succ := range g.Succs(cur) {
+			if !forward[succ] && succ != g.Exit {
+				forward[succ] = true
+				queue = append(queue, succ)
+			}
+		}
+	}
+
+	members := []ast.Stmt{loop}
+	seen := map[ast.Stmt]bool{loop: true}
+	queue = []ast.Stmt{loop}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, pred := range g.Preds(cur) {
+			if forward[pred] && !seen[pred] {
+				seen[pred] = true
+				members = append(members, pred)
+				queue = append(queue, pred)
+			}
+		}
+	}
+	return members
+}
+
+// isPolling reports whether s only tests conditions or jumps, without
+// receiving from a channel nor calling a function.
+func isPolling(s ast.Stmt, info *types.Info) bool {
+	switch s.(type) {
+	case *ast.IfStmt, *ast.ForStmt, *ast.SwitchStmt, *ast.CaseClause,
+		*ast.BranchStmt, *ast.EmptyStmt, *ast.BlockStmt, *ast.LabeledStmt:
+	default:
+		return false
+	}
+	for _, n := range stmtHeader(s) {
+		if progresses(n, info) {
+			return false
+		}
+	}
+	return true
+}
+
+// progresses reports whether n receives from a channel or calls a function,
+// outside of function literals. Calls of builtins and conversions do not
+// change any state.
+func progresses(n ast.Node, info *types.Info) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				found = true
+			}
+		case *ast.CallExpr:
+			tv := info.Types[unparen(n.Fun)]
+			if !tv.IsType() && !tv.IsBuiltin() {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
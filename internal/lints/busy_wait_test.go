@@ -0,0 +1,119 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectBusyWaits(t *testing.T) {
+	t.Parallel()
+	src := `package auction
+
+import "strings"
+
+var (
+	closed bool
+	bids   []int
+	events chan int
+)
+
+func Spin() {
+	for { // want "busy-wait loop"
+	}
+}
+
+func WaitClosed() {
+	for !closed { // want "busy-wait loop"
+	}
+}
+
+func WaitBids() {
+	for { // want "busy-wait loop"
+		if len(bids) > 3 {
+			break
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+func Retry() {
+	f := func() {
+		for !closed { // want "busy-wait loop"
+			continue
+		}
+	}
+	f()
+}
+
+func Count() int {
+	n := 0
+	for i := 0; i < 10; i++ {
+	}
+	for n < 10 {
+		n++
+	}
+	return n
+}
+
+func Drain() {
+	for {
+		if <-events == 0 {
+			break
+		}
+	}
+	for {
+		select {
+		case <-events:
+			return
+		}
+	}
+}
+
+func Poll() {
+	for !closed {
+		refresh()
+	}
+	for range bids {
+	}
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+func (l *lexer) next() rune {
+	l.pos++
+	return rune(l.input[l.pos-1])
+}
+
+func pop(q *[]int) bool {
+	*q = (*q)[1:]
+	return len(*q) > 0
+}
+
+func Advance(l *lexer, q []int, valid string) {
+	for l.next() == ' ' {
+	}
+	for pop(&q) {
+	}
+	for strings.ContainsRune(valid, l.next()) {
+	}
+	for len(q) > 0 { // want "busy-wait loop"
+	}
+	for int(l.pos) < 3 { // want "busy-wait loop"
+	}
+}
+
+func refresh() {}
+`
+	ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectBusyWaits(filename, node, fset, tt.SeverityWarning)
+	}, "auction.gno", src)
+}
@@ -60,7 +60,6 @@ func DetectConstErrorDeclaration(
 				End:        fset.Position(genDecl.End()),
 				Message:    "avoid declaring constant errors",
 				Suggestion: suggestion,
-				Confidence: 1.0,
 				Severity:   severity,
 			}
 			issues = append(issues, issue)
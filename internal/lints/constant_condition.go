@@ -0,0 +1,344 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	"github.com/gnolang/tlin/internal/analysis/constfold"
+	"github.com/gnolang/tlin/internal/analysis/lattice"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// compoundOps maps the assignment operators to the operators they apply.
+var compoundOps = map[token.Token]token.Token{
+	token.ADD_ASSIGN:     token.ADD,
+	token.SUB_ASSIGN:     token.SUB,
+	token.MUL_ASSIGN:     token.MUL,
+	token.QUO_ASSIGN:     token.QUO,
+	token.REM_ASSIGN:     token.REM,
+	token.AND_ASSIGN:     token.AND,
+	token.OR_ASSIGN:      token.OR,
+	token.XOR_ASSIGN:     token.XOR,
+	token.SHL_ASSIGN:     token.SHL,
+	token.SHR_ASSIGN:     token.SHR,
+	token.AND_NOT_ASSIGN: token.AND_NOT,
+}
+
+// DetectConstantConditions reports if statements whose condition is always
+// true or always false because of the local variables it reads, such as
+// done in
+//
+//	done := false
+//	...
+//	if done {
+//
+// when nothing assigns done in between. The booleans and integers of each
+// function are followed along its control flow graph with the lattice
+// domains. Variables whose address is taken, or that function literals
+// assign, can change behind the graph and are not followed, and conditions
+// that are constants of the language, such as a const debug flag, are left
+// alone.
+func DetectConstantConditions(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	check := func(fn ast.Node, body *ast.BlockStmt, g *cfg.CFG) {
+		f := &flagAnalysis{info: info, vars: trackedLocals(fn, body, info)}
+		if len(f.vars) == 0 {
+			return
+		}
+		d := lattice.EnvDomain[lattice.Value]{Values: lattice.ValueDomain{}}
+		states := lattice.Forward(g, d, d.Top(), f.transfer)
+
+		for stmt, env := range states {
+			ifStmt, ok := stmt.(*ast.IfStmt)
+			if !ok || env == nil {
+				continue
+			}
+			known := f.knownVars(ifStmt.Cond, env)
+			if len(known) == 0 {
+				continue
+			}
+			value, ok := constfold.Bool(constfold.EvalValues(ifStmt.Cond, info, env))
+			if !ok {
+				continue
+			}
+
+			facts := make([]string, len(known))
+			for i, name := range known {
+				facts[i] = name + " is " + describeValue(env[name])
+			}
+			consequence := "its body never runs"
+			switch {
+			case value && ifStmt.Else != nil:
+				consequence = "its else branch never runs"
+			case value:
+				consequence = "the condition can be removed"
+			}
+
+			issues = append(issues, tt.Issue{
+				Rule:     "constant-condition",
+				Filename: filename,
+				Start:    fset.Position(ifStmt.Cond.Pos()),
+				End:      fset.Position(ifStmt.Cond.End()),
+				Message:  fmt.Sprintf("condition %s is always %t", types.ExprString(ifStmt.Cond), value),
+				Note:     fmt.Sprintf("%s on every path reaching the if statement, so %s. assign the variable where it should change, or remove the dead branch.", joinAnd(facts), consequence),
+				Severity: severity,
+			})
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				check(n, n.Body, cfg.FromFunc(n))
+			}
+		case *ast.FuncLit:
+			check(n, n.Body, cfg.FromStmts(n.Body.List))
+		}
+		return true
+	})
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Start.Offset < issues[j].Start.Offset
+	})
+	return issues, nil
+}
+
+// describeValue spells v for the note of an issue: an integer known to the
+// analysis is printed as is, rather than as a singleton interval.
+func describeValue(v lattice.Value) string {
+	if v.Bool == lattice.BoolTop && v.Int.IsConst() {
+		return fmt.Sprint(v.Int.Lo)
+	}
+	return v.String()
+}
+
+// trackedLocals returns the boolean and integer variables declared by body,
+// outside of function literals, that only its own statements can change.
+// The analysis names the variables, so the names declared more than once
+// in fn are left out.
+func trackedLocals(fn ast.Node, body *ast.BlockStmt, info *types.Info) map[*types.Var]bool {
+	declared := make(map[string]int)
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Defs[id] != nil {
+			declared[id.Name]++
+		}
+		return true
+	})
+
+	vars := make(map[*types.Var]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		id, ok := n.(*ast.Ident)
+		if !ok || declared[id.Name] != 1 {
+			return true
+		}
+		v, ok := info.Defs[id].(*types.Var)
+		if !ok {
+			return true
+		}
+		if b, ok := v.Type().Underlying().(*types.Basic); ok && b.Info()&(types.IsBoolean|types.IsInteger) != 0 {
+			vars[v] = true
+		}
+		return true
+	})
+
+	// the address of a variable, taken explicitly or by calling a method
+	// with a pointer receiver, and the function literals can change it
+	// anywhere.
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				if id, ok := unparen(n.X).(*ast.Ident); ok {
+					delete(vars, asVar(info.Uses[id]))
+				}
+			}
+		case *ast.SelectorExpr:
+			if hasPointerReceiver(info.Selections[n]) {
+				if id, ok := unparen(n.X).(*ast.Ident); ok {
+					delete(vars, asVar(info.Uses[id]))
+				}
+			}
+		case *ast.FuncLit:
+			ast.Inspect(n.Body, func(n ast.Node) bool {
+				var lhs []ast.Expr
+				switch n := n.(type) {
+				case *ast.AssignStmt:
+					lhs = n.Lhs
+				case *ast.IncDecStmt:
+					lhs = []ast.Expr{n.X}
+				}
+				for _, e := range lhs {
+					if id, ok := unparen(e).(*ast.Ident); ok {
+						delete(vars, asVar(info.Uses[id]))
+					}
+				}
+				return true
+			})
+		}
+		return true
+	})
+	return vars
+}
+
+// hasPointerReceiver reports whether sel selects a method declared with a
+// pointer receiver.
+func hasPointerReceiver(sel *types.Selection) bool {
+	if sel == nil || sel.Kind() != types.MethodVal {
+		return false
+	}
+	recv := sel.Obj().Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	_, ok := recv.Type().(*types.Pointer)
+	return ok
+}
+
+func asVar(obj types.Object) *types.Var {
+	v, _ := obj.(*types.Var)
+	return v
+}
+
+type flagAnalysis struct {
+	info *types.Info
+	vars map[*types.Var]bool
+}
+
+// tracked returns the variable e names when it is followed.
+func (f *flagAnalysis) tracked(e ast.Expr) (*types.Var, bool) {
+	id, ok := unparen(e).(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	v := asVar(f.info.Defs[id])
+	if v == nil {
+		v = asVar(f.info.Uses[id])
+	}
+	return v, f.vars[v]
+}
+
+func (f *flagAnalysis) transfer(stmt ast.Stmt, in lattice.Env[lattice.Value]) lattice.Env[lattice.Value] {
+	if in == nil {
+		return nil
+	}
+	out := in.Clone()
+	assign := func(lhs, rhs ast.Expr) {
+		v, ok := f.tracked(lhs)
+		if !ok {
+			return
+		}
+		if rhs == nil {
+			delete(out, v.Name())
+			return
+		}
+		if value, ok := valueOf(constfold.EvalValues(rhs, f.info, in)); ok {
+			out[v.Name()] = value
+		} else {
+			delete(out, v.Name())
+		}
+	}
+
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		switch {
+		case len(s.Lhs) == len(s.Rhs) && (s.Tok == token.ASSIGN || s.Tok == token.DEFINE):
+			for i, lhs := range s.Lhs {
+				assign(lhs, s.Rhs[i])
+			}
+		case len(s.Lhs) == 1 && len(s.Rhs) == 1 && compoundOps[s.Tok] != token.ILLEGAL:
+			assign(s.Lhs[0], &ast.BinaryExpr{X: s.Lhs[0], Op: compoundOps[s.Tok], Y: s.Rhs[0]})
+		default:
+			for _, lhs := range s.Lhs {
+				assign(lhs, nil)
+			}
+		}
+	case *ast.IncDecStmt:
+		op := token.ADD
+		if s.Tok == token.DEC {
+			op = token.SUB
+		}
+		assign(s.X, &ast.BinaryExpr{X: s.X, Op: op, Y: &ast.BasicLit{Kind: token.INT, Value: "1"}})
+	case *ast.RangeStmt:
+		assign(s.Key, nil)
+		assign(s.Value, nil)
+	case *ast.DeclStmt:
+		ast.Inspect(s, func(n ast.Node) bool {
+			spec, ok := n.(*ast.ValueSpec)
+			if !ok {
+				return true
+			}
+			for i, name := range spec.Names {
+				switch {
+				case len(spec.Values) == len(spec.Names):
+					assign(name, spec.Values[i])
+				case len(spec.Values) == 0:
+					// the zero value: false or 0.
+					if v, ok := f.tracked(name); ok {
+						out[v.Name()] = zeroValue(v.Type())
+					}
+				default:
+					assign(name, nil)
+				}
+			}
+			return false
+		})
+	}
+	return out
+}
+
+// valueOf converts a boolean or integer constant to its abstract value.
+func valueOf(c constant.Value) (lattice.Value, bool) {
+	if b, ok := constfold.Bool(c); ok {
+		return lattice.BoolValue(b), true
+	}
+	if c.Kind() == constant.Int {
+		if n, ok := constant.Int64Val(c); ok {
+			return lattice.IntValue(lattice.Const(n)), true
+		}
+	}
+	return lattice.Value{}, false
+}
+
+func zeroValue(t types.Type) lattice.Value {
+	if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsBoolean != 0 {
+		return lattice.BoolValue(false)
+	}
+	return lattice.IntValue(lattice.Const(0))
+}
+
+// knownVars returns the names of the followed variables cond reads that
+// have a single possible value in env, in source order.
+func (f *flagAnalysis) knownVars(cond ast.Expr, env lattice.Env[lattice.Value]) []string {
+	var names []string
+	seen := make(map[string]bool)
+	ast.Inspect(cond, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		e, ok := n.(ast.Expr)
+		if !ok {
+			return true
+		}
+		v, ok := f.tracked(e)
+		if !ok || seen[v.Name()] {
+			return true
+		}
+		if value, ok := env[v.Name()]; ok && constfold.IsKnown(constfold.EvalValues(e, f.info, lattice.Env[lattice.Value]{v.Name(): value})) {
+			seen[v.Name()] = true
+			names = append(names, v.Name())
+		}
+		return true
+	})
+	return names
+}
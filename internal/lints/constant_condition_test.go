@@ -0,0 +1,156 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectConstantConditions(t *testing.T) {
+	t.Parallel()
+	src := `package auction
+
+const debug = false
+
+func Close(bids []int) int {
+	done := false
+	best := 0
+	for _, b := range bids {
+		if b > best {
+			best = b
+		}
+	}
+	if done { // want "condition done is always false"
+		return 0
+	}
+	return best
+}
+
+func Retries() int {
+	var attempts int
+	attempts++
+	if attempts > 1 && debug { // want "condition attempts > 1 && debug is always false"
+		println("retrying")
+	}
+	if debug {
+		println("debug")
+	}
+	return attempts
+}
+
+func Loop(items []int) bool {
+	found := false
+	for _, item := range items {
+		if found {
+			break
+		}
+		if item == 0 {
+			found = true
+		}
+	}
+	return found
+}
+
+func Branches(ok bool) {
+	ready := true
+	if ok {
+		ready = false
+	}
+	if ready {
+		println("ready")
+	}
+	checked := true
+	if ok {
+		checked = true
+	}
+	if checked { // want "condition checked is always true"
+		println("checked")
+	} else {
+		println("unchecked")
+	}
+}
+
+func Escapes() {
+	done := false
+	stop := func() { done = true }
+	stop()
+	if done {
+		println("stopped")
+	}
+	set(&done)
+	closed := false
+	if closed { // want "condition closed is always false"
+		println("closed")
+	}
+}
+
+func Shadowed(ok bool) {
+	done := false
+	if ok {
+		done := true
+		println(done)
+	}
+	if done {
+		println("done")
+	}
+}
+
+type Flag bool
+
+func (f *Flag) Set()     { *f = true }
+func (f Flag) Get() bool { return bool(f) }
+
+func Methods() {
+	var f Flag
+	f.Set()
+	if f {
+		println("set")
+	}
+	var g Flag
+	_ = g.Get()
+	if g { // want "condition g is always false"
+		println("get")
+	}
+}
+
+func set(b *bool) { *b = true }
+`
+	issues := ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectConstantConditions(filename, node, fset, tt.SeverityWarning)
+	}, "auction.gno", src)
+	require.Len(t, issues, 5)
+	assert.Equal(t, "done is false on every path reaching the if statement, so its body never runs. assign the variable where it should change, or remove the dead branch.", issues[0].Note)
+	assert.Contains(t, issues[2].Note, "so its else branch never runs")
+}
+
+func TestConstantConditionIntegerNote(t *testing.T) {
+	t.Parallel()
+	src := `package counter
+
+func Count(items []int, ok bool) int {
+	n := 0
+	if n > 0 { // want "condition n > 0 is always false"
+		return n
+	}
+	step := 1
+	if ok {
+		step = 1
+	}
+	if n+step == 1 { // want "condition n \\+ step == 1 is always true"
+		return step
+	}
+	return len(items)
+}
+`
+	issues := ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectConstantConditions(filename, node, fset, tt.SeverityWarning)
+	}, "counter.gno", src)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "n is 0 on every path reaching the if statement, so its body never runs. assign the variable where it should change, or remove the dead branch.", issues[0].Note)
+	assert.Equal(t, "n is 0 and step is 1 on every path reaching the if statement, so the condition can be removed. assign the variable where it should change, or remove the dead branch.", issues[1].Note)
+}
@@ -5,11 +5,16 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"strconv"
 
 	"github.com/fzipp/gocyclo"
+	"github.com/gnolang/tlin/internal/directive"
 	tt "github.com/gnolang/tlin/internal/types"
 )
 
+// DetectHighCyclomaticComplexity reports the functions whose cyclomatic
+// complexity exceeds threshold. A //tlin:max-complexity=N annotation on a
+// function, or before the package clause, overrides the threshold.
 func DetectHighCyclomaticComplexity(filename string, threshold int, severity tt.Severity) ([]tt.Issue, error) {
 	fset := token.NewFileSet()
 	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
@@ -17,6 +22,7 @@ func DetectHighCyclomaticComplexity(filename string, threshold int, severity tt.
 		return nil, err
 	}
 
+	annotations := directive.ParseAnnotations(f, fset)
 	stats := gocyclo.AnalyzeASTFile(f, fset, nil)
 	var issues []tt.Issue
 
@@ -29,18 +35,24 @@ func DetectHighCyclomaticComplexity(filename string, threshold int, severity tt.
 	})
 
 	for _, stat := range stats {
-		if stat.Complexity > threshold {
-			funcNode, ok := funcNodes[stat.FuncName]
-			if !ok {
-				continue
+		funcNode, ok := funcNodes[stat.FuncName]
+		if !ok {
+			continue
+		}
+		limit := threshold
+		if value, ok := annotations.Lookup(funcNode, "max-complexity"); ok {
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				limit = n
 			}
+		}
+		if stat.Complexity > limit {
 
 			issue := tt.Issue{
 				Rule:       "high-cyclomatic-complexity",
 				Filename:   filename,
 				Start:      fset.Position(funcNode.Pos()),
 				End:        fset.Position(funcNode.End()),
-				Message:    fmt.Sprintf("function %s has a cyclomatic complexity of %d (threshold %d)", stat.FuncName, stat.Complexity, threshold),
+				Message:    fmt.Sprintf("function %s has a cyclomatic complexity of %d (threshold %d)", stat.FuncName, stat.Complexity, limit),
 				Suggestion: "consider refactoring this function to reduce its complexity. you can split it into smaller functions or simplify the logic.\n",
 				Note:       "high cyclomatic complexity can make the code harder to understand, test, and maintain. aim for a complexity score of 10 or less for most functions.\n",
 				Severity:   severity,
@@ -0,0 +1,51 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectHighCyclomaticComplexityAnnotations(t *testing.T) {
+	t.Parallel()
+	code := `package main
+
+func branchy(a, b, c int) int {
+	if a > 0 {
+		return 1
+	}
+	if b > 0 {
+		return 2
+	}
+	if c > 0 {
+		return 3
+	}
+	return 0
+}
+
+//tlin:max-complexity=5
+func allowed(a, b, c int) int {
+	if a > 0 {
+		return 1
+	}
+	if b > 0 {
+		return 2
+	}
+	if c > 0 {
+		return 3
+	}
+	return 0
+}
+`
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte(code), 0o644))
+
+	issues, err := DetectHighCyclomaticComplexity(path, 3, types.SeverityWarning)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "function branchy has a cyclomatic complexity of 4 (threshold 3)", issues[0].Message)
+}
@@ -2,7 +2,7 @@ package lints
 
 import (
 	"encoding/json"
-	_ "fmt"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -39,9 +39,23 @@ type golangciOutput struct {
 	} `json:"Issues"`
 }
 
-func RunGolangciLint(filename string, severity tt.Severity) ([]tt.Issue, error) {
-	cmd := exec.Command("golangci-lint", "run", "--config=./.golangci.yml", "--out-format=json", filename)
-	output, _ := cmd.CombinedOutput()
+// LinterMapping overrides the severity and category of the findings of one
+// golangci-lint linter. A nil Severity keeps the severity of the rule.
+type LinterMapping struct {
+	Severity *tt.Severity `yaml:"severity"`
+	Category string       `yaml:"category"`
+}
+
+// RunGolangciLint runs the golangci-lint binary, as returned by
+// CheckGolangciLint, on filename.
+func RunGolangciLint(binary, filename string, severity tt.Severity, mappings map[string]LinterMapping) ([]tt.Issue, error) {
+	cmd := exec.Command(binary, "run", "--config=./.golangci.yml", "--out-format=json", filename)
+	output, err := cmd.CombinedOutput()
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		// golangci-lint exits with a non-zero code when it finds issues,
+		// any other error means it did not run.
+		return nil, fmt.Errorf("running golangci-lint: %w", err)
+	}
 
 	var golangciResult golangciOutput
 
@@ -49,17 +63,33 @@ func RunGolangciLint(filename string, severity tt.Severity) ([]tt.Issue, error)
 	// when source code contains gno package imports (i.e. p/demo, r/demo, std). [07/25/24]
 	json.Unmarshal(output, &golangciResult)
 
-	issues := make([]tt.Issue, 0, len(golangciResult.Issues))
-	for _, gi := range golangciResult.Issues {
-		issues = append(issues, tt.Issue{
+	return convertGolangciIssues(golangciResult, severity, mappings), nil
+}
+
+// convertGolangciIssues turns golangci-lint findings into issues, applying
+// the mapping of their linter. Findings mapped to OFF are dropped.
+func convertGolangciIssues(result golangciOutput, severity tt.Severity, mappings map[string]LinterMapping) []tt.Issue {
+	issues := make([]tt.Issue, 0, len(result.Issues))
+	for _, gi := range result.Issues {
+		issue := tt.Issue{
 			Rule:     gi.FromLinter,
 			Filename: gi.Pos.Filename, // Use the filename from golangci-lint output
 			Start:    token.Position{Filename: gi.Pos.Filename, Line: gi.Pos.Line, Column: gi.Pos.Column},
 			End:      token.Position{Filename: gi.Pos.Filename, Line: gi.Pos.Line, Column: gi.Pos.Column + 1},
 			Message:  gi.Text,
 			Severity: severity,
-		})
+		}
+		if m, ok := mappings[gi.FromLinter]; ok {
+			if m.Severity != nil {
+				issue.Severity = *m.Severity
+			}
+			issue.Category = m.Category
+		}
+		if issue.Severity == tt.SeverityOff {
+			continue
+		}
+		issues = append(issues, issue)
 	}
 
-	return issues, nil
+	return issues
 }
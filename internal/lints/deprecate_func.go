@@ -25,8 +25,17 @@ func DetectDeprecatedFunctions(
 	fset *token.FileSet,
 	severity tt.Severity,
 ) ([]tt.Issue, error) {
-	deprecated := register()
+	return DetectDeprecatedCalls(filename, node, fset, severity, register())
+}
 
+// DetectDeprecatedCalls reports calls to the functions registered in deprecated.
+func DetectDeprecatedCalls(
+	filename string,
+	node *ast.File,
+	fset *token.FileSet,
+	severity tt.Severity,
+	deprecated *checker.DeprecatedFuncChecker,
+) ([]tt.Issue, error) {
 	imports := extractDeprecatedImports(node)
 	if len(imports) == 0 {
 		return nil, nil
@@ -34,7 +43,7 @@ func DetectDeprecatedFunctions(
 
 	hasDeprecatedPackage := false
 	for imp := range imports {
-		if deprecatedPackages[imp] {
+		if deprecated.HasPackage(imp) {
 			hasDeprecatedPackage = true
 			break
 		}
@@ -77,10 +86,6 @@ func createDeprecationMessage(df checker.DeprecatedFunc) string {
 
 type pkgContainsDeprecatedMap map[string]bool
 
-var deprecatedPackages = pkgContainsDeprecatedMap{
-	"std": true,
-}
-
 func extractDeprecatedImports(node *ast.File) pkgContainsDeprecatedMap {
 	return extractImports(node, func(path string) bool {
 		return true
@@ -0,0 +1,158 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+var errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// DetectDiscardedErrors reports errors created with errors.New or Errorf and
+// returned in place of a checked error, as in
+//
+//	if err != nil {
+//		return errors.New("failed")
+//	}
+//
+// where the cause of the failure is lost. The issue is not reported when the
+// body of the if statement uses the error in any way.
+//
+// The suggestion wraps the error with wrapper, ufmt.Errorf in .gno files and
+// fmt.Errorf in .go files when it is empty.
+func DetectDiscardedErrors(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, wrapper string) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+	if wrapper == "" {
+		wrapper = "fmt.Errorf"
+		if strings.HasSuffix(filename, ".gno") {
+			wrapper = "ufmt.Errorf"
+		}
+	}
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok {
+			return true
+		}
+		errVar := checkedError(ifStmt.Cond, info)
+		if errVar == nil || usesObject(ifStmt.Body, errVar, info) {
+			return true
+		}
+
+		for _, stmt := range ifStmt.Body.List {
+			ret, ok := stmt.(*ast.ReturnStmt)
+			if !ok {
+				continue
+			}
+			for _, result := range ret.Results {
+				call, ok := result.(*ast.CallExpr)
+				if !ok || !createsError(call) {
+					continue
+				}
+				issues = append(issues, tt.Issue{
+					Rule:       "discarded-error",
+					Filename:   filename,
+					Start:      fset.Position(call.Pos()),
+					End:        fset.Position(call.End()),
+					Message:    fmt.Sprintf("the new error discards %s, checked by the if statement", errVar.Name()),
+					Suggestion: wrapError(call, errVar.Name(), wrapper),
+					Note:       fmt.Sprintf("wrap %s with %%w to keep the cause of the failure, which callers can still inspect with errors.Is and errors.As.", errVar.Name()),
+					Severity:   severity,
+				})
+			}
+		}
+		return true
+	})
+
+	return issues, nil
+}
+
+// checkedError returns the error variable compared with nil by cond, as in
+// err != nil.
+func checkedError(cond ast.Expr, info *types.Info) types.Object {
+	bin, ok := unparen(cond).(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return nil
+	}
+	x, y := unparen(bin.X), unparen(bin.Y)
+	if isNilIdent(x) {
+		x, y = y, x
+	}
+	id, ok := x.(*ast.Ident)
+	if !ok || !isNilIdent(y) {
+		return nil
+	}
+	obj, ok := info.ObjectOf(id).(*types.Var)
+	if !ok || !types.Implements(obj.Type(), errorInterface) {
+		return nil
+	}
+	return obj
+}
+
+func isNilIdent(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "nil"
+}
+
+func usesObject(n ast.Node, obj types.Object, info *types.Info) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// createsError reports whether call is errors.New or an Errorf function of
+// fmt or ufmt.
+func createsError(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	switch {
+	case pkg.Name == "errors" && sel.Sel.Name == "New":
+		return len(call.Args) == 1
+	case formatPackages[pkg.Name] && sel.Sel.Name == "Errorf":
+		return len(call.Args) >= 1
+	}
+	return false
+}
+
+// wrapError returns call rewritten to wrap errName, or nothing when its
+// message is not a string literal. Errorf calls keep their function, errors.New
+// is replaced with wrapper.
+func wrapError(call *ast.CallExpr, errName, wrapper string) string {
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+
+	fun := types.ExprString(call.Fun)
+	args := make([]string, 0, len(call.Args))
+	for _, arg := range call.Args[1:] {
+		args = append(args, types.ExprString(arg))
+	}
+	if fun == "errors.New" {
+		fun = wrapper
+		format = strings.ReplaceAll(format, "%", "%%")
+	}
+	args = append(args, errName)
+	return fmt.Sprintf("%s(%s, %s)", fun, strconv.Quote(format+": %w"), strings.Join(args, ", "))
+}
@@ -0,0 +1,98 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDiscardedErrors(t *testing.T) {
+	t.Parallel()
+	src := `package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func load(key string) (string, error) { return key, nil }
+
+func Get(key string) (string, error) {
+	v, err := load(key)
+	if err != nil {
+		return "", errors.New("load failed") // want "the new error discards err, checked by the if statement"
+	}
+	if err := check(v); nil != err {
+		return "", fmt.Errorf("invalid value %q", v) // want "the new error discards err"
+	}
+	return v, nil
+}
+
+func Wrapped(key string) error {
+	_, err := load(key)
+	if err != nil {
+		return fmt.Errorf("load %s: %w", key, err)
+	}
+	if err != nil {
+		println(err.Error())
+		return errors.New("load failed")
+	}
+	if err != nil {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func check(v string) error { return nil }
+`
+	ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectDiscardedErrors(filename, node, fset, tt.SeverityWarning, "")
+	}, "store.go", src)
+}
+
+func TestDiscardedErrorSuggestions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		filename string
+		wrapper  string
+		ret      string
+		expected string
+	}{
+		{"errors.New in go", "a.go", "", `errors.New("100% failed")`, `fmt.Errorf("100%% failed: %w", err)`},
+		{"errors.New in gno", "a.gno", "", `errors.New("failed")`, `ufmt.Errorf("failed: %w", err)`},
+		{"custom wrapper", "a.gno", "errs.Wrapf", `errors.New("failed")`, `errs.Wrapf("failed: %w", err)`},
+		{"Errorf keeps its args", "a.gno", "", `ufmt.Errorf("bad %d", n)`, `ufmt.Errorf("bad %d: %w", n, err)`},
+		{"message not a literal", "a.go", "", `errors.New(msg)`, ``},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			src := `package p
+
+import "errors"
+
+func f(n int, msg string, err error) error {
+	if err != nil {
+		return ` + tc.ret + `
+	}
+	return nil
+}
+`
+			node, fset, err := ParseFile(tc.filename, []byte(src))
+			require.NoError(t, err)
+			issues, err := DetectDiscardedErrors(tc.filename, node, fset, tt.SeverityWarning, tc.wrapper)
+			require.NoError(t, err)
+			require.Len(t, issues, 1)
+			assert.Equal(t, tc.expected, issues[0].Suggestion)
+		})
+	}
+}
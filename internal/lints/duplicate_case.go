@@ -0,0 +1,255 @@
+package lints
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectDuplicateCaseBodies reports switch statements where cases have the
+// same body, once formatted, and suggests combining their expressions in the
+// first of them.
+//
+// Combining moves the expressions of later cases before the cases between
+// them, so cases are only combined when they are adjacent, or when all the
+// expressions from the first case to the last are constants, which cannot
+// match twice. Default clauses and switches using fallthrough are left
+// alone, as are type switches, where combining cases changes the type of
+// the switch variable.
+func DetectDuplicateCaseBodies(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		sw, ok := n.(*ast.SwitchStmt)
+		if !ok {
+			return true
+		}
+		groups := duplicateCaseGroups(sw, fset, info)
+		if len(groups) == 0 {
+			return true
+		}
+
+		messages := make([]string, len(groups))
+		for i, group := range groups {
+			labels := make([]string, len(group))
+			for j, cc := range group {
+				labels[j] = caseLabel(cc)
+			}
+			messages[i] = joinAnd(labels) + " have the same body"
+		}
+
+		snippet := extractSnippet(sw, fset, content)
+		suggestion, err := generateCombinedCasesSuggestion(snippet, snippetBase(sw, fset, content), groups, fset)
+		if err != nil {
+			suggestion = ""
+		}
+
+		issues = append(issues, tt.Issue{
+			Rule:       "duplicate-case-body",
+			Filename:   filename,
+			Start:      fset.Position(sw.Pos()),
+			End:        fset.Position(sw.End()),
+			Message:    strings.Join(messages, "; "),
+			Suggestion: suggestion,
+			Note:       "combine the expressions of the cases in a single case clause, so that the body is written, and changed, once.",
+			Severity:   severity,
+		})
+		return true
+	})
+
+	return issues, nil
+}
+
+// duplicateCaseGroups returns the case clauses of sw with the same body that
+// can be combined, by groups in source order.
+func duplicateCaseGroups(sw *ast.SwitchStmt, fset *token.FileSet, info *types.Info) [][]*ast.CaseClause {
+	var clauses []*ast.CaseClause
+	for _, stmt := range sw.Body.List {
+		cc, ok := stmt.(*ast.CaseClause)
+		if !ok {
+			return nil
+		}
+		if fallThrough(cc.Body) {
+			return nil
+		}
+		clauses = append(clauses, cc)
+	}
+
+	indexes := make(map[string][]int)
+	var keys []string
+	for i, cc := range clauses {
+		if cc.List == nil || len(cc.Body) == 0 {
+			continue
+		}
+		key, err := renderStmts(fset, cc.Body)
+		if err != nil {
+			continue
+		}
+		if _, ok := indexes[key]; !ok {
+			keys = append(keys, key)
+		}
+		indexes[key] = append(indexes[key], i)
+	}
+
+	var groups [][]*ast.CaseClause
+	for _, key := range keys {
+		idx := indexes[key]
+		if len(idx) < 2 || !canCombineCases(clauses, idx, info) {
+			continue
+		}
+		group := make([]*ast.CaseClause, len(idx))
+		for i, j := range idx {
+			group[i] = clauses[j]
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// canCombineCases reports whether the clauses at the sorted indexes idx can
+// be combined without changing which case matches.
+func canCombineCases(clauses []*ast.CaseClause, idx []int, info *types.Info) bool {
+	adjacent := true
+	for i := 1; i < len(idx); i++ {
+		if idx[i] != idx[i-1]+1 {
+			adjacent = false
+		}
+	}
+	if adjacent {
+		return true
+	}
+	for _, cc := range clauses[idx[0] : idx[len(idx)-1]+1] {
+		for _, e := range cc.List {
+			if info.Types[e].Value == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fallThrough reports whether body ends with a fallthrough statement.
+func fallThrough(body []ast.Stmt) bool {
+	if len(body) == 0 {
+		return false
+	}
+	br, ok := body[len(body)-1].(*ast.BranchStmt)
+	return ok && br.Tok == token.FALLTHROUGH
+}
+
+func renderStmts(fset *token.FileSet, stmts []ast.Stmt) (string, error) {
+	parts := make([]string, len(stmts))
+	for i, s := range stmts {
+		src, err := renderNode(fset, s)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = src
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+func caseLabel(cc *ast.CaseClause) string {
+	exprs := make([]string, len(cc.List))
+	for i, e := range cc.List {
+		exprs[i] = types.ExprString(e)
+	}
+	return "case " + strings.Join(exprs, ", ")
+}
+
+// joinAnd joins items as "a, b and c".
+func joinAnd(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// snippetBase returns the offset in content of the start of the snippet
+// extractSnippet returns for sw, which begins with what precedes sw on its
+// line, such as a label.
+func snippetBase(sw *ast.SwitchStmt, fset *token.FileSet, content []byte) int {
+	start := fset.Position(sw.Pos()).Offset
+	lineStart := bytes.LastIndexByte(content[:start], '\n') + 1
+	return start - len(bytes.TrimLeft(content[lineStart:start], " \t"))
+}
+
+// lineStart returns the start of the line of s at offset i when only blanks
+// precede i on it, and i otherwise.
+func lineStart(s string, i int) int {
+	start := strings.LastIndexByte(s[:i], '\n') + 1
+	if strings.TrimLeft(s[start:i], " \t") != "" {
+		return i
+	}
+	return start
+}
+
+// lineEnd returns the offset after the end of the line of s at offset i,
+// newline included, when only blanks follow i on it, and i otherwise.
+func lineEnd(s string, i int) int {
+	end := strings.IndexByte(s[i:], '\n')
+	if end < 0 || strings.TrimRight(s[i:i+end], " \t") != "" {
+		return i
+	}
+	return i + end + 1
+}
+
+// generateCombinedCasesSuggestion moves the expressions of the duplicated
+// cases of each group to the first case of the group, and removes the other
+// clauses from snippet, which starts at offset base of the file.
+func generateCombinedCasesSuggestion(snippet string, base int, groups [][]*ast.CaseClause, fset *token.FileSet) (string, error) {
+	type edit struct {
+		start, end int
+		text       string
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset - base
+	}
+
+	var edits []edit
+	for _, group := range groups {
+		var moved []string
+		for _, cc := range group[1:] {
+			moved = append(moved, snippet[offset(cc.List[0].Pos()):offset(cc.List[len(cc.List)-1].End())])
+			edits = append(edits, edit{start: lineStart(snippet, offset(cc.Pos())), end: lineEnd(snippet, offset(cc.End()))})
+		}
+		colon := offset(group[0].Colon)
+		edits = append(edits, edit{start: colon, end: colon, text: ", " + strings.Join(moved, ", ")})
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+
+	src := snippet
+	for _, e := range edits {
+		if e.start < 0 || e.end > len(src) {
+			return "", fmt.Errorf("case outside of the switch")
+		}
+		src = src[:e.start] + e.text + src[e.end:]
+	}
+
+	formatted, err := format.Source([]byte(wrapSnippet(src)))
+	if err != nil {
+		return "", err
+	}
+	body := string(formatted)
+	body = body[strings.Index(body, "func main() ")+len("func main() "):]
+
+	suggestion := cleanUpResult(body)
+	if err := validateRewrite(snippet, suggestion); err != nil {
+		return "", err
+	}
+	return suggestion, nil
+}
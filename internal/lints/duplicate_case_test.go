@@ -0,0 +1,130 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDuplicateCaseBodies(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		code       string
+		messages   []string
+		suggestion string
+	}{
+		{
+			name: "constant cases",
+			code: `package game
+
+func Score(c int) int {
+	switch c {
+	case 1:
+		return 10
+	case 2:
+		return 20
+	case 3, 4:
+		return 10
+	case 5:
+		return  20
+	default:
+		return 10
+	}
+}
+`,
+			messages: []string{"case 1 and case 3, 4 have the same body; case 2 and case 5 have the same body"},
+			suggestion: `switch c {
+case 1, 3, 4:
+	return 10
+case 2, 5:
+	return 20
+default:
+	return 10
+}`,
+		},
+		{
+			name: "adjacent conditions",
+			code: `package game
+
+func Sign(x int) string {
+	s := ""
+	switch {
+	case x < 0:
+		s = "negative"
+	case x > 100:
+		s = "negative"
+	case x == 0:
+		s = "zero"
+	}
+	return s
+}
+`,
+			messages: []string{"case x < 0 and case x > 100 have the same body"},
+			suggestion: `switch {
+case x < 0, x > 100:
+	s = "negative"
+case x == 0:
+	s = "zero"
+}`,
+		},
+		{
+			name: "skipped switches",
+			code: `package game
+
+func Skipped(x, y int, v interface{}) {
+	switch {
+	case x < 0:
+		println(x)
+	case y < 0:
+		println(y)
+	case x > 10:
+		println(x)
+	}
+	switch x {
+	case 1:
+		println(x)
+		fallthrough
+	case 2:
+		println(x)
+	}
+	switch x {
+	case 1:
+	case 2:
+	}
+	switch v.(type) {
+	case int:
+		println(v)
+	case string:
+		println(v)
+	}
+}
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "game.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectDuplicateCaseBodies(path, node, fset, tt.SeverityInfo)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+			for i, issue := range issues {
+				assert.Equal(t, "duplicate-case-body", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				assert.Equal(t, tc.suggestion, issue.Suggestion)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package lints
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// minEarlyContinueStatements is the number of statements an if body must have
+// before inverting it into an early continue is worth the churn.
+const minEarlyContinueStatements = 3
+
+// DetectEarlyContinueOpportunities detects loops whose body is a single if statement
+// without else. Inverting the condition and continuing early removes a level of nesting.
+func DetectEarlyContinueOpportunities(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	var issues []tt.Issue
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.ForStmt:
+			body = loop.Body
+		case *ast.RangeStmt:
+			body = loop.Body
+		default:
+			return true
+		}
+		if earlyContinueCandidate(body) == nil {
+			return true
+		}
+
+		// report the loop even when no valid suggestion can be made.
+		suggestion, err := generateEarlyContinueSuggestion(extractSnippet(n, fset, content))
+		if err != nil {
+			suggestion = ""
+		}
+
+		issues = append(issues, tt.Issue{
+			Rule:       "early-continue",
+			Filename:   filename,
+			Start:      fset.Position(n.Pos()),
+			End:        fset.Position(n.End()),
+			Message:    "this loop body can be flattened using an early continue",
+			Suggestion: suggestion,
+			Note:       "invert the condition and continue early to remove a level of nesting from the loop body.",
+			Severity:   severity,
+		})
+		return true
+	})
+
+	return issues, nil
+}
+
+// earlyContinueCandidate returns the if statement making up the whole loop body,
+// or nil if the body cannot be flattened.
+func earlyContinueCandidate(body *ast.BlockStmt) *ast.IfStmt {
+	if body == nil || len(body.List) != 1 {
+		return nil
+	}
+	ifStmt, ok := body.List[0].(*ast.IfStmt)
+	if !ok || ifStmt.Else != nil || ifStmt.Init != nil {
+		return nil
+	}
+	if len(ifStmt.Body.List) < minEarlyContinueStatements {
+		return nil
+	}
+	return ifStmt
+}
+
+// generateEarlyContinueSuggestion rewrites the loop in snippet to continue early.
+func generateEarlyContinueSuggestion(snippet string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", wrapSnippet(snippet), parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	var funcBody *ast.BlockStmt
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok {
+			funcBody = fd.Body
+			break
+		}
+	}
+
+	rewritten := false
+	ast.Inspect(funcBody, func(n ast.Node) bool {
+		if rewritten {
+			return false
+		}
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.ForStmt:
+			body = loop.Body
+		case *ast.RangeStmt:
+			body = loop.Body
+		default:
+			return true
+		}
+		ifStmt := earlyContinueCandidate(body)
+		if ifStmt == nil {
+			return true
+		}
+
+		guard := &ast.IfStmt{
+			If:   ifStmt.If,
+			Cond: negateCondition(ifStmt.Cond),
+			Body: &ast.BlockStmt{
+				Lbrace: ifStmt.Body.Lbrace,
+				List:   []ast.Stmt{&ast.BranchStmt{TokPos: ifStmt.Body.Lbrace, Tok: token.CONTINUE}},
+				Rbrace: ifStmt.Body.Lbrace,
+			},
+		}
+		body.List = append([]ast.Stmt{guard}, ifStmt.Body.List...)
+		// close the loop where the if body was closed, so that no blank line is left behind.
+		body.Rbrace = ifStmt.Body.Rbrace
+		rewritten = true
+		return false
+	})
+
+	var buf strings.Builder
+	err = format.Node(&buf, fset, &printer.CommentedNode{Node: funcBody, Comments: file.Comments})
+	if err != nil {
+		return "", err
+	}
+
+	suggestion := cleanUpResult(buf.String())
+	if err := validateRewrite(snippet, suggestion); err != nil {
+		return "", err
+	}
+	return suggestion, nil
+}
+
+// negateCondition returns the logical negation of cond.
+// Only equality operators are inverted: `!(a < b)` and `a >= b` differ for NaN.
+func negateCondition(cond ast.Expr) ast.Expr {
+	switch c := cond.(type) {
+	case *ast.ParenExpr:
+		return negateCondition(c.X)
+	case *ast.UnaryExpr:
+		if c.Op == token.NOT {
+			return c.X
+		}
+	case *ast.BinaryExpr:
+		switch c.Op {
+		case token.EQL:
+			return &ast.BinaryExpr{X: c.X, OpPos: c.OpPos, Op: token.NEQ, Y: c.Y}
+		case token.NEQ:
+			return &ast.BinaryExpr{X: c.X, OpPos: c.OpPos, Op: token.EQL, Y: c.Y}
+		}
+		return &ast.UnaryExpr{OpPos: c.Pos(), Op: token.NOT, X: &ast.ParenExpr{Lparen: c.Pos(), X: c, Rparen: c.End()}}
+	case *ast.Ident, *ast.CallExpr, *ast.SelectorExpr, *ast.IndexExpr:
+		return &ast.UnaryExpr{OpPos: c.Pos(), Op: token.NOT, X: c}
+	}
+	return &ast.UnaryExpr{OpPos: cond.Pos(), Op: token.NOT, X: &ast.ParenExpr{Lparen: cond.Pos(), X: cond, Rparen: cond.End()}}
+}
@@ -0,0 +1,179 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectEarlyContinueOpportunities(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		code       string
+		expected   int
+		suggestion string
+	}{
+		{
+			name: "range body wrapped in a single if",
+			code: `package main
+
+func main() {
+	for _, item := range items {
+		// only active items
+		if item.Active {
+			a := item.A
+			b := item.B
+			process(a, b)
+		}
+	}
+}`,
+			expected: 1,
+			suggestion: `for _, item := range items {
+	// only active items
+	if !item.Active {
+		continue
+	}
+	a := item.A
+	b := item.B
+	process(a, b)
+}`,
+		},
+		{
+			name: "equality is inverted",
+			code: `package main
+
+func main() {
+	for i := 0; i < n; i++ {
+		if err == nil {
+			step1()
+			step2()
+			step3()
+		}
+	}
+}`,
+			expected: 1,
+			suggestion: `for i := 0; i < n; i++ {
+	if err != nil {
+		continue
+	}
+	step1()
+	step2()
+	step3()
+}`,
+		},
+		{
+			name: "ordering comparisons are negated as a whole",
+			code: `package main
+
+func main() {
+	for _, x := range xs {
+		if x > 0.5 {
+			step1()
+			step2()
+			step3()
+		}
+	}
+}`,
+			expected: 1,
+			suggestion: `for _, x := range xs {
+	if !(x > 0.5) {
+		continue
+	}
+	step1()
+	step2()
+	step3()
+}`,
+		},
+		{
+			name: "small body",
+			code: `package main
+
+func main() {
+	for _, x := range xs {
+		if x > 0 {
+			println(x)
+		}
+	}
+}`,
+			expected: 0,
+		},
+		{
+			name: "if with else",
+			code: `package main
+
+func main() {
+	for _, x := range xs {
+		if x > 0 {
+			step1()
+			step2()
+			step3()
+		} else {
+			step4()
+		}
+	}
+}`,
+			expected: 0,
+		},
+		{
+			name: "if with init",
+			code: `package main
+
+func main() {
+	for _, x := range xs {
+		if y := f(x); y > 0 {
+			step1()
+			step2()
+			step3()
+		}
+	}
+}`,
+			expected: 0,
+		},
+		{
+			name: "other statements in the loop",
+			code: `package main
+
+func main() {
+	for _, x := range xs {
+		println(x)
+		if x > 0 {
+			step1()
+			step2()
+			step3()
+		}
+	}
+}`,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tmpDir, err := os.MkdirTemp("", "lint-test")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpDir)
+
+			tmpfile := filepath.Join(tmpDir, "test.go")
+			require.NoError(t, os.WriteFile(tmpfile, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(tmpfile, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectEarlyContinueOpportunities(tmpfile, node, fset, types.SeverityInfo)
+			require.NoError(t, err)
+			require.Len(t, issues, tc.expected)
+
+			if tc.expected > 0 {
+				assert.Equal(t, "early-continue", issues[0].Rule)
+				assert.Equal(t, tc.suggestion, issues[0].Suggestion)
+			}
+		})
+	}
+}
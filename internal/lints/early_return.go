@@ -2,9 +2,11 @@ package lints
 
 import (
 	"bytes"
+	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
+	"go/printer"
 	"go/token"
 	"os"
 	"strings"
@@ -24,6 +26,8 @@ func DetectEarlyReturnOpportunities(filename string, node *ast.File, fset *token
 		return nil, err
 	}
 
+	elseIfs := elseIfStmts(node)
+
 	var inspectNode func(n ast.Node) bool
 	inspectNode = func(n ast.Node) bool {
 		ifStmt, ok := n.(*ast.IfStmt)
@@ -31,13 +35,11 @@ func DetectEarlyReturnOpportunities(filename string, node *ast.File, fset *token
 			return true
 		}
 
-		chain := analyzeIfElseChain(ifStmt)
-		if canUseEarlyReturn(chain) {
-			snippet := extractSnippet(ifStmt, fset, content)
-
-			suggestion, err := generateEarlyReturnSuggestion(snippet)
+		if earlyReturnPrecondition(ifStmt, elseIfs) == "" {
+			// report the chain even when no valid suggestion can be made.
+			suggestion, err := generateEarlyReturnSuggestion(extractSnippet(ifStmt, fset, content))
 			if err != nil {
-				return false
+				suggestion = ""
 			}
 
 			issue := tt.Issue{
@@ -47,7 +49,6 @@ func DetectEarlyReturnOpportunities(filename string, node *ast.File, fset *token
 				End:        fset.Position(ifStmt.End()),
 				Message:    "this if-else chain can be simplified using early returns",
 				Suggestion: suggestion,
-				Confidence: 0.8,
 				Severity:   severity,
 			}
 			issues = append(issues, issue)
@@ -72,6 +73,62 @@ func DetectEarlyReturnOpportunities(filename string, node *ast.File, fset *token
 	return issues, nil
 }
 
+// ExplainEarlyReturn returns, for each if statement starting on line,
+// the reason why DetectEarlyReturnOpportunities does not report it.
+func ExplainEarlyReturn(filename string, node *ast.File, fset *token.FileSet, line int) ([]string, error) {
+	elseIfs := elseIfStmts(node)
+
+	var reasons []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok || fset.Position(ifStmt.Pos()).Line != line {
+			return true
+		}
+		if reason := earlyReturnPrecondition(ifStmt, elseIfs); reason != "" {
+			reasons = append(reasons, reason)
+		}
+		return true
+	})
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no if statement starts on this line")
+	}
+	return reasons, nil
+}
+
+// earlyReturnPrecondition returns the reason why the if-else chain starting
+// at ifStmt cannot be simplified, or "" if it can.
+func earlyReturnPrecondition(ifStmt *ast.IfStmt, elseIfs map[*ast.IfStmt]bool) string {
+	if elseIfs[ifStmt] {
+		return "the if statement is an else-if branch, only the head of the chain can be simplified"
+	}
+	chain := analyzeIfElseChain(ifStmt)
+	if chain.Else.BranchKind.IsEmpty() {
+		return "the if statement has no else branch"
+	}
+	if chain.If.BranchKind.IsEmpty() {
+		return "the if body is empty"
+	}
+	if !canUseEarlyReturn(chain) {
+		return "the if body does not terminate with return, break, continue, goto, panic or os.Exit"
+	}
+	return ""
+}
+
+// elseIfStmts collects the if statements used as the else branch of another one.
+func elseIfStmts(node ast.Node) map[*ast.IfStmt]bool {
+	elseIfs := make(map[*ast.IfStmt]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ifStmt, ok := n.(*ast.IfStmt); ok {
+			if elseIf, ok := ifStmt.Else.(*ast.IfStmt); ok {
+				elseIfs[elseIf] = true
+			}
+		}
+		return true
+	})
+	return elseIfs
+}
+
 func analyzeIfElseChain(ifStmt *ast.IfStmt) branch.Chain {
 	chain := branch.Chain{
 		If:   branch.BlockBranch(ifStmt.Body),
@@ -96,7 +153,7 @@ func canUseEarlyReturn(chain branch.Chain) bool {
 }
 
 func RemoveUnnecessaryElse(snippet string) (string, error) {
-	wrappedSnippet := "package main\nfunc main() {\n" + snippet + "\n}"
+	wrappedSnippet := wrapSnippet(snippet)
 
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, "", wrappedSnippet, parser.ParseComments)
@@ -115,8 +172,9 @@ func RemoveUnnecessaryElse(snippet string) (string, error) {
 
 	removeUnnecessaryElseAndEarlyReturnRecursive(funcBody)
 
+	// print the comments of the snippet along with the rewritten body.
 	var buf strings.Builder
-	err = format.Node(&buf, fset, funcBody)
+	err = format.Node(&buf, fset, &printer.CommentedNode{Node: funcBody, Comments: file.Comments})
 	if err != nil {
 		return "", err
 	}
@@ -250,5 +308,53 @@ func generateEarlyReturnSuggestion(snippet string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if err := validateRewrite(snippet, improved); err != nil {
+		return "", err
+	}
 	return improved, nil
 }
+
+// validateRewrite checks that the suggestion is valid, gofmt-clean code
+// and that it keeps every comment of the original snippet.
+func validateRewrite(snippet, suggestion string) error {
+	original, err := snippetComments(snippet)
+	if err != nil {
+		return err
+	}
+	improved, err := snippetComments(suggestion)
+	if err != nil {
+		return fmt.Errorf("invalid suggestion: %w", err)
+	}
+
+	wrapped := wrapSnippet(suggestion)
+	if _, err := format.Source([]byte(wrapped)); err != nil {
+		return fmt.Errorf("suggestion cannot be formatted: %w", err)
+	}
+
+	for c, n := range original {
+		if improved[c] < n {
+			return fmt.Errorf("suggestion drops comment %q", c)
+		}
+	}
+	return nil
+}
+
+// snippetComments parses a statement list and counts its comments.
+func snippetComments(snippet string) (map[string]int, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", wrapSnippet(snippet), parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	comments := make(map[string]int)
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			comments[c.Text]++
+		}
+	}
+	return comments, nil
+}
+
+func wrapSnippet(snippet string) string {
+	return "package main\nfunc main() {\n" + snippet + "\n}"
+}
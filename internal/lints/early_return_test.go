@@ -202,6 +202,22 @@ func TestRemoveUnnecessaryElse(t *testing.T) {
 	return 1
 }
 return 2`,
+		},
+		{
+			name: "keep comments",
+			input: `if x {
+	// leave early
+	return 1
+} else {
+	// compute
+	return 2 // two
+}`,
+			expected: `if x {
+	// leave early
+	return 1
+}
+// compute
+return 2 // two`,
 		},
 		{
 			name: "nested if else",
@@ -243,3 +259,17 @@ if z {
 		})
 	}
 }
+
+func TestValidateEarlyReturnSuggestion(t *testing.T) {
+	t.Parallel()
+	snippet := `if x {
+	return 1
+} else {
+	// compute
+	return 2
+}`
+
+	assert.NoError(t, validateRewrite(snippet, "if x {\n\treturn 1\n}\n// compute\nreturn 2"))
+	assert.Error(t, validateRewrite(snippet, "if x {\n\treturn 1\n}\nreturn 2"), "dropped comment")
+	assert.Error(t, validateRewrite(snippet, "if x {\n\treturn 1\n// compute\nreturn 2"), "invalid code")
+}
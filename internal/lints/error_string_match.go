@@ -0,0 +1,264 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/analysis/constfold"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// messageMatchers are the functions of the strings package that match the
+// message of an error passed as their first argument, with how they match.
+var messageMatchers = map[string]func(message, s string) bool{
+	"Contains":  strings.Contains,
+	"HasPrefix": strings.HasPrefix,
+	"HasSuffix": strings.HasSuffix,
+	"EqualFold": strings.EqualFold,
+}
+
+// errorMatch is a test of the message of an error against a string constant.
+type errorMatch struct {
+	expr  ast.Expr
+	err   ast.Expr
+	text  string
+	match func(message, s string) bool
+}
+
+// DetectErrorStringMatches reports errors told apart by their message, as in
+//
+//	if err.Error() == "not found" {
+//	if strings.Contains(err.Error(), "not found") {
+//
+// which breaks as soon as the message is reworded or wrapped. Sentinel errors
+// compared with errors.Is, or error types matched with errors.As, do not.
+//
+// When err comes from a call to a function of the file, the errors created
+// by that function and the ones it calls, following the call graph of the
+// file, or by the variables of the file, are searched for the matched
+// message, and the first one found is given as a related location.
+func DetectErrorStringMatches(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+	stringsPkg := importName(node, "strings")
+	g := buildCallGraph(node)
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			m, ok := matchErrorString(n, stringsPkg, info)
+			if !ok {
+				return true
+			}
+
+			errName := types.ExprString(m.err)
+			note := "error messages are meant for people and change freely. declare a sentinel error and compare with errors.Is, or define an error type and match it with errors.As."
+			issue := tt.Issue{
+				Rule:     "error-string-match",
+				Filename: filename,
+				Start:    fset.Position(m.expr.Pos()),
+				End:      fset.Position(m.expr.End()),
+				Message:  fmt.Sprintf("%s is told apart by its message %q", errName, m.text),
+				Note:     note,
+				Severity: severity,
+			}
+
+			if created, sentinel := errorOrigin(node, g, fn, m, info); created != nil {
+				issue.RelatedLocations = []tt.Location{{
+					Filename: filename,
+					Start:    fset.Position(created.Pos()),
+					End:      fset.Position(created.End()),
+					Message:  "the error is created here",
+				}}
+				if sentinel != "" {
+					issue.Note = fmt.Sprintf("error messages are meant for people and change freely. compare with errors.Is(%s, %s) instead.", errName, sentinel)
+				}
+			}
+			issues = append(issues, issue)
+			return true
+		})
+	}
+
+	return issues, nil
+}
+
+// matchErrorString returns the test of n on the message of an error: a
+// comparison of err.Error() with a string constant, or a call to one of the
+// messageMatchers with err.Error() as first argument.
+func matchErrorString(n ast.Node, stringsPkg string, info *types.Info) (errorMatch, bool) {
+	switch n := n.(type) {
+	case *ast.BinaryExpr:
+		if n.Op != token.EQL && n.Op != token.NEQ {
+			return errorMatch{}, false
+		}
+		x, y := n.X, n.Y
+		if errorMessage(y, info) != nil {
+			x, y = y, x
+		}
+		err := errorMessage(x, info)
+		text, ok := stringConstant(y, info)
+		if err == nil || !ok {
+			return errorMatch{}, false
+		}
+		return errorMatch{expr: n, err: err, text: text, match: func(message, s string) bool { return message == s }}, true
+
+	case *ast.CallExpr:
+		sel, ok := n.Fun.(*ast.SelectorExpr)
+		if !ok || stringsPkg == "" || !isIdent(sel.X, stringsPkg) || len(n.Args) != 2 {
+			return errorMatch{}, false
+		}
+		match, ok := messageMatchers[sel.Sel.Name]
+		if !ok {
+			return errorMatch{}, false
+		}
+		err := errorMessage(n.Args[0], info)
+		text, ok := stringConstant(n.Args[1], info)
+		if err == nil || !ok {
+			return errorMatch{}, false
+		}
+		return errorMatch{expr: n, err: err, text: text, match: match}, true
+	}
+	return errorMatch{}, false
+}
+
+// errorMessage returns err when e is err.Error() on an error.
+func errorMessage(e ast.Expr, info *types.Info) ast.Expr {
+	call, ok := unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" {
+		return nil
+	}
+	t := info.TypeOf(sel.X)
+	if t == nil || !types.Implements(t, errorInterface) {
+		return nil
+	}
+	return sel.X
+}
+
+func stringConstant(e ast.Expr, info *types.Info) (string, bool) {
+	v := constfold.Eval(e, info, nil)
+	if v.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(v), true
+}
+
+// errorOrigin returns the creation of an error whose message m matches, and
+// the name of the variable holding it when it is a sentinel error.
+//
+// The variables of the file are searched first, then, when the matched
+// error is assigned from a call to a function of the file, the functions
+// reachable from it in g.
+func errorOrigin(node *ast.File, g *callGraph, fn *ast.FuncDecl, m errorMatch, info *types.Info) (*ast.CallExpr, string) {
+	for _, decl := range node.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, value := range vs.Values {
+				if call, ok := unparen(value).(*ast.CallExpr); ok && i < len(vs.Names) && createsMatchingError(call, m) {
+					return call, vs.Names[i].Name
+				}
+			}
+		}
+	}
+
+	call := assignedCall(fn, m, info)
+	if call == nil {
+		return nil, ""
+	}
+	callee := calleeKey(fn, call)
+	if g.decls[callee] == nil {
+		return nil, ""
+	}
+	reachable := g.reachable(callee)
+	reachable[callee] = true
+
+	var created *ast.CallExpr
+	for _, name := range g.names {
+		if !reachable[name] {
+			continue
+		}
+		inspectCalls(g.decls[name].Body, func(call *ast.CallExpr) {
+			if created == nil && createsMatchingError(call, m) {
+				created = call
+			}
+		})
+		if created != nil {
+			return created, ""
+		}
+	}
+	return nil, ""
+}
+
+// createsMatchingError reports whether call creates an error with a message
+// m matches. The verbs of Errorf formats are kept as they are, so only the
+// formats without verbs are compared for equality.
+func createsMatchingError(call *ast.CallExpr, m errorMatch) bool {
+	if !createsError(call) {
+		return false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+	message, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return false
+	}
+	if len(call.Args) > 1 && strings.Contains(message, "%") {
+		_, exact := m.expr.(*ast.BinaryExpr)
+		if exact {
+			return false
+		}
+	}
+	return m.match(message, m.text)
+}
+
+// assignedCall returns the call whose result is last assigned, before it is
+// matched, to the error variable of m.
+func assignedCall(fn *ast.FuncDecl, m errorMatch, info *types.Info) *ast.CallExpr {
+	id, ok := unparen(m.err).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	obj := info.ObjectOf(id)
+	if obj == nil {
+		return nil
+	}
+
+	var call *ast.CallExpr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if n == nil || n.Pos() >= m.expr.Pos() {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 {
+			return true
+		}
+		rhs, ok := unparen(assign.Rhs[0]).(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		for _, lhs := range assign.Lhs {
+			if lid, ok := lhs.(*ast.Ident); ok && info.ObjectOf(lid) == obj {
+				call = rhs
+			}
+		}
+		return true
+	})
+	return call
+}
@@ -0,0 +1,81 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectErrorStringMatches(t *testing.T) {
+	t.Parallel()
+	src := `package market
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrSoldOut = errors.New("sold out")
+
+const unknownItem = "unknown item"
+
+func find(id string) error {
+	if id == "" {
+		return fmt.Errorf("item %q: unknown item", id)
+	}
+	return nil
+}
+
+func reserve(id string) error {
+	if err := find(id); err != nil {
+		return err
+	}
+	return ErrSoldOut
+}
+
+func Buy(id string) string {
+	err := reserve(id)
+	if err.Error() == "sold out" { // want "err is told apart by its message \"sold out\""
+		return "later"
+	}
+	if strings.Contains(err.Error(), unknownItem) { // want "err is told apart by its message \"unknown item\""
+		return "missing"
+	}
+	if "timeout" != err.Error() { // want "told apart by its message \"timeout\""
+		return "failed"
+	}
+	if errors.Is(err, ErrSoldOut) || err.Error() == strings.ToUpper("x") {
+		return "later"
+	}
+	return strings.TrimSpace(err.Error())
+}
+
+type code string
+
+func (c code) Error() string { return string(c) }
+
+func Codes(c code) bool {
+	return c.Error() == "E1" // want "c is told apart by its message \"E1\""
+}
+`
+	issues := ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectErrorStringMatches(filename, node, fset, tt.SeverityWarning)
+	}, "market.go", src)
+	require.Len(t, issues, 4)
+
+	require.Len(t, issues[0].RelatedLocations, 1)
+	assert.Equal(t, 9, issues[0].RelatedLocations[0].Start.Line)
+	assert.Contains(t, issues[0].Note, "compare with errors.Is(err, ErrSoldOut) instead")
+
+	require.Len(t, issues[1].RelatedLocations, 1)
+	assert.Equal(t, 15, issues[1].RelatedLocations[0].Start.Line)
+
+	assert.Empty(t, issues[2].RelatedLocations)
+	assert.Empty(t, issues[3].RelatedLocations)
+}
@@ -0,0 +1,170 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/analysis/constfold"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectNonExhaustiveSwitches reports switches over a named integer or string
+// type that leave out some of the constants the package of the type declares
+// for it, unless the switch has a default clause.
+//
+// Switches with a case that is not a constant are skipped, since the values
+// they cover cannot be known.
+func DetectNonExhaustiveSwitches(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		sw, ok := n.(*ast.SwitchStmt)
+		if !ok || sw.Tag == nil || hasDefaultClause(sw.Body) {
+			return true
+		}
+		named, ok := types.Unalias(info.TypeOf(sw.Tag)).(*types.Named)
+		if !ok || !isEnumType(named) {
+			return true
+		}
+		missing, ok := missingEnumConsts(sw, named, node.Name.Name, info)
+		if !ok || len(missing) == 0 {
+			return true
+		}
+
+		qualifier := enumQualifier(node, named)
+		names := make([]string, len(missing))
+		for i, c := range missing {
+			names[i] = qualifier + c.Name()
+		}
+
+		suggestion, err := generateExhaustiveSwitchSuggestion(extractSnippet(sw, fset, content), names)
+		if err != nil {
+			suggestion = ""
+		}
+
+		issues = append(issues, tt.Issue{
+			Rule:       "exhaustive-switch",
+			Filename:   filename,
+			Start:      fset.Position(sw.Pos()),
+			End:        fset.Position(sw.End()),
+			Message:    fmt.Sprintf("switch over %s is missing cases: %s", named.Obj().Name(), strings.Join(names, ", ")),
+			Suggestion: suggestion,
+			Note:       "handle the missing constants, or add a default case if their values are deliberately ignored.",
+			Severity:   severity,
+		})
+		return true
+	})
+
+	return issues, nil
+}
+
+// isEnumType reports whether constants of the named type can be enumerated,
+// i.e. whether its underlying type is an integer or a string.
+func isEnumType(named *types.Named) bool {
+	basic, ok := named.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsInteger|types.IsString) != 0
+}
+
+// missingEnumConsts returns the constants of type named, in declaration order,
+// whose value no case of sw covers. Constants sharing a value are reported
+// once. ok is false when a case is not a constant.
+func missingEnumConsts(sw *ast.SwitchStmt, named *types.Named, pkg string, info *types.Info) (missing []*types.Const, ok bool) {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return nil, false
+	}
+	local := obj.Pkg().Name() == pkg
+
+	covered := make(map[string]bool)
+	for _, stmt := range sw.Body.List {
+		cc, ok := stmt.(*ast.CaseClause)
+		if !ok {
+			continue
+		}
+		for _, expr := range cc.List {
+			v := constfold.Eval(expr, info, nil)
+			if !constfold.IsKnown(v) {
+				return nil, false
+			}
+			covered[v.ExactString()] = true
+		}
+	}
+
+	scope := obj.Pkg().Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if ok && types.Identical(c.Type(), named) && (local || c.Exported()) {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	for _, c := range consts {
+		value := c.Val().ExactString()
+		if covered[value] {
+			continue
+		}
+		covered[value] = true
+		missing = append(missing, c)
+	}
+	return missing, true
+}
+
+// enumQualifier returns the prefix naming the package of the type in node,
+// e.g. "time." for time.Weekday, or "" for a type of the package itself.
+func enumQualifier(node *ast.File, named *types.Named) string {
+	pkg := named.Obj().Pkg()
+	if pkg.Name() == node.Name.Name {
+		return ""
+	}
+	for _, imp := range node.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || path != pkg.Path() {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name + "."
+		}
+		break
+	}
+	return pkg.Name() + "."
+}
+
+// generateExhaustiveSwitchSuggestion adds an empty case for the missing
+// constants at the end of the switch, which keeps its behavior unchanged.
+func generateExhaustiveSwitchSuggestion(snippet string, missing []string) (string, error) {
+	end := strings.LastIndex(snippet, "}")
+	if end < 0 {
+		return "", fmt.Errorf("switch body not found")
+	}
+	src := wrapSnippet(strings.TrimRight(snippet[:end], " \t\n") + "\ncase " + strings.Join(missing, ", ") + ":\n" + snippet[end:])
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return "", err
+	}
+	body := string(formatted)
+	body = body[strings.Index(body, "func main() ")+len("func main() "):]
+
+	suggestion := cleanUpResult(body)
+	if err := validateRewrite(snippet, suggestion); err != nil {
+		return "", err
+	}
+	return suggestion, nil
+}
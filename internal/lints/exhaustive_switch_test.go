@@ -0,0 +1,115 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectNonExhaustiveSwitches(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		code       string
+		messages   []string
+		suggestion string
+	}{
+		{
+			name: "missing constants",
+			code: `package game
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+	Crimson = Red
+)
+
+func Name(c Color) string {
+	switch c {
+	case Red:
+		return "red"
+	}
+	return ""
+}
+`,
+			messages: []string{"switch over Color is missing cases: Green, Blue"},
+			suggestion: `switch c {
+case Red:
+	return "red"
+case Green, Blue:
+}`,
+		},
+		{
+			name: "string type of another package",
+			code: `package game
+
+import t "time"
+
+func Weekend(d t.Weekday) bool {
+	switch d {
+	case t.Saturday, t.Sunday:
+		return true
+	case t.Monday, t.Tuesday, t.Wednesday, t.Thursday:
+	}
+	return false
+}
+`,
+			messages: []string{"switch over Weekday is missing cases: t.Friday"},
+		},
+		{
+			name: "default, complete and non-constant cases",
+			code: `package game
+
+type State string
+
+const (
+	Open   State = "open"
+	Closed State = "closed"
+)
+
+func Check(s, other State) {
+	switch s {
+	case Open:
+	default:
+	}
+	switch s {
+	case Open, Closed:
+	}
+	switch s {
+	case other:
+	}
+}
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "game.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectNonExhaustiveSwitches(path, node, fset, tt.SeverityWarning)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+			for i, issue := range issues {
+				assert.Equal(t, "exhaustive-switch", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				if tc.suggestion != "" {
+					assert.Equal(t, tc.suggestion, issue.Suggestion)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/gnolang/tlin/internal/analysis/constfold"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectFloatEquality reports == and != comparisons between floating-point
+// operands, which rounding errors make unreliable.
+//
+// Constant comparisons, folded at compile time, and comparisons of an
+// expression with itself, the usual NaN check, are left alone.
+func DetectFloatEquality(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		expr, ok := n.(*ast.BinaryExpr)
+		if !ok || (expr.Op != token.EQL && expr.Op != token.NEQ) {
+			return true
+		}
+		if constfold.IsKnown(constfold.Eval(expr, info, nil)) {
+			return true
+		}
+		if !isFloat(info, expr.X) || !isFloat(info, expr.Y) {
+			return true
+		}
+		x, y := types.ExprString(expr.X), types.ExprString(expr.Y)
+		if x == y {
+			return true
+		}
+
+		cmp := "<"
+		if expr.Op == token.NEQ {
+			cmp = ">="
+		}
+		issues = append(issues, tt.Issue{
+			Rule:     "float-equality",
+			Filename: filename,
+			Start:    fset.Position(expr.Pos()),
+			End:      fset.Position(expr.End()),
+			Message:  fmt.Sprintf("floating-point values compared with %s", expr.Op),
+			Note:     fmt.Sprintf("rounding errors can make nearly equal values differ. compare the difference with a tolerance instead, e.g. math.Abs(%s - %s) %s epsilon.", x, y, cmp),
+			Severity: severity,
+		})
+		return true
+	})
+
+	return issues, nil
+}
+
+func isFloat(info *types.Info, expr ast.Expr) bool {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Type == nil {
+		return false
+	}
+	basic, ok := tv.Type.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsFloat != 0
+}
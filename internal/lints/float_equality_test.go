@@ -0,0 +1,47 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectFloatEquality(t *testing.T) {
+	t.Parallel()
+	src := `package geometry
+
+const epsilon = 1e-9
+
+type Meters float64
+
+func Same(a, b float64) bool {
+	return a == b // want "floating-point values compared with =="
+}
+
+func Moved(from, to Meters) bool {
+	return from != to // want "floating-point values compared with !="
+}
+
+func IsZero(x float32) bool {
+	return x == 0 // want "floating-point values compared with =="
+}
+
+func IsNaN(x float64) bool {
+	return x != x
+}
+
+func Equal(a, b int) bool {
+	return a == b
+}
+
+func Tiny() bool {
+	return epsilon == 1e-9
+}
+`
+	ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectFloatEquality(filename, node, fset, tt.SeverityWarning)
+	}, "geometry.go", src)
+}
@@ -0,0 +1,92 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"github.com/gnolang/tlin/internal/analysis/constfold"
+	"github.com/gnolang/tlin/internal/directive"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// nondeterministicOK is the annotation of the functions and files allowed to
+// compute results that may differ across platforms.
+const nondeterministicOK = "nondeterministic-ok"
+
+const floatInRealmNote = "floating-point results may differ across platforms, which breaks the determinism realms rely on. consider integer or fixed-point arithmetic instead."
+
+// DetectFloatsInRealm reports float32/float64 declarations, floating-point
+// literals and floating-point arithmetic in realm packages.
+//
+// Functions listed in allow (e.g. display-only helpers such as Render) are
+// skipped, as are functions and files annotated with //tlin:nondeterministic-ok.
+func DetectFloatsInRealm(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, allow []string) ([]tt.Issue, error) {
+	if !IsRealmFile(filename) {
+		return nil, nil
+	}
+
+	annotations := directive.ParseAnnotations(node, fset)
+	if annotations.Has(nil, nondeterministicOK) {
+		return nil, nil
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	report := func(n ast.Node, message string) {
+		issues = append(issues, tt.Issue{
+			Rule:     "no-floats-in-realm",
+			Filename: filename,
+			Start:    fset.Position(n.Pos()),
+			End:      fset.Position(n.End()),
+			Message:  message,
+			Note:     floatInRealmNote,
+			Severity: severity,
+		})
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if slices.Contains(allow, n.Name.Name) || annotations.Has(n, nondeterministicOK) {
+				return false
+			}
+		case *ast.Ident:
+			if n.Name == "float32" || n.Name == "float64" {
+				if tv, ok := info.Types[n]; !ok || tv.IsType() {
+					report(n, "avoid floating-point types in realm code")
+				}
+			}
+		case *ast.BasicLit:
+			if n.Kind == token.FLOAT {
+				report(n, "avoid floating-point literals in realm code")
+			}
+		case *ast.BinaryExpr:
+			if isFloatArithmetic(n, info) {
+				report(n, "avoid floating-point arithmetic in realm code")
+				// operands of the outermost expression are covered by this issue.
+				return false
+			}
+		}
+		return true
+	})
+
+	return issues, nil
+}
+
+func isFloatArithmetic(expr *ast.BinaryExpr, info *types.Info) bool {
+	switch expr.Op {
+	case token.ADD, token.SUB, token.MUL, token.QUO:
+	default:
+		return false
+	}
+	tv, ok := info.Types[expr]
+	if !ok || constfold.IsKnown(constfold.Eval(expr, info, nil)) {
+		// constant expressions are folded at compile time.
+		return false
+	}
+	basic, ok := tv.Type.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsFloat != 0
+}
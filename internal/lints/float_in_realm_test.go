@@ -0,0 +1,152 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFloatsInRealm(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		module string
+		code   string
+		allow  []string
+	}{
+		{
+			name:   "float declaration and arithmetic in realm",
+			module: "gno.land/r/demo/bank",
+			code: `package bank
+
+var rate float64 // want "avoid floating-point types in realm code"
+
+func Interest(amount int) int {
+	return int(float64(amount) * rate) // want "avoid floating-point arithmetic in realm code"
+}
+`,
+		},
+		{
+			name:   "float literal",
+			module: "gno.land/r/demo/bank",
+			code: `package bank
+
+func Fee() int {
+	x := 1.5 // want "avoid floating-point literals in realm code"
+	_ = x
+	return 0
+}
+`,
+		},
+		{
+			name:   "allowed function",
+			module: "gno.land/r/demo/bank",
+			code: `package bank
+
+func Render(path string) string {
+	ratio := 0.5 * 2
+	_ = ratio
+	return ""
+}
+`,
+			allow: []string{"Render"},
+		},
+		{
+			name:   "annotated function",
+			module: "gno.land/r/demo/bank",
+			code: `package bank
+
+//tlin:nondeterministic-ok
+func Ratio() int {
+	x := 1.5
+	_ = x
+	return 0
+}
+
+func Fee() int {
+	y := 2.5 // want "avoid floating-point literals in realm code"
+	_ = y
+	return 0
+}
+`,
+		},
+		{
+			name:   "annotated file",
+			module: "gno.land/r/demo/bank",
+			code: `//tlin:nondeterministic-ok
+
+package bank
+
+var rate float64
+`,
+		},
+		{
+			name:   "integer math",
+			module: "gno.land/r/demo/bank",
+			code: `package bank
+
+func Fee(amount int) int {
+	return amount * 3 / 1000
+}
+`,
+		},
+		{
+			name:   "package is not a realm",
+			module: "gno.land/p/demo/ufmt",
+			code: `package ufmt
+
+func Ratio(a, b float64) float64 {
+	return a / b
+}
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			issues := ruletest.RunFiles(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+				return DetectFloatsInRealm(filename, node, fset, tt.SeverityWarning, tc.allow)
+			}, map[string]string{
+				"gno.mod": "module " + tc.module + "\n",
+				"file.go": tc.code,
+			})
+			for _, issue := range issues {
+				assert.Equal(t, "no-floats-in-realm", issue.Rule)
+			}
+		})
+	}
+}
+
+func TestIsRealmFile(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+
+	realm := filepath.Join(root, "realm", "sub")
+	require.NoError(t, os.MkdirAll(realm, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "realm", "gno.mod"), []byte("module gno.land/r/demo/foo\n"), 0o644))
+	assert.True(t, IsRealmFile(filepath.Join(realm, "a.gno")))
+
+	byPath := filepath.Join(root, "gno.land", "r", "demo")
+	require.NoError(t, os.MkdirAll(byPath, 0o755))
+	assert.True(t, IsRealmFile(filepath.Join(byPath, "a.gno")))
+
+	assert.False(t, IsRealmFile(filepath.Join(root, "gno.land", "p", "demo", "a.gno")))
+
+	// an `r` directory outside gno.land/ is not a realm.
+	assert.False(t, IsRealmFile(filepath.Join(root, "tools", "r", "a.gno")))
+
+	// nor is a package whose gno.mod declares a non-realm path.
+	pkg := filepath.Join(root, "gno.land", "r", "pkg")
+	require.NoError(t, os.MkdirAll(pkg, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkg, "gno.mod"), []byte("module gno.land/p/demo/pkg\n"), 0o644))
+	assert.False(t, IsRealmFile(filepath.Join(pkg, "a.gno")))
+}
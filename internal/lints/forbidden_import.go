@@ -0,0 +1,99 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// ImportPolicy forbids the files below some directories to import some
+// packages, such as pure packages importing realms.
+type ImportPolicy struct {
+	// Name describes the policy in the issues. It defaults to the paths.
+	Name string `yaml:"name"`
+	// Paths holds the directory patterns of the files the policy applies
+	// to, as matched by MatchPath. The policy applies to all the files when
+	// it is empty.
+	Paths []string `yaml:"paths"`
+	// Imports holds the forbidden import paths. Each one also forbids the
+	// packages below it: gno.land/r forbids gno.land/r/demo/users.
+	Imports []string `yaml:"imports"`
+}
+
+func (p ImportPolicy) appliesTo(filename string) bool {
+	if len(p.Paths) == 0 {
+		return true
+	}
+	for _, pattern := range p.Paths {
+		if MatchPath(filename, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// forbids returns the import of the policy forbidding path.
+func (p ImportPolicy) forbids(path string) (string, bool) {
+	for _, forbidden := range p.Imports {
+		prefix := strings.TrimSuffix(forbidden, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+func (p ImportPolicy) String() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return strings.Join(p.Paths, ", ")
+}
+
+// DetectForbiddenImports reports the imports of filename forbidden by one of
+// policies, at their import spec. An import forbidden by several policies is
+// reported for the first one.
+func DetectForbiddenImports(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, policies []ImportPolicy) ([]tt.Issue, error) {
+	var applied []ImportPolicy
+	for _, p := range policies {
+		if p.appliesTo(filename) {
+			applied = append(applied, p)
+		}
+	}
+	if len(applied) == 0 {
+		return nil, nil
+	}
+
+	var issues []tt.Issue
+	for _, imp := range node.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		for _, p := range applied {
+			prefix, ok := p.forbids(path)
+			if !ok {
+				continue
+			}
+			note := fmt.Sprintf("the %q policy forbids these files to import %s", p, prefix)
+			if prefix != path {
+				note += " and the packages below it"
+			}
+			issues = append(issues, tt.Issue{
+				Rule:     "forbidden-import",
+				Filename: filename,
+				Start:    fset.Position(imp.Pos()),
+				End:      fset.Position(imp.End()),
+				Message:  fmt.Sprintf("import of %q is forbidden by policy %q", path, p),
+				Note:     note + ".",
+				Severity: severity,
+			})
+			break
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,55 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectForbiddenImports(t *testing.T) {
+	t.Parallel()
+	policies := []ImportPolicy{
+		{Name: "realms may not use os", Paths: []string{"r"}, Imports: []string{"os"}},
+		{Name: "pure packages may not import realms", Paths: []string{"p"}, Imports: []string{"gno.land/r/"}},
+		{Paths: []string{"p/*/internal"}, Imports: []string{"strings"}},
+	}
+	check := func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectForbiddenImports(filename, node, fset, tt.SeverityError, policies)
+	}
+
+	issues := ruletest.RunFiles(t, check, map[string]string{
+		"p/demo/tokens.gno": `package tokens
+
+import (
+	"strings"
+
+	users "gno.land/r/demo/users" // want "import of \"gno.land/r/demo/users\" is forbidden by policy \"pure packages may not import realms\""
+	"gno.land/rx/demo/other"
+)
+`,
+		"p/demo/internal/util.gno": `package internal
+
+import "strings" // want "import of \"strings\" is forbidden by policy \"p/\\*/internal\""
+`,
+		"r/demo/bank.gno": `package bank
+
+import (
+	"os" // want "import of \"os\" is forbidden by policy \"realms may not use os\""
+	"os/exec" // want "import of \"os/exec\" is forbidden"
+	"osutil"
+)
+`,
+	})
+	require.Len(t, issues, 4)
+	var notes []string
+	for _, issue := range issues {
+		notes = append(notes, issue.Note)
+	}
+	assert.Contains(t, notes, `the "realms may not use os" policy forbids these files to import os.`)
+	assert.Contains(t, notes, `the "realms may not use os" policy forbids these files to import os and the packages below it.`)
+}
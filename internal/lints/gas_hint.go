@@ -0,0 +1,173 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectGasHints reports constructs whose execution cost grows with the data
+// they work on, and annotates each issue with a cost category:
+//
+//   - ranging over a package-level map, which grows with the realm state (unbounded),
+//   - concatenating strings in a loop, which copies the string on every iteration (quadratic),
+//   - recursion, which costs as much as its depth (unbounded).
+//
+// The analysis is experimental: costs are relative hints, not gas estimates.
+func DetectGasHints(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	report := func(n ast.Node, cost tt.CostCategory, message, note string) {
+		issues = append(issues, tt.Issue{
+			Rule:     "gas-hint",
+			Filename: filename,
+			Start:    fset.Position(n.Pos()),
+			End:      fset.Position(n.End()),
+			Message:  message,
+			Note:     note,
+			Severity: severity,
+			Cost:     cost,
+		})
+	}
+
+	concats := make(map[*ast.AssignStmt]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.RangeStmt:
+			if isStateMap(info, loop.X) {
+				report(loop.X, tt.CostUnbounded,
+					"range over a package-level map",
+					"the cost of the loop grows with the number of entries in the realm state. consider paginating or keeping an index.")
+			}
+			body = loop.Body
+		case *ast.ForStmt:
+			body = loop.Body
+		default:
+			return true
+		}
+
+		inspectLoopBody(body, func(assign *ast.AssignStmt) {
+			if concats[assign] || !isLoopCarriedConcat(info, assign, n) {
+				return
+			}
+			concats[assign] = true
+			report(assign, tt.CostQuadratic,
+				"string concatenation in a loop",
+				"every concatenation copies the whole string. use strings.Builder to build the result in linear time.")
+		})
+		return true
+	})
+
+	graph := buildCallGraph(node)
+	reported := make(map[string]bool)
+	for _, name := range graph.names {
+		if reported[name] {
+			continue
+		}
+		group := graph.recursiveGroup(name)
+		for _, member := range group {
+			reported[member] = true
+		}
+		if len(group) == 0 {
+			continue
+		}
+		fn := graph.decls[group[0]]
+		message := fmt.Sprintf("recursive function %s", group[0])
+		if len(group) > 1 {
+			message = fmt.Sprintf("mutually recursive functions %s", strings.Join(group, ", "))
+		}
+		report(fn.Name, tt.CostUnbounded, message,
+			"the cost grows with the recursion depth. make sure the depth is bounded by something the caller cannot control.")
+	}
+
+	return issues, nil
+}
+
+// inspectLoopBody calls fn for the assignments of a loop body, leaving out function literals.
+func inspectLoopBody(body *ast.BlockStmt, fn func(*ast.AssignStmt)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			fn(n)
+		}
+		return true
+	})
+}
+
+// isLoopCarriedConcat reports whether assign appends to a string declared outside loop.
+func isLoopCarriedConcat(info *types.Info, assign *ast.AssignStmt, loop ast.Node) bool {
+	if len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	lhs := assign.Lhs[0]
+
+	switch assign.Tok {
+	case token.ADD_ASSIGN:
+	case token.ASSIGN:
+		bin, ok := unparen(assign.Rhs[0]).(*ast.BinaryExpr)
+		if !ok || bin.Op != token.ADD || !sameOperand(bin.X, lhs) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if basic, ok := underlyingType(info.TypeOf(lhs)).(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+		return false
+	}
+
+	root := rootIdent(lhs)
+	if root == nil {
+		return false
+	}
+	obj := info.Uses[root]
+	if obj == nil {
+		return false
+	}
+	// a string declared in the loop starts over on every iteration.
+	return obj.Pos() < loop.Pos() || obj.Pos() >= loop.End()
+}
+
+// isStateMap reports whether expr is a map reached from a package-level variable.
+func isStateMap(info *types.Info, expr ast.Expr) bool {
+	if _, ok := underlyingType(info.TypeOf(expr)).(*types.Map); !ok {
+		return false
+	}
+	root := rootIdent(expr)
+	return root != nil && isPackageVar(root, info)
+}
+
+// rootIdent returns the variable an expression like a.b[c].d starts from.
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+func underlyingType(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	return t.Underlying()
+}
@@ -0,0 +1,133 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectGasHints(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		code     string
+		messages []string
+		costs    []tt.CostCategory
+	}{
+		{
+			name: "range over package-level map",
+			code: `package foo
+
+var balances = map[string]int{}
+
+func Total() int {
+	total := 0
+	for _, b := range balances {
+		total += b
+	}
+	return total
+}`,
+			messages: []string{"range over a package-level map"},
+			costs:    []tt.CostCategory{tt.CostUnbounded},
+		},
+		{
+			name: "range over a local map",
+			code: `package foo
+
+func Count(xs []string) int {
+	seen := map[string]bool{}
+	for _, x := range xs {
+		seen[x] = true
+	}
+	n := 0
+	for range seen {
+		n++
+	}
+	return n
+}`,
+		},
+		{
+			name: "string concatenation in a loop",
+			code: `package foo
+
+func Join(xs []string) string {
+	out := ""
+	for _, x := range xs {
+		out += x
+		out = out + ","
+	}
+	return out
+}`,
+			messages: []string{"string concatenation in a loop", "string concatenation in a loop"},
+			costs:    []tt.CostCategory{tt.CostQuadratic, tt.CostQuadratic},
+		},
+		{
+			name: "string reset on every iteration",
+			code: `package foo
+
+func Lines(xs []string) {
+	for _, x := range xs {
+		line := "- "
+		line += x
+		println(line)
+	}
+}`,
+		},
+		{
+			name: "concatenation in nested loops is reported once",
+			code: `package foo
+
+func Grid(n int) string {
+	out := ""
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			out += "."
+		}
+	}
+	return out
+}`,
+			messages: []string{"string concatenation in a loop"},
+			costs:    []tt.CostCategory{tt.CostQuadratic},
+		},
+		{
+			name: "recursion",
+			code: `package foo
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}`,
+			messages: []string{"recursive function fib"},
+			costs:    []tt.CostCategory{tt.CostUnbounded},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tmpDir := t.TempDir()
+			tmpfile := filepath.Join(tmpDir, "foo.gno")
+			require.NoError(t, os.WriteFile(tmpfile, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(tmpfile, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectGasHints(tmpfile, node, fset, tt.SeverityInfo)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+
+			for i, issue := range issues {
+				assert.Equal(t, "gas-hint", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				assert.Equal(t, tc.costs[i], issue.Cost)
+			}
+		})
+	}
+}
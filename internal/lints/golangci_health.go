@@ -0,0 +1,119 @@
+package lints
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MinGolangciLintVersion is the oldest golangci-lint release accepted by
+// default.
+const MinGolangciLintVersion = "1.50.0"
+
+var golangciVersionPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// CheckGolangciLint verifies that golangci-lint can run before any file is
+// linted with it, and returns the path of its binary. The binary must be in
+// PATH, have the SHA-256 digest when one is given, and report a 1.x version
+// of at least minVersion: v2 no longer accepts the flags tlin passes.
+func CheckGolangciLint(minVersion, digest string) (string, error) {
+	path, err := exec.LookPath("golangci-lint")
+	if err != nil {
+		return "", fmt.Errorf("golangci-lint was not found in PATH")
+	}
+
+	if digest != "" {
+		sum, err := fileDigest(path)
+		if err != nil {
+			return "", err
+		}
+		if !strings.EqualFold(sum, digest) {
+			return "", fmt.Errorf("the SHA-256 digest of %s is %s, expected %s", path, sum, digest)
+		}
+	}
+
+	out, err := exec.Command(path, "version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("`golangci-lint version` failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	version := golangciVersionPattern.FindString(string(out))
+	if version == "" {
+		return "", fmt.Errorf("no version in the output of `golangci-lint version`: %s", strings.TrimSpace(string(out)))
+	}
+	if err := checkGolangciVersion(version, minVersion); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// checkGolangciVersion reports an error when version is older than
+// minVersion, or is not a 1.x release.
+func checkGolangciVersion(version, minVersion string) error {
+	v, err := parseVersion(version)
+	if err != nil {
+		return err
+	}
+	if minVersion == "" {
+		minVersion = MinGolangciLintVersion
+	}
+	lowest, err := parseVersion(minVersion)
+	if err != nil {
+		return err
+	}
+	if v[0] >= 2 {
+		return fmt.Errorf("golangci-lint %s is not supported, tlin needs a 1.x release", version)
+	}
+	for i := range v {
+		if v[i] != lowest[i] {
+			if v[i] < lowest[i] {
+				return fmt.Errorf("golangci-lint %s is older than the required %s", version, minVersion)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// parseVersion parses a major.minor.patch version, with an optional v prefix.
+func parseVersion(version string) ([3]int, error) {
+	var v [3]int
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version %q, expected major.minor.patch", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q, expected major.minor.patch", version)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CheckVersionFormat reports whether version is a valid major.minor.patch
+// version.
+func CheckVersionFormat(version string) error {
+	_, err := parseVersion(version)
+	return err
+}
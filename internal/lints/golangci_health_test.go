@@ -0,0 +1,72 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckGolangciVersion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		version    string
+		minVersion string
+		err        string
+	}{
+		{"1.55.2", "", ""},
+		{"1.50.0", "1.50.0", ""},
+		{"1.49.9", "", "golangci-lint 1.49.9 is older than the required 1.50.0"},
+		{"1.55.2", "1.56.0", "older than the required 1.56.0"},
+		{"2.0.1", "", "golangci-lint 2.0.1 is not supported"},
+		{"1.55.2", "latest", `invalid version "latest"`},
+	}
+	for _, tt := range tests {
+		err := checkGolangciVersion(tt.version, tt.minVersion)
+		if tt.err == "" {
+			assert.NoError(t, err, tt.version)
+			continue
+		}
+		assert.ErrorContains(t, err, tt.err, tt.version)
+	}
+}
+
+// fakeGolangciLint installs a golangci-lint script printing output as its
+// version in a directory put first in PATH, and returns its path.
+func fakeGolangciLint(t *testing.T, output string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake golangci-lint is a shell script")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "golangci-lint")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho '"+output+"'\n"), 0o755))
+	t.Setenv("PATH", dir)
+	return path
+}
+
+func TestCheckGolangciLint(t *testing.T) {
+	path := fakeGolangciLint(t, "golangci-lint has version 1.55.2 built with go1.21.3 from e3c2265f on 2023-11-03T12:59:25Z")
+
+	binary, err := CheckGolangciLint("", "")
+	require.NoError(t, err)
+	assert.Equal(t, path, binary)
+
+	digest, err := fileDigest(path)
+	require.NoError(t, err)
+	_, err = CheckGolangciLint("", digest)
+	assert.NoError(t, err)
+
+	_, err = CheckGolangciLint("", "0000")
+	assert.ErrorContains(t, err, "expected 0000")
+
+	_, err = CheckGolangciLint("1.60.0", "")
+	assert.ErrorContains(t, err, "older than the required 1.60.0")
+
+	t.Setenv("PATH", t.TempDir())
+	_, err = CheckGolangciLint("", "")
+	assert.ErrorContains(t, err, "not found in PATH")
+}
@@ -0,0 +1,114 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// FunctionLimits holds the maximum size of a function. Zero disables a limit.
+type FunctionLimits struct {
+	MaxLines      int
+	MaxStatements int
+}
+
+// DetectLongFunctions reports functions exceeding the given limits.
+func DetectLongFunctions(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, limits FunctionLimits) ([]tt.Issue, error) {
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		lines := fset.Position(fn.End()).Line - fset.Position(fn.Pos()).Line + 1
+		statements := countStatements(fn.Body)
+
+		var exceeded []string
+		if limits.MaxLines > 0 && lines > limits.MaxLines {
+			exceeded = append(exceeded, fmt.Sprintf("%d lines (max %d)", lines, limits.MaxLines))
+		}
+		if limits.MaxStatements > 0 && statements > limits.MaxStatements {
+			exceeded = append(exceeded, fmt.Sprintf("%d statements (max %d)", statements, limits.MaxStatements))
+		}
+		if len(exceeded) == 0 {
+			continue
+		}
+
+		issues = append(issues, tt.Issue{
+			Rule:     "function-length",
+			Filename: filename,
+			Start:    fset.Position(fn.Pos()),
+			End:      fset.Position(fn.Name.End()),
+			Message:  fmt.Sprintf("function %s is too long: %s", fn.Name.Name, strings.Join(exceeded, ", ")),
+			Note:     "consider splitting it into smaller functions that are easier to review.",
+			Severity: severity,
+		})
+	}
+	return issues, nil
+}
+
+// countStatements counts the statements of a function body, including
+// nested ones. Init and post statements of control structures are part
+// of their statement and are not counted separately.
+func countStatements(body *ast.BlockStmt) int {
+	count := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			count += len(n.List)
+		case *ast.CaseClause:
+			count += len(n.Body)
+		case *ast.CommClause:
+			count += len(n.Body)
+		}
+		return true
+	})
+	return count
+}
+
+// DetectLongFile reports files longer than maxLines. Zero disables the check.
+func DetectLongFile(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, maxLines int) ([]tt.Issue, error) {
+	file := fset.File(node.Pos())
+	if maxLines <= 0 || file == nil || file.LineCount() <= maxLines {
+		return nil, nil
+	}
+
+	return []tt.Issue{{
+		Rule:     "file-length",
+		Filename: filename,
+		Start:    fset.Position(node.Package),
+		End:      fset.Position(node.Name.End()),
+		Message:  fmt.Sprintf("file is too long: %d lines (max %d)", file.LineCount(), maxLines),
+		Note:     "consider splitting it into several files.",
+		Severity: severity,
+	}}, nil
+}
+
+// MatchPath reports whether the directory of filename matches pattern.
+//
+// The pattern is a slash-separated directory path whose elements may use
+// filepath.Match syntax. It matches when it appears as a sequence of
+// consecutive directories, e.g. `r/legacy` or `r/*/internal`.
+func MatchPath(filename, pattern string) bool {
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(filename)), "/")
+	elems := strings.Split(strings.Trim(filepath.ToSlash(pattern), "/"), "/")
+
+	for start := 0; start+len(elems) <= len(dirs); start++ {
+		matched := true
+		for i, elem := range elems {
+			if ok, err := filepath.Match(elem, dirs[start+i]); err != nil || !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
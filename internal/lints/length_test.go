@@ -0,0 +1,86 @@
+package lints
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLongFunctions(t *testing.T) {
+	t.Parallel()
+	code := `package main
+
+func short() {
+	a := 1
+	_ = a
+}
+
+func long() {
+	a := 1
+	if a > 0 {
+		a++
+	}
+	for i := 0; i < 3; i++ {
+		a += i
+	}
+	_ = a
+}
+`
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "test.go", code, 0)
+	require.NoError(t, err)
+
+	issues, err := DetectLongFunctions("test.go", node, fset, tt.SeverityWarning, FunctionLimits{MaxStatements: 4})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "function long is too long: 6 statements (max 4)", issues[0].Message)
+
+	issues, err = DetectLongFunctions("test.go", node, fset, tt.SeverityWarning, FunctionLimits{MaxLines: 4})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "function long is too long: 10 lines (max 4)", issues[0].Message)
+
+	issues, err = DetectLongFunctions("test.go", node, fset, tt.SeverityWarning, FunctionLimits{})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestDetectLongFile(t *testing.T) {
+	t.Parallel()
+	code := "package main\n" + strings.Repeat("\nvar _ = 1", 10) + "\n"
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "test.go", code, 0)
+	require.NoError(t, err)
+
+	issues, err := DetectLongFile("test.go", node, fset, tt.SeverityWarning, 5)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "file-length", issues[0].Rule)
+
+	issues, err = DetectLongFile("test.go", node, fset, tt.SeverityWarning, 100)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestMatchPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		filename string
+		pattern  string
+		want     bool
+	}{
+		{"examples/gno.land/r/legacy/foo/foo.gno", "r/legacy", true},
+		{"examples/gno.land/r/legacy/foo/foo.gno", "r/*/foo", true},
+		{"examples/gno.land/r/demo/foo/foo.gno", "r/legacy", false},
+		{"/abs/r/legacy/foo.gno", "/r/legacy/", true},
+		{"foo.gno", "r", false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, MatchPath(tc.filename, tc.pattern), "%s ~ %s", tc.filename, tc.pattern)
+	}
+}
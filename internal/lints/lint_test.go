@@ -1,6 +1,7 @@
 package lints
 
 import (
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -19,10 +20,11 @@ func TestDetectUnnecessarySliceLength(t *testing.T) {
 	t.Parallel()
 	baseMsg := "unnecessary use of len() in slice expression, can be simplified"
 	tests := []struct {
-		name     string
-		code     string
-		message  string
-		expected int
+		name       string
+		code       string
+		message    string
+		suggestion string
+		expected   int
 	}{
 		{
 			name: "suggests to use slice[:]",
@@ -72,6 +74,58 @@ func main() {
 			expected: 1,
 			message:  baseMsg,
 		},
+		{
+			name: "selector base",
+			code: `
+package main
+
+type buffer struct{ buf []byte }
+
+func main() {
+	s := buffer{}
+	_ = s.buf[:len(s.buf)]
+}`,
+			expected:   1,
+			message:    baseMsg,
+			suggestion: "s.buf[:]",
+		},
+		{
+			name: "parenthesized base",
+			code: `
+package main
+
+func main() {
+	slice := []int{1, 2, 3}
+	_ = (slice)[2:len(slice)]
+}`,
+			expected:   1,
+			message:    baseMsg,
+			suggestion: "(slice)[2:]",
+		},
+		{
+			name: "different selector base",
+			code: `
+package main
+
+type pair struct{ a, b []int }
+
+func main() {
+	p := pair{}
+	_ = p.a[:len(p.b)]
+}`,
+			expected: 0,
+		},
+		{
+			name: "3-index slice is left untouched",
+			code: `
+package main
+
+func main() {
+	slice := []int{1, 2, 3}
+	_ = slice[1:len(slice):len(slice)]
+}`,
+			expected: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -107,6 +161,9 @@ func main() {
 						tt.message,
 						issue.Message,
 					)
+					if tt.suggestion != "" {
+						assert.Equal(t, tt.suggestion, issue.Suggestion)
+					}
 				}
 			}
 		})
@@ -178,6 +235,43 @@ func example() {
     x := 5
     y := 10
     _ = x + y
+}`,
+			expected: 0,
+		},
+		{
+			name: "Conversion to an alias of the same type",
+			code: `
+package main
+
+type Amount = int
+
+func example() {
+    var x int = 5
+    y := Amount(x)
+    _ = y
+}`,
+			expected: 1,
+		},
+		{
+			name: "Conversion to an instantiated generic type",
+			code: `
+package main
+
+type Box[T any] struct{ v T }
+
+func example(b Box[int], c Box[string]) {
+    _ = Box[int](b)
+    _ = Box[int](Box[int]{})
+}`,
+			expected: 2,
+		},
+		{
+			name: "Conversion from a type parameter",
+			code: `
+package main
+
+func example[T ~int](x T) int {
+    return int(x)
 }`,
 			expected: 0,
 		},
@@ -218,6 +312,138 @@ func example() {
 	}
 }
 
+func TestDetectUnnecessaryTypeConversionChainsAndAssertions(t *testing.T) {
+	t.Parallel()
+	code := `package main
+
+type Reader interface{ Read() }
+
+func example(s string, n int, n8 int8, u uint, r Reader, v any) {
+	_ = string([]byte(s))
+	_ = string([]rune(s)) // invalid UTF-8 is replaced, not a round trip
+	_ = int(int64(n))
+	_ = int8(uint16(n8))
+	_ = uint(int32(u))
+	_ = r.(Reader)
+	_, _ = r.(Reader)
+	_ = v.(Reader)
+	switch r.(type) {
+	}
+	x := int(n)
+	x = int(n) + 1
+	_ = x
+}
+`
+	tmpfile := filepath.Join(t.TempDir(), "test.go")
+	require.NoError(t, os.WriteFile(tmpfile, []byte(code), 0o644))
+
+	node, fset, err := ParseFile(tmpfile, nil)
+	require.NoError(t, err)
+
+	issues, err := DetectUnnecessaryConversions(tmpfile, node, fset, types.SeverityError)
+	require.NoError(t, err)
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, fmt.Sprintf("%d: %s -> %s", issue.Start.Line, issue.Message, issue.Suggestion))
+	}
+	assert.Equal(t, []string{
+		"6: unnecessary type conversion -> s",
+		"8: unnecessary type conversion -> n",
+		"9: unnecessary type conversion -> n8",
+		"11: unnecessary type assertion -> r",
+		"16: unnecessary type conversion -> n",
+		"17: unnecessary type conversion -> n",
+	}, got)
+}
+
+func TestDetectUnnecessaryTypeConversionPackageScope(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"state.gno": `package main
+
+type Amount = int
+
+var total int
+`,
+		"main.gno": `package main
+
+func example() {
+    a := int(total)
+    b := Amount(total)
+    _, _ = a, b
+}
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644))
+	}
+
+	// the engine lints a temporary .go copy of each .gno file.
+	tmpfile := filepath.Join(tmpDir, "temp_main.go")
+	require.NoError(t, os.WriteFile(tmpfile, []byte(files["main.gno"]), 0o644))
+
+	node, fset, err := ParseFile(tmpfile, nil)
+	require.NoError(t, err)
+
+	issues, err := DetectUnnecessaryConversions(tmpfile, node, fset, types.SeverityError)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Contains(t, issues[1].Note, "'Amount' is an alias of 'int'")
+}
+
+func TestPackageTypeInfoCombinesTestFiles(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"pkg.gno":         "package foo\n\nvar total int\n",
+		"helper_test.gno": "package foo\n\nvar expected int\n",
+		"main_test.go":    "package foo\n\nfunc check() { _ = int(total) + int(expected) }\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644))
+	}
+
+	for _, tc := range []struct {
+		file     string
+		expected bool
+	}{
+		{"main_test.go", true},
+		{"pkg.gno", false},
+	} {
+		path := filepath.Join(tmpDir, tc.file)
+		node, fset, err := ParseFile(path, nil)
+		require.NoError(t, err)
+
+		info := packageTypeInfo(path, node, fset)
+		found := false
+		for id, obj := range info.Defs {
+			if id.Name == "expected" && obj != nil {
+				found = true
+			}
+		}
+		assert.Equal(t, tc.expected, found, tc.file)
+	}
+}
+
+func TestShareTypeInfo(t *testing.T) {
+	t.Parallel()
+	node, fset, err := ParseFile("", []byte("package foo\n\nvar total int\n"))
+	require.NoError(t, err)
+
+	assert.NotSame(t, packageTypeInfo("", node, fset), packageTypeInfo("", node, fset))
+
+	release := ShareTypeInfo(node)
+	shared := packageTypeInfo("", node, fset)
+	assert.Same(t, shared, packageTypeInfo("", node, fset))
+	release()
+
+	assert.NotSame(t, shared, packageTypeInfo("", node, fset))
+}
+
 func TestDetectEmitFormat(t *testing.T) {
 	t.Parallel()
 	_, current, _, ok := runtime.Caller(0)
@@ -527,3 +753,30 @@ var err = errors.New("error")
 		})
 	}
 }
+
+func TestConvertGolangciIssues(t *testing.T) {
+	t.Parallel()
+	var result golangciOutput
+	require.NoError(t, json.Unmarshal([]byte(`{"Issues": [
+		{"FromLinter": "errcheck", "Text": "unchecked error", "Pos": {"Filename": "a.go", "Line": 3, "Column": 2}},
+		{"FromLinter": "unused", "Text": "unused variable", "Pos": {"Filename": "a.go", "Line": 5, "Column": 6}},
+		{"FromLinter": "gosimple", "Text": "could be simpler", "Pos": {"Filename": "a.go", "Line": 7, "Column": 1}},
+		{"FromLinter": "govet", "Text": "printf mismatch", "Pos": {"Filename": "a.go", "Line": 9, "Column": 1}}
+	]}`), &result))
+
+	errSeverity, offSeverity := types.SeverityError, types.SeverityOff
+	issues := convertGolangciIssues(result, types.SeverityWarning, map[string]LinterMapping{
+		"errcheck": {Severity: &errSeverity, Category: "correctness"},
+		"unused":   {Category: "style"},
+		"gosimple": {Severity: &offSeverity},
+	})
+
+	require.Len(t, issues, 3, "findings mapped to OFF are dropped")
+	assert.Equal(t, types.SeverityError, issues[0].Severity)
+	assert.Equal(t, "correctness", issues[0].Category)
+	assert.Equal(t, types.SeverityWarning, issues[1].Severity, "a mapping without severity keeps the rule severity")
+	assert.Equal(t, "style", issues[1].Category)
+	assert.Equal(t, "govet", issues[2].Rule)
+	assert.Equal(t, types.SeverityWarning, issues[2].Severity)
+	assert.Empty(t, issues[2].Category)
+}
@@ -0,0 +1,136 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"sort"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectMagicNumbers reports numeric literals used in arithmetic or
+// comparisons in realm code, except 0, 1 and the values listed in allow.
+//
+// A value used several times in a file is reported once, at its first
+// occurrence, with the other occurrences attached as related locations.
+func DetectMagicNumbers(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, allow []string) ([]tt.Issue, error) {
+	if !IsRealmFile(filename) {
+		return nil, nil
+	}
+
+	allowed := map[string]bool{"0": true, "1": true}
+	for _, v := range allow {
+		if value := numericValue(v); value != "" {
+			allowed[value] = true
+		}
+	}
+
+	var order []string
+	occurrences := make(map[string][]*ast.BasicLit)
+	record := func(expr ast.Expr) {
+		lit, ok := unparen(expr).(*ast.BasicLit)
+		if !ok || (lit.Kind != token.INT && lit.Kind != token.FLOAT) {
+			return
+		}
+		value := numericValue(lit.Value)
+		if value == "" || allowed[value] {
+			return
+		}
+		if _, seen := occurrences[value]; !seen {
+			order = append(order, value)
+		}
+		occurrences[value] = append(occurrences[value], lit)
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			// literals are how constants get their names.
+			return n.Tok != token.CONST
+		case *ast.BinaryExpr:
+			if isArithmeticOrComparison(n.Op) {
+				record(n.X)
+				record(n.Y)
+			}
+		case *ast.AssignStmt:
+			if n.Tok != token.ASSIGN && n.Tok != token.DEFINE {
+				// compound assignments such as x *= 3
+				for _, rhs := range n.Rhs {
+					record(rhs)
+				}
+			}
+		}
+		return true
+	})
+
+	for _, lits := range occurrences {
+		sort.Slice(lits, func(i, j int) bool { return lits[i].Pos() < lits[j].Pos() })
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return occurrences[order[i]][0].Pos() < occurrences[order[j]][0].Pos()
+	})
+
+	issues := make([]tt.Issue, 0, len(order))
+	for _, value := range order {
+		lits := occurrences[value]
+		first := lits[0]
+
+		message := fmt.Sprintf("magic number %s in arithmetic", first.Value)
+		if len(lits) > 1 {
+			message = fmt.Sprintf("magic number %s used %d times in arithmetic", first.Value, len(lits))
+		}
+
+		issue := tt.Issue{
+			Rule:     "magic-number",
+			Filename: filename,
+			Start:    fset.Position(first.Pos()),
+			End:      fset.Position(first.End()),
+			Message:  message,
+			Note:     "declare it as a named constant so its meaning is explicit and every use stays in sync.",
+			Severity: severity,
+		}
+		for _, lit := range lits[1:] {
+			issue.RelatedLocations = append(issue.RelatedLocations, tt.Location{
+				Filename: filename,
+				Start:    fset.Position(lit.Pos()),
+				End:      fset.Position(lit.End()),
+				Message:  "also used here",
+			})
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+func isArithmeticOrComparison(op token.Token) bool {
+	switch op {
+	case token.ADD, token.SUB, token.MUL, token.QUO, token.REM,
+		token.EQL, token.NEQ, token.LSS, token.GTR, token.LEQ, token.GEQ:
+		return true
+	}
+	return false
+}
+
+// numericValue normalizes a numeric literal, so that e.g. 1000, 1_000
+// and 0x3e8 are considered the same value. It returns "" for non numbers.
+func numericValue(lit string) string {
+	for _, kind := range []token.Token{token.INT, token.FLOAT} {
+		if v := constant.MakeFromLiteral(lit, kind, 0); v.Kind() != constant.Unknown {
+			return v.ExactString()
+		}
+	}
+	return ""
+}
+
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
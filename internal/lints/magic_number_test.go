@@ -0,0 +1,93 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectMagicNumbers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		code     string
+		allow    []string
+		messages []string
+		related  []int
+	}{
+		{
+			name: "repeated literal grouped",
+			code: `package bank
+
+func Fee(amount int) int {
+	if amount > 10_000 {
+		return amount * 3 / 1000
+	}
+	return amount * 5 / 1_000
+}
+`,
+			messages: []string{
+				"magic number 10_000 in arithmetic",
+				"magic number 3 in arithmetic",
+				"magic number 1000 used 2 times in arithmetic",
+				"magic number 5 in arithmetic",
+			},
+			related: []int{0, 0, 1, 0},
+		},
+		{
+			name: "constants, zero, one and allowlist",
+			code: `package bank
+
+const feeBps = 30
+
+func Fee(amount int) int {
+	if amount == 0 {
+		return 0
+	}
+	amount -= 1
+	return amount * feeBps / 100
+}
+`,
+			allow: []string{"100"},
+		},
+		{
+			name: "compound assignment",
+			code: `package bank
+
+func Grow(x int) int {
+	x *= 7
+	return x
+}
+`,
+			messages: []string{"magic number 7 in arithmetic"},
+			related:  []int{0},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "gno.mod"), []byte("module gno.land/r/demo/bank\n"), 0o644))
+			path := filepath.Join(dir, "bank.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectMagicNumbers(path, node, fset, tt.SeverityWarning, tc.allow)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+			for i, issue := range issues {
+				assert.Equal(t, "magic-number", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				assert.Len(t, issue.RelatedLocations, tc.related[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package lints
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/analysis/constfold"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+const mapFormatNote = "maps are not guaranteed to print their entries in the same order, so the comparison may fail from one run to the next. build the string from the sorted keys, or compare the map itself."
+
+// formatPackages lists the packages whose Sprint functions print maps.
+var formatPackages = map[string]bool{
+	"fmt":  true,
+	"ufmt": true,
+}
+
+// DetectMapFormatComparisons reports formatted maps compared with a fixed
+// string, either with == and != or as an argument of an assertion such as
+// uassert.Equal, directly or through a variable.
+func DetectMapFormatComparisons(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	// variables holding a formatted map, e.g. got := fmt.Sprint(m).
+	formatted := make(map[types.Object]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, rhs := range assign.Rhs {
+			id, ok := assign.Lhs[i].(*ast.Ident)
+			if ok && isMapFormat(rhs, info) {
+				if obj := info.ObjectOf(id); obj != nil {
+					formatted[obj] = true
+				}
+			}
+		}
+		return true
+	})
+	isFormatted := func(expr ast.Expr) bool {
+		if id, ok := unparen(expr).(*ast.Ident); ok && formatted[info.ObjectOf(id)] {
+			return true
+		}
+		return isMapFormat(expr, info)
+	}
+
+	var issues []tt.Issue
+	report := func(n ast.Node) {
+		issues = append(issues, tt.Issue{
+			Rule:     "map-format-comparison",
+			Filename: filename,
+			Start:    fset.Position(n.Pos()),
+			End:      fset.Position(n.End()),
+			Message:  "formatted map compared with a fixed string",
+			Note:     mapFormatNote,
+			Severity: severity,
+		})
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			if n.Op != token.EQL && n.Op != token.NEQ {
+				return true
+			}
+			if isFormatted(n.X) && isConstString(n.Y, info) || isFormatted(n.Y) && isConstString(n.X, info) {
+				report(n)
+			}
+		case *ast.CallExpr:
+			if !strings.Contains(calleeName(n), "Equal") {
+				return true
+			}
+			var got ast.Expr
+			golden := false
+			for _, arg := range n.Args {
+				if isFormatted(arg) {
+					got = arg
+				} else if isConstString(arg, info) {
+					golden = true
+				}
+			}
+			if got != nil && golden {
+				report(n)
+			}
+		}
+		return true
+	})
+
+	return issues, nil
+}
+
+// isMapFormat reports whether expr is a call to a Sprint function of fmt or
+// ufmt with a map argument.
+func isMapFormat(expr ast.Expr, info *types.Info) bool {
+	call, ok := unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !strings.HasPrefix(sel.Sel.Name, "Sprint") {
+		return false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || !formatPackages[pkg.Name] {
+		return false
+	}
+	for _, arg := range call.Args {
+		if tv, ok := info.Types[arg]; ok && tv.Type != nil {
+			if _, ok := tv.Type.Underlying().(*types.Map); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isConstString(expr ast.Expr, info *types.Info) bool {
+	return constfold.Eval(expr, info, nil).Kind() == constant.String
+}
+
+// calleeName returns the name of the function called by call, without its
+// package or receiver.
+func calleeName(call *ast.CallExpr) string {
+	switch fun := unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
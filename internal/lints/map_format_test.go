@@ -0,0 +1,47 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectMapFormatComparisons(t *testing.T) {
+	t.Parallel()
+	src := `package scores
+
+import (
+	"testing"
+
+	"gno.land/p/demo/uassert"
+	"gno.land/p/demo/ufmt"
+)
+
+const golden = "map[alice:1 bob:2]"
+
+func TestScores(t *testing.T) {
+	scores := map[string]int{"alice": 1, "bob": 2}
+
+	uassert.Equal(t, golden, ufmt.Sprintf("%v", scores)) // want "formatted map compared with a fixed string"
+
+	if ufmt.Sprint(scores) != "map[alice:1 bob:2]" { // want "formatted map compared with a fixed string"
+		t.Fail()
+	}
+
+	got := ufmt.Sprintf("%v", scores)
+	if got != golden { // want "formatted map compared with a fixed string"
+		t.Fail()
+	}
+
+	names := []string{"alice", "bob"}
+	uassert.Equal(t, "[alice bob]", ufmt.Sprint(names))
+	uassert.Equal(t, 2, len(scores))
+}
+`
+	ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectMapFormatComparisons(filename, node, fset, tt.SeverityWarning)
+	}, "scores_test.gno", src)
+}
@@ -0,0 +1,337 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"strconv"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+const mapRangeOrderNote = "go iterates over maps in a random order, which changes from one run to the next. iterate over the sorted keys instead."
+
+// DetectMapRangeOrder reports range loops over maps whose body depends on
+// the iteration order: it appends to a slice declared outside the loop,
+// concatenates to such a string, writes to such a writer, or prints.
+//
+// Slices sorted right after the loop, as in the usual collection of the
+// keys of a map followed by sort.Strings(keys), are not reported.
+//
+// With autofix, loops over maps with ordered keys come with a suggestion
+// collecting and sorting the keys first, when the file imports sort.
+func DetectMapRangeOrder(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, autofix bool) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var content []byte
+	if autofix {
+		var err error
+		if content, err = os.ReadFile(filename); err != nil {
+			return nil, err
+		}
+	}
+	sortName := importName(node, "sort")
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		following := followingStmts(fn.Body)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			loop, ok := n.(*ast.RangeStmt)
+			if !ok {
+				return true
+			}
+			t := info.TypeOf(loop.X)
+			if t == nil {
+				return true
+			}
+			m, ok := t.Underlying().(*types.Map)
+			if !ok {
+				return true
+			}
+			effect := orderDependentEffect(loop, info, following[loop])
+			if effect == "" {
+				return true
+			}
+
+			issue := tt.Issue{
+				Rule:     "map-range-order",
+				Filename: filename,
+				Start:    fset.Position(loop.Pos()),
+				End:      fset.Position(loop.End()),
+				Message:  fmt.Sprintf("the loop %s in the random iteration order of the map", effect),
+				Note:     mapRangeOrderNote,
+				Severity: severity,
+			}
+			if autofix && sortName != "" {
+				if suggestion, ok := sortedRangeSuggestion(fn, loop, m.Key(), content, fset, sortName, node.Name.Name); ok {
+					issue.Suggestion = suggestion
+				}
+			}
+			issues = append(issues, issue)
+			return true
+		})
+	}
+
+	return issues, nil
+}
+
+// orderDependentEffect describes the first effect of the body of loop
+// depending on the order of the iterations, or returns "". after holds the
+// statements following the loop in its block.
+func orderDependentEffect(loop *ast.RangeStmt, info *types.Info, after []ast.Stmt) string {
+	declaredOutside := func(expr ast.Expr) bool {
+		id := rootIdent(expr)
+		if id == nil {
+			return false
+		}
+		obj := info.Uses[id]
+		return obj != nil && (obj.Pos() < loop.Pos() || obj.Pos() > loop.End())
+	}
+
+	effect := ""
+	ast.Inspect(loop.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 || !declaredOutside(n.Lhs[0]) {
+				return true
+			}
+			if call, ok := n.Rhs[0].(*ast.CallExpr); ok && n.Tok == token.ASSIGN {
+				if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "append" && !sortedFirst(n.Lhs[0], after, info) {
+					effect = "appends"
+				}
+			}
+			if t := info.TypeOf(n.Lhs[0]); t != nil && n.Tok == token.ADD_ASSIGN {
+				if basic, ok := t.Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+					effect = "builds a string"
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); ok && formatPackages[pkg.Name] && (strings.HasPrefix(sel.Sel.Name, "Print") || strings.HasPrefix(sel.Sel.Name, "Fprint")) {
+				effect = "prints"
+			} else if strings.HasPrefix(sel.Sel.Name, "Write") && isMethodCall(sel, info) && declaredOutside(sel.X) {
+				// only writers shared by the iterations, not os.WriteFile.
+				effect = "writes"
+			}
+		}
+		return effect == ""
+	})
+	return effect
+}
+
+// isMethodCall reports whether sel selects a method of a value, rather than
+// a function of a package.
+func isMethodCall(sel *ast.SelectorExpr, info *types.Info) bool {
+	s, ok := info.Selections[sel]
+	return ok && s.Kind() == types.MethodVal
+}
+
+// followingStmts maps the statements of the blocks of body to the
+// statements following them in their block.
+func followingStmts(body *ast.BlockStmt) map[ast.Stmt][]ast.Stmt {
+	following := make(map[ast.Stmt][]ast.Stmt)
+	ast.Inspect(body, func(n ast.Node) bool {
+		var list []ast.Stmt
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		}
+		for i, stmt := range list {
+			following[stmt] = list[i+1:]
+		}
+		return true
+	})
+	return following
+}
+
+// sortFuncs holds the functions of the sort and slices packages sorting the
+// slice passed as their first argument, or wrapped in it for sort.Sort.
+var sortFuncs = map[string]map[string]bool{
+	"sort":   {"Strings": true, "Ints": true, "Float64s": true, "Slice": true, "SliceStable": true, "Sort": true, "Stable": true},
+	"slices": {"Sort": true, "SortFunc": true, "SortStableFunc": true},
+}
+
+// sortedFirst reports whether the slice variable lhs is sorted by the first
+// statement of after using it, so that the order of its elements does not
+// depend on the loop filling it.
+func sortedFirst(lhs ast.Expr, after []ast.Stmt, info *types.Info) bool {
+	id, ok := unparen(lhs).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	v := info.Uses[id]
+	if v == nil {
+		return false
+	}
+	for _, stmt := range after {
+		if !mentions(stmt, v, info) {
+			continue
+		}
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			return false
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 || !mentions(call.Args[0], v, info) {
+			return false
+		}
+		sel, ok := unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		pkgName, ok := info.Uses[pkg].(*types.PkgName)
+		return ok && sortFuncs[pkgName.Imported().Path()][sel.Sel.Name]
+	}
+	return false
+}
+
+// mentions reports whether n uses the object obj.
+func mentions(n ast.Node, obj types.Object, info *types.Info) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// sortedRangeSuggestion rewrites loop to range over the sorted keys of the
+// map, sorted with the sort package imported as sortName.
+func sortedRangeSuggestion(fn *ast.FuncDecl, loop *ast.RangeStmt, keyType types.Type, content []byte, fset *token.FileSet, sortName, pkgName string) (string, bool) {
+	if loop.Tok != token.DEFINE || !isPlainOperand(loop.X) {
+		return "", false
+	}
+	if _, ok := sortCallFor(keyType, sortName, ""); !ok {
+		return "", false
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	fresh := func(name string) string {
+		candidate := name
+		for i := 1; used[candidate]; i++ {
+			candidate = name + strconv.Itoa(i)
+		}
+		used[candidate] = true
+		return candidate
+	}
+
+	key := "_"
+	if id, ok := loop.Key.(*ast.Ident); ok {
+		key = id.Name
+	}
+	if key == "_" {
+		key = fresh("k")
+	}
+	value := ""
+	if id, ok := loop.Value.(*ast.Ident); ok && id.Name != "_" {
+		value = id.Name
+	}
+	keys := fresh("keys")
+
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+	lineStart := offset(loop.Pos()) - (fset.Position(loop.Pos()).Column - 1)
+	indent := string(content[lineStart:offset(loop.Pos())])
+	m := string(content[offset(loop.X.Pos()):offset(loop.X.End())])
+	qualifier := func(p *types.Package) string {
+		if p.Name() == pkgName {
+			return ""
+		}
+		return p.Name()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s := make([]%s, 0, len(%s))\n", keys, types.TypeString(keyType, qualifier), m)
+	fmt.Fprintf(&b, "%sfor %s := range %s {\n", indent, key, m)
+	fmt.Fprintf(&b, "%s\t%s = append(%s, %s)\n", indent, keys, keys, key)
+	fmt.Fprintf(&b, "%s}\n", indent)
+	sortCall, _ := sortCallFor(keyType, sortName, keys)
+	fmt.Fprintf(&b, "%s%s\n", indent, sortCall)
+	fmt.Fprintf(&b, "%sfor _, %s := range %s {", indent, key, keys)
+	if value != "" {
+		fmt.Fprintf(&b, "\n%s\t%s := %s[%s]", indent, value, m, key)
+	}
+	b.Write(content[offset(loop.Body.Lbrace)+1 : offset(loop.Body.Rbrace)])
+	b.WriteString("}")
+
+	suggestion := b.String()
+	if err := validateRewrite(string(content[offset(loop.Pos()):offset(loop.End())]), suggestion); err != nil {
+		return "", false
+	}
+	return suggestion, true
+}
+
+// sortCallFor returns the statement sorting the slice keys of t, or false
+// when the values of t are not ordered.
+func sortCallFor(t types.Type, sortName, keys string) (string, bool) {
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsOrdered == 0 {
+		return "", false
+	}
+	switch {
+	case types.Identical(t, types.Typ[types.String]):
+		return fmt.Sprintf("%s.Strings(%s)", sortName, keys), true
+	case types.Identical(t, types.Typ[types.Int]):
+		return fmt.Sprintf("%s.Ints(%s)", sortName, keys), true
+	case types.Identical(t, types.Typ[types.Float64]):
+		return fmt.Sprintf("%s.Float64s(%s)", sortName, keys), true
+	}
+	return fmt.Sprintf("%s.Slice(%s, func(i, j int) bool { return %s[i] < %s[j] })", sortName, keys, keys, keys), true
+}
+
+// isPlainOperand reports whether expr is an identifier or a selector of
+// identifiers, which can be evaluated twice without side effects.
+func isPlainOperand(expr ast.Expr) bool {
+	switch e := unparen(expr).(type) {
+	case *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		return isPlainOperand(e.X)
+	}
+	return false
+}
+
+// importName returns the name under which node imports path, or "" when it
+// does not import it.
+func importName(node *ast.File, path string) string {
+	for _, imp := range node.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err != nil || p != path {
+			continue
+		}
+		if imp.Name != nil {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				return ""
+			}
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
@@ -0,0 +1,264 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectMapRangeOrder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		code        string
+		autofix     bool
+		messages    []string
+		suggestions []string
+	}{
+		{
+			name: "order-dependent loops",
+			code: `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func Names(m map[string]int) ([]string, string) {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	out := ""
+	for name, n := range m {
+		out += fmt.Sprint(name, n)
+	}
+	var b strings.Builder
+	for name := range m {
+		b.WriteString(name)
+	}
+	for name, n := range m {
+		fmt.Println(name, n)
+	}
+	return names, out
+}
+`,
+			messages: []string{
+				"the loop appends in the random iteration order of the map",
+				"the loop builds a string in the random iteration order of the map",
+				"the loop writes in the random iteration order of the map",
+				"the loop prints in the random iteration order of the map",
+			},
+			suggestions: []string{"", "", "", ""},
+		},
+		{
+			name: "order-independent loops",
+			code: `package main
+
+func Sum(m map[string]int, xs []int) int {
+	total := 0
+	for _, n := range m {
+		total += n
+	}
+	for _, x := range xs {
+		total += x
+	}
+	for k := range m {
+		var local []string
+		local = append(local, k)
+		_ = local
+	}
+	return total
+}
+`,
+		},
+		{
+			name: "sorted keys and package-level writes",
+			code: `package main
+
+import (
+	"os"
+	"slices"
+	"sort"
+)
+
+func Keys(m map[string]int) ([]string, []string) {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var names []string
+	for k := range m {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	for k := range m {
+		os.WriteFile(k, nil, 0o644)
+	}
+	return keys, names
+}
+`,
+		},
+		{
+			name: "keys used before being sorted",
+			code: `package main
+
+import "sort"
+
+func Keys(m map[string]int) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	println(keys[0])
+	sort.Strings(keys)
+	return keys
+}
+`,
+			messages:    []string{"the loop appends in the random iteration order of the map"},
+			suggestions: []string{""},
+		},
+		{
+			name: "autofix with string keys",
+			code: `package main
+
+import "sort"
+
+func Values(m map[string]int) []int {
+	var out []int
+	for k, v := range m {
+		// keep the order of the keys
+		out = append(out, v+len(k))
+	}
+	_ = sort.Strings
+	return out
+}
+`,
+			autofix:  true,
+			messages: []string{"the loop appends in the random iteration order of the map"},
+			suggestions: []string{`keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := m[k]
+		// keep the order of the keys
+		out = append(out, v+len(k))
+	}`},
+		},
+		{
+			name: "autofix with a blank key and uint keys",
+			code: `package main
+
+import "sort"
+
+func Values(m map[uint]string, keys []string) []string {
+	for _, v := range m {
+		keys = append(keys, v)
+	}
+	_ = sort.Ints
+	return keys
+}
+`,
+			autofix:  true,
+			messages: []string{"the loop appends in the random iteration order of the map"},
+			suggestions: []string{`keys1 := make([]uint, 0, len(m))
+	for k := range m {
+		keys1 = append(keys1, k)
+	}
+	sort.Slice(keys1, func(i, j int) bool { return keys1[i] < keys1[j] })
+	for _, k := range keys1 {
+		v := m[k]
+		keys = append(keys, v)
+	}`},
+		},
+		{
+			name: "no autofix without the sort import or ordered keys",
+			code: `package main
+
+type point struct{ x, y int }
+
+func Values(m map[string]int, p map[point]int) []int {
+	var out []int
+	for _, v := range m {
+		out = append(out, v)
+	}
+	for _, v := range p {
+		out = append(out, v)
+	}
+	return out
+}
+`,
+			autofix: true,
+			messages: []string{
+				"the loop appends in the random iteration order of the map",
+				"the loop appends in the random iteration order of the map",
+			},
+			suggestions: []string{"", ""},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "main.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectMapRangeOrder(path, node, fset, tt.SeverityWarning, tc.autofix)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+			for i, issue := range issues {
+				assert.Equal(t, "map-range-order", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				assert.Equal(t, tc.suggestions[i], issue.Suggestion)
+			}
+		})
+	}
+}
+
+func TestMapRangeOrderSuggestionIsClean(t *testing.T) {
+	t.Parallel()
+	code := `package main
+
+import "sort"
+
+func Values(m map[string]int) []int {
+	var out []int
+	for k, v := range m {
+		out = append(out, v+len(k))
+	}
+	_ = sort.Strings
+	return out
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte(code), 0o644))
+
+	node, fset, err := ParseFile(path, nil)
+	require.NoError(t, err)
+	issues, err := DetectMapRangeOrder(path, node, fset, tt.SeverityWarning, true)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.NotEmpty(t, issues[0].Suggestion)
+
+	fixed := code[:issues[0].Start.Offset] + issues[0].Suggestion + code[issues[0].End.Offset:]
+	fixedPath := filepath.Join(dir, "fixed.go")
+	require.NoError(t, os.WriteFile(fixedPath, []byte(fixed), 0o644))
+
+	node, fset, err = ParseFile(fixedPath, nil)
+	require.NoError(t, err)
+	issues, err = DetectMapRangeOrder(fixedPath, node, fset, tt.SeverityWarning, true)
+	require.NoError(t, err)
+	assert.Empty(t, issues, "the suggested rewrite must not be reported again")
+}
@@ -0,0 +1,126 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// failNowMethods are the methods of testing.T and testing.B stopping the
+// goroutine calling them, through runtime.Goexit.
+var failNowMethods = map[string]bool{
+	"Fatal":   true,
+	"Fatalf":  true,
+	"FailNow": true,
+	"Skip":    true,
+	"Skipf":   true,
+	"SkipNow": true,
+}
+
+// failNowPackages are the assertion packages whose functions call FailNow.
+var failNowPackages = map[string]bool{
+	"urequire": true,
+	"require":  true,
+}
+
+var testingTypes = map[string]bool{
+	"*testing.T": true,
+	"*testing.B": true,
+	"testing.TB": true,
+}
+
+// DetectMisplacedTestFatal reports calls to t.Fatal, t.FailNow, t.Skip and
+// urequire assertions in goroutines and deferred functions of tests, where
+// they do not stop the test as expected.
+//
+// FailNow stops the goroutine it is called from: in a goroutine started by the
+// test, the test is marked as failed but keeps running. In a deferred
+// function, the test body has already run when the failure is reported.
+func DetectMisplacedTestFatal(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	// parameters holding a *testing.T, *testing.B or testing.TB.
+	testing := make(map[types.Object]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		ft, ok := n.(*ast.FuncType)
+		if !ok {
+			return true
+		}
+		for _, field := range ft.Params.List {
+			if !testingTypes[types.ExprString(field.Type)] {
+				continue
+			}
+			for _, name := range field.Names {
+				if obj := info.Defs[name]; obj != nil {
+					testing[obj] = true
+				}
+			}
+		}
+		return true
+	})
+	if len(testing) == 0 {
+		return nil, nil
+	}
+
+	var issues []tt.Issue
+	check := func(lit *ast.FuncLit, message, note string) {
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isFailNowCall(call, info, testing) {
+				return true
+			}
+			name := types.ExprString(call.Fun)
+			issues = append(issues, tt.Issue{
+				Rule:     "misplaced-test-fatal",
+				Filename: filename,
+				Start:    fset.Position(call.Pos()),
+				End:      fset.Position(call.End()),
+				Message:  fmt.Sprintf(message, name),
+				Note:     note,
+				Severity: severity,
+			})
+			return true
+		})
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			if lit, ok := n.Call.Fun.(*ast.FuncLit); ok {
+				check(lit, "%s called in a goroutine",
+					"FailNow, and the Fatal, Skip and urequire helpers calling it, only stop the goroutine they run in. the test is marked as failed but keeps running. report the failure with t.Error, or send it to the test goroutine over a channel.")
+			}
+		case *ast.DeferStmt:
+			if lit, ok := n.Call.Fun.(*ast.FuncLit); ok {
+				check(lit, "%s called in a deferred function",
+					"deferred functions run once the test function returns, so the failure cannot stop the statements it was meant to guard. check the condition where it arises, or use t.Error to report it.")
+			}
+		}
+		return true
+	})
+
+	return issues, nil
+}
+
+func isFailNowCall(call *ast.CallExpr, info *types.Info, testing map[types.Object]bool) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if failNowMethods[sel.Sel.Name] && testing[info.ObjectOf(x)] {
+		return true
+	}
+	// urequire.Equal(t, ...), with the package possibly not resolved.
+	if _, isVar := info.ObjectOf(x).(*types.Var); isVar || !failNowPackages[x.Name] || len(call.Args) == 0 {
+		return false
+	}
+	arg, ok := call.Args[0].(*ast.Ident)
+	return ok && testing[info.ObjectOf(arg)]
+}
@@ -0,0 +1,66 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectMisplacedTestFatal(t *testing.T) {
+	t.Parallel()
+	src := `package worker
+
+import (
+	"testing"
+
+	"gno.land/p/demo/uassert"
+	"gno.land/p/demo/urequire"
+)
+
+func TestWorker(t *testing.T) {
+	done := make(chan bool)
+	go func() {
+		if err := run(); err != nil {
+			t.Fatal(err) // want "t.Fatal called in a goroutine"
+		}
+		urequire.True(t, check()) // want "urequire.True called in a goroutine"
+		uassert.True(t, check())
+		t.Error("reported")
+		done <- true
+	}()
+	<-done
+
+	defer func() {
+		if !check() {
+			t.FailNow() // want "t.FailNow called in a deferred function"
+		}
+	}()
+
+	if err := run(); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
+
+func BenchmarkWorker(b *testing.B) {
+	go func() {
+		b.Skip("slow") // want "b.Skip called in a goroutine"
+	}()
+}
+
+func helper(fatal func(...any)) {
+	go func() {
+		fatal("not a test")
+	}()
+}
+
+func run() error { return nil }
+
+func check() bool { return true }
+`
+	ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectMisplacedTestFatal(filename, node, fset, tt.SeverityWarning)
+	}, "worker_test.gno", src)
+}
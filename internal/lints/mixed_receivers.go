@@ -0,0 +1,242 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectMixedReceivers reports value receiver methods assigning to their
+// receiver, on types that also have pointer receiver methods.
+//
+// Methods with a value receiver operate on a copy, so mixing both kinds
+// makes it easy to write a method that silently loses its mutations. Value
+// methods that do not assign to their receiver are fine as they are.
+// Value methods required for the value type to implement an interface used
+// in the package, or one of the interfaces of the standard library such as
+// fmt.Stringer and json.Marshaler, are left alone, since switching them to a
+// pointer receiver would break that interface.
+func DetectMixedReceivers(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+	interfaces := usedInterfaces(info)
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+			continue
+		}
+		if _, isPtr := fn.Recv.List[0].Type.(*ast.StarExpr); isPtr {
+			continue
+		}
+
+		method, ok := info.Defs[fn.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		named := receiverNamed(method)
+		if named == nil {
+			continue
+		}
+
+		ptrMethod := firstPointerMethod(named)
+		if ptrMethod == nil || requiredByInterface(named, method, interfaces) || implementsStdInterface(method) {
+			continue
+		}
+		recv := receiverVar(fn, info)
+		if recv == nil || !assignsTo(fn.Body, recv, info) {
+			continue
+		}
+
+		issue := tt.Issue{
+			Rule:     "mixed-receivers",
+			Filename: filename,
+			Start:    fset.Position(fn.Recv.Pos()),
+			End:      fset.Position(fn.Recv.End()),
+			Message: fmt.Sprintf(
+				"method %s of %s has a value receiver while other methods use a pointer receiver",
+				fn.Name.Name, named.Obj().Name(),
+			),
+			Note: fmt.Sprintf(
+				"%s assigns to %s, a copy of the value it is called on, so the change is lost when it returns. use a pointer receiver (*%s) for every method, so that all of them see and modify the same value.",
+				fn.Name.Name, recv.Name(), named.Obj().Name(),
+			),
+			Severity: severity,
+		}
+		if ptrMethod.Pos().IsValid() {
+			issue.RelatedLocations = []tt.Location{{
+				Filename: fset.Position(ptrMethod.Pos()).Filename,
+				Start:    fset.Position(ptrMethod.Pos()),
+				End:      fset.Position(ptrMethod.Pos()),
+				Message:  fmt.Sprintf("%s has a pointer receiver", ptrMethod.Name()),
+			}}
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// receiverNamed returns the named type of the receiver of method, whether
+// it is a value or a pointer receiver.
+func receiverNamed(method *types.Func) *types.Named {
+	sig, ok := method.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return nil
+	}
+	recv := types.Unalias(sig.Recv().Type())
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = types.Unalias(ptr.Elem())
+	}
+	named, _ := recv.(*types.Named)
+	return named
+}
+
+func firstPointerMethod(named *types.Named) *types.Func {
+	var first *types.Func
+	for i := 0; i < named.NumMethods(); i++ {
+		m := named.Method(i)
+		sig, ok := m.Type().(*types.Signature)
+		if !ok || sig.Recv() == nil {
+			continue
+		}
+		if _, isPtr := sig.Recv().Type().(*types.Pointer); !isPtr {
+			continue
+		}
+		if first == nil || m.Pos() < first.Pos() {
+			first = m
+		}
+	}
+	return first
+}
+
+// usedInterfaces returns the non-empty interface types appearing in the package.
+func usedInterfaces(info *types.Info) []*types.Interface {
+	seen := make(map[types.Type]bool)
+	var interfaces []*types.Interface
+	for _, tv := range info.Types {
+		if tv.Type == nil || seen[tv.Type] {
+			continue
+		}
+		seen[tv.Type] = true
+		iface, ok := tv.Type.Underlying().(*types.Interface)
+		if ok && iface.NumMethods() > 0 {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	return interfaces
+}
+
+// requiredByInterface reports whether the value type named implements one of
+// interfaces through method.
+func requiredByInterface(named *types.Named, method *types.Func, interfaces []*types.Interface) bool {
+	for _, iface := range interfaces {
+		if !types.Implements(named, iface) {
+			continue
+		}
+		for i := 0; i < iface.NumMethods(); i++ {
+			if iface.Method(i).Name() == method.Name() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stdMethods holds the methods through which the standard library uses
+// values, such as fmt with String and encoding/json with MarshalJSON, by
+// name and signature.
+var stdMethods = map[string]string{
+	"String":        "func() string",
+	"GoString":      "func() string",
+	"Error":         "func() string",
+	"Format":        "func(fmt.State, rune)",
+	"MarshalJSON":   "func() ([]byte, error)",
+	"MarshalText":   "func() ([]byte, error)",
+	"MarshalBinary": "func() ([]byte, error)",
+}
+
+// implementsStdInterface reports whether method is one of stdMethods.
+func implementsStdInterface(method *types.Func) bool {
+	want, ok := stdMethods[method.Name()]
+	if !ok {
+		return false
+	}
+	sig := method.Type().(*types.Signature)
+	unnamed := func(tuple *types.Tuple) *types.Tuple {
+		vars := make([]*types.Var, tuple.Len())
+		for i := range vars {
+			vars[i] = types.NewParam(token.NoPos, nil, "", tuple.At(i).Type())
+		}
+		return types.NewTuple(vars...)
+	}
+	plain := types.NewSignatureType(nil, nil, nil, unnamed(sig.Params()), unnamed(sig.Results()), sig.Variadic())
+	return types.TypeString(plain, func(p *types.Package) string { return p.Name() }) == want
+}
+
+// receiverVar returns the receiver variable of fn, nil if it is unnamed.
+func receiverVar(fn *ast.FuncDecl, info *types.Info) *types.Var {
+	names := fn.Recv.List[0].Names
+	if len(names) == 0 {
+		return nil
+	}
+	v, _ := info.Defs[names[0]].(*types.Var)
+	return v
+}
+
+// assignsTo reports whether body assigns to recv, one of its fields or an
+// element of one of its arrays, which only changes the copy recv is.
+func assignsTo(body *ast.BlockStmt, recv *types.Var, info *types.Info) bool {
+	if body == nil {
+		return false
+	}
+	modifies := func(e ast.Expr) bool {
+		for {
+			switch x := unparen(e).(type) {
+			case *ast.Ident:
+				return info.Uses[x] == recv
+			case *ast.SelectorExpr:
+				// fields reached through a pointer are shared with the caller.
+				t := info.TypeOf(x.X)
+				if t == nil {
+					return false
+				}
+				if _, isPtr := t.Underlying().(*types.Pointer); isPtr {
+					return false
+				}
+				e = x.X
+			case *ast.IndexExpr:
+				t := info.TypeOf(x.X)
+				if t == nil {
+					return false
+				}
+				if _, isArray := t.Underlying().(*types.Array); !isArray {
+					return false
+				}
+				e = x.X
+			default:
+				return false
+			}
+		}
+	}
+
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				return true
+			}
+			for _, lhs := range n.Lhs {
+				found = found || modifies(lhs)
+			}
+		case *ast.IncDecStmt:
+			found = found || modifies(n.X)
+		}
+		return !found
+	})
+	return found
+}
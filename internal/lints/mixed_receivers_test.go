@@ -0,0 +1,164 @@
+package lints
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectMixedReceivers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		code     string
+		expected []string
+	}{
+		{
+			name: "mixed receivers",
+			code: `package main
+
+type Counter struct{ n int }
+
+func (c *Counter) Inc() { c.n++ }
+
+func (c Counter) Reset() { c.n = 0 }
+
+func (c Counter) Value() int { return c.n }
+
+func (c Counter) Bump() int {
+	c.n++
+	return c.n
+}
+`,
+			expected: []string{"Reset", "Bump"},
+		},
+		{
+			name: "value methods changing shared data",
+			code: `package main
+
+type List struct {
+	items []int
+	next  *List
+	fixed [2]int
+}
+
+func (l *List) Push(v int) { l.items = append(l.items, v) }
+
+func (l List) Set(i, v int) { l.items[i] = v }
+
+func (l List) Link(v int) { l.next.items = nil }
+
+func (l List) Fix(v int) { l.fixed[0] = v }
+
+func (l List) Copy() List {
+	c := l
+	c.items = nil
+	return c
+}
+`,
+			expected: []string{"Fix"},
+		},
+		{
+			name: "value methods used by the standard library",
+			code: `package main
+
+import "fmt"
+
+type Severity int
+
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	*s = 1
+	return nil
+}
+
+func (s Severity) String() string {
+	s = 2
+	return "severity"
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	s++
+	return []byte("1"), nil
+}
+
+func (s Severity) Format(f fmt.State, verb rune) { s = 0 }
+
+func (s Severity) Level() int {
+	s--
+	return int(s)
+}
+`,
+			expected: []string{"Level"},
+		},
+		{
+			name: "only pointer receivers",
+			code: `package main
+
+type Counter struct{ n int }
+
+func (c *Counter) Inc() { c.n++ }
+
+func (c *Counter) Value() int { return c.n }
+`,
+		},
+		{
+			name: "only value receivers",
+			code: `package main
+
+type Point struct{ x, y int }
+
+func (p Point) X() int { return p.x }
+
+func (p Point) Y() int { return p.y }
+`,
+		},
+		{
+			name: "value method required by an interface",
+			code: `package main
+
+type Stringer interface{ String() string }
+
+type Counter struct{ n int }
+
+func (c *Counter) Inc() { c.n++ }
+
+func (c Counter) String() string {
+	c.n = 0
+	return "counter"
+}
+
+func (c Counter) Reset() { c.n = 0 }
+
+var _ Stringer = Counter{}
+`,
+			expected: []string{"Reset"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "test.go", tc.code, 0)
+			require.NoError(t, err)
+
+			issues, err := DetectMixedReceivers("test.go", node, fset, tt.SeverityWarning)
+			require.NoError(t, err)
+
+			var methods []string
+			for _, issue := range issues {
+				assert.Equal(t, "mixed-receivers", issue.Rule)
+				require.Len(t, issue.RelatedLocations, 1)
+				assert.Contains(t, issue.RelatedLocations[0].Message, "has a pointer receiver")
+				methods = append(methods, strings.Fields(issue.Message)[1])
+			}
+			assert.ElementsMatch(t, tc.expected, methods)
+		})
+	}
+}
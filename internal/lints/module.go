@@ -0,0 +1,169 @@
+package lints
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleRoot returns the closest directory holding a gno.mod or a go.mod
+// file, among dir and its parents. A checkout may hold several modules, each
+// file belongs to the module of its closest root.
+func ModuleRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, name := range []string{"gno.mod", "go.mod"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// moduleAt returns the path of the module declared in dir, by its gno.mod
+// or else its go.mod.
+func moduleAt(dir string) (string, bool) {
+	if path, ok := ReadModulePath(filepath.Join(dir, "gno.mod")); ok {
+		return path, true
+	}
+	return ReadModulePath(filepath.Join(dir, "go.mod"))
+}
+
+// FindWorkFile returns the closest go.work file among dir and its parents.
+func FindWorkFile(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, "go.work")
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// ReadWorkFile returns the directories of the modules listed by the use
+// directives of a go.work file, relative directories being resolved from
+// the directory of the file.
+func ReadWorkFile(workFile string) ([]string, bool) {
+	f, err := os.Open(workFile)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	base := filepath.Dir(workFile)
+	add := func(dirs []string, dir string) []string {
+		dir = strings.Trim(dir, `"`)
+		if dir == "" {
+			return dirs
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(base, filepath.FromSlash(dir))
+		}
+		return append(dirs, filepath.Clean(dir))
+	}
+
+	var dirs []string
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			dirs = add(dirs, line)
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use ") || strings.HasPrefix(line, "use\t"):
+			rest := strings.TrimSpace(line[len("use"):])
+			if rest == "(" {
+				inBlock = true
+			} else {
+				dirs = add(dirs, rest)
+			}
+		}
+	}
+	return dirs, true
+}
+
+// resolveImportDir returns the directory holding the sources of the package
+// imported as path by a file of dir, when it belongs to a module of the
+// checkout: the module of dir itself, a module listed by the go.work file
+// around dir, or a module of the tree dir is part of, such as the examples
+// directory of gno where gno.land/p/demo/avl lives in gno.land/p/demo/avl.
+func resolveImportDir(dir, path string) (string, bool) {
+	var roots []string
+	if root, ok := ModuleRoot(dir); ok {
+		roots = append(roots, root)
+	}
+	if workFile, ok := FindWorkFile(dir); ok {
+		if uses, ok := ReadWorkFile(workFile); ok {
+			roots = append(roots, uses...)
+		}
+	}
+	for _, root := range roots {
+		modPath, ok := moduleAt(root)
+		if !ok {
+			continue
+		}
+		if rest, ok := cutImportPrefix(path, modPath); ok {
+			return existingDir(filepath.Join(root, filepath.FromSlash(rest)))
+		}
+	}
+
+	if len(roots) == 0 {
+		return "", false
+	}
+	modPath, ok := moduleAt(roots[0])
+	if !ok {
+		return "", false
+	}
+	slashed := filepath.ToSlash(roots[0])
+	tree, ok := strings.CutSuffix(slashed, "/"+modPath)
+	if !ok {
+		return "", false
+	}
+	candidate := filepath.Join(filepath.FromSlash(tree), filepath.FromSlash(path))
+	if declared, ok := moduleAt(candidate); !ok || declared != path {
+		return "", false
+	}
+	return candidate, true
+}
+
+// cutImportPrefix returns what follows the module path modPath in the import
+// path path, if path belongs to the module.
+func cutImportPrefix(path, modPath string) (string, bool) {
+	if path == modPath {
+		return "", true
+	}
+	return strings.CutPrefix(path, modPath+"/")
+}
+
+func existingDir(dir string) (string, bool) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+	return dir, true
+}
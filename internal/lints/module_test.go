@@ -0,0 +1,116 @@
+package lints
+
+import (
+	"go/types"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleRoot(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"gno.mod":           "module gno.land/r/demo/outer\n",
+		"inner/gno.mod":     "module gno.land/r/demo/inner\n",
+		"inner/sub/a.gno":   "package sub\n",
+		"tools/go.mod":      "module example.com/tools\n",
+		"plain/nothing.txt": "\n",
+	})
+
+	for dir, expected := range map[string]string{
+		"inner/sub": "inner",
+		"inner":     "inner",
+		"tools":     "tools",
+		"plain":     ".",
+	} {
+		got, ok := ModuleRoot(filepath.Join(root, filepath.FromSlash(dir)))
+		require.True(t, ok, dir)
+		assert.Equal(t, filepath.Join(root, filepath.FromSlash(expected)), got, dir)
+	}
+}
+
+func TestReadWorkFile(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"go.work": `go 1.22
+
+use ./tools // the tools module
+use (
+	./examples
+	"../shared"
+)
+`,
+	})
+
+	dirs, ok := ReadWorkFile(filepath.Join(root, "go.work"))
+	require.True(t, ok)
+	assert.Equal(t, []string{
+		filepath.Join(root, "tools"),
+		filepath.Join(root, "examples"),
+		filepath.Join(filepath.Dir(root), "shared"),
+	}, dirs)
+
+	_, ok = ReadWorkFile(filepath.Join(root, "missing", "go.work"))
+	assert.False(t, ok)
+}
+
+func TestResolveImportDir(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"go.work":                                      "go 1.22\n\nuse ./lib\n",
+		"lib/go.mod":                                   "module example.com/lib\n",
+		"lib/text/text.go":                             "package text\n",
+		"examples/gno.land/p/demo/avl/gno.mod":         "module gno.land/p/demo/avl\n",
+		"examples/gno.land/r/demo/app/gno.mod":         "module gno.land/r/demo/app\n",
+		"examples/gno.land/r/demo/app/store/store.gno": "package store\n",
+	})
+	app := filepath.Join(root, "examples", "gno.land", "r", "demo", "app")
+
+	for path, expected := range map[string]string{
+		"gno.land/r/demo/app/store": "examples/gno.land/r/demo/app/store",
+		"gno.land/p/demo/avl":       "examples/gno.land/p/demo/avl",
+		"example.com/lib/text":      "lib/text",
+		"gno.land/p/demo/missing":   "",
+		"example.com/lib/missing":   "",
+	} {
+		dir, ok := resolveImportDir(app, path)
+		if expected == "" {
+			assert.False(t, ok, path)
+			continue
+		}
+		require.True(t, ok, path)
+		assert.Equal(t, filepath.Join(root, filepath.FromSlash(expected)), dir, path)
+	}
+}
+
+func TestPackageTypeInfoResolvesModuleImports(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"gno.land/p/demo/coins/gno.mod":   "module gno.land/p/demo/coins\n",
+		"gno.land/p/demo/coins/coins.gno": "package coins\n\ntype Coin struct{ Amount int }\n",
+		"gno.land/r/demo/bank/gno.mod":    "module gno.land/r/demo/bank\n",
+		"gno.land/r/demo/bank/bank.gno": `package bank
+
+import "gno.land/p/demo/coins"
+
+var reserve coins.Coin
+`,
+	})
+
+	path := filepath.Join(root, "gno.land", "r", "demo", "bank", "bank.gno")
+	node, fset, err := ParseFile(path, nil)
+	require.NoError(t, err)
+
+	info := packageTypeInfo(path, node, fset)
+	var reserve types.Object
+	for id, obj := range info.Defs {
+		if id.Name == "reserve" {
+			reserve = obj
+		}
+	}
+	require.NotNil(t, reserve)
+	assert.Equal(t, "gno.land/p/demo/coins.Coin", reserve.Type().String())
+}
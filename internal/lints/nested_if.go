@@ -0,0 +1,194 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/directive"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectNestedIfs detects if statements whose body is a single if statement,
+// both without else, which can be merged into one if with combined conditions.
+func DetectNestedIfs(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	var issues []tt.Issue
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	elseIfs := elseIfStmts(node)
+	pure := pureFuncs(node, fset)
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		outer, ok := n.(*ast.IfStmt)
+		if !ok || elseIfs[outer] {
+			return true
+		}
+		inner := nestedIfCandidate(outer, pure)
+		if inner == nil {
+			return true
+		}
+		suggestion, err := generateNestedIfSuggestion(extractSnippet(outer, fset, content), pure)
+		if err != nil {
+			suggestion = ""
+		}
+
+		issues = append(issues, tt.Issue{
+			Rule:       "nested-if",
+			Filename:   filename,
+			Start:      fset.Position(outer.Pos()),
+			End:        fset.Position(outer.End()),
+			Message:    "nested if statements can be merged into one",
+			Suggestion: suggestion,
+			Note:       "the inner condition is only evaluated when the outer one holds, which `&&` preserves.",
+			Severity:   severity,
+		})
+		// overlapping fixes cannot be applied together,
+		// deeper nestings are reported again once this one is merged.
+		return false
+	})
+
+	return issues, nil
+}
+
+// nestedIfCandidate returns the if statement nested in outer that can be merged
+// with it, or nil.
+func nestedIfCandidate(outer *ast.IfStmt, pure map[string]bool) *ast.IfStmt {
+	if outer.Else != nil || outer.Init != nil || len(outer.Body.List) != 1 {
+		return nil
+	}
+	inner, ok := outer.Body.List[0].(*ast.IfStmt)
+	if !ok || inner.Else != nil || inner.Init != nil {
+		return nil
+	}
+	if hasSideEffects(inner.Cond, pure) {
+		return nil
+	}
+	return inner
+}
+
+// pureFuncs returns the names of the functions of node annotated with
+// //tlin:pure, whose calls have no side effects.
+func pureFuncs(node *ast.File, fset *token.FileSet) map[string]bool {
+	pure := make(map[string]bool)
+	for _, fn := range directive.ParseAnnotations(node, fset).Funcs("pure") {
+		if fn.Recv == nil {
+			pure[fn.Name.Name] = true
+		}
+	}
+	return pure
+}
+
+// hasSideEffects reports whether evaluating expr may call a function or receive from a channel.
+// Calls to the functions in pure do not count, their arguments still do.
+func hasSideEffects(expr ast.Expr, pure map[string]bool) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if id, ok := unparen(n.Fun).(*ast.Ident); !ok || !pure[id.Name] {
+				found = true
+			}
+		case *ast.FuncLit:
+			found = true
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// generateNestedIfSuggestion merges the nested if statements in snippet.
+// The rewrite is done on the text and then formatted, so that comments stay in place.
+func generateNestedIfSuggestion(snippet string, pure map[string]bool) (string, error) {
+	src := wrapSnippet(snippet)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	var outer, inner *ast.IfStmt
+	ast.Inspect(file, func(n ast.Node) bool {
+		if outer != nil {
+			return false
+		}
+		if ifStmt, ok := n.(*ast.IfStmt); ok {
+			if inner = nestedIfCandidate(ifStmt, pure); inner != nil {
+				outer = ifStmt
+			}
+			return false
+		}
+		return true
+	})
+	if outer == nil {
+		return "", fmt.Errorf("no nested if statements to merge")
+	}
+
+	x, err := andOperand(fset, outer.Cond)
+	if err != nil {
+		return "", err
+	}
+	y, err := andOperand(fset, inner.Cond)
+	if err != nil {
+		return "", err
+	}
+
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+	// edit from the end so that earlier offsets stay valid.
+	start, end := lineSpan(src, offset(inner.Body.Rbrace), offset(inner.Body.Rbrace)+1)
+	src = src[:start] + src[end:]
+	start, end = lineSpan(src, offset(inner.If), offset(inner.Body.Lbrace)+1)
+	src = src[:start] + src[end:]
+	src = src[:offset(outer.Cond.Pos())] + x + " && " + y + src[offset(outer.Cond.End()):]
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return "", err
+	}
+	body := string(formatted)
+	body = body[strings.Index(body, "func main() ")+len("func main() "):]
+
+	suggestion := cleanUpResult(body)
+	if err := validateRewrite(snippet, suggestion); err != nil {
+		return "", err
+	}
+	return suggestion, nil
+}
+
+// lineSpan widens [start, end) to whole lines when nothing else than blanks
+// shares them, so that removing the span leaves no empty line behind.
+func lineSpan(src string, start, end int) (int, int) {
+	lineStart := strings.LastIndexByte(src[:start], '\n') + 1
+	lineEnd := len(src)
+	if i := strings.IndexByte(src[end:], '\n'); i >= 0 {
+		lineEnd = end + i + 1
+	}
+	if strings.TrimSpace(src[lineStart:start]) == "" && strings.TrimSpace(src[end:lineEnd]) == "" {
+		return lineStart, lineEnd
+	}
+	return start, end
+}
+
+// andOperand prints expr as an operand of `&&`, parenthesizing it when it binds looser.
+func andOperand(fset *token.FileSet, expr ast.Expr) (string, error) {
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, expr); err != nil {
+		return "", err
+	}
+	if bin, ok := expr.(*ast.BinaryExpr); ok && bin.Op == token.LOR {
+		return "(" + buf.String() + ")", nil
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,204 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectNestedIfs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		code       string
+		expected   int
+		suggestion string
+	}{
+		{
+			name: "merge conditions",
+			code: `package main
+
+func main() {
+	if a > 0 {
+		// both positive
+		if b > 0 {
+			println(a, b)
+		}
+	}
+}`,
+			expected: 1,
+			suggestion: `if a > 0 && b > 0 {
+	// both positive
+	println(a, b)
+}`,
+		},
+		{
+			name: "or operands are parenthesized",
+			code: `package main
+
+func main() {
+	if a || b {
+		if c || d {
+			println()
+		}
+	}
+}`,
+			expected: 1,
+			suggestion: `if (a || b) && (c || d) {
+	println()
+}`,
+		},
+		{
+			name: "deeper nesting is reported once",
+			code: `package main
+
+func main() {
+	if a {
+		if b {
+			if c {
+				println()
+			}
+		}
+	}
+}`,
+			expected: 1,
+			suggestion: `if a && b {
+	if c {
+		println()
+	}
+}`,
+		},
+		{
+			name: "inner condition calls a function",
+			code: `package main
+
+func main() {
+	if a {
+		if valid() {
+			println()
+		}
+	}
+}`,
+			expected: 0,
+		},
+		{
+			name: "inner condition calls a pure function",
+			code: `package main
+
+//tlin:pure
+func valid(n int) bool { return n > 0 }
+
+func main() {
+	if a {
+		if valid(b) {
+			println()
+		}
+	}
+}`,
+			expected: 1,
+			suggestion: `if a && valid(b) {
+	println()
+}`,
+		},
+		{
+			name: "pure function with an impure argument",
+			code: `package main
+
+//tlin:pure
+func valid(n int) bool { return n > 0 }
+
+func main() {
+	if a {
+		if valid(next()) {
+			println()
+		}
+	}
+}`,
+			expected: 0,
+		},
+		{
+			name: "inner condition receives from a channel",
+			code: `package main
+
+func main() {
+	if a {
+		if <-done {
+			println()
+		}
+	}
+}`,
+			expected: 0,
+		},
+		{
+			name: "outer if has an else",
+			code: `package main
+
+func main() {
+	if a {
+		if b {
+			println()
+		}
+	} else {
+		println()
+	}
+}`,
+			expected: 0,
+		},
+		{
+			name: "outer body has other statements",
+			code: `package main
+
+func main() {
+	if a {
+		println()
+		if b {
+			println()
+		}
+	}
+}`,
+			expected: 0,
+		},
+		{
+			name: "inner if has an init statement",
+			code: `package main
+
+func main() {
+	if a {
+		if x := 1; b {
+			println(x)
+		}
+	}
+}`,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tmpDir, err := os.MkdirTemp("", "lint-test")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpDir)
+
+			tmpfile := filepath.Join(tmpDir, "test.go")
+			require.NoError(t, os.WriteFile(tmpfile, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(tmpfile, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectNestedIfs(tmpfile, node, fset, types.SeverityInfo)
+			require.NoError(t, err)
+			require.Len(t, issues, tc.expected)
+
+			if tc.expected > 0 {
+				assert.Equal(t, "nested-if", issues[0].Rule)
+				assert.Equal(t, tc.suggestion, issues[0].Suggestion)
+			}
+		})
+	}
+}
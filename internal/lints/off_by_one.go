@@ -0,0 +1,491 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	"github.com/gnolang/tlin/internal/analysis/constfold"
+	"github.com/gnolang/tlin/internal/analysis/lattice"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectOffByOne reports indexing one past the end of a slice, array or
+// string:
+//
+//   - s[i] in a loop whose condition is i <= len(s), and
+//   - s[len(s)], which is always out of range.
+//
+// The offset of i from len(s) is tracked with the interval domain along the
+// control flow graph of the function, narrowed by the conditions of the
+// loops and if statements, so that accesses that cannot run when i is
+// len(s), such as the ones guarded by i < len(s), are left alone.
+func DetectOffByOne(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	check := func(body *ast.BlockStmt, g *cfg.CFG) {
+		a := &offByOne{info: info, loops: inclusiveLoops(body, info)}
+		d := lattice.EnvDomain[lattice.Interval]{Values: lattice.IntervalDomain{}}
+		states := lattice.ForwardBranches(g, d, d.Top(), a.transfer, a.branch)
+
+		for stmt, env := range states {
+			if env == nil || stmt == g.Entry || stmt == g.Exit {
+				continue
+			}
+			for _, n := range stmtHeader(stmt) {
+				ast.Inspect(n, func(n ast.Node) bool {
+					switch n := n.(type) {
+					case *ast.FuncLit:
+						return false
+					case *ast.IndexExpr:
+						if issue, ok := a.check(n, env); ok {
+							issue.Filename = filename
+							issue.Start = fset.Position(n.Pos())
+							issue.End = fset.Position(n.End())
+							issue.Severity = severity
+							issues = append(issues, issue)
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				check(n.Body, cfg.FromFunc(n))
+			}
+		case *ast.FuncLit:
+			check(n.Body, cfg.FromStmts(n.Body.List))
+		}
+		return true
+	})
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Start.Offset < issues[j].Start.Offset
+	})
+	return issues, nil
+}
+
+// inclusiveLoop is a loop whose condition lets index reach len(seq), and
+// that indexes seq with it.
+type inclusiveLoop struct {
+	loop       *ast.ForStmt
+	index, seq string
+	// bound is the condition the loop should have.
+	bound string
+}
+
+// key names the offset of index from len(seq) in the environments.
+func (l inclusiveLoop) key() string {
+	return l.index + " - len(" + l.seq + ")"
+}
+
+// inclusiveLoops returns the loops of body, outside of function literals,
+// with a condition i <= len(s) and an s[i] access.
+func inclusiveLoops(body *ast.BlockStmt, info *types.Info) []inclusiveLoop {
+	var loops []inclusiveLoop
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt:
+			cond, ok := unparen(n.Cond).(*ast.BinaryExpr)
+			if !ok {
+				return true
+			}
+			index, lenCall, bound := cond.X, cond.Y, "%s < %s"
+			if cond.Op == token.GEQ {
+				index, lenCall, bound = cond.Y, cond.X, "%[2]s > %[1]s"
+			} else if cond.Op != token.LEQ {
+				return true
+			}
+			id, ok := unparen(index).(*ast.Ident)
+			seq := lenArg(lenCall, info)
+			if !ok || seq == "" || !indexes(n, seq, id.Name) {
+				return true
+			}
+			loops = append(loops, inclusiveLoop{
+				loop:  n,
+				index: id.Name,
+				seq:   seq,
+				bound: fmt.Sprintf(bound, id.Name, types.ExprString(lenCall)),
+			})
+		}
+		return true
+	})
+	return loops
+}
+
+// indexes reports whether the body of loop indexes seq with index.
+func indexes(loop *ast.ForStmt, seq, index string) bool {
+	found := false
+	ast.Inspect(loop.Body, func(n ast.Node) bool {
+		if ix, ok := n.(*ast.IndexExpr); ok && isIdent(ix.X, seq) && isIdent(ix.Index, index) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// lenArg returns the name of the slice, array or string that e is the
+// length of, or "" when e is not a call to len on one.
+func lenArg(e ast.Expr, info *types.Info) string {
+	call, ok := unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return ""
+	}
+	fn, ok := call.Fun.(*ast.Ident)
+	if !ok || fn.Name != "len" {
+		return ""
+	}
+	if _, ok := info.Uses[fn].(*types.Builtin); !ok {
+		return ""
+	}
+	arg, ok := unparen(call.Args[0]).(*ast.Ident)
+	if !ok || !indexable(info.TypeOf(arg)) {
+		return ""
+	}
+	return arg.Name
+}
+
+// indexable reports whether indexing t past its length is out of range,
+// unlike indexing a map.
+func indexable(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Slice, *types.Array:
+		return true
+	case *types.Basic:
+		return u.Info()&types.IsString != 0
+	case *types.Pointer:
+		_, ok := u.Elem().Underlying().(*types.Array)
+		return ok
+	}
+	return false
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := unparen(e).(*ast.Ident)
+	return ok && id.Name == name
+}
+
+type offByOne struct {
+	info  *types.Info
+	loops []inclusiveLoop
+}
+
+// offset returns the interval of e - len(seq), with the offsets of the loop
+// indexes in env.
+func (a *offByOne) offset(e ast.Expr, seq string, env lattice.Env[lattice.Interval]) lattice.Interval {
+	top := lattice.IntervalDomain{}.Top()
+	if lenArg(e, a.info) == seq {
+		return lattice.Const(0)
+	}
+	if n, ok := a.constant(e); ok {
+		// len(seq) >= 0
+		return lattice.Range(lattice.NegInf, n)
+	}
+
+	switch e := unparen(e).(type) {
+	case *ast.Ident:
+		for _, l := range a.loops {
+			if l.index != e.Name || l.seq != seq {
+				continue
+			}
+			if v, ok := env[l.key()]; ok {
+				return v
+			}
+		}
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD && e.Op != token.SUB {
+			return top
+		}
+		if n, ok := a.constant(e.Y); ok {
+			if e.Op == token.SUB {
+				n = -n
+			}
+			return a.offset(e.X, seq, env).Add(lattice.Const(n))
+		}
+		if n, ok := a.constant(e.X); ok && e.Op == token.ADD {
+			return a.offset(e.Y, seq, env).Add(lattice.Const(n))
+		}
+	}
+	return top
+}
+
+func (a *offByOne) constant(e ast.Expr) (int64, bool) {
+	v := constfold.Eval(e, a.info, nil)
+	if v.Kind() != constant.Int {
+		return 0, false
+	}
+	return constant.Int64Val(v)
+}
+
+func (a *offByOne) transfer(stmt ast.Stmt, in lattice.Env[lattice.Interval]) lattice.Env[lattice.Interval] {
+	if in == nil || len(a.loops) == 0 {
+		return in
+	}
+	out := in.Clone()
+	assign := func(lhs, rhs ast.Expr) {
+		for _, l := range a.loops {
+			switch {
+			case isIdent(lhs, l.index) && rhs != nil:
+				out[l.key()] = a.offset(rhs, l.seq, in)
+			case isIdent(lhs, l.index):
+				delete(out, l.key())
+			case isIdent(lhs, l.seq):
+				// s = append(s, x, y) moves len(s) 2 further from i.
+				if n, ok := appended(rhs, l.seq); ok {
+					if v, ok := in[l.key()]; ok {
+						out[l.key()] = v.Sub(lattice.Const(n))
+					}
+				} else {
+					delete(out, l.key())
+				}
+			}
+		}
+	}
+
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		switch {
+		case len(s.Lhs) == len(s.Rhs) && (s.Tok == token.ASSIGN || s.Tok == token.DEFINE):
+			for i, lhs := range s.Lhs {
+				assign(lhs, s.Rhs[i])
+			}
+		case len(s.Lhs) == 1 && len(s.Rhs) == 1 && (s.Tok == token.ADD_ASSIGN || s.Tok == token.SUB_ASSIGN):
+			op := token.ADD
+			if s.Tok == token.SUB_ASSIGN {
+				op = token.SUB
+			}
+			assign(s.Lhs[0], &ast.BinaryExpr{X: s.Lhs[0], Op: op, Y: s.Rhs[0]})
+		default:
+			for _, lhs := range s.Lhs {
+				assign(lhs, nil)
+			}
+		}
+	case *ast.IncDecStmt:
+		op := token.ADD
+		if s.Tok == token.DEC {
+			op = token.SUB
+		}
+		assign(s.X, &ast.BinaryExpr{X: s.X, Op: op, Y: &ast.BasicLit{Kind: token.INT, Value: "1"}})
+	case *ast.RangeStmt:
+		assign(s.Key, nil)
+		assign(s.Value, nil)
+	case *ast.DeclStmt:
+		ast.Inspect(s, func(n ast.Node) bool {
+			spec, ok := n.(*ast.ValueSpec)
+			if !ok {
+				return true
+			}
+			for i, name := range spec.Names {
+				var value ast.Expr
+				if len(spec.Values) == len(spec.Names) {
+					value = spec.Values[i]
+				}
+				assign(name, value)
+			}
+			return false
+		})
+	}
+	return out
+}
+
+// appended returns the number of elements e appends to seq, when e is
+// append(seq, ...) with a fixed number of elements.
+func appended(e ast.Expr, seq string) (int64, bool) {
+	call, ok := unparen(e).(*ast.CallExpr)
+	if !ok || call.Ellipsis.IsValid() || len(call.Args) == 0 || !isIdent(call.Fun, "append") || !isIdent(call.Args[0], seq) {
+		return 0, false
+	}
+	return int64(len(call.Args) - 1), true
+}
+
+// branch narrows the offsets entering the body of a loop or an if statement
+// with its condition, and the ones leaving it with its negation.
+func (a *offByOne) branch(from, to ast.Stmt, out lattice.Env[lattice.Interval]) lattice.Env[lattice.Interval] {
+	if out == nil {
+		return nil
+	}
+	var cond ast.Expr
+	var body *ast.BlockStmt
+	switch s := from.(type) {
+	case *ast.IfStmt:
+		cond, body = s.Cond, s.Body
+	case *ast.ForStmt:
+		cond, body = s.Cond, s.Body
+	}
+	if cond == nil || len(body.List) == 0 {
+		return out
+	}
+	return a.narrow(out, cond, body.Pos() <= to.Pos() && to.End() <= body.End())
+}
+
+// narrow returns env where cond holds, or does not, or nil when it cannot be.
+func (a *offByOne) narrow(env lattice.Env[lattice.Interval], cond ast.Expr, holds bool) lattice.Env[lattice.Interval] {
+	if env == nil {
+		return nil
+	}
+	switch c := unparen(cond).(type) {
+	case *ast.UnaryExpr:
+		if c.Op == token.NOT {
+			return a.narrow(env, c.X, !holds)
+		}
+	case *ast.BinaryExpr:
+		switch c.Op {
+		case token.LAND, token.LOR:
+			if holds == (c.Op == token.LAND) {
+				return a.narrow(a.narrow(env, c.X, holds), c.Y, holds)
+			}
+			return env
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return a.compare(env, c, holds)
+		}
+	}
+	return env
+}
+
+// compare narrows the offsets of the loop indexes compared by c.
+func (a *offByOne) compare(env lattice.Env[lattice.Interval], c *ast.BinaryExpr, holds bool) lattice.Env[lattice.Interval] {
+	d := lattice.IntervalDomain{}
+	out := env.Clone()
+	for _, l := range a.loops {
+		x, y, op := c.X, c.Y, c.Op
+		if isIdent(y, l.index) {
+			x, y, op = y, x, swapComparison(op)
+		}
+		if !isIdent(x, l.index) {
+			continue
+		}
+		if !holds {
+			op = negateComparison(op)
+		}
+
+		bound := a.offset(y, l.seq, env)
+		v, ok := env[l.key()]
+		if !ok {
+			v = d.Top()
+		}
+		switch op {
+		case token.LSS:
+			v = d.Meet(v, lattice.Range(lattice.NegInf, bound.Add(lattice.Const(-1)).Hi))
+		case token.LEQ:
+			v = d.Meet(v, lattice.Range(lattice.NegInf, bound.Hi))
+		case token.GTR:
+			v = d.Meet(v, lattice.Range(bound.Add(lattice.Const(1)).Lo, lattice.PosInf))
+		case token.GEQ:
+			v = d.Meet(v, lattice.Range(bound.Lo, lattice.PosInf))
+		case token.EQL:
+			v = d.Meet(v, bound)
+		case token.NEQ:
+			if bound.IsConst() && v.Lo == bound.Lo {
+				v = lattice.Range(v.Lo+1, v.Hi)
+			}
+			if bound.IsConst() && v.Hi == bound.Hi {
+				v = lattice.Range(v.Lo, v.Hi-1)
+			}
+		}
+		if v.IsBottom() {
+			return nil
+		}
+		out[l.key()] = v
+	}
+	return out
+}
+
+func swapComparison(op token.Token) token.Token {
+	switch op {
+	case token.LSS:
+		return token.GTR
+	case token.LEQ:
+		return token.GEQ
+	case token.GTR:
+		return token.LSS
+	case token.GEQ:
+		return token.LEQ
+	}
+	return op
+}
+
+func negateComparison(op token.Token) token.Token {
+	switch op {
+	case token.EQL:
+		return token.NEQ
+	case token.NEQ:
+		return token.EQL
+	case token.LSS:
+		return token.GEQ
+	case token.LEQ:
+		return token.GTR
+	case token.GTR:
+		return token.LEQ
+	case token.GEQ:
+		return token.LSS
+	}
+	return op
+}
+
+// check returns the issue of ix when it can index one past the end of the
+// indexed value, as the offsets in env tell.
+func (a *offByOne) check(ix *ast.IndexExpr, env lattice.Env[lattice.Interval]) (tt.Issue, bool) {
+	seq, ok := unparen(ix.X).(*ast.Ident)
+	if !ok || !indexable(a.info.TypeOf(seq)) {
+		return tt.Issue{}, false
+	}
+	access := types.ExprString(ix)
+
+	if id, ok := unparen(ix.Index).(*ast.Ident); ok {
+		for _, l := range a.loops {
+			if l.index != id.Name || l.seq != seq.Name || ix.Pos() < l.loop.Pos() || ix.End() > l.loop.End() {
+				continue
+			}
+			if !a.offset(id, l.seq, env).Contains(0) {
+				return tt.Issue{}, false
+			}
+			return tt.Issue{
+				Rule:    "off-by-one",
+				Message: fmt.Sprintf("%s is out of range when %s == len(%s), which the loop condition %s allows", access, l.index, l.seq, types.ExprString(l.loop.Cond)),
+				Note:    fmt.Sprintf("the last element of %s is at len(%s)-1. loop while %s instead.", l.seq, l.seq, l.bound),
+			}, true
+		}
+		return tt.Issue{}, false
+	}
+
+	// only the indexes computed from len(s) are certain to be past its end.
+	if !a.fromLen(ix.Index, seq.Name) {
+		return tt.Issue{}, false
+	}
+	if v := a.offset(ix.Index, seq.Name, nil); v.IsBottom() || v.Lo < 0 {
+		return tt.Issue{}, false
+	}
+	return tt.Issue{
+		Rule:    "off-by-one",
+		Message: fmt.Sprintf("%s is always out of range", access),
+		Note:    fmt.Sprintf("the last element of %s is %s[len(%s)-1].", seq.Name, seq.Name, seq.Name),
+	}, true
+}
+
+// fromLen reports whether e uses len(seq).
+func (a *offByOne) fromLen(e ast.Expr, seq string) bool {
+	found := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		if expr, ok := n.(ast.Expr); ok && lenArg(expr, a.info) == seq {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
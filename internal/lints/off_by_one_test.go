@@ -0,0 +1,98 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectOffByOne(t *testing.T) {
+	t.Parallel()
+	src := `package scores
+
+func Sum(s []int) int {
+	total := 0
+	for i := 0; i <= len(s); i++ {
+		total += s[i] // want "s\\[i\\] is out of range when i == len\\(s\\)"
+	}
+	return total
+}
+
+func Reversed(name string) bool {
+	for i := 1; len(name) >= i; i++ {
+		if name[i] == 'x' { // want "name\\[i\\] is out of range"
+			return true
+		}
+	}
+	return false
+}
+
+func Last(s []int) int {
+	return s[len(s)] // want "s\\[len\\(s\\)\\] is always out of range"
+}
+
+func Guarded(s []int) int {
+	total := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			total += s[i]
+		}
+	}
+	return total
+}
+
+func Breaks(s []int) int {
+	total := 0
+	for i := 0; i <= len(s); i++ {
+		if i == len(s) {
+			break
+		}
+		total += s[i]
+	}
+	return total
+}
+
+func Shifted(s []int) int {
+	total := 0
+	for i := 1; i <= len(s); i++ {
+		total += s[i-1]
+	}
+	return total + s[len(s)-1]
+}
+
+func Grows(s []int) []int {
+	for i := 0; i <= len(s); i++ {
+		if i == len(s) {
+			s = append(s, 0)
+		}
+		s[i]++
+	}
+	return s
+}
+
+func Maps(m map[int]int) int {
+	total := m[len(m)]
+	for i := 0; i <= len(m); i++ {
+		total += m[i]
+	}
+	return total
+}
+
+func Unreachable(s []int) int {
+	return 0
+	return s[len(s)]
+}
+`
+	issues := ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectOffByOne(filename, node, fset, tt.SeverityWarning)
+	}, "scores.gno", src)
+	require.Len(t, issues, 3)
+	assert.Equal(t, "the last element of s is at len(s)-1. loop while i < len(s) instead.", issues[0].Note)
+	assert.Equal(t, "the last element of name is at len(name)-1. loop while len(name) > i instead.", issues[1].Note)
+	assert.Equal(t, "the last element of s is s[len(s)-1].", issues[2].Note)
+}
@@ -0,0 +1,61 @@
+package lints
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const realmPathPrefix = "gno.land/r/"
+
+// IsRealmFile reports whether filename belongs to a realm package.
+//
+// The nearest gno.mod is used to resolve the package path. When the file is
+// not part of a module, its directory must contain a gno.land/r/ path,
+// following the layout of the gno repository (examples/gno.land/r/...).
+func IsRealmFile(filename string) bool {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return false
+	}
+
+	if modPath, ok := findModulePath(filepath.Dir(abs)); ok {
+		return strings.HasPrefix(modPath, realmPathPrefix)
+	}
+
+	return strings.Contains(filepath.ToSlash(filepath.Dir(abs))+"/", "/"+realmPathPrefix)
+}
+
+// findModulePath searches dir and its parents for a gno.mod file and
+// returns the module path it declares.
+func findModulePath(dir string) (string, bool) {
+	for {
+		if path, ok := ReadModulePath(filepath.Join(dir, "gno.mod")); ok {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// ReadModulePath returns the module path declared in a gno.mod file.
+func ReadModulePath(modFile string) (string, bool) {
+	f, err := os.Open(modFile)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,138 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectReceiverNames reports methods whose receiver is named differently
+// from the other methods of the same type in the package, or is named one
+// of banned, such as this or self.
+//
+// The expected name is the one used by most methods of the type, the
+// earliest winning ties, or the lowercased first letter of the type when
+// that name is banned. Issues come with a suggestion renaming the receiver
+// and its references in the method, provided the name is free there.
+func DetectReceiverNames(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, banned []string) ([]tt.Issue, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+	isBanned := make(map[string]bool)
+	for _, name := range banned {
+		isBanned[name] = true
+	}
+
+	uses := make(map[types.Object][]*ast.Ident)
+	for id, obj := range info.Uses {
+		uses[obj] = append(uses[obj], id)
+	}
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 || len(fn.Recv.List[0].Names) == 0 {
+			continue
+		}
+		id := fn.Recv.List[0].Names[0]
+		recv, ok := info.Defs[id].(*types.Var)
+		if !ok || id.Name == "_" {
+			continue
+		}
+		method, ok := info.Defs[fn.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		named := receiverNamed(method)
+		if named == nil {
+			continue
+		}
+
+		expected := expectedReceiverName(named, isBanned)
+		if id.Name == expected && !isBanned[id.Name] {
+			continue
+		}
+
+		var message string
+		if isBanned[id.Name] {
+			message = fmt.Sprintf("receiver of %s should not be named %s", fn.Name.Name, id.Name)
+		} else {
+			message = fmt.Sprintf("receiver of %s is named %s, while other methods of %s use %s", fn.Name.Name, id.Name, named.Obj().Name(), expected)
+		}
+		issue := tt.Issue{
+			Rule:     "receiver-name",
+			Filename: filename,
+			Start:    fset.Position(id.Pos()),
+			End:      fset.Position(id.End()),
+			Message:  message,
+			Severity: severity,
+		}
+		if expected != "" {
+			issue.Note = fmt.Sprintf("name the receiver of every method of %s %s, so that it reads the same in all of them.", named.Obj().Name(), expected)
+		}
+
+		refs := uses[recv]
+		if expected != "" && canRename(recv, refs, expected) {
+			idents := append([]*ast.Ident{id}, refs...)
+			sort.Slice(idents, func(i, j int) bool { return idents[i].Pos() < idents[j].Pos() })
+			issue.Start = fset.Position(idents[0].Pos())
+			issue.End = fset.Position(idents[len(idents)-1].End())
+			issue.Suggestion = renameInRange(content, fset, idents, expected)
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// expectedReceiverName returns the receiver name most used by the methods of
+// named, or a name derived from the type when that one is banned.
+func expectedReceiverName(named *types.Named, isBanned map[string]bool) string {
+	counts := make(map[string]int)
+	first := make(map[string]token.Pos)
+	for i := 0; i < named.NumMethods(); i++ {
+		sig, ok := named.Method(i).Type().(*types.Signature)
+		if !ok || sig.Recv() == nil {
+			continue
+		}
+		name := sig.Recv().Name()
+		if name == "" || name == "_" {
+			continue
+		}
+		counts[name]++
+		if pos, ok := first[name]; !ok || sig.Recv().Pos() < pos {
+			first[name] = sig.Recv().Pos()
+		}
+	}
+
+	best := ""
+	for name, n := range counts {
+		if isBanned[name] {
+			continue
+		}
+		if best == "" || n > counts[best] || (n == counts[best] && first[name] < first[best]) {
+			best = name
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	r, _ := utf8.DecodeRuneInString(named.Obj().Name())
+	name := string(unicode.ToLower(r))
+	if isBanned[name] || !token.IsIdentifier(name) {
+		return ""
+	}
+	return name
+}
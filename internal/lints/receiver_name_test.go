@@ -0,0 +1,105 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectReceiverNames(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		code        string
+		messages    []string
+		suggestions []string
+	}{
+		{
+			name: "consistent receivers",
+			code: `package foo
+
+type Counter struct{ n int }
+
+func (c *Counter) Inc() { c.n++ }
+
+func (c *Counter) Value() int { return c.n }
+
+func (*Counter) Reset() {}
+`,
+		},
+		{
+			name: "inconsistent receiver",
+			code: `package foo
+
+type Counter struct{ n int }
+
+func (c *Counter) Inc() { c.n++ }
+
+func (c *Counter) Dec() { c.n-- }
+
+func (cnt *Counter) Value() int {
+	if cnt == nil {
+		return 0
+	}
+	return cnt.n
+}
+`,
+			messages:    []string{"receiver of Value is named cnt, while other methods of Counter use c"},
+			suggestions: []string{"c *Counter) Value() int {\n\tif c == nil {\n\t\treturn 0\n\t}\n\treturn c"},
+		},
+		{
+			name: "banned names",
+			code: `package foo
+
+type Point struct{ x, y int }
+
+func (this Point) X() int { return this.x }
+
+func (self Point) Y() int { return self.y }
+`,
+			messages: []string{
+				"receiver of X should not be named this",
+				"receiver of Y should not be named self",
+			},
+			suggestions: []string{"p Point) X() int { return p", "p Point) Y() int { return p"},
+		},
+		{
+			name: "new name already in scope",
+			code: `package foo
+
+type Point struct{ x int }
+
+func (p Point) X() int { return p.x }
+
+func (pt Point) Add(p Point) int { return pt.x + p.x }
+`,
+			messages:    []string{"receiver of Add is named pt, while other methods of Point use p"},
+			suggestions: []string{""},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "foo.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectReceiverNames(path, node, fset, tt.SeverityInfo, []string{"this", "self"})
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+			for i, issue := range issues {
+				assert.Equal(t, "receiver-name", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				assert.Equal(t, tc.suggestions[i], issue.Suggestion)
+			}
+		})
+	}
+}
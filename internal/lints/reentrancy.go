@@ -0,0 +1,188 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+const reentrancyNote = "the called realm may call back into this realm before the state is updated, and see or act on the old state. update the state first, then make the external call (checks-effects-interactions)."
+
+// DetectReentrancy reports functions of realm packages writing package-level
+// state after calling another realm, on some path of their control flow
+// graph.
+//
+// Calls are external when they go through an imported package matched by one
+// of external: an import path, a path ending with `/...` matching the
+// packages below it, or `path.Func` matching a single function.
+func DetectReentrancy(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, external []string) ([]tt.Issue, error) {
+	if !IsRealmFile(filename) {
+		return nil, nil
+	}
+
+	imports := externalImports(node, external)
+	if len(imports) == 0 {
+		return nil, nil
+	}
+	info := packageTypeInfo(filename, node, fset)
+	isExternal := func(call *ast.CallExpr) bool {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		path, ok := imports[pkg.Name]
+		if !ok {
+			return false
+		}
+		if obj := info.Uses[pkg]; obj != nil {
+			if _, ok := obj.(*types.PkgName); !ok {
+				// a local variable shadows the package.
+				return false
+			}
+		}
+		return matchesExternal(path, sel.Sel.Name, external)
+	}
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		g := cfg.FromFunc(fn)
+		stmts := g.Blocks()
+		sort.Slice(stmts, func(i, j int) bool { return stmts[i].Pos() < stmts[j].Pos() })
+		for _, s := range stmts {
+			if s == g.Entry || s == g.Exit {
+				continue
+			}
+			call := externalCallIn(s, isExternal)
+			if call == nil {
+				continue
+			}
+			write, name := firstWriteAfter(g, s, info)
+			if write == nil {
+				continue
+			}
+			issues = append(issues, tt.Issue{
+				Rule:     "reentrancy",
+				Category: "security",
+				Filename: filename,
+				Start:    fset.Position(write.Pos()),
+				End:      fset.Position(write.End()),
+				Message:  fmt.Sprintf("%s writes %s after calling %s", fn.Name.Name, name, types.ExprString(call.Fun)),
+				Note:     reentrancyNote,
+				Severity: severity,
+				Actions:  []tt.Action{tt.ActionNeedsReview},
+				RelatedLocations: []tt.Location{{
+					Filename: filename,
+					Start:    fset.Position(call.Pos()),
+					End:      fset.Position(call.End()),
+					Message:  "the external call is made here",
+				}},
+			})
+			// one issue per function is enough to reorder it.
+			break
+		}
+	}
+
+	return issues, nil
+}
+
+// externalImports maps the names of the packages imported by node that may
+// be matched by external to their import path.
+func externalImports(node *ast.File, external []string) map[string]string {
+	imports := make(map[string]string)
+	for _, imp := range node.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || !matchesExternal(path, "", external) {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+// matchesExternal reports whether the function fn of the package path is
+// matched by one of patterns. An empty fn matches the packages of which some
+// function may be matched.
+func matchesExternal(path, fn string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "/..."); ok {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if p == path {
+			return true
+		}
+		if i := strings.LastIndex(p, "."); i > strings.LastIndex(p, "/") && p[:i] == path && (fn == "" || p[i+1:] == fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// externalCallIn returns the first external call made by the header of s.
+func externalCallIn(s ast.Stmt, isExternal func(*ast.CallExpr) bool) *ast.CallExpr {
+	var found *ast.CallExpr
+	for _, n := range stmtHeader(s) {
+		inspectCalls(n, func(call *ast.CallExpr) {
+			if found == nil && isExternal(call) {
+				found = call
+			}
+		})
+	}
+	return found
+}
+
+// firstWriteAfter returns the first write to a package-level variable that
+// can be reached from s, along with the name of the variable. Writes made by
+// s itself are not reported.
+func firstWriteAfter(g *cfg.CFG, s ast.Stmt, info *types.Info) (ast.Node, string) {
+	var reached []ast.Stmt
+	seen := map[ast.Stmt]bool{s: true}
+	queue := []ast.Stmt{s}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, succ := range g.Succs(cur) {
+			if seen[succ] || succ == g.Exit {
+				continue
+			}
+			seen[succ] = true
+			reached = append(reached, succ)
+			queue = append(queue, succ)
+		}
+	}
+	sort.Slice(reached, func(i, j int) bool { return reached[i].Pos() < reached[j].Pos() })
+
+	for _, r := range reached {
+		for _, n := range stmtHeader(r) {
+			if write, name := packageVarWrite(n, info); write != nil {
+				return write, name
+			}
+		}
+	}
+	return nil, ""
+}
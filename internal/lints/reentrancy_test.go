@@ -0,0 +1,105 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectReentrancy(t *testing.T) {
+	t.Parallel()
+	external := []string{"gno.land/r/...", "gno.land/p/demo/grc20.Transfer"}
+
+	issues := ruletest.RunFiles(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectReentrancy(filename, node, fset, tt.SeverityWarning, external)
+	}, map[string]string{
+		"gno.mod": "module gno.land/r/demo/vault\n",
+		"vault.gno": `package vault
+
+import (
+	"gno.land/p/demo/grc20"
+	"gno.land/p/demo/ufmt"
+	bank "gno.land/r/demo/bank"
+)
+
+var (
+	balances = map[string]int{}
+	total    int
+)
+
+func Withdraw(who string) {
+	amount := balances[who]
+	bank.Send(who, amount)
+	balances[who] = 0 // want "Withdraw writes balances after calling bank.Send"
+}
+
+func WithdrawSafe(who string) {
+	amount := balances[who]
+	balances[who] = 0
+	bank.Send(who, amount)
+}
+
+func Refund(who string, ok bool) {
+	if ok {
+		grc20.Transfer(who, total)
+	}
+	total-- // want "Refund writes total after calling grc20.Transfer"
+}
+
+func Report(who string) string {
+	s := ufmt.Sprintf("%d", total)
+	grc20.Approve(who, total)
+	total = 0
+	return s
+}
+
+func Loop(names []string) {
+	for _, name := range names {
+		total = total + 1 // want "Loop writes total after calling bank.Send"
+		bank.Send(name, 1)
+	}
+}
+
+func Fetch() {
+	total = bank.Balance()
+}
+
+func Shadowed() {
+	bank := struct{ Send func(string, int) }{}
+	bank.Send("x", 1)
+	total = 0
+}
+`,
+	})
+	require.Len(t, issues, 3)
+	assert.Equal(t, "security", issues[0].Category)
+	require.Len(t, issues[0].RelatedLocations, 1)
+	assert.Equal(t, 16, issues[0].RelatedLocations[0].Start.Line)
+}
+
+func TestMatchesExternal(t *testing.T) {
+	t.Parallel()
+	patterns := []string{"gno.land/r/...", "gno.land/p/demo/tokens", "gno.land/p/demo/grc20.Transfer"}
+
+	tests := []struct {
+		path, fn string
+		expected bool
+	}{
+		{"gno.land/r/demo/bank", "Send", true},
+		{"gno.land/r", "Send", true},
+		{"gno.land/rx/demo", "Send", false},
+		{"gno.land/p/demo/tokens", "Mint", true},
+		{"gno.land/p/demo/grc20", "Transfer", true},
+		{"gno.land/p/demo/grc20", "Approve", false},
+		{"gno.land/p/demo/grc20", "", true},
+		{"gno.land/p/demo/ufmt", "", false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, matchesExternal(tc.path, tc.fn, patterns), "%s.%s", tc.path, tc.fn)
+	}
+}
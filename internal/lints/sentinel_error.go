@@ -0,0 +1,253 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectSentinelErrors reports sentinel errors, the errors created once with
+// errors.New to be compared against:
+//
+//   - package-level sentinels not named ErrXxx (errXxx when unexported).
+//     Unexported ones used only in the file come with a rename suggestion.
+//   - sentinels named like one but created inside a function, which makes a
+//     new error on every call that no comparison can match.
+//   - comparisons of an error with a sentinel using == or !=, which miss the
+//     wrapped errors, with a suggestion calling errors.Is instead when the
+//     file imports errors. Is methods of error types are left alone.
+//
+// Sentinels declared as constants are left to const-error-declaration.
+func DetectSentinelErrors(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+	uses := make(map[types.Object][]*ast.Ident)
+	for id, obj := range info.Uses {
+		uses[obj] = append(uses[obj], id)
+	}
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for _, id := range errorsNewNames(spec.(*ast.ValueSpec)) {
+				// A lone err says nothing the rename would improve.
+				if hasErrPrefix(id.Name) || strings.EqualFold(id.Name, "err") {
+					continue
+				}
+				issue := tt.Issue{
+					Rule:     "sentinel-error",
+					Filename: filename,
+					Start:    fset.Position(id.Pos()),
+					End:      fset.Position(id.End()),
+					Message:  fmt.Sprintf("sentinel error %s should be named %s", id.Name, sentinelName(id.Name)),
+					Note:     "sentinel errors are named ErrXxx, or errXxx when unexported, so that they stand out in comparisons.",
+					Severity: severity,
+				}
+				if suggestion, start, end, ok := renameSentinel(filename, content, fset, info.Defs[id], id, uses); ok {
+					issue.Start, issue.End, issue.Suggestion = start, end, suggestion
+				}
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	errorsName := importName(node, "errors")
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.DeclStmt:
+				gen, ok := n.Decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.VAR {
+					return true
+				}
+				for _, spec := range gen.Specs {
+					for _, id := range errorsNewNames(spec.(*ast.ValueSpec)) {
+						if hasErrPrefix(id.Name) {
+							issues = append(issues, localSentinelIssue(filename, fset, fn, id, severity))
+						}
+					}
+				}
+			case *ast.AssignStmt:
+				if n.Tok != token.DEFINE || len(n.Lhs) != len(n.Rhs) {
+					return true
+				}
+				for i, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok && isErrorNew(n.Rhs[i]) && hasErrPrefix(id.Name) {
+						issues = append(issues, localSentinelIssue(filename, fset, fn, id, severity))
+					}
+				}
+			case *ast.BinaryExpr:
+				if fn.Name.Name == "Is" && fn.Recv != nil {
+					// Is methods, which errors.Is calls, compare with ==.
+					return true
+				}
+				if issue, ok := sentinelComparison(filename, content, fset, n, info, node.Name.Name, errorsName); ok {
+					issue.Severity = severity
+					issues = append(issues, issue)
+				}
+			}
+			return true
+		})
+	}
+
+	return issues, nil
+}
+
+// errorsNewNames returns the names of spec initialized with errors.New.
+func errorsNewNames(spec *ast.ValueSpec) []*ast.Ident {
+	if len(spec.Names) != len(spec.Values) {
+		return nil
+	}
+	var names []*ast.Ident
+	for i, name := range spec.Names {
+		if name.Name != "_" && isErrorNew(spec.Values[i]) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hasErrPrefix reports whether name reads as Err or err followed by a word,
+// such as ErrNotFound.
+func hasErrPrefix(name string) bool {
+	rest, ok := strings.CutPrefix(name, "Err")
+	if !ok {
+		rest, ok = strings.CutPrefix(name, "err")
+	}
+	if !ok || rest == "" {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return unicode.IsUpper(r) || unicode.IsDigit(r)
+}
+
+// sentinelName returns the conventional name of the sentinel error name,
+// dropping an Error suffix: notFoundError becomes errNotFound.
+func sentinelName(name string) string {
+	if trimmed := strings.TrimSuffix(name, "Error"); trimmed != "" {
+		name = trimmed
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	if unicode.IsUpper(r) {
+		return "Err" + name
+	}
+	return "err" + string(unicode.ToUpper(r)) + name[size:]
+}
+
+// renameSentinel returns the source from the declaration of the sentinel id
+// to its last use, renamed, when obj is unexported and all its uses are in
+// filename.
+func renameSentinel(filename string, content []byte, fset *token.FileSet, obj types.Object, id *ast.Ident, uses map[types.Object][]*ast.Ident) (string, token.Position, token.Position, bool) {
+	if obj == nil || obj.Exported() {
+		return "", token.Position{}, token.Position{}, false
+	}
+	refs := uses[obj]
+	if len(refs) > maxRenameUses {
+		return "", token.Position{}, token.Position{}, false
+	}
+	for _, ref := range refs {
+		if fset.Position(ref.Pos()).Filename != filename {
+			return "", token.Position{}, token.Position{}, false
+		}
+	}
+	newName := sentinelName(id.Name)
+	if !canRename(obj, refs, newName) {
+		return "", token.Position{}, token.Position{}, false
+	}
+
+	idents := append([]*ast.Ident{id}, refs...)
+	sort.Slice(idents, func(i, j int) bool { return idents[i].Pos() < idents[j].Pos() })
+	return renameInRange(content, fset, idents, newName), fset.Position(idents[0].Pos()), fset.Position(idents[len(idents)-1].End()), true
+}
+
+func localSentinelIssue(filename string, fset *token.FileSet, fn *ast.FuncDecl, id *ast.Ident, severity tt.Severity) tt.Issue {
+	return tt.Issue{
+		Rule:     "sentinel-error",
+		Filename: filename,
+		Start:    fset.Position(id.Pos()),
+		End:      fset.Position(id.End()),
+		Message:  fmt.Sprintf("sentinel error %s is created inside %s", id.Name, fn.Name.Name),
+		Note:     "errors.New returns a distinct error on each call, which no comparison with a previous one matches. declare the sentinel at package level.",
+		Severity: severity,
+	}
+}
+
+// sentinelComparison reports a comparison of an error with a sentinel error
+// using == or !=.
+func sentinelComparison(filename string, content []byte, fset *token.FileSet, expr *ast.BinaryExpr, info *types.Info, pkgName, errorsName string) (tt.Issue, bool) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return tt.Issue{}, false
+	}
+	value, sentinel := expr.X, expr.Y
+	if !isSentinelError(sentinel, info, pkgName) {
+		value, sentinel = sentinel, value
+	}
+	if !isSentinelError(sentinel, info, pkgName) || isNilIdent(value) {
+		return tt.Issue{}, false
+	}
+
+	src := func(e ast.Expr) string {
+		return string(content[fset.Position(e.Pos()).Offset:fset.Position(e.End()).Offset])
+	}
+	call := fmt.Sprintf("errors.Is(%s, %s)", src(value), src(sentinel))
+	if expr.Op == token.NEQ {
+		call = "!" + call
+	}
+
+	issue := tt.Issue{
+		Rule:     "sentinel-error",
+		Filename: filename,
+		Start:    fset.Position(expr.Pos()),
+		End:      fset.Position(expr.End()),
+		Message:  fmt.Sprintf("compare errors with %s rather than %s", call, expr.Op),
+		Note:     fmt.Sprintf("%s does not match errors wrapping %s, which errors.Is does.", expr.Op, src(sentinel)),
+	}
+	if errorsName != "" {
+		issue.Suggestion = strings.Replace(call, "errors.", errorsName+".", 1)
+	}
+	return issue, true
+}
+
+// isSentinelError reports whether expr refers to a package-level variable of
+// type error, named like a sentinel error when it belongs to the package
+// pkgName being linted. Variables of other packages, such as io.EOF, are
+// sentinels whatever their name.
+func isSentinelError(expr ast.Expr, info *types.Info, pkgName string) bool {
+	var id *ast.Ident
+	switch e := unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return false
+	}
+	v, ok := info.Uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return false
+	}
+	if v.Pkg().Name() == pkgName && !hasErrPrefix(v.Name()) {
+		return false
+	}
+	return types.Identical(v.Type(), types.Universe.Lookup("error").Type())
+}
@@ -0,0 +1,131 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSentinelErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		code        string
+		messages    []string
+		suggestions []string
+	}{
+		{
+			name: "naming",
+			code: `package foo
+
+import "errors"
+
+var (
+	ErrClosed     = errors.New("closed")
+	NotFound      = errors.New("not found")
+	timeoutError  = errors.New("timeout")
+	errorsSeen    int
+	_             = errors.New("ignored")
+)
+
+func wait() error { return timeoutError }
+`,
+			messages: []string{
+				"sentinel error NotFound should be named ErrNotFound",
+				"sentinel error timeoutError should be named errTimeout",
+			},
+			suggestions: []string{"", "errTimeout  = errors.New(\"timeout\")\n\terrorsSeen    int\n\t_             = errors.New(\"ignored\")\n)\n\nfunc wait() error { return errTimeout"},
+		},
+		{
+			name: "created inside a function",
+			code: `package foo
+
+import "errors"
+
+func open(name string) error {
+	ErrEmpty := errors.New("empty name")
+	var errLong = errors.New("long name")
+	err := errors.New("other")
+	if name == "" {
+		return ErrEmpty
+	}
+	if len(name) > 10 {
+		return errLong
+	}
+	return err
+}
+`,
+			messages: []string{
+				"sentinel error ErrEmpty is created inside open",
+				"sentinel error errLong is created inside open",
+			},
+			suggestions: []string{"", ""},
+		},
+		{
+			name: "comparisons",
+			code: `package foo
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrClosed = errors.New("closed")
+
+type wrapped struct{}
+
+func (wrapped) Error() string { return "wrapped" }
+
+func (wrapped) Is(target error) bool { return target == ErrClosed }
+
+func read(err error) bool {
+	if err == nil || err == io.EOF {
+		return false
+	}
+	return ErrClosed != err
+}
+`,
+			messages: []string{
+				"compare errors with errors.Is(err, io.EOF) rather than ==",
+				"compare errors with !errors.Is(err, ErrClosed) rather than !=",
+			},
+			suggestions: []string{"errors.Is(err, io.EOF)", "!errors.Is(err, ErrClosed)"},
+		},
+		{
+			name: "comparison without errors imported",
+			code: `package foo
+
+import "io"
+
+func done(err error) bool { return err == io.EOF }
+`,
+			messages:    []string{"compare errors with errors.Is(err, io.EOF) rather than =="},
+			suggestions: []string{""},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "foo.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectSentinelErrors(path, node, fset, tt.SeverityWarning)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+			for i, issue := range issues {
+				assert.Equal(t, "sentinel-error", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				assert.Equal(t, tc.suggestions[i], issue.Suggestion)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectShadowedErr reports two common ways of swallowing errors with
+// `if err := f(); err != nil` statements:
+//
+//   - the if-init declares a new err that shadows an outer err, and the outer
+//     err is read after the if statement, although f's error never reached it.
+//   - the body of the if statement declares yet another err, shadowing the
+//     one being checked.
+func DetectShadowedErr(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	// declarations reported as shadowing an if-init err are not reported twice.
+	reported := make(map[*ast.Ident]bool)
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			ifStmt, ok := n.(*ast.IfStmt)
+			if !ok {
+				return true
+			}
+			inner := ifInitErr(ifStmt, info)
+			if inner == nil {
+				return true
+			}
+			innerObj := info.Defs[inner]
+
+			if issue, ok := checkOuterErrUse(filename, fset, fn, ifStmt, inner, innerObj, info); ok && !reported[inner] {
+				issue.Severity = severity
+				issues = append(issues, issue)
+			}
+
+			ast.Inspect(ifStmt.Body, func(n ast.Node) bool {
+				id, ok := n.(*ast.Ident)
+				if !ok || id.Name != "err" {
+					return true
+				}
+				if obj, ok := info.Defs[id].(*types.Var); ok && obj != innerObj {
+					reported[id] = true
+					issues = append(issues, tt.Issue{
+						Rule:     "shadowed-err",
+						Filename: filename,
+						Start:    fset.Position(id.Pos()),
+						End:      fset.Position(id.End()),
+						Message:  "err is redeclared inside the block checking it",
+						Note:     "the new err shadows the one returned by the if-init statement. assign to it with `=` or use a different name.",
+						RelatedLocations: []tt.Location{{
+							Filename: filename,
+							Start:    fset.Position(inner.Pos()),
+							End:      fset.Position(inner.End()),
+							Message:  "err being checked is declared here",
+						}},
+						Severity: severity,
+					})
+				}
+				return true
+			})
+
+			return true
+		})
+	}
+
+	return issues, nil
+}
+
+// ifInitErr returns the err identifier declared by the init statement of
+// ifStmt, if any.
+func ifInitErr(ifStmt *ast.IfStmt, info *types.Info) *ast.Ident {
+	assign, ok := ifStmt.Init.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE {
+		return nil
+	}
+	for _, lhs := range assign.Lhs {
+		if id, ok := lhs.(*ast.Ident); ok && id.Name == "err" && info.Defs[id] != nil {
+			return id
+		}
+	}
+	return nil
+}
+
+// checkOuterErrUse reports the if-init err when it shadows an outer err
+// which is read, before being reassigned, after the if statement.
+func checkOuterErrUse(
+	filename string,
+	fset *token.FileSet,
+	fn *ast.FuncDecl,
+	ifStmt *ast.IfStmt,
+	inner *ast.Ident,
+	innerObj types.Object,
+	info *types.Info,
+) (tt.Issue, bool) {
+	scope, ok := info.Scopes[ifStmt]
+	if !ok || scope.Parent() == nil || innerObj == nil {
+		return tt.Issue{}, false
+	}
+	_, outer := scope.Parent().LookupParent("err", ifStmt.Pos())
+	outerVar, ok := outer.(*types.Var)
+	if !ok || outerVar.Parent() == nil || outerVar.Parent() == outerVar.Pkg().Scope() {
+		return tt.Issue{}, false
+	}
+
+	var firstUse *ast.Ident
+	assigned := make(map[*ast.Ident]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if firstUse != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Pos() > ifStmt.End() {
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						assigned[id] = true
+					}
+				}
+			}
+		case *ast.Ident:
+			if n.Pos() > ifStmt.End() && info.Uses[n] == outerVar {
+				firstUse = n
+			}
+		}
+		return true
+	})
+	if firstUse == nil || assigned[firstUse] {
+		return tt.Issue{}, false
+	}
+
+	return tt.Issue{
+		Rule:     "shadowed-err",
+		Filename: filename,
+		Start:    fset.Position(inner.Pos()),
+		End:      fset.Position(inner.End()),
+		Message:  fmt.Sprintf("err declared here shadows the outer err read at line %d", fset.Position(firstUse.Pos()).Line),
+		Note:     "the error checked by this if statement never reaches the outer err. use `=` instead of `:=` if it should.",
+		RelatedLocations: []tt.Location{
+			{
+				Filename: filename,
+				Start:    fset.Position(outerVar.Pos()),
+				End:      fset.Position(outerVar.Pos() + token.Pos(len("err"))),
+				Message:  "outer err is declared here",
+			},
+			{
+				Filename: filename,
+				Start:    fset.Position(firstUse.Pos()),
+				End:      fset.Position(firstUse.End()),
+				Message:  "outer err is read here",
+			},
+		},
+	}, true
+}
@@ -0,0 +1,119 @@
+package lints
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectShadowedErr(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		code    string
+		lines   []int
+		related int
+	}{
+		{
+			name: "outer err read after if-init shadows it",
+			code: `package main
+
+func f() error { return nil }
+
+func example() error {
+	var err error
+	if err := f(); err != nil {
+		println(err)
+	}
+	return err
+}`,
+			lines:   []int{7},
+			related: 2,
+		},
+		{
+			name: "outer err reassigned after the if",
+			code: `package main
+
+func f() error { return nil }
+
+func example() error {
+	var err error
+	if err := f(); err != nil {
+		return err
+	}
+	err = f()
+	return err
+}`,
+		},
+		{
+			name: "no outer err",
+			code: `package main
+
+func f() error { return nil }
+
+func example() error {
+	if err := f(); err != nil {
+		return err
+	}
+	return nil
+}`,
+		},
+		{
+			name: "err redeclared in the body",
+			code: `package main
+
+func f() error { return nil }
+
+func example() error {
+	if err := f(); err != nil {
+		if err := f(); err != nil {
+			return err
+		}
+		return err
+	}
+	return nil
+}`,
+			lines:   []int{7},
+			related: 1,
+		},
+		{
+			name: "assignment in the body",
+			code: `package main
+
+func f() error { return nil }
+
+func example() error {
+	if err := f(); err != nil {
+		err = f()
+		return err
+	}
+	return nil
+}`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "test.go", tc.code, 0)
+			require.NoError(t, err)
+
+			issues, err := DetectShadowedErr("test.go", node, fset, tt.SeverityWarning)
+			require.NoError(t, err)
+
+			lines := make([]int, 0, len(issues))
+			for _, issue := range issues {
+				assert.Equal(t, "shadowed-err", issue.Rule)
+				assert.Len(t, issue.RelatedLocations, tc.related)
+				lines = append(lines, issue.Start.Line)
+			}
+			assert.ElementsMatch(t, tc.lines, lines)
+		})
+	}
+}
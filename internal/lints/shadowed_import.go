@@ -0,0 +1,109 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectShadowedImports reports local declarations named like a package
+// imported by the file, such as a variable named std or strings, which hide
+// the package where they are in scope.
+//
+// As for shadowed predeclared identifiers, declarations with few uses come
+// with a suggestion renaming them.
+func DetectShadowedImports(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	imports := importSpecs(node)
+	if len(imports) == 0 {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+
+	uses := make(map[types.Object][]*ast.Ident)
+	for id, obj := range info.Uses {
+		uses[obj] = append(uses[obj], id)
+	}
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		imp, ok := imports[id.Name]
+		if !ok {
+			return true
+		}
+		obj := info.Defs[id]
+		if obj == nil || !isLocal(obj) || !shadowsPredeclared(obj) {
+			return true
+		}
+		if _, ok := obj.(*types.PkgName); ok {
+			return true
+		}
+
+		newName := renameOf(id.Name)
+		issue := tt.Issue{
+			Rule:     "shadowed-import",
+			Filename: filename,
+			Start:    fset.Position(id.Pos()),
+			End:      fset.Position(id.End()),
+			Message:  fmt.Sprintf("declaration of %s shadows the imported package %s", id.Name, id.Name),
+			Note:     fmt.Sprintf("the package %s cannot be used where this declaration is in scope, and readers may mistake one for the other. consider renaming it, e.g. to %s.", id.Name, newName),
+			RelatedLocations: []tt.Location{{
+				Filename: filename,
+				Start:    fset.Position(imp.Pos()),
+				End:      fset.Position(imp.End()),
+				Message:  fmt.Sprintf("package %s is imported here", id.Name),
+			}},
+			Severity: severity,
+		}
+
+		if refs := uses[obj]; len(refs) <= maxRenameUses && canRename(obj, refs, newName) {
+			idents := append([]*ast.Ident{id}, refs...)
+			sort.Slice(idents, func(i, j int) bool { return idents[i].Pos() < idents[j].Pos() })
+			issue.Start = fset.Position(idents[0].Pos())
+			issue.End = fset.Position(idents[len(idents)-1].End())
+			issue.Suggestion = renameInRange(content, fset, idents, newName)
+		}
+
+		issues = append(issues, issue)
+		return true
+	})
+
+	return issues, nil
+}
+
+// importSpecs maps the names under which node refers to its imports, aliases
+// included, to their import specs. Blank and dot imports have no name.
+func importSpecs(node *ast.File) map[string]*ast.ImportSpec {
+	specs := make(map[string]*ast.ImportSpec)
+	for _, imp := range node.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		specs[name] = imp
+	}
+	return specs
+}
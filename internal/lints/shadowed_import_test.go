@@ -0,0 +1,97 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectShadowedImports(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		code        string
+		messages    []string
+		suggestions []string
+	}{
+		{
+			name: "local variable named like a package",
+			code: `package foo
+
+import "strings"
+
+func Join(xs []string) string {
+	strings := strings.Join(xs, ",")
+	return strings
+}
+`,
+			messages:    []string{"declaration of strings shadows the imported package strings"},
+			suggestions: []string{"stringsVal := strings.Join(xs, \",\")\n\treturn stringsVal"},
+		},
+		{
+			name: "alias, parameter and local type",
+			code: `package foo
+
+import (
+	str "strings"
+	"std"
+)
+
+func Upper(str string) string {
+	type std struct{}
+	return str
+}
+
+var _ = str.ToUpper
+var _ = std.Address("")
+`,
+			messages: []string{
+				"declaration of str shadows the imported package str",
+				"declaration of std shadows the imported package std",
+			},
+			suggestions: []string{"strVal string) string {\n\ttype std struct{}\n\treturn strVal", "stdVal"},
+		},
+		{
+			name: "fields, methods and blank imports",
+			code: `package foo
+
+import (
+	"strings"
+	_ "embed"
+)
+
+type config struct {
+	strings []string
+}
+
+func (c config) embed() string { return strings.Join(c.strings, "") }
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "foo.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectShadowedImports(path, node, fset, tt.SeverityWarning)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+			for i, issue := range issues {
+				assert.Equal(t, "shadowed-import", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				assert.Equal(t, tc.suggestions[i], issue.Suggestion)
+				require.Len(t, issue.RelatedLocations, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// gnoBuiltins are the builtins Gno adds to the predeclared identifiers of Go.
+var gnoBuiltins = map[string]bool{
+	"cross":      true,
+	"crossing":   true,
+	"istypednil": true,
+	"revive":     true,
+}
+
+// predeclaredRenames holds the names suggested in place of commonly shadowed identifiers.
+// Other identifiers get a "Val" suffix.
+var predeclaredRenames = map[string]string{
+	"cap":    "capacity",
+	"copy":   "dup",
+	"error":  "err",
+	"len":    "length",
+	"max":    "maxVal",
+	"min":    "minVal",
+	"new":    "newVal",
+	"print":  "printFn",
+	"string": "str",
+}
+
+// maxRenameUses is the number of uses up to which a local declaration
+// comes with a rename suggestion.
+const maxRenameUses = 3
+
+// DetectShadowedPredeclared reports declarations shadowing a predeclared
+// identifier of Go, such as len or string, or a builtin of Gno.
+//
+// Local declarations with few uses come with a suggestion renaming them,
+// provided the new name does not conflict with a name in scope.
+func DetectShadowedPredeclared(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+
+	uses := make(map[types.Object][]*ast.Ident)
+	for id, obj := range info.Uses {
+		uses[obj] = append(uses[obj], id)
+	}
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || !isPredeclared(id.Name) {
+			return true
+		}
+		obj := info.Defs[id]
+		if obj == nil || !shadowsPredeclared(obj) {
+			return true
+		}
+
+		kind := "predeclared identifier"
+		if gnoBuiltins[id.Name] {
+			kind = "gno builtin"
+		}
+		newName := renameOf(id.Name)
+		issue := tt.Issue{
+			Rule:     "shadowed-predeclared",
+			Filename: filename,
+			Start:    fset.Position(id.Pos()),
+			End:      fset.Position(id.End()),
+			Message:  fmt.Sprintf("declaration of %s shadows the %s", id.Name, kind),
+			Note:     fmt.Sprintf("the %s %s cannot be used where this declaration is in scope, and readers may mistake one for the other. consider renaming it, e.g. to %s.", kind, id.Name, newName),
+			Severity: severity,
+		}
+
+		if refs := uses[obj]; isLocal(obj) && len(refs) <= maxRenameUses && canRename(obj, refs, newName) {
+			idents := append([]*ast.Ident{id}, refs...)
+			sort.Slice(idents, func(i, j int) bool { return idents[i].Pos() < idents[j].Pos() })
+			issue.Start = fset.Position(idents[0].Pos())
+			issue.End = fset.Position(idents[len(idents)-1].End())
+			issue.Suggestion = renameInRange(content, fset, idents, newName)
+		}
+
+		issues = append(issues, issue)
+		return true
+	})
+
+	return issues, nil
+}
+
+func isPredeclared(name string) bool {
+	return gnoBuiltins[name] || (name != "_" && types.Universe.Lookup(name) != nil)
+}
+
+// shadowsPredeclared reports whether declaring obj hides a predeclared
+// identifier. Fields, methods and labels live in their own namespace.
+func shadowsPredeclared(obj types.Object) bool {
+	switch o := obj.(type) {
+	case *types.Var:
+		return !o.IsField()
+	case *types.Func:
+		sig, ok := o.Type().(*types.Signature)
+		return !ok || sig.Recv() == nil
+	case *types.Label:
+		return false
+	}
+	return true
+}
+
+func isLocal(obj types.Object) bool {
+	scope := obj.Parent()
+	return scope != nil && obj.Pkg() != nil && scope != obj.Pkg().Scope()
+}
+
+func renameOf(name string) string {
+	if renamed, ok := predeclaredRenames[name]; ok {
+		return renamed
+	}
+	return name + "Val"
+}
+
+// canRename reports whether newName is free at the declaration of obj and at
+// each of its references.
+func canRename(obj types.Object, refs []*ast.Ident, newName string) bool {
+	positions := []token.Pos{obj.Pos()}
+	for _, ref := range refs {
+		positions = append(positions, ref.Pos())
+	}
+	for _, pos := range positions {
+		scope := obj.Parent().Innermost(pos)
+		if scope == nil {
+			scope = obj.Parent()
+		}
+		if _, found := scope.LookupParent(newName, token.NoPos); found != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// renameInRange returns the source from the first to the last of idents,
+// with each of them renamed to newName.
+func renameInRange(content []byte, fset *token.FileSet, idents []*ast.Ident, newName string) string {
+	var b strings.Builder
+	prev := fset.Position(idents[0].Pos()).Offset
+	for _, id := range idents {
+		offset := fset.Position(id.Pos()).Offset
+		b.Write(content[prev:offset])
+		b.WriteString(newName)
+		prev = offset + len(id.Name)
+	}
+	return b.String()
+}
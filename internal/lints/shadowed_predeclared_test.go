@@ -0,0 +1,95 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectShadowedPredeclared(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		code        string
+		messages    []string
+		suggestions []string
+	}{
+		{
+			name: "local variable with few uses",
+			code: `package foo
+
+func Sum(xs []int) int {
+	len := 0
+	for _, x := range xs {
+		len += x
+	}
+	return len
+}
+`,
+			messages:    []string{"declaration of len shadows the predeclared identifier"},
+			suggestions: []string{"length := 0\n\tfor _, x := range xs {\n\t\tlength += x\n\t}\n\treturn length"},
+		},
+		{
+			name: "package-level, parameter conflicting with the new name and gno builtin",
+			code: `package foo
+
+type string struct{}
+
+func Parse(new int, newVal int) int {
+	return new + newVal
+}
+
+func cross() {}
+`,
+			messages: []string{
+				"declaration of string shadows the predeclared identifier",
+				"declaration of new shadows the predeclared identifier",
+				"declaration of cross shadows the gno builtin",
+			},
+			suggestions: []string{"", "", ""},
+		},
+		{
+			name: "fields, methods and too many uses",
+			code: `package foo
+
+type list struct {
+	len int
+}
+
+func (l list) cap() int { return l.len }
+
+func f() {
+	copy := 1
+	_, _, _, _ = copy, copy, copy, copy
+}
+`,
+			messages:    []string{"declaration of copy shadows the predeclared identifier"},
+			suggestions: []string{""},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "foo.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectShadowedPredeclared(path, node, fset, tt.SeverityWarning)
+			require.NoError(t, err)
+			require.Len(t, issues, len(tc.messages))
+			for i, issue := range issues {
+				assert.Equal(t, "shadowed-predeclared", issue.Rule)
+				assert.Equal(t, tc.messages[i], issue.Message)
+				assert.Equal(t, tc.suggestions[i], issue.Suggestion)
+			}
+		})
+	}
+}
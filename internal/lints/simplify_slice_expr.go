@@ -1,8 +1,10 @@
 package lints
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/token"
 
 	tt "github.com/gnolang/tlin/internal/types"
@@ -16,49 +18,90 @@ func DetectUnnecessarySliceLength(filename string, node *ast.File, fset *token.F
 			return true
 		}
 
-		if callExpr, ok := sliceExpr.High.(*ast.CallExpr); ok {
-			if ident, ok := callExpr.Fun.(*ast.Ident); ok && ident.Name == "len" {
-				if arg, ok := callExpr.Args[0].(*ast.Ident); ok {
-					if sliceExpr.X.(*ast.Ident).Name == arg.Name {
-						var suggestion, detailedMessage string
-						baseMessage := "unnecessary use of len() in slice expression, can be simplified"
+		// x[a:len(x):m] caps the capacity, dropping the high bound would not compile.
+		if sliceExpr.Slice3 {
+			return true
+		}
+
+		callExpr, ok := sliceExpr.High.(*ast.CallExpr)
+		if !ok || len(callExpr.Args) != 1 {
+			return true
+		}
+		if ident, ok := callExpr.Fun.(*ast.Ident); !ok || ident.Name != "len" {
+			return true
+		}
+		if !sameOperand(sliceExpr.X, callExpr.Args[0]) {
+			return true
+		}
+
+		original, err := renderNode(fset, sliceExpr)
+		if err != nil {
+			return true
+		}
+		simplified := *sliceExpr
+		simplified.High = nil
+		suggestion, err := renderNode(fset, &simplified)
+		if err != nil {
+			return true
+		}
+
+		baseMessage := "unnecessary use of len() in slice expression, can be simplified"
 
-						if sliceExpr.Low == nil {
-							suggestion = fmt.Sprintf("%s[:]", arg.Name)
-							detailedMessage = fmt.Sprintf(
-								"%s\nin this case, `%s[:len(%s)]` is equivalent to `%s[:]`. "+
-									"the full length of the slice is already implied when omitting both start and end indices.",
-								baseMessage, arg.Name, arg.Name, arg.Name)
-						} else if basicLit, ok := sliceExpr.Low.(*ast.BasicLit); ok {
-							suggestion = fmt.Sprintf("%s[%s:]", arg.Name, basicLit.Value)
-							detailedMessage = fmt.Sprintf("%s\nhere, `%s[%s:len(%s)]` can be simplified to `%s[%s:]`. "+
-								"when slicing to the end of a slice, using len() is unnecessary.",
-								baseMessage, arg.Name, basicLit.Value, arg.Name, arg.Name, basicLit.Value)
-						} else if lowIdent, ok := sliceExpr.Low.(*ast.Ident); ok {
-							suggestion = fmt.Sprintf("%s[%s:]", arg.Name, lowIdent.Name)
-							detailedMessage = fmt.Sprintf("%s\nin this instance, `%s[%s:len(%s)]` can be written as `%s[%s:]`. "+
-								"the len() function is redundant when slicing to the end, regardless of the start index.",
-								baseMessage, arg.Name, lowIdent.Name, arg.Name, arg.Name, lowIdent.Name)
-						}
+		var detailedMessage string
+		switch sliceExpr.Low.(type) {
+		case nil:
+			detailedMessage = fmt.Sprintf(
+				"%s\nin this case, `%s` is equivalent to `%s`. "+
+					"the full length of the slice is already implied when omitting both start and end indices.",
+				baseMessage, original, suggestion)
+		case *ast.BasicLit:
+			detailedMessage = fmt.Sprintf("%s\nhere, `%s` can be simplified to `%s`. "+
+				"when slicing to the end of a slice, using len() is unnecessary.",
+				baseMessage, original, suggestion)
+		default:
+			detailedMessage = fmt.Sprintf("%s\nin this instance, `%s` can be written as `%s`. "+
+				"the len() function is redundant when slicing to the end, regardless of the start index.",
+				baseMessage, original, suggestion)
+		}
 
-						issue := tt.Issue{
-							Rule:       "simplify-slice-range",
-							Filename:   filename,
-							Start:      fset.Position(sliceExpr.Pos()),
-							End:        fset.Position(sliceExpr.End()),
-							Message:    baseMessage,
-							Suggestion: suggestion,
-							Note:       detailedMessage,
-							Severity:   severity,
-						}
-						issues = append(issues, issue)
-					}
-				}
-			}
+		issue := tt.Issue{
+			Rule:       "simplify-slice-range",
+			Filename:   filename,
+			Start:      fset.Position(sliceExpr.Pos()),
+			End:        fset.Position(sliceExpr.End()),
+			Message:    baseMessage,
+			Suggestion: suggestion,
+			Note:       detailedMessage,
+			Severity:   severity,
 		}
+		issues = append(issues, issue)
 
 		return true
 	})
 
 	return issues, nil
 }
+
+// sameOperand reports whether a and b denote the same variable.
+// Only identifiers and selector chains are compared, since evaluating
+// anything else (calls, index expressions) twice may yield different values.
+func sameOperand(a, b ast.Expr) bool {
+	a, b = unparen(a), unparen(b)
+	switch x := a.(type) {
+	case *ast.Ident:
+		y, ok := b.(*ast.Ident)
+		return ok && x.Name == y.Name && x.Name != "_"
+	case *ast.SelectorExpr:
+		y, ok := b.(*ast.SelectorExpr)
+		return ok && x.Sel.Name == y.Sel.Name && sameOperand(x.X, y.X)
+	}
+	return false
+}
+
+func renderNode(fset *token.FileSet, node ast.Node) (string, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
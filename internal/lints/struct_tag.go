@@ -0,0 +1,128 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// TagNamingConventions maps the naming conventions accepted for the names
+// of struct tags to the pattern the names must match.
+var TagNamingConventions = map[string]*regexp.Regexp{
+	"camel":  regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	"pascal": regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+	"snake":  regexp.MustCompile(`^[a-z][a-z0-9_]*$`),
+	"kebab":  regexp.MustCompile(`^[a-z][a-z0-9-]*$`),
+}
+
+// DetectStructTagIssues checks the encoding tags, under each of keys, of the
+// struct types declared in node that use at least one of them. It reports
+// names used by several fields of a struct, names not following naming
+// (one of TagNamingConventions, or empty for any), and exported fields
+// excluded with `-` from some encodings but not from the others.
+func DetectStructTagIssues(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, keys []string, naming string) ([]tt.Issue, error) {
+	pattern := TagNamingConventions[naming]
+
+	var issues []tt.Issue
+	report := func(n ast.Node, message string) {
+		issues = append(issues, tt.Issue{
+			Rule:     "struct-tag",
+			Filename: filename,
+			Start:    fset.Position(n.Pos()),
+			End:      fset.Position(n.End()),
+			Message:  message,
+			Severity: severity,
+		})
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok || !hasTagKey(st, keys) {
+			return true
+		}
+
+		for _, key := range keys {
+			seen := make(map[string]string)
+			for _, field := range st.Fields.List {
+				name, tagged := tagName(field, key)
+				for _, ident := range field.Names {
+					if !ident.IsExported() || name == "-" {
+						continue
+					}
+					effective := name
+					if effective == "" {
+						effective = ident.Name
+					}
+					if other, ok := seen[effective]; ok {
+						report(ident, fmt.Sprintf("%s name %q of field %s is already used by field %s of %s", key, effective, ident.Name, other, spec.Name.Name))
+						continue
+					}
+					seen[effective] = ident.Name
+					if tagged && name != "" && pattern != nil && !pattern.MatchString(name) {
+						report(field.Tag, fmt.Sprintf("%s name %q of field %s does not follow the %s naming convention", key, name, ident.Name, naming))
+					}
+				}
+			}
+		}
+
+		for _, field := range st.Fields.List {
+			if len(field.Names) == 0 || !field.Names[0].IsExported() {
+				continue
+			}
+			var excluded, included []string
+			for _, key := range keys {
+				if name, tagged := tagName(field, key); name == "-" {
+					excluded = append(excluded, key)
+				} else if tagged {
+					included = append(included, key)
+				}
+			}
+			if len(excluded) > 0 && len(included) > 0 {
+				report(field.Tag, fmt.Sprintf("field %s is excluded from %s but not from %s",
+					field.Names[0].Name, strings.Join(excluded, ", "), strings.Join(included, ", ")))
+			}
+		}
+		return true
+	})
+
+	return issues, nil
+}
+
+func hasTagKey(st *ast.StructType, keys []string) bool {
+	for _, field := range st.Fields.List {
+		for _, key := range keys {
+			if _, tagged := tagName(field, key); tagged {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tagName returns the name given to field under key, without its options,
+// and whether the tag has the key at all.
+func tagName(field *ast.Field, key string) (string, bool) {
+	if field.Tag == nil {
+		return "", false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+	value, ok := reflect.StructTag(tag).Lookup(key)
+	if !ok {
+		return "", false
+	}
+	name, _, _ := strings.Cut(value, ",")
+	return name, true
+}
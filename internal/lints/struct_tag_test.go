@@ -0,0 +1,63 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectStructTagIssues(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		code   string
+		naming string
+	}{
+		{
+			name: "duplicates and inconsistent exclusion",
+			code: `package bank
+
+type Account struct {
+	ID      string 'json:"id"'
+	Owner   string 'json:"id"' // want "json name \"id\" of field Owner is already used by field ID of Account"
+	Balance int
+	Secret  string 'json:"-" amino:"secret"' // want "field Secret is excluded from json but not from amino"
+	Hidden  string 'json:"-" amino:"-"'
+	Other   string 'json:"-"'
+	Extra   string 'json:"Balance"' // want "json name \"Balance\" of field Extra is already used by field Balance"
+	note    string 'json:"id"'
+}
+
+type Untagged struct {
+	A, B int
+}
+`,
+		},
+		{
+			name:   "naming convention",
+			naming: "camel",
+			code: `package bank
+
+type Payload struct {
+	UserID   string 'json:"userId,omitempty"'
+	Amount   int    'json:"amount_total"' // want "json name \"amount_total\" of field Amount does not follow the camel naming convention"
+	Currency string 'json:",omitempty" amino:"Currency"' // want "amino name \"Currency\" of field Currency does not follow the camel naming convention"
+}
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+				return DetectStructTagIssues(filename, node, fset, tt.SeverityWarning, []string{"json", "amino"}, tc.naming)
+			}, "bank.gno", strings.ReplaceAll(tc.code, "'", "`"))
+		})
+	}
+}
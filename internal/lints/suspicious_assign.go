@@ -0,0 +1,204 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectSuspiciousAssignments reports assignments where a comparison, or
+// another kind of declaration, was likely intended:
+//
+//   - `if x = v; x {`, which assigns v to the boolean x and then tests it,
+//     where `if x == v {` was probably meant.
+//   - `for i = 0; ...` reusing a variable of the function that is not read
+//     after the loop, where `:=` was probably meant.
+//   - `for i := 0; ...` shadowing a variable of the function that is read
+//     after the loop, which the loop then does not update.
+func DetectSuspiciousAssignments(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.IfStmt:
+				if id := assignedAndTested(n.Init, n.Cond, info); id != nil {
+					issues = append(issues, tt.Issue{
+						Rule:     "suspicious-assignment",
+						Filename: filename,
+						Start:    fset.Position(n.Init.Pos()),
+						End:      fset.Position(n.Cond.End()),
+						Message:  fmt.Sprintf("%s is assigned and then tested by the if statement, a comparison was probably intended", id.Name),
+						Note:     fmt.Sprintf("`if %s = v; %s {` always overwrites %s before testing it. write `if %s == v {` to compare, or assign %s before the if statement.", id.Name, id.Name, id.Name, id.Name, id.Name),
+						Severity: severity,
+					})
+				}
+			case *ast.ForStmt:
+				if issue, ok := checkLoopVariable(filename, fset, fn, n, info); ok {
+					issue.Severity = severity
+					issues = append(issues, issue)
+				}
+			}
+			return true
+		})
+	}
+
+	return issues, nil
+}
+
+// assignedAndTested returns the boolean variable assigned with `=` by init
+// and tested alone, possibly negated, by cond.
+func assignedAndTested(init ast.Stmt, cond ast.Expr, info *types.Info) *ast.Ident {
+	assign, ok := init.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil
+	}
+	lhs, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	cond = unparen(cond)
+	if not, ok := cond.(*ast.UnaryExpr); ok && not.Op == token.NOT {
+		cond = unparen(not.X)
+	}
+	tested, ok := cond.(*ast.Ident)
+	if !ok || info.Uses[lhs] == nil || info.Uses[tested] != info.Uses[lhs] {
+		return nil
+	}
+	if basic, ok := info.Uses[lhs].Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsBoolean == 0 {
+		return nil
+	}
+	return lhs
+}
+
+// checkLoopVariable reports the variable initialized by loop when it is
+// declared with `=` but only used by the loop, or with `:=` while the
+// variable of the same name it shadows is read after the loop.
+func checkLoopVariable(filename string, fset *token.FileSet, fn *ast.FuncDecl, loop *ast.ForStmt, info *types.Info) (tt.Issue, bool) {
+	assign, ok := loop.Init.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 {
+		return tt.Issue{}, false
+	}
+	id, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok || id.Name == "_" {
+		return tt.Issue{}, false
+	}
+
+	switch assign.Tok {
+	case token.ASSIGN:
+		outer, ok := info.Uses[id].(*types.Var)
+		if !ok || !isLocal(outer) || outer.IsField() || readAfter(fn.Body, loop.End(), outer, info) != nil {
+			return tt.Issue{}, false
+		}
+		if capturedIn(loop.Body, outer, info) || fn.Type.Pos() <= outer.Pos() && outer.Pos() < fn.Type.End() {
+			// closures of the loop may read the variable after it ends,
+			// and a bare return reads the results.
+			return tt.Issue{}, false
+		}
+		return tt.Issue{
+			Rule:     "suspicious-assignment",
+			Filename: filename,
+			Start:    fset.Position(id.Pos()),
+			End:      fset.Position(id.End()),
+			Message:  fmt.Sprintf("loop variable %s reuses the %s of the function, which is not read after the loop", id.Name, id.Name),
+			Note:     fmt.Sprintf("`=` updates the %s declared outside the loop. declare the loop variable with `:=` if only the loop uses it.", id.Name),
+			RelatedLocations: []tt.Location{{
+				Filename: filename,
+				Start:    fset.Position(outer.Pos()),
+				End:      fset.Position(outer.Pos() + token.Pos(len(id.Name))),
+				Message:  fmt.Sprintf("%s is declared here", id.Name),
+			}},
+		}, true
+
+	case token.DEFINE:
+		scope, ok := info.Scopes[loop]
+		if !ok || scope.Parent() == nil {
+			return tt.Issue{}, false
+		}
+		_, obj := scope.Parent().LookupParent(id.Name, loop.Pos())
+		outer, ok := obj.(*types.Var)
+		if !ok || !isLocal(outer) || outer.IsField() {
+			return tt.Issue{}, false
+		}
+		read := readAfter(fn.Body, loop.End(), outer, info)
+		if read == nil {
+			return tt.Issue{}, false
+		}
+		return tt.Issue{
+			Rule:     "suspicious-assignment",
+			Filename: filename,
+			Start:    fset.Position(id.Pos()),
+			End:      fset.Position(id.End()),
+			Message:  fmt.Sprintf("loop variable %s shadows the %s read after the loop at line %d", id.Name, id.Name, fset.Position(read.Pos()).Line),
+			Note:     fmt.Sprintf("the loop does not update the outer %s, which keeps its value. use `=` instead of `:=` if it should.", id.Name),
+			RelatedLocations: []tt.Location{
+				{
+					Filename: filename,
+					Start:    fset.Position(outer.Pos()),
+					End:      fset.Position(outer.Pos() + token.Pos(len(id.Name))),
+					Message:  fmt.Sprintf("outer %s is declared here", id.Name),
+				},
+				{
+					Filename: filename,
+					Start:    fset.Position(read.Pos()),
+					End:      fset.Position(read.End()),
+					Message:  fmt.Sprintf("outer %s is read here", id.Name),
+				},
+			},
+		}, true
+	}
+	return tt.Issue{}, false
+}
+
+// readAfter returns the first use of obj in body after pos, unless it is an
+// assignment to obj.
+func readAfter(body *ast.BlockStmt, pos token.Pos, obj types.Object, info *types.Info) *ast.Ident {
+	var first *ast.Ident
+	assigned := make(map[*ast.Ident]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if first != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.ASSIGN && n.Pos() > pos {
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						assigned[id] = true
+					}
+				}
+			}
+		case *ast.Ident:
+			if n.Pos() > pos && info.Uses[n] == obj {
+				first = n
+			}
+		}
+		return true
+	})
+	if first == nil || assigned[first] {
+		return nil
+	}
+	return first
+}
+
+// capturedIn reports whether a function literal in node uses obj.
+func capturedIn(node ast.Node, obj types.Object, info *types.Info) bool {
+	captured := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.FuncLit); ok && usesObject(lit, obj, info) {
+			captured = true
+		}
+		return !captured
+	})
+	return captured
+}
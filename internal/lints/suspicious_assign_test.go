@@ -0,0 +1,85 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectSuspiciousAssignments(t *testing.T) {
+	t.Parallel()
+	src := `package main
+
+func ready() bool { return true }
+
+func Check(done bool) int {
+	if done = ready(); done { // want "done is assigned and then tested by the if statement"
+		return 1
+	}
+	if done = ready(); !done { // want "done is assigned and then tested by the if statement"
+		return 2
+	}
+	if n := 3; n > 2 {
+		return n
+	}
+	return 0
+}
+
+func Sum(xs []int) int {
+	var i int
+	total := 0
+	for i = 0; i < len(xs); i++ { // want "loop variable i reuses the i of the function"
+		total += xs[i]
+	}
+	return total
+}
+
+func Last(xs []int) int {
+	i := 0
+	for i := 0; i < len(xs); i++ { // want "loop variable i shadows the i read after the loop at line 32"
+		_ = xs[i]
+	}
+	return i
+}
+
+func Find(xs []int, x int) int {
+	i := 0
+	for i = 0; i < len(xs); i++ {
+		if xs[i] == x {
+			break
+		}
+	}
+	return i
+}
+
+func Count() (n int) {
+	for n = 0; n < 3; n++ {
+	}
+	return
+}
+
+func Later(xs []int) []func() int {
+	var fs []func() int
+	var i int
+	for i = 0; i < len(xs); i++ {
+		fs = append(fs, func() int { return i })
+	}
+	return fs
+}
+
+func Reset(xs []int) int {
+	i := 5
+	for i := 0; i < len(xs); i++ {
+		_ = xs[i]
+	}
+	i = 0
+	return i
+}
+`
+	ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectSuspiciousAssignments(filename, node, fset, tt.SeverityWarning)
+	}, "main.go", src)
+}
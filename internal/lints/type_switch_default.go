@@ -0,0 +1,134 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectMissingTypeSwitchDefault reports type switches without a default
+// clause over interfaces that cannot be assumed exhaustive: values of a type
+// that has no case are silently ignored.
+//
+// An interface declared in the linted package may be sealed (e.g. through an
+// unexported method), so only interfaces declared in another package, and
+// the empty interface, are reported. Interfaces listed in sealed, written as
+// `pkg.Name`, are considered exhaustive as well.
+func DetectMissingTypeSwitchDefault(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, sealed []string) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	isSealed := make(map[string]bool, len(sealed))
+	for _, name := range sealed {
+		isSealed[name] = true
+	}
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		sw, ok := n.(*ast.TypeSwitchStmt)
+		if !ok || hasDefaultClause(sw.Body) {
+			return true
+		}
+		x := typeSwitchOperand(sw)
+		if x == nil {
+			return true
+		}
+		name, open := openInterface(x, node.Name.Name, info)
+		if !open || isSealed[name] {
+			return true
+		}
+
+		issues = append(issues, tt.Issue{
+			Rule:     "type-switch-default",
+			Filename: filename,
+			Start:    fset.Position(sw.Pos()),
+			End:      fset.Position(sw.Body.Lbrace + 1),
+			Message:  fmt.Sprintf("type switch over %s has no default case", name),
+			Note:     "the interface is not declared in this package, so other types may implement it. add a default case to handle, or explicitly ignore, the values of the types not listed.",
+			Severity: severity,
+		})
+		return true
+	})
+
+	return issues, nil
+}
+
+func hasDefaultClause(body *ast.BlockStmt) bool {
+	for _, stmt := range body.List {
+		if cc, ok := stmt.(*ast.CaseClause); ok && cc.List == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// typeSwitchOperand returns x in `switch x.(type)` or `switch v := x.(type)`.
+func typeSwitchOperand(sw *ast.TypeSwitchStmt) ast.Expr {
+	var expr ast.Expr
+	switch s := sw.Assign.(type) {
+	case *ast.ExprStmt:
+		expr = s.X
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 {
+			expr = s.Rhs[0]
+		}
+	}
+	if ta, ok := expr.(*ast.TypeAssertExpr); ok {
+		return ta.X
+	}
+	return nil
+}
+
+// openInterface returns the name of the interface type of x, and whether it
+// is open to implementations outside of the package pkg.
+//
+// When the type cannot be resolved, which happens for imports of gno
+// packages, the type written in the declaration of x is used instead.
+func openInterface(x ast.Expr, pkg string, info *types.Info) (string, bool) {
+	if tv, ok := info.Types[x]; ok && tv.Type != nil && tv.Type != types.Typ[types.Invalid] {
+		iface, ok := tv.Type.Underlying().(*types.Interface)
+		if !ok {
+			return "", false
+		}
+		named, ok := tv.Type.(*types.Named)
+		if !ok {
+			if iface.Empty() {
+				return "any", true
+			}
+			return "", false
+		}
+		obj := named.Obj()
+		if obj.Pkg() == nil {
+			return obj.Name(), true // error
+		}
+		if obj.Pkg().Name() == pkg {
+			return "", false
+		}
+		return obj.Pkg().Name() + "." + obj.Name(), true
+	}
+
+	id, ok := x.(*ast.Ident)
+	if !ok || id.Obj == nil {
+		return "", false
+	}
+	var typ ast.Expr
+	switch decl := id.Obj.Decl.(type) {
+	case *ast.Field:
+		typ = decl.Type
+	case *ast.ValueSpec:
+		typ = decl.Type
+	}
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	pkgIdent, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	// a type assertion on a non-interface does not compile, so a type
+	// switch operand declared with a type of another package is an interface.
+	return pkgIdent.Name + "." + sel.Sel.Name, true
+}
@@ -0,0 +1,109 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectMissingTypeSwitchDefault(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		code   string
+		sealed []string
+	}{
+		{
+			name: "interface of another package",
+			code: `package handler
+
+import "fmt"
+
+func Handle(s fmt.Stringer) string {
+	switch v := s.(type) { // want "type switch over fmt.Stringer has no default case"
+	case nil:
+		return ""
+	case error:
+		return v.Error()
+	}
+	return "unknown"
+}
+`,
+		},
+		{
+			name: "empty interface and error",
+			code: `package handler
+
+func Handle(msg interface{}, err error) {
+	switch msg.(type) { // want "type switch over any has no default case"
+	case int:
+	}
+	switch err.(type) { // want "type switch over error has no default case"
+	case nil:
+	}
+}
+`,
+		},
+		{
+			name: "unresolved gno package",
+			code: `package handler
+
+import "gno.land/p/demo/msgs"
+
+func Handle(m msgs.Message) {
+	switch m.(type) { // want "type switch over msgs.Message has no default case"
+	case msgs.Transfer:
+	}
+}
+`,
+		},
+		{
+			name: "default case and local interface",
+			code: `package handler
+
+type message interface{ isMessage() }
+
+type ping struct{}
+
+func (ping) isMessage() {}
+
+func Handle(m message, v any) {
+	switch m.(type) {
+	case ping:
+	}
+	switch v.(type) {
+	case int:
+	default:
+	}
+}
+`,
+		},
+		{
+			name: "sealed list",
+			code: `package handler
+
+import "fmt"
+
+func Handle(s fmt.Stringer) {
+	switch s.(type) {
+	case error:
+	}
+}
+`,
+			sealed: []string{"fmt.Stringer"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+				return DetectMissingTypeSwitchDefault(filename, node, fset, tt.SeverityWarning, tc.sealed)
+			}, "handler.go", tc.code)
+		})
+	}
+}
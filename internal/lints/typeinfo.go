@@ -0,0 +1,239 @@
+package lints
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// stdImporter is shared by all type checks, so that the export data of a
+// package is only read once per process.
+var stdImporter = &lockedImporter{importer: importer.Default()}
+
+// lockedImporter serializes the imports of a types.Importer, which caches the
+// packages it imports and is not safe for concurrent use.
+type lockedImporter struct {
+	mu       sync.Mutex
+	importer types.Importer
+}
+
+func (l *lockedImporter) Import(path string) (*types.Package, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.importer.Import(path)
+}
+
+// sharedTypeInfo holds the type information of the files the engine is
+// linting, keyed by their *ast.File. The rules checking a file all need the
+// same information, which is expensive to compute.
+var sharedTypeInfo sync.Map
+
+type typeInfoEntry struct {
+	once sync.Once
+	info *types.Info
+}
+
+// ShareTypeInfo makes the rules checking node share its type information,
+// which is computed by the first rule asking for it, until release is
+// called. The engine calls it once per linted file, before running the
+// rules.
+func ShareTypeInfo(node *ast.File) (release func()) {
+	sharedTypeInfo.Store(node, new(typeInfoEntry))
+	return func() { sharedTypeInfo.Delete(node) }
+}
+
+// packageTypeInfo returns the type information of node, shared with the
+// other rules when the engine made it so with ShareTypeInfo.
+func packageTypeInfo(filename string, node *ast.File, fset *token.FileSet) *types.Info {
+	v, ok := sharedTypeInfo.Load(node)
+	if !ok {
+		return checkPackageTypes(filename, node, fset)
+	}
+	entry := v.(*typeInfoEntry)
+	entry.once.Do(func() {
+		entry.info = checkPackageTypes(filename, node, fset)
+	})
+	return entry.info
+}
+
+// checkPackageTypes type-checks node together with the other files of its
+// package, so that identifiers declared in sibling files resolve.
+//
+// Sibling files are parsed into fset. Packages may mix .gno sources with .go
+// files, and both are combined. Test files are only combined with other test
+// files. A sibling is also left out when it belongs to another package or
+// declares a name that is already declared, which happens for the temporary
+// copy the engine makes of .gno files and for directories holding
+// independent examples.
+//
+// Imports of packages of the modules around the file are type-checked from
+// their sources, see resolveImportDir. Type errors are ignored: other imports
+// of gno packages cannot be resolved, and the partial information is still
+// useful.
+func checkPackageTypes(filename string, node *ast.File, fset *token.FileSet) *types.Info {
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Instances:  make(map[*ast.Ident]types.Instance),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+
+	files := append([]*ast.File{node}, siblingFiles(filename, node, fset)...)
+
+	conf := types.Config{
+		Importer: newModuleImporter(fset, filepath.Dir(filename)),
+		//! DO NOT STOP AT ERRORS.
+		//! error check may broke the lint formatting process.
+		Error: func(error) {},
+	}
+	conf.Check(node.Name.Name, fset, files, info)
+
+	return info
+}
+
+// moduleImporter imports the packages of the modules of the checkout from
+// their sources, parsed into fset, and the other packages with stdImporter.
+type moduleImporter struct {
+	fset *token.FileSet
+	dir  string
+	// pkgs holds the packages imported from sources. A nil package is being
+	// imported, and importing it again means an import cycle.
+	pkgs map[string]*types.Package
+}
+
+func newModuleImporter(fset *token.FileSet, dir string) *moduleImporter {
+	return &moduleImporter{fset: fset, dir: dir, pkgs: make(map[string]*types.Package)}
+}
+
+func (m *moduleImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := m.pkgs[path]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", path)
+		}
+		return pkg, nil
+	}
+	// standard packages have no dot in their first element.
+	if first, _, _ := strings.Cut(path, "/"); !strings.Contains(first, ".") {
+		return stdImporter.Import(path)
+	}
+	dir, ok := resolveImportDir(m.dir, path)
+	if !ok {
+		return stdImporter.Import(path)
+	}
+	files := packageDirFiles(m.fset, dir)
+	if len(files) == 0 {
+		return stdImporter.Import(path)
+	}
+
+	m.pkgs[path] = nil
+	conf := types.Config{Importer: m, Error: func(error) {}}
+	pkg, _ := conf.Check(path, m.fset, files, nil)
+	m.pkgs[path] = pkg
+	return pkg, nil
+}
+
+// packageDirFiles parses the sources of the package in dir, leaving out
+// tests and the temporary copies made by the engine.
+func packageDirFiles(fset *token.FileSet, dir string) []*ast.File {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if entry.IsDir() || (ext != ".gno" && ext != ".go") || strings.HasPrefix(name, "temp_") || isTestFile(name) || isFiletest(name) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil || (len(files) > 0 && file.Name.Name != files[0].Name.Name) {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+func siblingFiles(filename string, node *ast.File, fset *token.FileSet) []*ast.File {
+	dir := filepath.Dir(filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	current, err := os.ReadFile(filename)
+	if err != nil {
+		return nil
+	}
+
+	includeTests := isTestFile(filename)
+
+	declared := make(map[string]bool)
+	for name := range node.Scope.Objects {
+		declared[name] = true
+	}
+
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if entry.IsDir() || (ext != ".gno" && ext != ".go") {
+			continue
+		}
+		if strings.HasPrefix(name, "temp_") || isFiletest(name) || (isTestFile(name) && !includeTests) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if path == filename {
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil || bytes.Equal(src, current) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil || file.Name.Name != node.Name.Name {
+			continue
+		}
+		if redeclares(file, declared) {
+			continue
+		}
+		for name := range file.Scope.Objects {
+			declared[name] = true
+		}
+		files = append(files, file)
+	}
+
+	return files
+}
+
+func redeclares(file *ast.File, declared map[string]bool) bool {
+	for name := range file.Scope.Objects {
+		if declared[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func isTestFile(filename string) bool {
+	return strings.HasSuffix(filename, "_test.gno") || strings.HasSuffix(filename, "_test.go")
+}
+
+// isFiletest reports whether filename is a gno filetest, which is a
+// standalone program rather than part of the package.
+func isFiletest(filename string) bool {
+	return strings.HasSuffix(filename, "_filetest.gno") || strings.HasSuffix(filename, "_filetest.go")
+}
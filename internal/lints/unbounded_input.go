@@ -0,0 +1,212 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+const unboundedInputNote = "exported realm functions can be called with arguments of any length, and the gas spent by loops and allocations grows with them. check the length of %s against a maximum before using it."
+
+// DetectUnboundedInputs reports exported functions of realm packages that
+// loop over a string or slice parameter, or allocate from its length, on a
+// path of their control flow graph where the length of the parameter was
+// not checked first.
+//
+// An if statement comparing the length of the parameter with anything but
+// zero counts as a check, as does passing the parameter to a function whose
+// name contains "valid", "check" or "assert", such as validateName(name).
+func DetectUnboundedInputs(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	if !IsRealmFile(filename) {
+		return nil, nil
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() || fn.Body == nil {
+			continue
+		}
+
+		var g *cfg.CFG
+		for _, param := range sizedParams(fn, info) {
+			if g == nil {
+				g = cfg.FromFunc(fn)
+			}
+			use, what := firstUnboundedUse(g, param, info)
+			if use == nil {
+				continue
+			}
+			issues = append(issues, tt.Issue{
+				Rule:     "unbounded-input",
+				Category: "security",
+				Filename: filename,
+				Start:    fset.Position(use.Pos()),
+				End:      fset.Position(use.End()),
+				Message:  fmt.Sprintf("%s %s %s without checking its length", fn.Name.Name, what, param.Name()),
+				Note:     fmt.Sprintf(unboundedInputNote, param.Name()),
+				Cost:     tt.CostUnbounded,
+				Severity: severity,
+				RelatedLocations: []tt.Location{{
+					Filename: filename,
+					Start:    fset.Position(param.Pos()),
+					End:      fset.Position(param.Pos() + token.Pos(len(param.Name()))),
+					Message:  fmt.Sprintf("%s is a parameter of the exported function %s", param.Name(), fn.Name.Name),
+				}},
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// sizedParams returns the string and slice parameters of fn.
+func sizedParams(fn *ast.FuncDecl, info *types.Info) []*types.Var {
+	var params []*types.Var
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			v, ok := info.Defs[name].(*types.Var)
+			if !ok || name.Name == "_" {
+				continue
+			}
+			switch t := v.Type().Underlying().(type) {
+			case *types.Basic:
+				if t.Info()&types.IsString != 0 {
+					params = append(params, v)
+				}
+			case *types.Slice:
+				params = append(params, v)
+			}
+		}
+	}
+	return params
+}
+
+// firstUnboundedUse returns the first loop over param, or allocation sized
+// by its length, that can be reached from the entry of g without going
+// through a check of its length, along with a description of the use.
+func firstUnboundedUse(g *cfg.CFG, param *types.Var, info *types.Info) (ast.Node, string) {
+	var reached []ast.Stmt
+	seen := map[ast.Stmt]bool{g.Entry: true}
+	queue := []ast.Stmt{g.Entry}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, succ := range g.Succs(cur) {
+			if seen[succ] || succ == g.Exit || checksLength(succ, param, info) {
+				continue
+			}
+			seen[succ] = true
+			reached = append(reached, succ)
+			queue = append(queue, succ)
+		}
+	}
+	sort.Slice(reached, func(i, j int) bool { return reached[i].Pos() < reached[j].Pos() })
+
+	for _, s := range reached {
+		switch s := s.(type) {
+		case *ast.RangeStmt:
+			if isParam(s.X, param, info) {
+				return s.X, "loops over"
+			}
+		case *ast.ForStmt:
+			if s.Cond != nil && lenOfParam(s.Cond, param, info) != nil {
+				return s.Cond, "loops over"
+			}
+			continue
+		}
+		for _, n := range stmtHeader(s) {
+			var alloc ast.Node
+			inspectCalls(n, func(call *ast.CallExpr) {
+				if alloc != nil {
+					return
+				}
+				if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "make" && len(call.Args) > 1 {
+					for _, arg := range call.Args[1:] {
+						if lenOfParam(arg, param, info) != nil {
+							alloc = call
+						}
+					}
+				}
+			})
+			if alloc != nil {
+				return alloc, "allocates from the length of"
+			}
+		}
+	}
+	return nil, ""
+}
+
+// checksLength reports whether s is an if statement comparing the length of
+// param with something other than zero, or calls a validation function with
+// param.
+func checksLength(s ast.Stmt, param *types.Var, info *types.Info) bool {
+	var expr ast.Expr
+	switch s := s.(type) {
+	case *ast.IfStmt:
+		expr = s.Cond
+	case *ast.ExprStmt:
+		expr = s.X
+	default:
+		return false
+	}
+
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BinaryExpr:
+			switch n.Op {
+			case token.LSS, token.LEQ, token.GTR, token.GEQ, token.EQL, token.NEQ:
+			default:
+				return true
+			}
+			if lenOfParam(n.X, param, info) != nil && !isZero(n.Y) ||
+				lenOfParam(n.Y, param, info) != nil && !isZero(n.X) {
+				found = true
+			}
+		case *ast.CallExpr:
+			name := strings.ToLower(calleeName(n))
+			if !strings.Contains(name, "valid") && !strings.Contains(name, "check") && !strings.Contains(name, "assert") {
+				return true
+			}
+			for _, arg := range n.Args {
+				if isParam(arg, param, info) {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// lenOfParam returns the call len(param) in expr, if any.
+func lenOfParam(expr ast.Expr, param *types.Var, info *types.Info) *ast.CallExpr {
+	var found *ast.CallExpr
+	inspectCalls(expr, func(call *ast.CallExpr) {
+		if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "len" && len(call.Args) == 1 && isParam(call.Args[0], param, info) {
+			found = call
+		}
+	})
+	return found
+}
+
+func isParam(expr ast.Expr, param *types.Var, info *types.Info) bool {
+	id, ok := unparen(expr).(*ast.Ident)
+	return ok && info.Uses[id] == param
+}
+
+func isZero(expr ast.Expr) bool {
+	lit, ok := unparen(expr).(*ast.BasicLit)
+	return ok && lit.Kind == token.INT && lit.Value == "0"
+}
@@ -0,0 +1,99 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectUnboundedInputs(t *testing.T) {
+	t.Parallel()
+	check := func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectUnboundedInputs(filename, node, fset, tt.SeverityWarning)
+	}
+
+	ruletest.RunFiles(t, check, map[string]string{
+		"gno.mod": "module gno.land/r/demo/board\n",
+		"board.gno": `package board
+
+const maxLen = 256
+
+var posts []string
+
+func Post(body string) {
+	for _, r := range body { // want "Post loops over body without checking its length"
+		_ = r
+	}
+	posts = append(posts, body)
+}
+
+func Tag(tags []string) {
+	for i := 0; i < len(tags); i++ { // want "Tag loops over tags without checking its length"
+		posts = append(posts, tags[i])
+	}
+}
+
+func Copy(data []byte) []byte {
+	out := make([]byte, 0, len(data)) // want "Copy allocates from the length of data without checking its length"
+	return append(out, data...)
+}
+
+func PostChecked(body string) {
+	if len(body) > maxLen {
+		panic("body too long")
+	}
+	for _, r := range body {
+		_ = r
+	}
+}
+
+func PostValidated(body string) {
+	validateBody(body)
+	for _, r := range body {
+		_ = r
+	}
+}
+
+func PostEmpty(body string) {
+	if len(body) == 0 {
+		return
+	}
+	for _, r := range body { // want "PostEmpty loops over body without checking its length"
+		_ = r
+	}
+}
+
+func Count(n int, name string) int {
+	return n + len(name)
+}
+
+func render(body string) {
+	for _, r := range body {
+		_ = r
+	}
+}
+
+func validateBody(body string) {
+	if len(body) > maxLen {
+		panic("body too long")
+	}
+}
+`,
+	})
+
+	// packages that are not realms are not checked.
+	ruletest.RunFiles(t, check, map[string]string{
+		"gno.mod": "module gno.land/p/demo/board\n",
+		"board.gno": `package board
+
+func Post(body string) {
+	for _, r := range body {
+		_ = r
+	}
+}
+`,
+	})
+}
@@ -0,0 +1,273 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectUnboundedRecursion reports recursive functions in realm code for
+// which every path through the function body reaches a recursive call.
+//
+// Recursion is found on the call graph of the file. Direct and mutual
+// recursion are handled alike: a group of mutually recursive functions is
+// reported only when none of its members can return without calling
+// back into the group.
+func DetectUnboundedRecursion(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	if !IsRealmFile(filename) {
+		return nil, nil
+	}
+
+	graph := buildCallGraph(node)
+
+	var issues []tt.Issue
+	reported := make(map[string]bool)
+	for _, name := range graph.names {
+		if reported[name] {
+			continue
+		}
+		group := graph.recursiveGroup(name)
+		if len(group) == 0 {
+			continue
+		}
+		for _, member := range group {
+			reported[member] = true
+		}
+
+		if graph.anyHasBaseCase(group) {
+			continue
+		}
+
+		fn := graph.decls[group[0]]
+		message := fmt.Sprintf("recursive function %s has no base case", group[0])
+		if len(group) > 1 {
+			message = fmt.Sprintf("mutually recursive functions %s have no base case", strings.Join(group, ", "))
+		}
+		issues = append(issues, tt.Issue{
+			Rule:     "unbounded-recursion",
+			Filename: filename,
+			Start:    fset.Position(fn.Pos()),
+			End:      fset.Position(fn.Name.End()),
+			Message:  message,
+			Note:     "every path reaches a recursive call before returning. unbounded recursion exhausts gas or the stack on-chain; add a condition that returns before recursing.",
+			Severity: severity,
+			Cost:     tt.CostUnbounded,
+		})
+	}
+
+	return issues, nil
+}
+
+// callGraph is the call graph between the functions and methods declared in a single file.
+type callGraph struct {
+	names []string // in declaration order
+	decls map[string]*ast.FuncDecl
+	calls map[string]map[string]bool
+}
+
+func buildCallGraph(file *ast.File) *callGraph {
+	g := &callGraph{
+		decls: make(map[string]*ast.FuncDecl),
+		calls: make(map[string]map[string]bool),
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		name := funcKey(fn)
+		g.names = append(g.names, name)
+		g.decls[name] = fn
+	}
+
+	for _, name := range g.names {
+		fn := g.decls[name]
+		callees := make(map[string]bool)
+		inspectCalls(fn.Body, func(call *ast.CallExpr) {
+			if callee := calleeKey(fn, call); g.decls[callee] != nil {
+				callees[callee] = true
+			}
+		})
+		g.calls[name] = callees
+	}
+
+	return g
+}
+
+// recursiveGroup returns the functions that are mutually recursive with
+// name (including name itself), or nil if name is not recursive.
+func (g *callGraph) recursiveGroup(name string) []string {
+	from := g.reachable(name)
+	if !from[name] {
+		return nil
+	}
+
+	var group []string
+	for _, other := range g.names {
+		if other == name || (from[other] && g.reachable(other)[name]) {
+			group = append(group, other)
+		}
+	}
+	return group
+}
+
+func (g *callGraph) reachable(name string) map[string]bool {
+	seen := make(map[string]bool)
+	stack := []string{name}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for callee := range g.calls[cur] {
+			if !seen[callee] {
+				seen[callee] = true
+				stack = append(stack, callee)
+			}
+		}
+	}
+	return seen
+}
+
+func (g *callGraph) anyHasBaseCase(group []string) bool {
+	members := make(map[string]bool, len(group))
+	for _, name := range group {
+		members[name] = true
+	}
+
+	for _, name := range group {
+		fn := g.decls[name]
+		callsGroup := func(s ast.Stmt) bool {
+			found := false
+			for _, n := range stmtHeader(s) {
+				inspectCalls(n, func(call *ast.CallExpr) {
+					if members[calleeKey(fn, call)] {
+						found = true
+					}
+				})
+			}
+			return found
+		}
+		if exitReachableAvoiding(cfg.FromFunc(fn), callsGroup) {
+			return true
+		}
+	}
+	return false
+}
+
+// exitReachableAvoiding reports whether the exit of the graph can be reached
+// from its entry without going through a statement for which blocked is true.
+func exitReachableAvoiding(g *cfg.CFG, blocked func(ast.Stmt) bool) bool {
+	seen := map[ast.Stmt]bool{g.Entry: true}
+	queue := []ast.Stmt{g.Entry}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == g.Exit {
+			return true
+		}
+		for _, succ := range g.Succs(cur) {
+			if seen[succ] || (succ != g.Exit && blocked(succ)) {
+				continue
+			}
+			seen[succ] = true
+			queue = append(queue, succ)
+		}
+	}
+	return false
+}
+
+// stmtHeader returns the parts of a CFG block that are evaluated when the
+// block itself is executed. Nested statements form their own blocks.
+func stmtHeader(s ast.Stmt) []ast.Node {
+	var nodes []ast.Node
+	add := func(e ast.Expr) {
+		if e != nil {
+			nodes = append(nodes, e)
+		}
+	}
+
+	switch s := s.(type) {
+	case *ast.IfStmt:
+		add(s.Cond)
+	case *ast.ForStmt:
+		add(s.Cond)
+	case *ast.RangeStmt:
+		add(s.X)
+	case *ast.SwitchStmt:
+		add(s.Tag)
+	case *ast.CaseClause:
+		for _, e := range s.List {
+			add(e)
+		}
+	case *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.CommClause, *ast.LabeledStmt, *ast.BlockStmt:
+		// only contain nested statements
+	default:
+		nodes = append(nodes, s)
+	}
+	return nodes
+}
+
+// inspectCalls calls fn for every call expression in n, except the ones
+// inside function literals, which may never be invoked.
+func inspectCalls(n ast.Node, fn func(*ast.CallExpr)) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			fn(n)
+		}
+		return true
+	})
+}
+
+// funcKey returns the call graph key of a declaration: the function name, or
+// Type.Method for methods.
+func funcKey(fn *ast.FuncDecl) string {
+	if recv := receiverTypeName(fn); recv != "" {
+		return recv + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// calleeKey resolves the call graph key of a call made from caller. Only
+// plain function calls and method calls on the caller's receiver are resolved.
+func calleeKey(caller *ast.FuncDecl, call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		x, ok := fun.X.(*ast.Ident)
+		if !ok || caller.Recv == nil || len(caller.Recv.List) == 0 || len(caller.Recv.List[0].Names) == 0 {
+			return ""
+		}
+		if x.Name != caller.Recv.List[0].Names[0].Name {
+			return ""
+		}
+		return receiverTypeName(caller) + "." + fun.Sel.Name
+	}
+	return ""
+}
+
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		if id, ok := t.X.(*ast.Ident); ok {
+			return id.Name
+		}
+	}
+	return ""
+}
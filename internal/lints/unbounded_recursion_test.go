@@ -0,0 +1,125 @@
+package lints
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectUnboundedRecursion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		code     string
+		messages []string
+	}{
+		{
+			name: "base case before recursive call",
+			code: `package foo
+
+func Fact(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * Fact(n-1)
+}
+`,
+		},
+		{
+			name: "no base case",
+			code: `package foo
+
+func Loop(n int) int {
+	return Loop(n + 1)
+}
+`,
+			messages: []string{"recursive function Loop has no base case"},
+		},
+		{
+			name: "recursive call in condition",
+			code: `package foo
+
+func Walk(n int) int {
+	if Walk(n-1) > 0 {
+		return 1
+	}
+	return 0
+}
+`,
+			messages: []string{"recursive function Walk has no base case"},
+		},
+		{
+			name: "recursion only inside closure",
+			code: `package foo
+
+func Lazy() func() {
+	return func() { Lazy() }
+}
+`,
+		},
+		{
+			name: "mutual recursion without base case",
+			code: `package foo
+
+func Ping(n int) { Pong(n) }
+
+func Pong(n int) { Ping(n) }
+`,
+			messages: []string{"mutually recursive functions Ping, Pong have no base case"},
+		},
+		{
+			name: "mutual recursion with a base case in one member",
+			code: `package foo
+
+func Even(n int) bool {
+	if n == 0 {
+		return true
+	}
+	return Odd(n - 1)
+}
+
+func Odd(n int) bool { return Even(n - 1) }
+`,
+		},
+		{
+			name: "method recursion on receiver",
+			code: `package foo
+
+type Tree struct{ left *Tree }
+
+func (t *Tree) Depth() int {
+	return 1 + t.Depth()
+}
+`,
+			messages: []string{"recursive function Tree.Depth has no base case"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "gno.mod"), []byte("module gno.land/r/demo/foo\n"), 0o644))
+			path := filepath.Join(dir, "file.go")
+			require.NoError(t, os.WriteFile(path, []byte(tc.code), 0o644))
+
+			node, fset, err := ParseFile(path, nil)
+			require.NoError(t, err)
+
+			issues, err := DetectUnboundedRecursion(path, node, fset, tt.SeverityWarning)
+			require.NoError(t, err)
+
+			messages := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				assert.Equal(t, "unbounded-recursion", issue.Rule)
+				messages = append(messages, issue.Message)
+			}
+			assert.ElementsMatch(t, tc.messages, messages)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectUnbufferedSends reports sends on an unbuffered channel that the same
+// function receives from later, following its control flow graph. The send
+// blocks until another goroutine receives, and none can:
+//
+//   - no go statement can run before the send, and
+//   - the channel is made by the function and only sent to, received from,
+//     ranged over or given to len, cap and close, so that no other function
+//     gets hold of it.
+//
+// Sends in select statements do not block when other cases are ready, and
+// are left alone.
+func DetectUnbufferedSends(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	var issues []tt.Issue
+	check := func(body *ast.BlockStmt, g *cfg.CFG) {
+		for _, send := range blockingSends(body, info) {
+			if goStmtBefore(g, send.stmt) {
+				continue
+			}
+			recv := firstReceiveAfter(g, send.stmt, send.ch, info)
+			if recv == nil {
+				continue
+			}
+			name := send.ch.Name()
+			issues = append(issues, tt.Issue{
+				Rule:     "unbuffered-send",
+				Filename: filename,
+				Start:    fset.Position(send.stmt.Pos()),
+				End:      fset.Position(send.stmt.End()),
+				Message:  fmt.Sprintf("send on unbuffered channel %s blocks forever: it is only received from later by the same goroutine", name),
+				Note:     fmt.Sprintf("a send on an unbuffered channel waits for a receiver, and no other goroutine can receive from %s. start the receiver in a goroutine before sending, or give the channel a buffer with make(chan T, n).", name),
+				Severity: severity,
+				RelatedLocations: []tt.Location{{
+					Filename: filename,
+					Start:    fset.Position(recv.Pos()),
+					End:      fset.Position(recv.End()),
+					Message:  "the channel is received from here",
+				}},
+			})
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				check(n.Body, cfg.FromFunc(n))
+			}
+		case *ast.FuncLit:
+			check(n.Body, cfg.FromStmts(n.Body.List))
+		}
+		return true
+	})
+
+	// the function literals are checked after their enclosing function.
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Start.Offset < issues[j].Start.Offset
+	})
+	return issues, nil
+}
+
+type channelSend struct {
+	stmt *ast.SendStmt
+	ch   *types.Var
+}
+
+// blockingSends returns the sends of body, outside of select statements and
+// function literals, on unbuffered channels made by body and not handed to
+// anything else.
+func blockingSends(body *ast.BlockStmt, info *types.Info) []channelSend {
+	made := make(map[*types.Var]bool)
+	allowed := make(map[*ast.Ident]bool)
+	inSelect := make(map[*ast.SendStmt]bool)
+	var sends []*ast.SendStmt
+	allow := func(e ast.Expr) {
+		if id, ok := unparen(e).(*ast.Ident); ok {
+			allowed[id] = true
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CommClause:
+			// the comm of a select case is not a blocking send.
+			if send, ok := n.Comm.(*ast.SendStmt); ok {
+				inSelect[send] = true
+			}
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					markUnbuffered(lhs, n.Rhs[i], info, made)
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) == len(n.Values) {
+				for i, name := range n.Names {
+					markUnbuffered(name, n.Values[i], info, made)
+				}
+			}
+		case *ast.SendStmt:
+			allow(n.Chan)
+			if !inSelect[n] {
+				sends = append(sends, n)
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				allow(n.X)
+			}
+		case *ast.RangeStmt:
+			allow(n.X)
+		case *ast.CallExpr:
+			if id, ok := n.Fun.(*ast.Ident); ok && len(n.Args) == 1 {
+				if _, ok := info.Uses[id].(*types.Builtin); ok && (id.Name == "len" || id.Name == "cap" || id.Name == "close") {
+					allow(n.Args[0])
+				}
+			}
+		}
+		return true
+	})
+
+	// a channel escapes through any other use, including the uses by
+	// function literals, which the walk above did not allow.
+	for id, obj := range info.Uses {
+		if v, ok := obj.(*types.Var); ok && made[v] && !allowed[id] {
+			delete(made, v)
+		}
+	}
+
+	var result []channelSend
+	for _, send := range sends {
+		id, ok := unparen(send.Chan).(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if v, ok := info.Uses[id].(*types.Var); ok && made[v] {
+			result = append(result, channelSend{stmt: send, ch: v})
+		}
+	}
+	return result
+}
+
+// markUnbuffered records name in made when value makes an unbuffered
+// channel.
+func markUnbuffered(name, value ast.Expr, info *types.Info, made map[*types.Var]bool) {
+	id, ok := name.(*ast.Ident)
+	if !ok {
+		return
+	}
+	v, ok := info.Defs[id].(*types.Var)
+	if !ok {
+		return
+	}
+	call, ok := unparen(value).(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return
+	}
+	if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "make" {
+		return
+	}
+	if _, ok := unparen(call.Args[0]).(*ast.ChanType); !ok {
+		return
+	}
+	if len(call.Args) == 2 {
+		size := info.Types[call.Args[1]].Value
+		if size == nil || constant.Sign(size) != 0 {
+			return
+		}
+	}
+	made[v] = true
+}
+
+// goStmtBefore reports whether a go statement can run before s.
+func goStmtBefore(g *cfg.CFG, s ast.Stmt) bool {
+	seen := map[ast.Stmt]bool{s: true}
+	queue := []ast.Stmt{s}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, pred := range g.Preds(cur) {
+			if seen[pred] {
+				continue
+			}
+			if _, ok := pred.(*ast.GoStmt); ok {
+				return true
+			}
+			seen[pred] = true
+			queue = append(queue, pred)
+		}
+	}
+	return false
+}
+
+// firstReceiveAfter returns the first receive from ch, or range over it,
+// that can be reached from s.
+func firstReceiveAfter(g *cfg.CFG, s ast.Stmt, ch *types.Var, info *types.Info) ast.Node {
+	isCh := func(e ast.Expr) bool {
+		id, ok := unparen(e).(*ast.Ident)
+		return ok && info.Uses[id] == ch
+	}
+
+	var reached []ast.Stmt
+	seen := map[ast.Stmt]bool{s: true}
+	queue := []ast.Stmt{s}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, succ := range g.Succs(cur) {
+			if seen[succ] || succ == g.Exit {
+				continue
+			}
+			seen[succ] = true
+			reached = append(reached, succ)
+			queue = append(queue, succ)
+		}
+	}
+	sort.Slice(reached, func(i, j int) bool { return reached[i].Pos() < reached[j].Pos() })
+
+	for _, r := range reached {
+		if rng, ok := r.(*ast.RangeStmt); ok && isCh(rng.X) {
+			return rng.X
+		}
+		var found ast.Node
+		for _, n := range stmtHeader(r) {
+			ast.Inspect(n, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.UnaryExpr:
+					if n.Op == token.ARROW && isCh(n.X) {
+						found = n
+					}
+				}
+				return found == nil
+			})
+			if found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectUnbufferedSends(t *testing.T) {
+	t.Parallel()
+	src := `package queue
+
+func Direct() int {
+	ch := make(chan int)
+	ch <- 1 // want "send on unbuffered channel ch blocks forever"
+	return <-ch
+}
+
+func ZeroSized() {
+	var done = make(chan struct{}, 0)
+	done <- struct{}{} // want "send on unbuffered channel done blocks forever"
+	close(done)
+	for range done {
+	}
+}
+
+func Buffered() int {
+	ch := make(chan int, 1)
+	ch <- 1
+	return <-ch
+}
+
+func Goroutine() int {
+	ch := make(chan int)
+	go func() {
+		ch <- 1
+	}()
+	return <-ch
+}
+
+func Started() int {
+	ch := make(chan int)
+	results := make(chan int)
+	go worker(results)
+	ch <- 1
+	return <-ch
+}
+
+func Escapes() int {
+	ch := make(chan int)
+	worker(ch)
+	ch <- 1
+	return <-ch
+}
+
+func Select() int {
+	ch := make(chan int)
+	select {
+	case ch <- 1:
+	default:
+	}
+	return <-ch
+}
+
+func NeverReceived() {
+	ch := make(chan int)
+	ch <- 1
+}
+
+func Literal() {
+	f := func() int {
+		ch := make(chan int)
+		if len(ch) == 0 {
+			ch <- 1 // want "send on unbuffered channel ch blocks forever"
+		}
+		return <-ch
+	}
+	f()
+}
+
+func worker(ch chan int) {}
+`
+	issues := ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectUnbufferedSends(filename, node, fset, tt.SeverityWarning)
+	}, "queue.gno", src)
+	require.Len(t, issues, 3)
+	require.Len(t, issues[0].RelatedLocations, 1)
+	assert.Equal(t, 6, issues[0].RelatedLocations[0].Start.Line)
+}
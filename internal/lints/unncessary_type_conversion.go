@@ -3,29 +3,24 @@ package lints
 import (
 	"fmt"
 	"go/ast"
-	"go/importer"
 	"go/token"
 	"go/types"
 
 	tt "github.com/gnolang/tlin/internal/types"
 )
 
+// DetectUnnecessaryConversions reports conversions of an expression to its
+// own type, chains of conversions coming back to the type of their operand
+// without loss (e.g. string([]byte(s))), and type assertions of an
+// interface to its own type. Suggestions replace them with their operand.
 func DetectUnnecessaryConversions(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
-	info := &types.Info{
-		Types: make(map[ast.Expr]types.TypeAndValue),
-		Uses:  make(map[*ast.Ident]types.Object),
-		Defs:  make(map[*ast.Ident]types.Object),
-	}
-
-	conf := types.Config{Importer: importer.Default()}
-	//! DO NOT CHECK ERROR HERE.
-	//! error check may broke the lint formatting process.
-	conf.Check("", fset, []*ast.File{node}, info)
+	info := packageTypeInfo(filename, node, fset)
 
 	var issues []tt.Issue
 	varDecls := make(map[*types.Var]ast.Node)
+	commaOk := make(map[*ast.TypeAssertExpr]bool)
 
-	// First pass: collect variable declarations
+	// First pass: collect variable declarations and comma-ok assertions
 	ast.Inspect(node, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.ValueSpec:
@@ -36,6 +31,9 @@ func DetectUnnecessaryConversions(filename string, node *ast.File, fset *token.F
 					}
 				}
 			}
+			if len(node.Names) == 2 && len(node.Values) == 1 {
+				markCommaOk(node.Values[0], commaOk)
+			}
 		case *ast.AssignStmt:
 			for _, lhs := range node.Lhs {
 				if id, ok := lhs.(*ast.Ident); ok {
@@ -46,80 +44,93 @@ func DetectUnnecessaryConversions(filename string, node *ast.File, fset *token.F
 					}
 				}
 			}
+			if len(node.Lhs) == 2 && len(node.Rhs) == 1 {
+				markCommaOk(node.Rhs[0], commaOk)
+			}
 		}
 		return true
 	})
 
-	// Second pass: check for unnecessary conversions
-	ast.Inspect(node, func(n ast.Node) bool {
-		call, ok := n.(*ast.CallExpr)
-		if !ok || len(call.Args) != 1 {
-			return true
-		}
-
-		ft, ok := info.Types[call.Fun]
-		if !ok || !ft.IsType() {
-			return true
-		}
-
-		at, ok := info.Types[call.Args[0]]
-		if !ok {
-			return true
-		}
+	report := func(n ast.Node, message string, operand ast.Expr, memo string) {
+		issues = append(issues, tt.Issue{
+			Rule:       "unnecessary-type-conversion",
+			Filename:   filename,
+			Start:      fset.Position(n.Pos()),
+			End:        fset.Position(n.End()),
+			Message:    message,
+			Suggestion: types.ExprString(operand),
+			Note:       memo,
+			Severity:   severity,
+		})
+	}
 
-		if types.Identical(ft.Type, at.Type) && !isUntypedValue(call.Args[0], info) {
-			var memo, suggestion string
+	// Second pass: check for unnecessary conversions and assertions
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.TypeAssertExpr:
+			if n.Type == nil || commaOk[n] {
+				return true
+			}
+			xt, ok := info.Types[n.X]
+			at, ok2 := info.Types[n.Type]
+			if ok && ok2 && types.Identical(xt.Type, at.Type) {
+				report(n, "unnecessary type assertion", n.X, fmt.Sprintf(
+					"'%s' is already of type '%s'. the assertion only panics when it is nil.",
+					types.ExprString(n.X), xt.Type.String(),
+				))
+			}
 
-			// find parent node and retrieve the entire assignment statement
-			var parent ast.Node
-			ast.Inspect(node, func(node ast.Node) bool {
-				if node == n {
-					return false
-				}
-				if containsNode(node, n) {
-					parent = node
-					return false
-				}
+		case *ast.CallExpr:
+			target, arg, ok := conversion(n, info)
+			if !ok {
+				return true
+			}
+			at, ok := info.Types[arg]
+			if !ok {
 				return true
-			})
-
-			if assignStmt, ok := parent.(*ast.AssignStmt); ok {
-				if len(assignStmt.Lhs) > 0 {
-					lhs := types.ExprString(assignStmt.Lhs[0])
-					rhs := types.ExprString(call.Args[0])
-					if len(assignStmt.Lhs) == len(assignStmt.Rhs) {
-						// normal assignment
-						suggestion = fmt.Sprintf("%s = %s", lhs, rhs)
-					} else {
-						// short variable declaration
-						suggestion = fmt.Sprintf("%s := %s", lhs, rhs)
-					}
-				}
 			}
 
-			if id, ok := call.Args[0].(*ast.Ident); ok {
-				if obj, ok := info.Uses[id].(*types.Var); ok {
-					if _, exists := varDecls[obj]; exists {
-						declType := obj.Type().String()
-						memo = fmt.Sprintf(
-							"the variable '%s' is declared as type '%s'. this type conversion appears unnecessary.",
-							id.Name, declType,
-						)
+			if types.Identical(target, at.Type) && !isUntypedValue(arg, info) {
+				var memo string
+				if alias := aliasTypeName(n.Fun, info); alias != nil {
+					memo = fmt.Sprintf(
+						"'%s' is an alias of '%s'. converting to it does not change the type.",
+						alias.Name(), types.Unalias(alias.Type()).String(),
+					)
+				} else if id, ok := arg.(*ast.Ident); ok {
+					if obj, ok := info.Uses[id].(*types.Var); ok {
+						if _, exists := varDecls[obj]; exists {
+							declType := obj.Type().String()
+							memo = fmt.Sprintf(
+								"the variable '%s' is declared as type '%s'. this type conversion appears unnecessary.",
+								id.Name, declType,
+							)
+						}
 					}
 				}
+				report(n, "unnecessary type conversion", arg, memo)
+				return true
 			}
 
-			issues = append(issues, tt.Issue{
-				Rule:       "unnecessary-type-conversion",
-				Filename:   filename,
-				Start:      fset.Position(call.Pos()),
-				End:        fset.Position(call.End()),
-				Message:    "unnecessary type conversion",
-				Suggestion: suggestion,
-				Note:       memo,
-				Confidence: 0.8,
-				Severity:   severity,
-			})
+			// T(U(x)) where x is of type T and U holds every value of T.
+			inner, ok := unparen(arg).(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			middle, operand, ok := conversion(inner, info)
+			if !ok {
+				return true
+			}
+			ot, ok := info.Types[operand]
+			if !ok || isUntypedValue(operand, info) || !types.Identical(target, ot.Type) || !losslessRoundTrip(target, middle) {
+				return true
+			}
+			report(n, "unnecessary type conversion", operand, fmt.Sprintf(
+				"'%s' is already of type '%s'. converting it to '%s' and back gives the same value.",
+				types.ExprString(operand), target.String(), middle.String(),
+			))
+			// the inner conversion is part of this issue.
+			return false
 		}
 
 		return true
@@ -128,6 +139,83 @@ func DetectUnnecessaryConversions(filename string, node *ast.File, fset *token.F
 	return issues, nil
 }
 
+func markCommaOk(expr ast.Expr, commaOk map[*ast.TypeAssertExpr]bool) {
+	if ta, ok := unparen(expr).(*ast.TypeAssertExpr); ok {
+		commaOk[ta] = true
+	}
+}
+
+// conversion returns the target type and the operand of call if it is a
+// type conversion.
+func conversion(call *ast.CallExpr, info *types.Info) (types.Type, ast.Expr, bool) {
+	if len(call.Args) != 1 {
+		return nil, nil, false
+	}
+	ft, ok := info.Types[call.Fun]
+	if !ok || !ft.IsType() {
+		return nil, nil, false
+	}
+	return ft.Type, call.Args[0], true
+}
+
+// losslessRoundTrip reports whether converting a value of type t to middle
+// and back to t always gives the original value.
+func losslessRoundTrip(t, middle types.Type) bool {
+	tb, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	if tb.Info()&types.IsString != 0 {
+		// strings go through byte slices unchanged. Rune slices are not
+		// lossless: invalid UTF-8 comes back as utf8.RuneError.
+		slice, ok := middle.Underlying().(*types.Slice)
+		if !ok {
+			return false
+		}
+		elem, ok := slice.Elem().Underlying().(*types.Basic)
+		return ok && elem.Kind() == types.Byte
+	}
+
+	mb, ok := middle.Underlying().(*types.Basic)
+	if !ok || tb.Info()&types.IsInteger == 0 || mb.Info()&types.IsInteger == 0 {
+		return false
+	}
+	// integer conversions keep the low bits, so the value comes back
+	// unchanged when middle is at least as wide, whatever its sign.
+	return gnoSizes.Sizeof(mb) >= gnoSizes.Sizeof(tb)
+}
+
+// gnoSizes are the sizes of the basic types in Gno, where int is 64 bits wide.
+var gnoSizes = types.SizesFor("gc", "amd64")
+
+// aliasTypeName returns the type name used by a conversion if it is an alias.
+// Instantiated generic types (e.g. List[int]) are never aliases here.
+func aliasTypeName(fun ast.Expr, info *types.Info) *types.TypeName {
+	for {
+		paren, ok := fun.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		fun = paren.X
+	}
+
+	var id *ast.Ident
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+
+	tn, ok := info.Uses[id].(*types.TypeName)
+	if !ok || !tn.IsAlias() {
+		return nil
+	}
+	return tn
+}
+
 // ref: https://github.com/mdempsky/unconvert/blob/master/unconvert.go#L570
 func isUntypedValue(n ast.Expr, info *types.Info) (res bool) {
 	switch n := n.(type) {
@@ -203,16 +291,3 @@ func asBuiltin(n ast.Expr, info *types.Info) (*types.Builtin, bool) {
 	b, ok := obj.(*types.Builtin)
 	return b, ok
 }
-
-// containsNode checks if parent containsNode child node
-func containsNode(parent, child ast.Node) bool {
-	found := false
-	ast.Inspect(parent, func(n ast.Node) bool {
-		if n == child {
-			found = true
-			return false
-		}
-		return true
-	})
-	return found
-}
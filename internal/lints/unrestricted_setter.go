@@ -0,0 +1,193 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/analysis/cfg"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+const unrestrictedSetterNote = "exported realm functions can be called by anyone. check the caller (e.g. std.AssertOriginCall or an ownable check) before modifying the realm state."
+
+// DetectUnrestrictedSetters reports exported functions of realm packages
+// that can write package-level state on some path of their control flow
+// graph without calling one of guards first.
+//
+// A guard is written either as `pkg.Func`, matching calls through that
+// package name, or as a bare name matching any function or method of that
+// name. Functions of the file calling a guard are guards themselves.
+func DetectUnrestrictedSetters(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity, guards []string) ([]tt.Issue, error) {
+	if !IsRealmFile(filename) {
+		return nil, nil
+	}
+
+	info := packageTypeInfo(filename, node, fset)
+	isGuard := guardMatcher(node, guards)
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() || fn.Body == nil {
+			continue
+		}
+
+		write, name := firstUncheckedWrite(cfg.FromFunc(fn), info, isGuard)
+		if write == nil {
+			continue
+		}
+		issues = append(issues, tt.Issue{
+			Rule:     "unrestricted-setter",
+			Category: "security",
+			Filename: filename,
+			Start:    fset.Position(fn.Pos()),
+			End:      fset.Position(fn.Name.End()),
+			Message:  fmt.Sprintf("%s modifies the realm state without checking its caller", fn.Name.Name),
+			Note:     unrestrictedSetterNote,
+			Severity: severity,
+			Actions:  []tt.Action{tt.ActionNeedsReview},
+			RelatedLocations: []tt.Location{{
+				Start:   fset.Position(write.Pos()),
+				End:     fset.Position(write.End()),
+				Message: fmt.Sprintf("%s is written here without a caller check", name),
+			}},
+		})
+	}
+
+	return issues, nil
+}
+
+// guardMatcher returns a function reporting whether a call is a guard,
+// including calls to the functions of node that call a guard.
+func guardMatcher(node *ast.File, guards []string) func(*ast.CallExpr) bool {
+	qualified := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, g := range guards {
+		if strings.Contains(g, ".") {
+			qualified[g] = true
+		} else {
+			names[g] = true
+		}
+	}
+
+	isGuard := func(call *ast.CallExpr) bool {
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			return names[fun.Name]
+		case *ast.SelectorExpr:
+			if x, ok := fun.X.(*ast.Ident); ok && qualified[x.Name+"."+fun.Sel.Name] {
+				return true
+			}
+			return names[fun.Sel.Name]
+		}
+		return false
+	}
+
+	// a helper may call another helper, so repeat until no function is added.
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range node.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Body == nil || names[fn.Name.Name] {
+				continue
+			}
+			inspectCalls(fn.Body, func(call *ast.CallExpr) {
+				if !names[fn.Name.Name] && isGuard(call) {
+					names[fn.Name.Name] = true
+					changed = true
+				}
+			})
+		}
+	}
+
+	return isGuard
+}
+
+// firstUncheckedWrite returns the first write to a package-level variable
+// that can be reached from the entry of g without going through a guard,
+// along with the name of the variable.
+func firstUncheckedWrite(g *cfg.CFG, info *types.Info, isGuard func(*ast.CallExpr) bool) (ast.Node, string) {
+	guarded := func(s ast.Stmt) bool {
+		found := false
+		for _, n := range stmtHeader(s) {
+			inspectCalls(n, func(call *ast.CallExpr) {
+				if isGuard(call) {
+					found = true
+				}
+			})
+		}
+		return found
+	}
+
+	var reached []ast.Stmt
+	seen := map[ast.Stmt]bool{g.Entry: true}
+	queue := []ast.Stmt{g.Entry}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, succ := range g.Succs(cur) {
+			if seen[succ] || succ == g.Exit || guarded(succ) {
+				continue
+			}
+			seen[succ] = true
+			reached = append(reached, succ)
+			queue = append(queue, succ)
+		}
+	}
+	sort.Slice(reached, func(i, j int) bool { return reached[i].Pos() < reached[j].Pos() })
+
+	for _, s := range reached {
+		for _, n := range stmtHeader(s) {
+			if write, name := packageVarWrite(n, info); write != nil {
+				return write, name
+			}
+		}
+	}
+	return nil, ""
+}
+
+// packageVarWrite returns the first assignment, increment or delete call in
+// n modifying a package-level variable, and the name of the variable.
+func packageVarWrite(n ast.Node, info *types.Info) (ast.Node, string) {
+	var write ast.Node
+	var name string
+	check := func(node ast.Node, target ast.Expr) {
+		if write != nil {
+			return
+		}
+		if id := rootIdent(target); id != nil && isPackageVar(id, info) {
+			write, name = node, id.Name
+		}
+	}
+
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				for _, lhs := range n.Lhs {
+					check(n, lhs)
+				}
+			}
+		case *ast.IncDecStmt:
+			check(n, n.X)
+		case *ast.CallExpr:
+			if id, ok := n.Fun.(*ast.Ident); ok && id.Name == "delete" && len(n.Args) > 0 {
+				check(n, n.Args[0])
+			}
+		}
+		return write == nil
+	})
+	return write, name
+}
+
+// isPackageVar reports whether id refers to a package-level variable.
+func isPackageVar(id *ast.Ident, info *types.Info) bool {
+	v, ok := info.Uses[id].(*types.Var)
+	return ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+}
@@ -0,0 +1,99 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectUnrestrictedSetters(t *testing.T) {
+	t.Parallel()
+	guards := []string{"std.AssertOriginCall", "AssertCallerIsOwner"}
+
+	issues := ruletest.RunFiles(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectUnrestrictedSetters(filename, node, fset, tt.SeverityWarning, guards)
+	}, map[string]string{
+		"gno.mod": "module gno.land/r/demo/config\n",
+		"config.gno": `package config
+
+import (
+	"std"
+
+	"gno.land/p/demo/ownable"
+)
+
+var (
+	owner  = ownable.New()
+	fee    int
+	admins = map[string]bool{}
+)
+
+func SetFee(f int) { // want "SetFee modifies the realm state without checking its caller"
+	fee = f
+}
+
+func SetFeeChecked(f int) {
+	std.AssertOriginCall()
+	fee = f
+}
+
+func RemoveAdmin(name string) {
+	owner.AssertCallerIsOwner()
+	delete(admins, name)
+}
+
+func AddAdmin(name string, force bool) { // want "AddAdmin modifies the realm state"
+	if !force {
+		assertAdmin()
+	}
+	admins[name] = true
+}
+
+func Reset() {
+	assertAdmin()
+	fee = 0
+}
+
+func Fee() int {
+	local := fee
+	local++
+	return local
+}
+
+func assertAdmin() {
+	owner.AssertCallerIsOwner()
+}
+`,
+	})
+
+	require.Len(t, issues, 2)
+	for _, issue := range issues {
+		assert.Equal(t, "security", issue.Category)
+		require.Len(t, issue.RelatedLocations, 1)
+	}
+	assert.Equal(t, 16, issues[0].RelatedLocations[0].Start.Line)
+	assert.Equal(t, "fee is written here without a caller check", issues[0].RelatedLocations[0].Message)
+	assert.Equal(t, 33, issues[1].RelatedLocations[0].Start.Line)
+}
+
+func TestDetectUnrestrictedSettersOutsideRealm(t *testing.T) {
+	t.Parallel()
+	ruletest.RunFiles(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectUnrestrictedSetters(filename, node, fset, tt.SeverityWarning, nil)
+	}, map[string]string{
+		"gno.mod": "module gno.land/p/demo/config\n",
+		"config.gno": `package config
+
+var fee int
+
+func SetFee(f int) {
+	fee = f
+}
+`,
+	})
+}
@@ -0,0 +1,98 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectUnusedStructFields reports unexported fields of the struct types
+// declared in node that are never read nor written in their package.
+//
+// Fields tagged for json or amino encoding are skipped, as they may only be
+// used through reflection, and so are embedded and blank fields.
+func DetectUnusedStructFields(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+
+	// fields of instantiated generic types are told apart from the declared
+	// ones, which their Origin is.
+	used := make(map[*types.Var]bool)
+	for _, obj := range info.Uses {
+		if v, ok := obj.(*types.Var); ok && v.IsField() {
+			used[v.Origin()] = true
+		}
+	}
+	// unkeyed composite literals set every field of their type.
+	for expr, tv := range info.Types {
+		lit, ok := expr.(*ast.CompositeLit)
+		if !ok || len(lit.Elts) == 0 || isKeyed(lit) || tv.Type == nil {
+			continue
+		}
+		if st, ok := tv.Type.Underlying().(*types.Struct); ok {
+			for i := 0; i < st.NumFields(); i++ {
+				used[st.Field(i).Origin()] = true
+			}
+		}
+	}
+
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			if len(field.Names) == 0 || isEncodedField(field) {
+				continue
+			}
+			for _, name := range field.Names {
+				if name.Name == "_" || name.IsExported() {
+					continue
+				}
+				v, ok := info.Defs[name].(*types.Var)
+				if !ok || used[v] {
+					continue
+				}
+				issues = append(issues, tt.Issue{
+					Rule:     "unused-struct-field",
+					Filename: filename,
+					Start:    fset.Position(name.Pos()),
+					End:      fset.Position(name.End()),
+					Message:  fmt.Sprintf("field %s of %s is never used", name.Name, spec.Name.Name),
+					Note:     "consider removing it. fields of realm state are persisted, so unused ones still cost storage.",
+					Severity: severity,
+				})
+			}
+		}
+		return true
+	})
+
+	return issues, nil
+}
+
+func isKeyed(lit *ast.CompositeLit) bool {
+	_, ok := lit.Elts[0].(*ast.KeyValueExpr)
+	return ok
+}
+
+func isEncodedField(field *ast.Field) bool {
+	if field.Tag == nil {
+		return false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return false
+	}
+	_, json := reflect.StructTag(tag).Lookup("json")
+	_, amino := reflect.StructTag(tag).Lookup("amino")
+	return json || amino
+}
@@ -0,0 +1,71 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectUnusedStructFields(t *testing.T) {
+	t.Parallel()
+	ruletest.RunFiles(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectUnusedStructFields(filename, node, fset, tt.SeverityWarning)
+	}, map[string]string{
+		"state.gno": `package bank
+
+type account struct {
+	owner   string
+	balance int
+	memo    string // want "field memo of account is never used"
+	Label   string
+	nonce   int ` + "`json:\"nonce\"`" + `
+	_       int
+	version int
+}
+
+type point struct {
+	x, y int
+	z    int // want "field z of point is never used"
+}
+
+var origin = point{x: 0}
+`,
+		"bank.gno": `package bank
+
+type pair struct{ a, b int }
+
+var p = pair{1, 2}
+
+func Deposit(a *account, amount int) {
+	a.balance += amount
+}
+
+func newAccount(owner string) account {
+	return account{owner: owner, version: 1}
+}
+
+func Y(pt point) int { return pt.y }
+`,
+		"generic.gno": `package bank
+
+type cache[K comparable, V any] struct {
+	entries map[K]V
+	limit   int
+	hits    int // want "field hits of cache is never used"
+}
+
+type tuple[T any] struct{ first, second T }
+
+var pairs = tuple[int]{1, 2}
+
+func newCache[K comparable, V any]() *cache[K, V] {
+	return &cache[K, V]{limit: 10}
+}
+
+func (c *cache[K, V]) Get(k K) V { return c.entries[k] }
+`,
+	})
+}
@@ -0,0 +1,110 @@
+package lints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DetectUnusedParameters reports parameters of unexported functions and
+// methods that are never referenced in their body, suggesting to rename them
+// to _.
+//
+// The signature of a function used as a value anywhere in its package, or of
+// a method required by an interface used in the package, may be imposed by
+// its uses, so such functions are skipped.
+func DetectUnusedParameters(filename string, node *ast.File, fset *token.FileSet, severity tt.Severity) ([]tt.Issue, error) {
+	info := packageTypeInfo(filename, node, fset)
+	interfaces := usedInterfaces(info)
+
+	used := make(map[types.Object]bool)
+	for _, obj := range info.Uses {
+		used[obj] = true
+	}
+	asValue := funcValues(info)
+
+	var issues []tt.Issue
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || fn.Name.IsExported() {
+			continue
+		}
+		obj, ok := info.Defs[fn.Name].(*types.Func)
+		if !ok || asValue[obj] {
+			continue
+		}
+		if named := receiverNamed(obj); named != nil && implementsUsedInterface(named, obj, interfaces) {
+			continue
+		}
+
+		for _, field := range fn.Type.Params.List {
+			for _, name := range field.Names {
+				param := info.Defs[name]
+				if name.Name == "_" || param == nil || used[param] {
+					continue
+				}
+				issues = append(issues, tt.Issue{
+					Rule:       "unused-parameter",
+					Filename:   filename,
+					Start:      fset.Position(name.Pos()),
+					End:        fset.Position(name.End()),
+					Message:    fmt.Sprintf("parameter %s of %s is never used", name.Name, fn.Name.Name),
+					Suggestion: "_",
+					Note:       "remove the parameter, or rename it to _ if the signature must stay as is.",
+					Severity:   severity,
+				})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// funcValues returns the functions and methods of the package referenced
+// other than by calling them, e.g. passed as a callback.
+func funcValues(info *types.Info) map[*types.Func]bool {
+	called := make(map[*ast.Ident]bool)
+	for expr := range info.Types {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		switch fun := unparen(call.Fun).(type) {
+		case *ast.Ident:
+			called[fun] = true
+		case *ast.SelectorExpr:
+			called[fun.Sel] = true
+		case *ast.IndexExpr: // generic instantiation
+			if id, ok := fun.X.(*ast.Ident); ok {
+				called[id] = true
+			}
+		}
+	}
+
+	values := make(map[*types.Func]bool)
+	for id, obj := range info.Uses {
+		if fn, ok := obj.(*types.Func); ok && !called[id] {
+			values[fn] = true
+		}
+	}
+	return values
+}
+
+// implementsUsedInterface reports whether named, or a pointer to it,
+// implements one of interfaces through method.
+func implementsUsedInterface(named *types.Named, method *types.Func, interfaces []*types.Interface) bool {
+	for _, iface := range interfaces {
+		if !types.Implements(named, iface) && !types.Implements(types.NewPointer(named), iface) {
+			continue
+		}
+		for i := 0; i < iface.NumMethods(); i++ {
+			if iface.Method(i).Name() == method.Name() {
+				return true
+			}
+		}
+	}
+	return false
+}
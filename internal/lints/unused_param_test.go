@@ -0,0 +1,54 @@
+package lints
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
+)
+
+func TestDetectUnusedParameters(t *testing.T) {
+	t.Parallel()
+	src := `package bank
+
+type store struct{ balances map[string]int }
+
+type getter interface {
+	get(key string, fallback int) int
+}
+
+func (s *store) get(key string, fallback int) int {
+	return s.balances[key]
+}
+
+func (s *store) set(key string, amount int, memo string) { // want "parameter memo of set is never used"
+	s.balances[key] = amount
+}
+
+func fee(amount int, rate int) int { // want "parameter rate of fee is never used"
+	return amount / 100
+}
+
+func onTransfer(from, to string, amount int) {
+}
+
+func register(cb func(string, string, int)) {} // want "parameter cb of register is never used"
+
+func init() {
+	register(onTransfer)
+	var _ getter = &store{}
+}
+
+func Transfer(from, to string, amount int) {
+}
+
+func withBlank(_ int, used int) int {
+	return used
+}
+`
+	ruletest.Run(t, func(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+		return DetectUnusedParameters(filename, node, fset, tt.SeverityWarning)
+	}, "bank.go", src)
+}
@@ -5,9 +5,11 @@ import (
 	"go/ast"
 	"go/token"
 	"strings"
+
+	"github.com/gnolang/tlin/internal/directive"
 )
 
-const nolintPrefix = "//nolint"
+const nolintName = "nolint"
 
 // Manager manages nolint scopes and checks if a position is nolinted.
 type Manager struct {
@@ -52,27 +54,12 @@ func parseComment(
 	packageLine int,
 ) (scope, error) {
 	var scope scope
-	text := comment.Text
-
-	if !strings.HasPrefix(text, nolintPrefix) {
-		return scope, fmt.Errorf("invalid nolint comment")
+	rest, ok, err := directive.Parse(comment.Text, nolintName)
+	if err != nil {
+		return scope, err
 	}
-
-	prefixLen := len(nolintPrefix)
-	rest := text[prefixLen:]
-
-	if len(rest) > 0 && rest[0] != ':' {
-		return scope, fmt.Errorf("invalid nolint comment format")
-	}
-
-	if len(rest) > 0 && rest[0] == ':' {
-		rest = strings.TrimPrefix(rest, ":")
-		rest = strings.TrimSpace(rest)
-		if rest == "" {
-			return scope, fmt.Errorf("invalid nolint comment: no rules specified after colon")
-		}
-	} else if len(rest) > 0 {
-		return scope, fmt.Errorf("invalid nolint comment: expected colon after 'nolint'")
+	if !ok {
+		return scope, fmt.Errorf("invalid nolint comment")
 	}
 
 	scope.rules = parseIgnoreRuleNames(rest)
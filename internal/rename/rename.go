@@ -0,0 +1,337 @@
+// Package rename renames a package-level symbol and every reference to it
+// across a source tree.
+package rename
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/fixer"
+)
+
+// Options describes a rename request.
+type Options struct {
+	// Package is the name of the package declaring the symbol.
+	Package string
+	// From is the current name of the package-level symbol.
+	From string
+	// To is the new name.
+	To string
+}
+
+// Result holds the rewritten content of every file touched by a rename.
+type Result struct {
+	Files      map[string][]byte
+	References int
+}
+
+// Apply writes the result to disk. Either every file is updated or none.
+func (r *Result) Apply() error {
+	return fixer.WriteFilesAtomically(r.Files)
+}
+
+// pkg is the set of files of one package in one directory.
+type pkg struct {
+	dir   string
+	name  string
+	files []*ast.File
+	paths []string
+}
+
+// Plan computes the edits needed to rename opts.From to opts.To in the
+// package named opts.Package found below paths.
+//
+// References from other packages are resolved through their imports: an
+// import whose path ends with the package name is assumed to refer to it.
+// The declaring package is type-checked before and after the rename, and the
+// rename is refused if it introduces new errors.
+func Plan(paths []string, opts Options) (*Result, error) {
+	if !token.IsIdentifier(opts.To) {
+		return nil, fmt.Errorf("invalid identifier %q", opts.To)
+	}
+	if opts.From == opts.To {
+		return nil, errors.New("old and new names are identical")
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := load(fset, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *pkg
+	for _, p := range pkgs {
+		if p.name != opts.Package || !declares(p, opts.From) {
+			continue
+		}
+		if target != nil {
+			return nil, fmt.Errorf("%s.%s is declared in both %s and %s", opts.Package, opts.From, target.dir, p.dir)
+		}
+		target = p
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%s.%s not found", opts.Package, opts.From)
+	}
+
+	edits := make(map[string][]fixer.Edit)
+
+	errsBefore, err := renameInPackage(fset, target, opts, edits)
+	if err != nil {
+		return nil, err
+	}
+
+	external := 0
+	for _, p := range pkgs {
+		if p == target {
+			continue
+		}
+		external += renameQualified(fset, p, opts, edits)
+	}
+	if external > 0 && ast.IsExported(opts.From) && !ast.IsExported(opts.To) {
+		return nil, fmt.Errorf("%s is referenced from other packages and cannot be unexported", opts.From)
+	}
+
+	result := &Result{Files: make(map[string][]byte)}
+	for filename, fileEdits := range edits {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := fixer.ApplyEdits(content, fileEdits)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), filename, updated, parser.ParseComments); err != nil {
+			return nil, fmt.Errorf("rename produces invalid code in %s: %w", filename, err)
+		}
+		result.Files[filename] = updated
+		result.References += len(fileEdits)
+	}
+
+	errsAfter, err := checkRewritten(target, result.Files)
+	if err != nil {
+		return nil, err
+	}
+	if errsAfter > errsBefore {
+		return nil, fmt.Errorf("rename introduces type errors in package %s", target.name)
+	}
+
+	return result, nil
+}
+
+// renameInPackage adds the edits for the declaring package and returns the
+// number of type errors reported for it before the rename.
+func renameInPackage(fset *token.FileSet, p *pkg, opts Options, edits map[string][]fixer.Edit) (int, error) {
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	errCount := 0
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error:    func(error) { errCount++ },
+	}
+	checked, _ := conf.Check(p.name, fset, p.files, info)
+
+	obj := checked.Scope().Lookup(opts.From)
+	if obj == nil {
+		return 0, fmt.Errorf("%s.%s not found", opts.Package, opts.From)
+	}
+	if checked.Scope().Lookup(opts.To) != nil {
+		return 0, fmt.Errorf("%s is already declared in package %s", opts.To, p.name)
+	}
+
+	add := func(id *ast.Ident) error {
+		scope := checked.Scope().Innermost(id.Pos())
+		if scope == nil {
+			scope = checked.Scope()
+		}
+		if _, conflict := scope.LookupParent(opts.To, id.Pos()); conflict != nil {
+			pos := fset.Position(id.Pos())
+			return fmt.Errorf("%s: renamed reference would be shadowed by %s", pos, opts.To)
+		}
+		addEdit(fset, edits, id, opts.To)
+		return nil
+	}
+
+	for id, o := range info.Defs {
+		if o == obj {
+			if err := add(id); err != nil {
+				return 0, err
+			}
+		}
+	}
+	for id, o := range info.Uses {
+		if o == obj {
+			if err := add(id); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return errCount, nil
+}
+
+// renameQualified adds the edits for references of the form pkg.From in a
+// package importing the declaring package.
+func renameQualified(fset *token.FileSet, p *pkg, opts Options, edits map[string][]fixer.Edit) int {
+	count := 0
+	for _, file := range p.files {
+		local := importName(file, opts.Package)
+		if local == "" {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != opts.From {
+				return true
+			}
+			// a resolved identifier is a local declaration shadowing the import.
+			if x, ok := sel.X.(*ast.Ident); ok && x.Name == local && x.Obj == nil {
+				addEdit(fset, edits, sel.Sel, opts.To)
+				count++
+			}
+			return true
+		})
+	}
+	return count
+}
+
+// importName returns the name under which file imports the package named
+// pkgName, or "" if it does not.
+func importName(file *ast.File, pkgName string) string {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path.Base(importPath) != pkgName {
+			continue
+		}
+		if spec.Name == nil {
+			return pkgName
+		}
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			continue
+		}
+		return spec.Name.Name
+	}
+	return ""
+}
+
+func addEdit(fset *token.FileSet, edits map[string][]fixer.Edit, id *ast.Ident, to string) {
+	pos := fset.Position(id.Pos())
+	edits[pos.Filename] = append(edits[pos.Filename], fixer.Edit{
+		Start:   pos.Offset,
+		End:     pos.Offset + len(id.Name),
+		NewText: to,
+	})
+}
+
+// checkRewritten type-checks the declaring package with the rewritten
+// files and returns the number of errors.
+func checkRewritten(p *pkg, rewritten map[string][]byte) (int, error) {
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(p.paths))
+	for _, filename := range p.paths {
+		var src interface{}
+		if content, ok := rewritten[filename]; ok {
+			src = content
+		}
+		file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+		if err != nil {
+			return 0, err
+		}
+		files = append(files, file)
+	}
+
+	errCount := 0
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error:    func(error) { errCount++ },
+	}
+	conf.Check(p.name, fset, files, nil)
+	return errCount, nil
+}
+
+func declares(p *pkg, name string) bool {
+	for _, file := range p.files {
+		if file.Scope.Lookup(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// load parses the .gno and .go files below paths, grouped by directory and package name.
+func load(fset *token.FileSet, paths []string) ([]*pkg, error) {
+	byKey := make(map[string]*pkg)
+	addFile := func(filename string) error {
+		if ext := filepath.Ext(filename); ext != ".gno" && ext != ".go" {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Dir(filename)
+		key := dir + "\x00" + file.Name.Name
+		p, ok := byKey[key]
+		if !ok {
+			p = &pkg{dir: dir, name: file.Name.Name}
+			byKey[key] = p
+		}
+		p.files = append(p.files, file)
+		p.paths = append(p.paths, filename)
+		return nil
+	}
+
+	for _, root := range paths {
+		root = strings.TrimSuffix(root, "/...")
+		if root == "..." {
+			root = "."
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if err := addFile(root); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			return addFile(path)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pkgs := make([]*pkg, 0, len(byKey))
+	for _, p := range byKey {
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].dir != pkgs[j].dir {
+			return pkgs[i].dir < pkgs[j].dir
+		}
+		return pkgs[i].name < pkgs[j].name
+	})
+	return pkgs, nil
+}
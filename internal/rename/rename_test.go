@@ -0,0 +1,100 @@
+package rename
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanAcrossPackages(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"p/coins/coins.gno": `package coins
+
+func OldName() int { return 1 }
+
+func Twice() int { return OldName() * 2 }
+`,
+		"p/coins/other.gno": `package coins
+
+var x = OldName()
+`,
+		"r/bank/bank.gno": `package bank
+
+import "gno.land/p/demo/coins"
+
+func Use() int {
+	return coins.OldName()
+}
+
+func Shadowed(coins struct{ OldName int }) int {
+	return coins.OldName
+}
+`,
+	})
+
+	result, err := Plan([]string{root + "/..."}, Options{Package: "coins", From: "OldName", To: "NewName"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.References)
+
+	require.NoError(t, result.Apply())
+
+	coins, err := os.ReadFile(filepath.Join(root, "p/coins/coins.gno"))
+	require.NoError(t, err)
+	assert.Contains(t, string(coins), "func NewName() int")
+	assert.Contains(t, string(coins), "return NewName() * 2")
+
+	bank, err := os.ReadFile(filepath.Join(root, "r/bank/bank.gno"))
+	require.NoError(t, err)
+	assert.Contains(t, string(bank), "return coins.NewName()")
+	assert.Contains(t, string(bank), "return coins.OldName\n", "shadowed import must not be renamed")
+}
+
+func TestPlanRejectsUnsafeRenames(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"p/coins/coins.gno": `package coins
+
+var Existing = 1
+
+func OldName() int { return 1 }
+
+func Local() int {
+	shadow := 2
+	return OldName() + shadow
+}
+`,
+		"r/bank/bank.gno": `package bank
+
+import "gno.land/p/demo/coins"
+
+var v = coins.OldName()
+`,
+	})
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"missing symbol", Options{Package: "coins", From: "Missing", To: "Other"}, "not found"},
+		{"name conflict", Options{Package: "coins", From: "OldName", To: "Existing"}, "already declared"},
+		{"shadowed reference", Options{Package: "coins", From: "OldName", To: "shadow"}, "shadowed"},
+		{"unexporting", Options{Package: "coins", From: "OldName", To: "oldName"}, "cannot be unexported"},
+		{"invalid identifier", Options{Package: "coins", From: "OldName", To: "1abc"}, "invalid identifier"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Plan([]string{root}, tc.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
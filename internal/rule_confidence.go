@@ -0,0 +1,51 @@
+package internal
+
+import tt "github.com/gnolang/tlin/internal/types"
+
+// ruleConfidence holds the default confidence of the suggestions made by each rule.
+// Rules missing from this map never have their suggestions applied by the fixer
+// unless a confidence is set in the configuration.
+var ruleConfidence = map[string]float64{
+	"append-result-ignored":       0.9,
+	"const-error-declaration":     1.0,
+	"deprecated":                  0.5,
+	"discarded-error":             0.7,
+	"duplicate-case-body":         0.9,
+	"early-continue":              0.8,
+	"early-return-opportunity":    0.8,
+	"emit-format":                 1.0,
+	"exhaustive-switch":           0.8,
+	"map-range-order":             0.8,
+	"nested-if":                   0.8,
+	"receiver-name":               0.8,
+	"shadowed-import":             0.8,
+	"sentinel-error":              0.8,
+	"shadowed-predeclared":        0.8,
+	"simplify-slice-range":        0.9,
+	"unnecessary-type-conversion": 0.8,
+	"unused-parameter":            0.9,
+}
+
+// RuleConfidence returns the default confidence of the suggestions of a rule.
+func RuleConfidence(rule string) float64 {
+	return ruleConfidence[rule]
+}
+
+// confidenceOf returns the confidence of the suggestions of a rule,
+// preferring the value set in the configuration.
+func (e *Engine) confidenceOf(rule string) float64 {
+	if c, ok := e.confidence[rule]; ok {
+		return c
+	}
+	return RuleConfidence(rule)
+}
+
+// attachConfidence sets the confidence of the rule on issues carrying a suggestion.
+func (e *Engine) attachConfidence(issues []tt.Issue, rule string) {
+	c := e.confidenceOf(rule)
+	for i := range issues {
+		if issues[i].Suggestion != "" {
+			issues[i].Confidence = c
+		}
+	}
+}
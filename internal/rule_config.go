@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigurableRule is implemented by lint rules that accept options
+// through the `data` field of their entry in the configuration file.
+type ConfigurableRule interface {
+	LintRule
+
+	// SetData applies the rule specific options.
+	SetData(data interface{}) error
+
+	// Parameters describes the options accepted by SetData, with the current
+	// values of the rule as defaults.
+	Parameters() []RuleParameter
+}
+
+// RuleParameter describes an option of a configurable rule. Type is a JSON
+// schema type: integer, boolean, string, array or object.
+type RuleParameter struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Items       string      `json:"items,omitempty"` // type of the elements of an array
+	Enum        []string    `json:"enum,omitempty"`
+	Minimum     *int        `json:"minimum,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+// RuleDescription describes a rule and its parameters with their defaults.
+type RuleDescription struct {
+	Name       string          `json:"name"`
+	Severity   string          `json:"severity"`
+	Parameters []RuleParameter `json:"parameters,omitempty"`
+}
+
+// DescribeRules returns the description of every rule with its default
+// settings, sorted by name.
+func DescribeRules() []RuleDescription {
+	descriptions := make([]RuleDescription, 0, len(allRuleConstructors))
+	for name, newRule := range allRuleConstructors {
+		rule := newRule()
+		d := RuleDescription{Name: name, Severity: rule.Severity().String()}
+		if cr, ok := rule.(ConfigurableRule); ok {
+			d.Parameters = cr.Parameters()
+		}
+		descriptions = append(descriptions, d)
+	}
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].Name < descriptions[j].Name
+	})
+	return descriptions
+}
+
+// listDefault returns values as the default of a parameter, leaving the
+// default unset when there are none.
+func listDefault(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// decodeRuleData converts the loosely typed `data` value of a configuration
+// entry into the options struct of a rule.
+func decodeRuleData(data interface{}, out interface{}) error {
+	raw, err := yaml.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("invalid rule data: %w", err)
+	}
+	if err := yaml.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("invalid rule data: %w", err)
+	}
+	return nil
+}
+
+// validateRuleData checks the `data` of a configuration entry against the
+// parameters of its rule: unknown names, types, enums and minimums.
+func validateRuleData(data interface{}, params []RuleParameter) error {
+	var normalized interface{}
+	if err := decodeRuleData(data, &normalized); err != nil {
+		return err
+	}
+	if normalized == nil {
+		return nil
+	}
+	values, ok := normalized.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid rule data: expected a mapping of parameters")
+	}
+
+	byName := make(map[string]RuleParameter, len(params))
+	names := make([]string, 0, len(params))
+	for _, p := range params {
+		byName[p.Name] = p
+		names = append(names, p.Name)
+	}
+	for key, value := range values {
+		p, ok := byName[key]
+		if !ok {
+			if len(names) == 0 {
+				return fmt.Errorf("unknown parameter %q: the rule takes none", key)
+			}
+			return fmt.Errorf("unknown parameter %q, expected one of %s", key, strings.Join(names, ", "))
+		}
+		if err := p.validate(value); err != nil {
+			return fmt.Errorf("parameter %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (p RuleParameter) validate(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	if err := checkType(p.Type, value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case int:
+		if p.Minimum != nil && v < *p.Minimum {
+			return fmt.Errorf("must be at least %d, got %d", *p.Minimum, v)
+		}
+	case string:
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, v) {
+			return fmt.Errorf("must be one of %s, got %q", strings.Join(p.Enum, ", "), v)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := checkType(p.Items, item); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkType reports whether value, decoded from YAML, has the JSON schema
+// type typ. Any scalar is accepted as a string, as YAML decodes it into one.
+func checkType(typ string, value interface{}) error {
+	ok := true
+	switch typ {
+	case "integer":
+		_, ok = value.(int)
+	case "boolean":
+		_, ok = value.(bool)
+	case "string":
+		switch value.(type) {
+		case []interface{}, map[string]interface{}:
+			ok = false
+		}
+	case "array":
+		_, ok = value.([]interface{})
+	case "object":
+		_, ok = value.(map[string]interface{})
+	}
+	if !ok {
+		return fmt.Errorf("expected %s, got %v", typ, value)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package internal
+
+import tt "github.com/gnolang/tlin/internal/types"
+
+const ruleDocBaseURL = "https://github.com/gnolang/tlin/blob/main/docs/rfc/"
+
+// ruleDocs maps rule names to the document describing their rationale and examples.
+var ruleDocs = map[string]string{
+	"const-error-declaration":     "const-error-decl.md",
+	"defer-issues":                "defer-checks.md",
+	"early-return-opportunity":    "early-return.md",
+	"emit-format":                 "emit-event.md",
+	"gno-mod-tidy":                "mod-tidy.md",
+	"repeated-regex-compilation":  "repeated-regex-compilation.md",
+	"simplify-slice-range":        "unnecesary-slice-length.md",
+	"unnecessary-type-conversion": "unnecessary-type-conversion.md",
+	"useless-break":               "unnecessary-break.md",
+}
+
+// RuleDocURL returns the documentation URL of a rule, or "" if it has none.
+func RuleDocURL(rule string) string {
+	doc, ok := ruleDocs[rule]
+	if !ok {
+		return ""
+	}
+	return ruleDocBaseURL + doc
+}
+
+// attachDocURL sets the documentation URL of the rule on issues that have none.
+func attachDocURL(issues []tt.Issue, rule string) {
+	url := RuleDocURL(rule)
+	if url == "" {
+		return
+	}
+	for i := range issues {
+		if issues[i].DocURL == "" {
+			issues[i].DocURL = url
+		}
+	}
+}
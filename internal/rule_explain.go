@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/gnolang/tlin/internal/lints"
+	"github.com/gnolang/tlin/internal/nolint"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// ExplainableRule is implemented by rules able to tell why they do not report a line.
+type ExplainableRule interface {
+	LintRule
+	// Explain returns the preconditions that failed for the code starting on line.
+	Explain(filename string, node *ast.File, fset *token.FileSet, line int) ([]string, error)
+}
+
+// Explanation is the result of Engine.WhyNot.
+type Explanation struct {
+	// Issues holds the issues of the rule starting on the line, if it is reported after all.
+	Issues  []tt.Issue
+	Reasons []string
+}
+
+// WhyNot runs a single rule on filename and explains why it does not report
+// anything on the given line.
+func (e *Engine) WhyNot(ruleName, filename string, line int) (*Explanation, error) {
+	rule := e.findRule(ruleName)
+	if rule == nil {
+		newRuleCstr, ok := allRuleConstructors[ruleName]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule %q", ruleName)
+		}
+		rule = newRuleCstr()
+	}
+	explainable, ok := rule.(ExplainableRule)
+	if !ok {
+		return nil, fmt.Errorf("rule %s cannot explain its results", ruleName)
+	}
+
+	tempFile, err := e.prepareFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer e.cleanupTemp(tempFile)
+
+	node, fset, err := lints.ParseFile(tempFile, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	var reasons []string
+	if e.ignoredRules[ruleName] || rule.Severity() == tt.SeverityOff {
+		reasons = append(reasons, "the rule is disabled in the configuration")
+	}
+	if fr, ok := rule.(FileScopedRule); ok && !fr.AppliesTo(filename) {
+		reasons = append(reasons, "the rule does not apply to this file")
+	}
+
+	issues, err := rule.Check(tempFile, node, fset)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := nolint.ParseComments(node, fset)
+	explanation := &Explanation{}
+	suppressed := false
+	for _, issue := range issues {
+		if issue.Start.Line != line {
+			continue
+		}
+		if mgr.IsNolint(token.Position{Filename: issue.Filename, Line: line}, issue.Rule) {
+			suppressed = true
+			continue
+		}
+		issue.Filename = filename
+		explanation.Issues = append(explanation.Issues, issue)
+	}
+
+	switch {
+	case suppressed:
+		reasons = append(reasons, "the issue is suppressed by a nolint comment")
+	case len(explanation.Issues) == 0:
+		failed, err := explainable.Explain(tempFile, node, fset, line)
+		if err != nil {
+			return nil, err
+		}
+		reasons = append(reasons, failed...)
+	}
+	explanation.Reasons = reasons
+	return explanation, nil
+}
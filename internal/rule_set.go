@@ -1,9 +1,14 @@
 package internal
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/gnolang/tlin/internal/checker"
 	"github.com/gnolang/tlin/internal/lints"
 	tt "github.com/gnolang/tlin/internal/types"
 )
@@ -27,18 +32,70 @@ type LintRule interface {
 	SetSeverity(tt.Severity)
 }
 
+// FileScopedRule is implemented by rules that only apply to some files,
+// e.g. to .gno sources but not to the _test.go harnesses next to them.
+type FileScopedRule interface {
+	// AppliesTo reports whether the rule should run on the given file.
+	// filename is the original name of the file, before any .gno to .go conversion.
+	AppliesTo(filename string) bool
+}
+
+// isGnoSource reports whether filename is a .gno file that is not a test.
+func isGnoSource(filename string) bool {
+	return strings.HasSuffix(filename, ".gno") &&
+		!strings.HasSuffix(filename, "_test.gno") &&
+		!strings.HasSuffix(filename, "_filetest.gno")
+}
+
+// isTestSource reports whether filename is a Go or Gno test file.
+func isTestSource(filename string) bool {
+	return strings.HasSuffix(filename, "_test.go") ||
+		strings.HasSuffix(filename, "_test.gno") ||
+		strings.HasSuffix(filename, "_filetest.gno")
+}
+
 type GolangciLintRule struct {
-	severity tt.Severity
+	severity   tt.Severity
+	linters    map[string]lints.LinterMapping
+	minVersion string
+	digest     string
+
+	// golangci-lint is checked once, before linting the first file.
+	health sync.Once
+	binary string
+	err    error
 }
 
 func NewGolangciLintRule() LintRule {
 	return &GolangciLintRule{
-		severity: tt.SeverityWarning,
+		severity:   tt.SeverityWarning,
+		minVersion: lints.MinGolangciLintVersion,
 	}
 }
 
+// Check runs golangci-lint on filename. When golangci-lint cannot run, the
+// rule is disabled and reports why once, on the first file.
 func (r *GolangciLintRule) Check(filename string, _ *ast.File, _ *token.FileSet) ([]tt.Issue, error) {
-	return lints.RunGolangciLint(filename, r.severity)
+	first := false
+	r.health.Do(func() {
+		r.binary, r.err = lints.CheckGolangciLint(r.minVersion, r.digest)
+		first = true
+	})
+	if r.err != nil {
+		if !first {
+			return nil, nil
+		}
+		return []tt.Issue{{
+			Rule:     r.Name(),
+			Filename: filename,
+			Start:    token.Position{Filename: filename, Line: 1, Column: 1},
+			End:      token.Position{Filename: filename, Line: 1, Column: 1},
+			Message:  fmt.Sprintf("golangci-lint checks are disabled: %s", r.err),
+			Note:     fmt.Sprintf("install golangci-lint %s or a later 1.x release, or set the severity of the golangci-lint rule to OFF.", r.minVersion),
+			Severity: tt.SeverityWarning,
+		}}, nil
+	}
+	return lints.RunGolangciLint(r.binary, filename, r.severity, r.linters)
 }
 
 func (r *GolangciLintRule) Name() string {
@@ -53,6 +110,50 @@ func (r *GolangciLintRule) SetSeverity(severity tt.Severity) {
 	r.severity = severity
 }
 
+// SetData accepts a `linters` table mapping the name of a golangci-lint
+// linter to the `severity` and `category` of its findings, the
+// `min-version` of golangci-lint and the `sha256` digest its binary must have.
+func (r *GolangciLintRule) SetData(data interface{}) error {
+	var opts struct {
+		Linters    map[string]lints.LinterMapping `yaml:"linters"`
+		MinVersion string                         `yaml:"min-version"`
+		SHA256     string                         `yaml:"sha256"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if opts.MinVersion != "" {
+		if err := lints.CheckVersionFormat(opts.MinVersion); err != nil {
+			return err
+		}
+		r.minVersion = opts.MinVersion
+	}
+	r.linters = opts.Linters
+	r.digest = opts.SHA256
+	return nil
+}
+
+func (r *GolangciLintRule) Parameters() []RuleParameter {
+	return []RuleParameter{
+		{
+			Name:        "linters",
+			Type:        "object",
+			Description: "Maps golangci-lint linters to the severity and category of their findings.",
+		},
+		{
+			Name:        "min-version",
+			Type:        "string",
+			Default:     r.minVersion,
+			Description: "Oldest golangci-lint version accepted.",
+		},
+		{
+			Name:        "sha256",
+			Type:        "string",
+			Description: "SHA-256 digest the golangci-lint binary must have.",
+		},
+	}
+}
+
 type SimplifySliceExprRule struct {
 	severity tt.Severity
 }
@@ -183,6 +284,58 @@ func (r *UselessBreakRule) SetSeverity(severity tt.Severity) {
 	r.severity = severity
 }
 
+type EarlyContinueRule struct {
+	severity tt.Severity
+}
+
+func NewEarlyContinueRule() LintRule {
+	return &EarlyContinueRule{
+		severity: tt.SeverityInfo,
+	}
+}
+
+func (r *EarlyContinueRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectEarlyContinueOpportunities(filename, node, fset, r.severity)
+}
+
+func (r *EarlyContinueRule) Name() string {
+	return "early-continue"
+}
+
+func (r *EarlyContinueRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *EarlyContinueRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type NestedIfRule struct {
+	severity tt.Severity
+}
+
+func NewNestedIfRule() LintRule {
+	return &NestedIfRule{
+		severity: tt.SeverityInfo,
+	}
+}
+
+func (r *NestedIfRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectNestedIfs(filename, node, fset, r.severity)
+}
+
+func (r *NestedIfRule) Name() string {
+	return "nested-if"
+}
+
+func (r *NestedIfRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *NestedIfRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
 type EarlyReturnOpportunityRule struct {
 	severity tt.Severity
 }
@@ -197,6 +350,10 @@ func (r *EarlyReturnOpportunityRule) Check(filename string, node *ast.File, fset
 	return lints.DetectEarlyReturnOpportunities(filename, node, fset, r.severity)
 }
 
+func (r *EarlyReturnOpportunityRule) Explain(filename string, node *ast.File, fset *token.FileSet, line int) ([]string, error) {
+	return lints.ExplainEarlyReturn(filename, node, fset, line)
+}
+
 func (r *EarlyReturnOpportunityRule) Name() string {
 	return "early-return-opportunity"
 }
@@ -261,6 +418,1494 @@ func (r *ConstErrorDeclarationRule) Severity() tt.Severity {
 	return r.severity
 }
 
+type SentinelErrorRule struct {
+	severity tt.Severity
+}
+
+func NewSentinelErrorRule() LintRule {
+	return &SentinelErrorRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *SentinelErrorRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectSentinelErrors(filename, node, fset, r.severity)
+}
+
+func (r *SentinelErrorRule) Name() string {
+	return "sentinel-error"
+}
+
+func (r *SentinelErrorRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+func (r *SentinelErrorRule) Severity() tt.Severity {
+	return r.severity
+}
+
+type AppendResultIgnoredRule struct {
+	severity tt.Severity
+}
+
+func NewAppendResultIgnoredRule() LintRule {
+	return &AppendResultIgnoredRule{
+		severity: tt.SeverityError,
+	}
+}
+
+func (r *AppendResultIgnoredRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectIgnoredAppendResult(filename, node, fset, r.severity)
+}
+
+func (r *AppendResultIgnoredRule) Name() string {
+	return "append-result-ignored"
+}
+
+func (r *AppendResultIgnoredRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *AppendResultIgnoredRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type NoFloatsInRealmRule struct {
+	severity tt.Severity
+	allow    []string
+}
+
+func NewNoFloatsInRealmRule() LintRule {
+	return &NoFloatsInRealmRule{
+		severity: tt.SeverityWarning,
+		// Render only formats output, so it is allowed to use floats by default.
+		allow: []string{"Render"},
+	}
+}
+
+func (r *NoFloatsInRealmRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectFloatsInRealm(filename, node, fset, r.severity, r.allow)
+}
+
+func (r *NoFloatsInRealmRule) Name() string {
+	return "no-floats-in-realm"
+}
+
+func (r *NoFloatsInRealmRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *NoFloatsInRealmRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+func (r *NoFloatsInRealmRule) AppliesTo(filename string) bool {
+	return isGnoSource(filename)
+}
+
+// SetData accepts an `allow` list of function names in which floats are permitted.
+func (r *NoFloatsInRealmRule) SetData(data interface{}) error {
+	var opts struct {
+		Allow []string `yaml:"allow"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if opts.Allow != nil {
+		r.allow = opts.Allow
+	}
+	return nil
+}
+
+func (r *NoFloatsInRealmRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "allow",
+		Type:        "array",
+		Items:       "string",
+		Default:     listDefault(r.allow),
+		Description: "Functions in which floats are permitted.",
+	}}
+}
+
+type UnboundedRecursionRule struct {
+	severity tt.Severity
+}
+
+func NewUnboundedRecursionRule() LintRule {
+	return &UnboundedRecursionRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *UnboundedRecursionRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectUnboundedRecursion(filename, node, fset, r.severity)
+}
+
+func (r *UnboundedRecursionRule) Name() string {
+	return "unbounded-recursion"
+}
+
+func (r *UnboundedRecursionRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *UnboundedRecursionRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+func (r *UnboundedRecursionRule) AppliesTo(filename string) bool {
+	return isGnoSource(filename)
+}
+
+type UnrestrictedSetterRule struct {
+	severity tt.Severity
+	guards   []string
+}
+
+func NewUnrestrictedSetterRule() LintRule {
+	return &UnrestrictedSetterRule{
+		severity: tt.SeverityWarning,
+		guards: []string{
+			"std.AssertOriginCall",
+			// checks of gno.land/p/demo/ownable.
+			"AssertCallerIsOwner",
+			"CallerIsOwner",
+		},
+	}
+}
+
+func (r *UnrestrictedSetterRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectUnrestrictedSetters(filename, node, fset, r.severity, r.guards)
+}
+
+func (r *UnrestrictedSetterRule) Name() string {
+	return "unrestricted-setter"
+}
+
+func (r *UnrestrictedSetterRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *UnrestrictedSetterRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+func (r *UnrestrictedSetterRule) AppliesTo(filename string) bool {
+	return isGnoSource(filename)
+}
+
+// SetData accepts a `guards` list of the calls checking the caller, written
+// as `pkg.Func` or as a bare function or method name. It replaces the defaults.
+func (r *UnrestrictedSetterRule) SetData(data interface{}) error {
+	var opts struct {
+		Guards []string `yaml:"guards"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if opts.Guards != nil {
+		r.guards = opts.Guards
+	}
+	return nil
+}
+
+func (r *UnrestrictedSetterRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "guards",
+		Type:        "array",
+		Items:       "string",
+		Default:     listDefault(r.guards),
+		Description: "Calls checking the caller, as pkg.Func or a bare function or method name.",
+	}}
+}
+
+type UnusedStructFieldRule struct {
+	severity tt.Severity
+}
+
+func NewUnusedStructFieldRule() LintRule {
+	return &UnusedStructFieldRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *UnusedStructFieldRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectUnusedStructFields(filename, node, fset, r.severity)
+}
+
+func (r *UnusedStructFieldRule) Name() string {
+	return "unused-struct-field"
+}
+
+func (r *UnusedStructFieldRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *UnusedStructFieldRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type StructTagRule struct {
+	severity tt.Severity
+	keys     []string
+	naming   string
+}
+
+func NewStructTagRule() LintRule {
+	return &StructTagRule{
+		severity: tt.SeverityWarning,
+		keys:     []string{"json", "amino"},
+	}
+}
+
+func (r *StructTagRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectStructTagIssues(filename, node, fset, r.severity, r.keys, r.naming)
+}
+
+func (r *StructTagRule) Name() string {
+	return "struct-tag"
+}
+
+func (r *StructTagRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *StructTagRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts the tag `keys` to check and the `naming` convention of
+// the names: camel, pascal, snake or kebab.
+func (r *StructTagRule) SetData(data interface{}) error {
+	var opts struct {
+		Keys   []string `yaml:"keys"`
+		Naming string   `yaml:"naming"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if opts.Naming != "" {
+		if _, ok := lints.TagNamingConventions[opts.Naming]; !ok {
+			return fmt.Errorf("unknown naming convention %q", opts.Naming)
+		}
+	}
+	if opts.Keys != nil {
+		r.keys = opts.Keys
+	}
+	r.naming = opts.Naming
+	return nil
+}
+
+func (r *StructTagRule) Parameters() []RuleParameter {
+	naming := make([]string, 0, len(lints.TagNamingConventions))
+	for name := range lints.TagNamingConventions {
+		naming = append(naming, name)
+	}
+	sort.Strings(naming)
+	return []RuleParameter{
+		{
+			Name:        "keys",
+			Type:        "array",
+			Items:       "string",
+			Default:     listDefault(r.keys),
+			Description: "Tag keys to check.",
+		},
+		{
+			Name:        "naming",
+			Type:        "string",
+			Enum:        naming,
+			Description: "Naming convention of the tag names.",
+		},
+	}
+}
+
+type ReceiverNameRule struct {
+	severity tt.Severity
+	banned   []string
+}
+
+func NewReceiverNameRule() LintRule {
+	return &ReceiverNameRule{
+		severity: tt.SeverityInfo,
+		banned:   []string{"this", "self"},
+	}
+}
+
+func (r *ReceiverNameRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectReceiverNames(filename, node, fset, r.severity, r.banned)
+}
+
+func (r *ReceiverNameRule) Name() string {
+	return "receiver-name"
+}
+
+func (r *ReceiverNameRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ReceiverNameRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts a `banned` list of receiver names, replacing this and self.
+func (r *ReceiverNameRule) SetData(data interface{}) error {
+	var opts struct {
+		Banned []string `yaml:"banned"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if opts.Banned != nil {
+		r.banned = opts.Banned
+	}
+	return nil
+}
+
+func (r *ReceiverNameRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "banned",
+		Type:        "array",
+		Items:       "string",
+		Default:     listDefault(r.banned),
+		Description: "Receiver names that are reported.",
+	}}
+}
+
+type FloatEqualityRule struct {
+	severity tt.Severity
+}
+
+func NewFloatEqualityRule() LintRule {
+	return &FloatEqualityRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *FloatEqualityRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectFloatEquality(filename, node, fset, r.severity)
+}
+
+func (r *FloatEqualityRule) Name() string {
+	return "float-equality"
+}
+
+func (r *FloatEqualityRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *FloatEqualityRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type UnusedParameterRule struct {
+	severity tt.Severity
+}
+
+func NewUnusedParameterRule() LintRule {
+	return &UnusedParameterRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *UnusedParameterRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectUnusedParameters(filename, node, fset, r.severity)
+}
+
+func (r *UnusedParameterRule) Name() string {
+	return "unused-parameter"
+}
+
+func (r *UnusedParameterRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *UnusedParameterRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type MapFormatComparisonRule struct {
+	severity tt.Severity
+}
+
+func NewMapFormatComparisonRule() LintRule {
+	return &MapFormatComparisonRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+// AppliesTo restricts the rule to test files, where formatted values are
+// compared with golden strings.
+func (r *MapFormatComparisonRule) AppliesTo(filename string) bool {
+	return isTestSource(filename)
+}
+
+func (r *MapFormatComparisonRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectMapFormatComparisons(filename, node, fset, r.severity)
+}
+
+func (r *MapFormatComparisonRule) Name() string {
+	return "map-format-comparison"
+}
+
+func (r *MapFormatComparisonRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *MapFormatComparisonRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type MisplacedTestFatalRule struct {
+	severity tt.Severity
+}
+
+func NewMisplacedTestFatalRule() LintRule {
+	return &MisplacedTestFatalRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *MisplacedTestFatalRule) AppliesTo(filename string) bool {
+	return isTestSource(filename)
+}
+
+func (r *MisplacedTestFatalRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectMisplacedTestFatal(filename, node, fset, r.severity)
+}
+
+func (r *MisplacedTestFatalRule) Name() string {
+	return "misplaced-test-fatal"
+}
+
+func (r *MisplacedTestFatalRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *MisplacedTestFatalRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type BusyWaitRule struct {
+	severity tt.Severity
+}
+
+func NewBusyWaitRule() LintRule {
+	return &BusyWaitRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *BusyWaitRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectBusyWaits(filename, node, fset, r.severity)
+}
+
+func (r *BusyWaitRule) Name() string {
+	return "busy-wait"
+}
+
+func (r *BusyWaitRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *BusyWaitRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type UnbufferedSendRule struct {
+	severity tt.Severity
+}
+
+func NewUnbufferedSendRule() LintRule {
+	return &UnbufferedSendRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *UnbufferedSendRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectUnbufferedSends(filename, node, fset, r.severity)
+}
+
+func (r *UnbufferedSendRule) Name() string {
+	return "unbuffered-send"
+}
+
+func (r *UnbufferedSendRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *UnbufferedSendRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type OffByOneRule struct {
+	severity tt.Severity
+}
+
+func NewOffByOneRule() LintRule {
+	return &OffByOneRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *OffByOneRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectOffByOne(filename, node, fset, r.severity)
+}
+
+func (r *OffByOneRule) Name() string {
+	return "off-by-one"
+}
+
+func (r *OffByOneRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *OffByOneRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type ConstantConditionRule struct {
+	severity tt.Severity
+}
+
+func NewConstantConditionRule() LintRule {
+	return &ConstantConditionRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *ConstantConditionRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectConstantConditions(filename, node, fset, r.severity)
+}
+
+func (r *ConstantConditionRule) Name() string {
+	return "constant-condition"
+}
+
+func (r *ConstantConditionRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ConstantConditionRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type ErrorStringMatchRule struct {
+	severity tt.Severity
+}
+
+func NewErrorStringMatchRule() LintRule {
+	return &ErrorStringMatchRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *ErrorStringMatchRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectErrorStringMatches(filename, node, fset, r.severity)
+}
+
+func (r *ErrorStringMatchRule) Name() string {
+	return "error-string-match"
+}
+
+func (r *ErrorStringMatchRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ErrorStringMatchRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type DiscardedErrorRule struct {
+	severity tt.Severity
+	wrapper  string
+}
+
+func NewDiscardedErrorRule() LintRule {
+	return &DiscardedErrorRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *DiscardedErrorRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectDiscardedErrors(filename, node, fset, r.severity, r.wrapper)
+}
+
+func (r *DiscardedErrorRule) Name() string {
+	return "discarded-error"
+}
+
+func (r *DiscardedErrorRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *DiscardedErrorRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts the `wrapper` function suggested in place of errors.New,
+// called like fmt.Errorf.
+func (r *DiscardedErrorRule) SetData(data interface{}) error {
+	var opts struct {
+		Wrapper string `yaml:"wrapper"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	r.wrapper = opts.Wrapper
+	return nil
+}
+
+func (r *DiscardedErrorRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "wrapper",
+		Type:        "string",
+		Description: "Function called like fmt.Errorf suggested to wrap errors, ufmt.Errorf in .gno files and fmt.Errorf in .go files by default.",
+	}}
+}
+
+type AVLTreeMisuseRule struct {
+	severity tt.Severity
+	model    lints.AVLModel
+}
+
+func NewAVLTreeMisuseRule() LintRule {
+	return &AVLTreeMisuseRule{
+		severity: tt.SeverityWarning,
+		model:    lints.DefaultAVLModel(),
+	}
+}
+
+func (r *AVLTreeMisuseRule) AppliesTo(filename string) bool {
+	return isGnoSource(filename)
+}
+
+func (r *AVLTreeMisuseRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectAVLTreeMisuse(filename, node, fset, r.severity, r.model)
+}
+
+func (r *AVLTreeMisuseRule) Name() string {
+	return "avl-tree-misuse"
+}
+
+func (r *AVLTreeMisuseRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *AVLTreeMisuseRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts the import `packages` of avl and `methods` describing the
+// methods of avl.Tree, which replace the default descriptions of the same name.
+func (r *AVLTreeMisuseRule) SetData(data interface{}) error {
+	var opts struct {
+		Packages []string                   `yaml:"packages"`
+		Methods  map[string]lints.AVLMethod `yaml:"methods"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if opts.Packages != nil {
+		r.model.Packages = opts.Packages
+	}
+	for name, method := range opts.Methods {
+		for _, i := range []*int{method.Found, method.Callback, method.Value} {
+			if i != nil && *i < 0 {
+				return fmt.Errorf("method %s: negative index %d", name, *i)
+			}
+		}
+		r.model.Methods[name] = method
+	}
+	return nil
+}
+
+func (r *AVLTreeMisuseRule) Parameters() []RuleParameter {
+	return []RuleParameter{
+		{
+			Name:        "packages",
+			Type:        "array",
+			Items:       "string",
+			Default:     listDefault(r.model.Packages),
+			Description: "Import paths of the avl package.",
+		},
+		{
+			Name:        "methods",
+			Type:        "object",
+			Description: "Methods of avl.Tree, by name, with the index of their found result, callback and stored value arguments, and whether they modify the tree.",
+		},
+	}
+}
+
+type SuspiciousAssignmentRule struct {
+	severity tt.Severity
+}
+
+func NewSuspiciousAssignmentRule() LintRule {
+	return &SuspiciousAssignmentRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *SuspiciousAssignmentRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectSuspiciousAssignments(filename, node, fset, r.severity)
+}
+
+func (r *SuspiciousAssignmentRule) Name() string {
+	return "suspicious-assignment"
+}
+
+func (r *SuspiciousAssignmentRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *SuspiciousAssignmentRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type UnboundedInputRule struct {
+	severity tt.Severity
+}
+
+func NewUnboundedInputRule() LintRule {
+	return &UnboundedInputRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *UnboundedInputRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectUnboundedInputs(filename, node, fset, r.severity)
+}
+
+func (r *UnboundedInputRule) Name() string {
+	return "unbounded-input"
+}
+
+func (r *UnboundedInputRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *UnboundedInputRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type MapRangeOrderRule struct {
+	severity tt.Severity
+	autofix  bool
+}
+
+func NewMapRangeOrderRule() LintRule {
+	return &MapRangeOrderRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+// AppliesTo excludes .gno files: gno iterates over maps in insertion order.
+func (r *MapRangeOrderRule) AppliesTo(filename string) bool {
+	return strings.HasSuffix(filename, ".go")
+}
+
+func (r *MapRangeOrderRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectMapRangeOrder(filename, node, fset, r.severity, r.autofix)
+}
+
+func (r *MapRangeOrderRule) Name() string {
+	return "map-range-order"
+}
+
+func (r *MapRangeOrderRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *MapRangeOrderRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts `autofix`, which enables the suggestions sorting the keys.
+// They are off by default since sorting costs an allocation and O(n log n).
+func (r *MapRangeOrderRule) SetData(data interface{}) error {
+	var opts struct {
+		Autofix bool `yaml:"autofix"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	r.autofix = opts.Autofix
+	return nil
+}
+
+func (r *MapRangeOrderRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "autofix",
+		Type:        "boolean",
+		Default:     false,
+		Description: "Suggest iterating over the sorted keys of the map, in files importing sort.",
+	}}
+}
+
+// ReentrancyRule is experimental and off by default.
+type ReentrancyRule struct {
+	severity tt.Severity
+	external []string
+}
+
+func NewReentrancyRule() LintRule {
+	return &ReentrancyRule{
+		severity: tt.SeverityOff,
+		external: []string{"gno.land/r/..."},
+	}
+}
+
+func (r *ReentrancyRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectReentrancy(filename, node, fset, r.severity, r.external)
+}
+
+func (r *ReentrancyRule) Name() string {
+	return "reentrancy"
+}
+
+func (r *ReentrancyRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ReentrancyRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+func (r *ReentrancyRule) AppliesTo(filename string) bool {
+	return isGnoSource(filename)
+}
+
+// SetData accepts an `external-calls` list of the packages whose calls are
+// external, written as an import path, a path ending with `/...`, or
+// `path.Func`. It replaces the defaults.
+func (r *ReentrancyRule) SetData(data interface{}) error {
+	var opts struct {
+		External []string `yaml:"external-calls"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if opts.External != nil {
+		r.external = opts.External
+	}
+	return nil
+}
+
+func (r *ReentrancyRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "external-calls",
+		Type:        "array",
+		Items:       "string",
+		Default:     listDefault(r.external),
+		Description: "Packages whose calls are external, as an import path, a path ending with /..., or path.Func.",
+	}}
+}
+
+// GasHintRule is experimental and off by default.
+type GasHintRule struct {
+	severity tt.Severity
+}
+
+func NewGasHintRule() LintRule {
+	return &GasHintRule{
+		severity: tt.SeverityOff,
+	}
+}
+
+func (r *GasHintRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectGasHints(filename, node, fset, r.severity)
+}
+
+func (r *GasHintRule) Name() string {
+	return "gas-hint"
+}
+
+func (r *GasHintRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *GasHintRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+func (r *GasHintRule) AppliesTo(filename string) bool {
+	return isGnoSource(filename)
+}
+
+type ShadowedErrRule struct {
+	severity tt.Severity
+}
+
+func NewShadowedErrRule() LintRule {
+	return &ShadowedErrRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *ShadowedErrRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectShadowedErr(filename, node, fset, r.severity)
+}
+
+func (r *ShadowedErrRule) Name() string {
+	return "shadowed-err"
+}
+
+func (r *ShadowedErrRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ShadowedErrRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type MixedReceiversRule struct {
+	severity tt.Severity
+}
+
+func NewMixedReceiversRule() LintRule {
+	return &MixedReceiversRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *MixedReceiversRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectMixedReceivers(filename, node, fset, r.severity)
+}
+
+func (r *MixedReceiversRule) Name() string {
+	return "mixed-receivers"
+}
+
+func (r *MixedReceiversRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *MixedReceiversRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type MagicNumberRule struct {
+	severity tt.Severity
+	allow    []string
+}
+
+func NewMagicNumberRule() LintRule {
+	return &MagicNumberRule{
+		severity: tt.SeverityInfo,
+	}
+}
+
+func (r *MagicNumberRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectMagicNumbers(filename, node, fset, r.severity, r.allow)
+}
+
+func (r *MagicNumberRule) Name() string {
+	return "magic-number"
+}
+
+func (r *MagicNumberRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *MagicNumberRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+func (r *MagicNumberRule) AppliesTo(filename string) bool {
+	return isGnoSource(filename)
+}
+
+// SetData accepts an `allow` list of numbers that are not reported, besides 0 and 1.
+func (r *MagicNumberRule) SetData(data interface{}) error {
+	var opts struct {
+		Allow []string `yaml:"allow"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	r.allow = opts.Allow
+	return nil
+}
+
+func (r *MagicNumberRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "allow",
+		Type:        "array",
+		Items:       "string",
+		Default:     listDefault(r.allow),
+		Description: "Numbers that are not reported, besides 0 and 1.",
+	}}
+}
+
+type TypeSwitchDefaultRule struct {
+	severity tt.Severity
+	sealed   []string
+}
+
+func NewTypeSwitchDefaultRule() LintRule {
+	return &TypeSwitchDefaultRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *TypeSwitchDefaultRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectMissingTypeSwitchDefault(filename, node, fset, r.severity, r.sealed)
+}
+
+func (r *TypeSwitchDefaultRule) Name() string {
+	return "type-switch-default"
+}
+
+func (r *TypeSwitchDefaultRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *TypeSwitchDefaultRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts a `sealed` list of interfaces, written as `pkg.Name`,
+// whose type switches need no default case.
+func (r *TypeSwitchDefaultRule) SetData(data interface{}) error {
+	var opts struct {
+		Sealed []string `yaml:"sealed"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	r.sealed = opts.Sealed
+	return nil
+}
+
+func (r *TypeSwitchDefaultRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "sealed",
+		Type:        "array",
+		Items:       "string",
+		Default:     listDefault(r.sealed),
+		Description: "Interfaces, as pkg.Name, whose type switches need no default case.",
+	}}
+}
+
+type ExhaustiveSwitchRule struct {
+	severity tt.Severity
+}
+
+func NewExhaustiveSwitchRule() LintRule {
+	return &ExhaustiveSwitchRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *ExhaustiveSwitchRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectNonExhaustiveSwitches(filename, node, fset, r.severity)
+}
+
+func (r *ExhaustiveSwitchRule) Name() string {
+	return "exhaustive-switch"
+}
+
+func (r *ExhaustiveSwitchRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ExhaustiveSwitchRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type DuplicateCaseBodyRule struct {
+	severity tt.Severity
+}
+
+func NewDuplicateCaseBodyRule() LintRule {
+	return &DuplicateCaseBodyRule{
+		severity: tt.SeverityInfo,
+	}
+}
+
+func (r *DuplicateCaseBodyRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectDuplicateCaseBodies(filename, node, fset, r.severity)
+}
+
+func (r *DuplicateCaseBodyRule) Name() string {
+	return "duplicate-case-body"
+}
+
+func (r *DuplicateCaseBodyRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *DuplicateCaseBodyRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type ShadowedPredeclaredRule struct {
+	severity tt.Severity
+}
+
+func NewShadowedPredeclaredRule() LintRule {
+	return &ShadowedPredeclaredRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *ShadowedPredeclaredRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectShadowedPredeclared(filename, node, fset, r.severity)
+}
+
+func (r *ShadowedPredeclaredRule) Name() string {
+	return "shadowed-predeclared"
+}
+
+func (r *ShadowedPredeclaredRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ShadowedPredeclaredRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+type ShadowedImportRule struct {
+	severity tt.Severity
+}
+
+func NewShadowedImportRule() LintRule {
+	return &ShadowedImportRule{
+		severity: tt.SeverityWarning,
+	}
+}
+
+func (r *ShadowedImportRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectShadowedImports(filename, node, fset, r.severity)
+}
+
+func (r *ShadowedImportRule) Name() string {
+	return "shadowed-import"
+}
+
+func (r *ShadowedImportRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ShadowedImportRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+const (
+	defaultMaxFunctionLines      = 80
+	defaultMaxFunctionStatements = 50
+	defaultMaxFileLines          = 1000
+)
+
+// lengthOverride replaces the limits of a length rule for the files below a path.
+// Limits left unset keep their default value.
+type lengthOverride struct {
+	Path          string `yaml:"path"`
+	MaxLines      *int   `yaml:"max-lines"`
+	MaxStatements *int   `yaml:"max-statements"`
+}
+
+type lengthOptions struct {
+	MaxLines      *int             `yaml:"max-lines"`
+	MaxStatements *int             `yaml:"max-statements"`
+	Overrides     []lengthOverride `yaml:"overrides"`
+}
+
+// minLimit is the smallest limit accepted by the length rules.
+var minLimit = 1
+
+var overridesParameter = RuleParameter{
+	Name:        "overrides",
+	Type:        "array",
+	Items:       "object",
+	Description: "Limits replacing the defaults for the files below a path, as path, max-lines and max-statements.",
+}
+
+// validate checks the overrides, whose fields are not described by the
+// parameters of the rules.
+func (o lengthOptions) validate() error {
+	for i, override := range o.Overrides {
+		if override.Path == "" {
+			return fmt.Errorf("override %d: missing path", i)
+		}
+		if override.MaxLines != nil && *override.MaxLines < minLimit {
+			return fmt.Errorf("override %s: max-lines must be at least %d, got %d", override.Path, minLimit, *override.MaxLines)
+		}
+		if override.MaxStatements != nil && *override.MaxStatements < minLimit {
+			return fmt.Errorf("override %s: max-statements must be at least %d, got %d", override.Path, minLimit, *override.MaxStatements)
+		}
+	}
+	return nil
+}
+
+type FunctionLengthRule struct {
+	severity  tt.Severity
+	limits    lints.FunctionLimits
+	overrides []lengthOverride
+}
+
+func NewFunctionLengthRule() LintRule {
+	return &FunctionLengthRule{
+		severity: tt.SeverityWarning,
+		limits: lints.FunctionLimits{
+			MaxLines:      defaultMaxFunctionLines,
+			MaxStatements: defaultMaxFunctionStatements,
+		},
+	}
+}
+
+func (r *FunctionLengthRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	limits := r.limits
+	for _, o := range r.overrides {
+		if !lints.MatchPath(filename, o.Path) {
+			continue
+		}
+		if o.MaxLines != nil {
+			limits.MaxLines = *o.MaxLines
+		}
+		if o.MaxStatements != nil {
+			limits.MaxStatements = *o.MaxStatements
+		}
+	}
+	return lints.DetectLongFunctions(filename, node, fset, r.severity, limits)
+}
+
+func (r *FunctionLengthRule) Name() string {
+	return "function-length"
+}
+
+func (r *FunctionLengthRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *FunctionLengthRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts `max-lines`, `max-statements` and per-path `overrides`.
+func (r *FunctionLengthRule) SetData(data interface{}) error {
+	var opts lengthOptions
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	if opts.MaxLines != nil {
+		r.limits.MaxLines = *opts.MaxLines
+	}
+	if opts.MaxStatements != nil {
+		r.limits.MaxStatements = *opts.MaxStatements
+	}
+	r.overrides = opts.Overrides
+	return nil
+}
+
+func (r *FunctionLengthRule) Parameters() []RuleParameter {
+	return []RuleParameter{
+		{
+			Name:        "max-lines",
+			Type:        "integer",
+			Minimum:     &minLimit,
+			Default:     r.limits.MaxLines,
+			Description: "Maximum number of lines of a function.",
+		},
+		{
+			Name:        "max-statements",
+			Type:        "integer",
+			Minimum:     &minLimit,
+			Default:     r.limits.MaxStatements,
+			Description: "Maximum number of statements of a function.",
+		},
+		overridesParameter,
+	}
+}
+
+type FileLengthRule struct {
+	severity  tt.Severity
+	maxLines  int
+	overrides []lengthOverride
+}
+
+func NewFileLengthRule() LintRule {
+	return &FileLengthRule{
+		severity: tt.SeverityWarning,
+		maxLines: defaultMaxFileLines,
+	}
+}
+
+func (r *FileLengthRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	maxLines := r.maxLines
+	for _, o := range r.overrides {
+		if lints.MatchPath(filename, o.Path) && o.MaxLines != nil {
+			maxLines = *o.MaxLines
+		}
+	}
+	return lints.DetectLongFile(filename, node, fset, r.severity, maxLines)
+}
+
+func (r *FileLengthRule) Name() string {
+	return "file-length"
+}
+
+func (r *FileLengthRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *FileLengthRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts `max-lines` and per-path `overrides`.
+func (r *FileLengthRule) SetData(data interface{}) error {
+	var opts lengthOptions
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	if opts.MaxLines != nil {
+		r.maxLines = *opts.MaxLines
+	}
+	r.overrides = opts.Overrides
+	return nil
+}
+
+func (r *FileLengthRule) Parameters() []RuleParameter {
+	return []RuleParameter{
+		{
+			Name:        "max-lines",
+			Type:        "integer",
+			Minimum:     &minLimit,
+			Default:     r.maxLines,
+			Description: "Maximum number of lines of a file.",
+		},
+		overridesParameter,
+	}
+}
+
+type ForbiddenImportRule struct {
+	severity tt.Severity
+	policies []lints.ImportPolicy
+}
+
+func NewForbiddenImportRule() LintRule {
+	return &ForbiddenImportRule{
+		severity: tt.SeverityError,
+	}
+}
+
+func (r *ForbiddenImportRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectForbiddenImports(filename, node, fset, r.severity, r.policies)
+}
+
+func (r *ForbiddenImportRule) Name() string {
+	return "forbidden-import"
+}
+
+func (r *ForbiddenImportRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *ForbiddenImportRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
+// SetData accepts the import `policies`, each with a `name`, the `paths` of
+// the directories it applies to and the `imports` it forbids.
+func (r *ForbiddenImportRule) SetData(data interface{}) error {
+	var opts struct {
+		Policies []lints.ImportPolicy `yaml:"policies"`
+	}
+	if err := decodeRuleData(data, &opts); err != nil {
+		return err
+	}
+	for i, p := range opts.Policies {
+		if len(p.Imports) == 0 {
+			return fmt.Errorf("policy %d: missing imports", i)
+		}
+	}
+	r.policies = opts.Policies
+	return nil
+}
+
+func (r *ForbiddenImportRule) Parameters() []RuleParameter {
+	return []RuleParameter{{
+		Name:        "policies",
+		Type:        "array",
+		Items:       "object",
+		Description: "Imports forbidden below some directories, as name, paths and imports.",
+	}}
+}
+
+// BannedCallRule reports calls registered through Engine.RegisterBannedCall.
+// It is not part of the default rule set.
+type BannedCallRule struct {
+	severity   tt.Severity
+	bans       *checker.BannedCallChecker
+	severities map[string]tt.Severity
+}
+
+func newBannedCallRule() *BannedCallRule {
+	return &BannedCallRule{
+		severity:   tt.SeverityError,
+		bans:       checker.NewBannedCallChecker(),
+		severities: make(map[string]tt.Severity),
+	}
+}
+
+func (r *BannedCallRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectBannedCalls(filename, node, fset, r.bans, r.severities)
+}
+
+func (r *BannedCallRule) Name() string {
+	return "banned-call"
+}
+
+func (r *BannedCallRule) Severity() tt.Severity {
+	return r.severity
+}
+
+// SetSeverity overrides the severity of every pattern registered so far.
+// Patterns registered afterwards keep their own severity.
+func (r *BannedCallRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+	for pattern := range r.severities {
+		r.severities[pattern] = severity
+	}
+}
+
+// DeprecatedFuncRule reports calls to functions registered through
+// Engine.RegisterDeprecatedFunc. It is not part of the default rule set.
+type DeprecatedFuncRule struct {
+	severity   tt.Severity
+	deprecated *checker.DeprecatedFuncChecker
+}
+
+func newDeprecatedFuncRule() *DeprecatedFuncRule {
+	return &DeprecatedFuncRule{
+		severity:   tt.SeverityWarning,
+		deprecated: checker.NewDeprecatedFuncChecker(),
+	}
+}
+
+func (r *DeprecatedFuncRule) Check(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	return lints.DetectDeprecatedCalls(filename, node, fset, r.severity, r.deprecated)
+}
+
+func (r *DeprecatedFuncRule) Name() string {
+	return "deprecated"
+}
+
+func (r *DeprecatedFuncRule) Severity() tt.Severity {
+	return r.severity
+}
+
+func (r *DeprecatedFuncRule) SetSeverity(severity tt.Severity) {
+	r.severity = severity
+}
+
 // -----------------------------------------------------------------------------
 // Regex related rules
 
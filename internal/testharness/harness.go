@@ -0,0 +1,198 @@
+// Package testharness runs the lint engine over testdata corpora and compares
+// the results against recorded golden outputs.
+//
+// Rule authors regenerate the golden files after an intended change with:
+//
+//	go test ./internal/testharness -run Golden -update
+//
+// The same command records a per-rule timing baseline for the local machine,
+// which later runs compare against when the -timing flag is given.
+package testharness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gnolang/tlin/internal"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// DefaultTimingThreshold is the slowdown factor tolerated before a rule is
+// considered to have regressed.
+const DefaultTimingThreshold = 5.0
+
+// minTimingSlack avoids reporting regressions for rules that are too fast
+// for the measurement to be meaningful.
+const minTimingSlack = 100 * time.Millisecond
+
+// Snapshot is the outcome of running the engine over a corpus.
+type Snapshot struct {
+	// Issues holds one line per issue, sorted, with paths relative to the corpus root.
+	Issues []string
+	// Timings holds the time spent in each rule.
+	Timings map[string]time.Duration
+}
+
+// Collect runs the engine over every .go and .gno file below root.
+func Collect(engine *internal.Engine, root string) (*Snapshot, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), "temp_") {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext == ".go" || ext == ".gno" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking corpus %s: %w", root, err)
+	}
+	sort.Strings(files)
+
+	snapshot := &Snapshot{}
+	for _, file := range files {
+		issues, err := engine.Run(file)
+		if err != nil {
+			return nil, fmt.Errorf("error running engine on %s: %w", file, err)
+		}
+		sortIssues(issues)
+		for _, issue := range issues {
+			snapshot.Issues = append(snapshot.Issues, formatIssue(root, file, issue))
+		}
+	}
+	snapshot.Timings = engine.RuleTimings()
+
+	return snapshot, nil
+}
+
+func sortIssues(issues []tt.Issue) {
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Start.Line != b.Start.Line {
+			return a.Start.Line < b.Start.Line
+		}
+		if a.Start.Column != b.Start.Column {
+			return a.Start.Column < b.Start.Column
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+}
+
+func formatIssue(root, file string, issue tt.Issue) string {
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		rel = file
+	}
+	message := strings.ReplaceAll(issue.Message, "\n", `\n`)
+	return fmt.Sprintf("%s:%d:%d: %s: %s", filepath.ToSlash(rel), issue.Start.Line, issue.Start.Column, issue.Rule, message)
+}
+
+// CompareIssues compares the snapshot issues with the golden file at path.
+// When update is true, the golden file is rewritten instead.
+func (s *Snapshot) CompareIssues(path string, update bool) error {
+	got := strings.Join(s.Issues, "\n") + "\n"
+	if update {
+		return os.WriteFile(path, []byte(got), 0o644)
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading golden file (run with -update to create it): %w", err)
+	}
+	if string(want) == got {
+		return nil
+	}
+
+	return fmt.Errorf("rule outputs changed (run with -update if intended):\n%s", diffLines(string(want), got))
+}
+
+// CompareTimings compares the snapshot timings with the baseline at path and
+// reports every rule that became slower than threshold times its baseline.
+// When update is true, the baseline is rewritten instead.
+func (s *Snapshot) CompareTimings(path string, threshold float64, update bool) error {
+	if update {
+		data, err := json.MarshalIndent(s.Timings, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, append(data, '\n'), 0o644)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// no baseline recorded yet, nothing to compare against.
+			return nil
+		}
+		return err
+	}
+
+	var baseline map[string]time.Duration
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return fmt.Errorf("error parsing timing baseline: %w", err)
+	}
+
+	var regressions []string
+	for rule, got := range s.Timings {
+		want, ok := baseline[rule]
+		if !ok {
+			continue
+		}
+		limit := time.Duration(float64(want) * threshold)
+		if limit < want+minTimingSlack {
+			limit = want + minTimingSlack
+		}
+		if got > limit {
+			regressions = append(regressions, fmt.Sprintf("%s: %s (baseline %s)", rule, got, want))
+		}
+	}
+	if len(regressions) == 0 {
+		return nil
+	}
+
+	sort.Strings(regressions)
+	return fmt.Errorf("rule timings regressed beyond %.1fx:\n%s", threshold, strings.Join(regressions, "\n"))
+}
+
+// diffLines returns the lines only present in want (prefixed with "-")
+// and the lines only present in got (prefixed with "+").
+func diffLines(want, got string) string {
+	wantSet := make(map[string]int)
+	for _, line := range strings.Split(want, "\n") {
+		wantSet[line]++
+	}
+	gotSet := make(map[string]int)
+	for _, line := range strings.Split(got, "\n") {
+		gotSet[line]++
+	}
+
+	var diff []string
+	for _, line := range strings.Split(want, "\n") {
+		if gotSet[line] > 0 {
+			gotSet[line]--
+			continue
+		}
+		diff = append(diff, "- "+line)
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if wantSet[line] > 0 {
+			wantSet[line]--
+			continue
+		}
+		diff = append(diff, "+ "+line)
+	}
+	return strings.Join(diff, "\n")
+}
@@ -0,0 +1,78 @@
+package testharness
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gnolang/tlin/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	update = flag.Bool("update", false, "update golden files")
+	timing = flag.Bool("timing", false, "compare rule timings against the local baseline")
+)
+
+// rules that depend on the local toolchain are left out so the
+// golden output is reproducible across machines.
+var environmentDependentRules = []string{
+	"golangci-lint",
+	"repeated-regex-compilation",
+}
+
+func TestGolden(t *testing.T) {
+	corpus, err := filepath.Abs(filepath.Join("..", "..", "testdata"))
+	require.NoError(t, err)
+
+	engine, err := internal.NewEngine(corpus, nil, nil)
+	require.NoError(t, err)
+	for _, rule := range environmentDependentRules {
+		engine.IgnoreRule(rule)
+	}
+
+	snapshot, err := Collect(engine, corpus)
+	require.NoError(t, err)
+
+	assert.NoError(t, snapshot.CompareIssues(filepath.Join("testdata", "issues.golden"), *update))
+
+	// timings depend on the machine, so the baseline is only kept locally.
+	if *update || *timing {
+		assert.NoError(t, snapshot.CompareTimings(filepath.Join("testdata", "timings.json"), DefaultTimingThreshold, *update))
+	}
+}
+
+func TestCompareIssuesReportsDiff(t *testing.T) {
+	t.Parallel()
+	golden := filepath.Join(t.TempDir(), "issues.golden")
+	require.NoError(t, os.WriteFile(golden, []byte("a.gno:1:1: rule: old\nb.gno:2:1: rule: same\n"), 0o644))
+
+	snapshot := &Snapshot{Issues: []string{"a.gno:1:1: rule: new", "b.gno:2:1: rule: same"}}
+	err := snapshot.CompareIssues(golden, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "- a.gno:1:1: rule: old")
+	assert.Contains(t, err.Error(), "+ a.gno:1:1: rule: new")
+	assert.NotContains(t, err.Error(), "b.gno")
+
+	require.NoError(t, snapshot.CompareIssues(golden, true))
+	assert.NoError(t, snapshot.CompareIssues(golden, false))
+}
+
+func TestCompareTimingsThreshold(t *testing.T) {
+	t.Parallel()
+	baseline := filepath.Join(t.TempDir(), "timings.json")
+
+	recorded := &Snapshot{Timings: map[string]time.Duration{"slow-rule": time.Second}}
+	require.NoError(t, recorded.CompareTimings(baseline, 2, true))
+
+	stable := &Snapshot{Timings: map[string]time.Duration{"slow-rule": 1500 * time.Millisecond}}
+	assert.NoError(t, stable.CompareTimings(baseline, 2, false))
+
+	regressed := &Snapshot{Timings: map[string]time.Duration{"slow-rule": 3 * time.Second}}
+	err := regressed.CompareTimings(baseline, 2, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "slow-rule")
+}
@@ -19,6 +19,121 @@ type Issue struct {
 	End        token.Position `json:"end"`
 	Confidence float64        `json:"confidence"` // 0.0 to 1.0
 	Severity   Severity       `json:"severity"`
+
+	// RelatedLocations holds secondary positions that help explaining the issue,
+	// such as the declaration of a symbol referenced by the message.
+	RelatedLocations []Location `json:"related_locations,omitempty"`
+
+	// DocURL links to the documentation of the rule, if any.
+	DocURL string `json:"doc_url,omitempty"`
+
+	// Cost estimates the execution cost of the flagged code, if known.
+	Cost CostCategory `json:"cost,omitempty"`
+
+	// FileHash identifies the content of the file the issue was found in.
+	// It is set in JSON reports so that fixes are not applied to a changed file.
+	FileHash string `json:"file_hash,omitempty"`
+
+	// Fix is the edit applying the suggestion, set in JSON reports so that
+	// tools can preview or apply it without running tlin.
+	Fix *FixEdit `json:"fix,omitempty"`
+
+	// Package is the path of the gno package of the file, set when linting a workspace.
+	Package string `json:"package,omitempty"`
+
+	// ConfigRule is the name the reporting rule is registered and configured
+	// under, set by the engine when it differs from Rule.
+	ConfigRule string `json:"config_rule,omitempty"`
+
+	// Actions tells tools, such as review bots, what can be done with the
+	// issue. Rules add the ones they know of, and the engine and the JSON
+	// report the others.
+	Actions []Action `json:"actions,omitempty"`
+}
+
+// ConfigName returns the name of the rule that reported the issue in the
+// configuration, which fix policies and severities are keyed by.
+func (i *Issue) ConfigName() string {
+	if i.ConfigRule != "" {
+		return i.ConfigRule
+	}
+	return i.Rule
+}
+
+// AddAction adds a to the actions of the issue, unless it already has it.
+func (i *Issue) AddAction(a Action) {
+	if !i.HasAction(a) {
+		i.Actions = append(i.Actions, a)
+	}
+}
+
+// HasAction reports whether a is one of the actions of the issue.
+func (i *Issue) HasAction(a Action) bool {
+	for _, action := range i.Actions {
+		if action == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Action is something a tool can do with an issue without reading it, such
+// as applying its fix or asking for a review.
+type Action string
+
+const (
+	// ActionAutofixable marks issues whose fix is verified and confident
+	// enough to be applied by -fix.
+	ActionAutofixable Action = "autofixable"
+	// ActionSuppressible marks issues a nolint comment can silence.
+	ActionSuppressible Action = "suppressible"
+	// ActionNeedsReview marks issues a person has to decide about, such as
+	// security findings and suggestions that cannot be applied as they are.
+	ActionNeedsReview Action = "needs-human-review"
+)
+
+// FixEdit replaces the bytes in [Start, End) of a file with NewText.
+// Offsets are counted in the file as stored, BOM and CRLF line endings included.
+type FixEdit struct {
+	Filename   string  `json:"filename"`
+	NewText    string  `json:"new_text"`
+	Start      int     `json:"start"`
+	End        int     `json:"end"`
+	Confidence float64 `json:"confidence"`
+	// Verified tells whether the file still parses once the edit is applied.
+	Verified bool `json:"verified"`
+}
+
+// CostCategory is a rough estimate of how the execution cost (gas, in Gno)
+// of a construct grows.
+type CostCategory string
+
+const (
+	CostUnknown   CostCategory = ""
+	CostQuadratic CostCategory = "quadratic" // grows with the square of the iterations
+	CostUnbounded CostCategory = "unbounded" // grows with data or input that has no fixed bound
+)
+
+// Location represents a secondary position attached to an issue.
+type Location struct {
+	Filename string         `json:"filename"`
+	Message  string         `json:"message"`
+	Start    token.Position `json:"start"`
+	End      token.Position `json:"end"`
+}
+
+func (l Location) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Filename string                  `json:"filename"`
+		Message  string                  `json:"message"`
+		Start    PositionWithoutFilename `json:"start"`
+		End      PositionWithoutFilename `json:"end"`
+	}{
+		Filename: l.Filename,
+		Message:  l.Message,
+		Start:    PositionWithoutFilename{Offset: l.Start.Offset, Line: l.Start.Line, Column: l.Start.Column},
+		End:      PositionWithoutFilename{Offset: l.End.Offset, Line: l.End.Line, Column: l.End.Column},
+	})
 }
 
 func (i Issue) String() string {
@@ -44,6 +159,15 @@ type IssueWithoutFilename struct {
 	End        PositionWithoutFilename `json:"end"`
 	Confidence float64                 `json:"confidence"`
 	Severity   Severity                `json:"severity"`
+
+	RelatedLocations []Location   `json:"related_locations,omitempty"`
+	DocURL           string       `json:"doc_url,omitempty"`
+	Cost             CostCategory `json:"cost,omitempty"`
+	FileHash         string       `json:"file_hash,omitempty"`
+	Fix              *FixEdit     `json:"fix,omitempty"`
+	Package          string       `json:"package,omitempty"`
+	ConfigRule       string       `json:"config_rule,omitempty"`
+	Actions          []Action     `json:"actions,omitempty"`
 }
 
 func (i *Issue) MarshalJSON() ([]byte, error) {
@@ -57,6 +181,15 @@ func (i *Issue) MarshalJSON() ([]byte, error) {
 		End:        PositionWithoutFilename{Offset: i.End.Offset, Line: i.End.Line, Column: i.End.Column},
 		Confidence: i.Confidence,
 		Severity:   i.Severity,
+
+		RelatedLocations: i.RelatedLocations,
+		DocURL:           i.DocURL,
+		Cost:             i.Cost,
+		FileHash:         i.FileHash,
+		Fix:              i.Fix,
+		Package:          i.Package,
+		ConfigRule:       i.ConfigRule,
+		Actions:          i.Actions,
 	})
 }
 
@@ -126,5 +259,8 @@ func (s *Severity) UnmarshalYAML(unmarshal func(interface{}) error) error {
 // Rule represents an individual rule with an ID and severity.
 type ConfigRule struct {
 	Severity Severity    `yaml:"severity"`
-	Data     interface{} `yaml:"data"` // Data can be anything
+	Data     interface{} `yaml:"data"`    // Data can be anything
+	Fixable  *bool       `yaml:"fixable"` // nil means the rule's fixes may be applied
+	// nil means the rule's default confidence is used
+	Confidence *float64 `yaml:"confidence"`
 }
@@ -0,0 +1,122 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// factsVersion changes whenever Facts or the way they are collected change,
+// so that facts stored by another version of tlin are not used.
+const factsVersion = "3"
+
+// CacheDir returns the directory tlin keeps its cache in: $TLIN_CACHE when
+// set, the tlin directory of the user cache directory otherwise.
+func CacheDir() (string, error) {
+	if dir := os.Getenv("TLIN_CACHE"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tlin"), nil
+}
+
+// FactsCache stores the facts of packages, keyed by a hash of their files,
+// so that the packages a workspace depends on are analyzed once until they
+// change.
+type FactsCache struct {
+	dir string
+}
+
+// NewFactsCache returns a cache storing facts below the cache directory dir.
+func NewFactsCache(dir string) *FactsCache {
+	return &FactsCache{dir: filepath.Join(dir, "facts")}
+}
+
+// Facts returns the facts of pkg, from the cache when they were stored for
+// the same files, collecting and storing them otherwise. Failing to store
+// them is not an error: they are collected again next time.
+func (c *FactsCache) Facts(pkg *Package) (*Facts, error) {
+	hash, err := packageHash(pkg)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(c.dir, hash+".json")
+
+	if data, err := os.ReadFile(path); err == nil {
+		var facts Facts
+		if err := json.Unmarshal(data, &facts); err == nil {
+			return &facts, nil
+		}
+	}
+
+	facts, err := CollectFacts(pkg)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.store(path, facts)
+	return facts, nil
+}
+
+// store writes facts to path through a temporary file, so that concurrent
+// runs never read a partial file.
+func (c *FactsCache) store(path string, facts *Facts) error {
+	data, err := json.Marshal(facts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, "facts-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// ClearCache removes the cache directory dir and everything stored in it.
+func ClearCache(dir string) error {
+	err := os.RemoveAll(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// packageHash hashes the path of pkg and the names and contents of the files
+// its facts are collected from.
+func packageHash(pkg *Package) (string, error) {
+	h := sha256.New()
+	io.WriteString(h, factsVersion+"\x00"+pkg.Path+"\x00")
+	for _, file := range sourceFiles(pkg) {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", err
+		}
+		io.WriteString(h, filepath.Base(file)+"\x00")
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
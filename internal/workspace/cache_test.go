@@ -0,0 +1,51 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactsCache(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"gno.mod": "module gno.land/p/demo/coins\n",
+		"coins.gno": `package coins
+
+// Deprecated: use NewCoins instead.
+func Mint() {}
+`,
+	})
+	pkgs, err := Discover(root)
+	require.NoError(t, err)
+	pkg := pkgs[0]
+
+	dir := t.TempDir()
+	cache := NewFactsCache(dir)
+	facts, err := cache.Facts(pkg)
+	require.NoError(t, err)
+	require.Len(t, facts.Deprecated, 1)
+
+	stored, err := filepath.Glob(filepath.Join(dir, "facts", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+
+	// stored facts are used as long as the files do not change.
+	require.NoError(t, os.WriteFile(stored[0], []byte(`{"deprecated":[{"package":"cached","function":"Mint"}]}`), 0o644))
+	facts, err = cache.Facts(pkg)
+	require.NoError(t, err)
+	assert.Equal(t, []Deprecation{{Package: "cached", Function: "Mint"}}, facts.Deprecated)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "coins.gno"), []byte("package coins\n\nfunc Mint() {}\n"), 0o644))
+	facts, err = cache.Facts(pkg)
+	require.NoError(t, err)
+	assert.Empty(t, facts.Deprecated)
+
+	require.NoError(t, ClearCache(dir))
+	assert.NoDirExists(t, dir)
+	assert.NoError(t, ClearCache(dir))
+}
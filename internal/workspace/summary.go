@@ -0,0 +1,55 @@
+package workspace
+
+import (
+	"sort"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// Summary counts the issues of a workspace by package and rule, for
+// dashboards tracking the packages over time.
+type Summary struct {
+	// Packages maps the path of each package with issues to its number of
+	// issues by rule.
+	Packages map[string]map[string]int `json:"packages"`
+	// Rules lists the rules reported in the workspace, sorted.
+	Rules []string `json:"rules"`
+	// Total is the number of issues.
+	Total int `json:"total"`
+}
+
+// Summarize counts issues by the package they are tagged with.
+func Summarize(issues []tt.Issue) Summary {
+	summary := Summary{Packages: make(map[string]map[string]int), Rules: []string{}}
+	rules := make(map[string]bool)
+	for _, issue := range issues {
+		counts, ok := summary.Packages[issue.Package]
+		if !ok {
+			counts = make(map[string]int)
+			summary.Packages[issue.Package] = counts
+		}
+		counts[issue.Rule]++
+		summary.Total++
+		if !rules[issue.Rule] {
+			rules[issue.Rule] = true
+			summary.Rules = append(summary.Rules, issue.Rule)
+		}
+	}
+	sort.Strings(summary.Rules)
+	return summary
+}
+
+// GroupByPackage groups issues by the package they are tagged with and
+// returns the package paths, sorted.
+func GroupByPackage(issues []tt.Issue) ([]string, map[string][]tt.Issue) {
+	byPackage := make(map[string][]tt.Issue)
+	for _, issue := range issues {
+		byPackage[issue.Package] = append(byPackage[issue.Package], issue)
+	}
+	paths := make([]string, 0, len(byPackage))
+	for path := range byPackage {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, byPackage
+}
@@ -0,0 +1,35 @@
+package workspace
+
+import (
+	"encoding/json"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	issues := []tt.Issue{
+		{Package: "gno.land/r/demo/app", Rule: "deprecated"},
+		{Package: "gno.land/r/demo/app", Rule: "deprecated"},
+		{Package: "gno.land/p/demo/coins", Rule: "magic-number"},
+		{Package: "gno.land/r/demo/app", Rule: "magic-number"},
+	}
+
+	d, err := json.Marshal(Summarize(issues))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"packages": {
+			"gno.land/p/demo/coins": {"magic-number": 1},
+			"gno.land/r/demo/app": {"deprecated": 2, "magic-number": 1}
+		},
+		"rules": ["deprecated", "magic-number"],
+		"total": 4
+	}`, string(d))
+
+	paths, byPackage := GroupByPackage(issues)
+	assert.Equal(t, []string{"gno.land/p/demo/coins", "gno.land/r/demo/app"}, paths)
+	assert.Len(t, byPackage["gno.land/r/demo/app"], 3)
+}
@@ -0,0 +1,412 @@
+// Package workspace discovers the gno packages of a monorepo, such as the
+// examples directory of gno, and the facts they export to their importers.
+package workspace
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/tlin/internal/lints"
+)
+
+// Package is a directory of .gno files declaring a module in its gno.mod.
+type Package struct {
+	Dir  string
+	Path string // module path declared in gno.mod
+	// Files holds the .gno files of the package, sorted.
+	Files []string
+	// Imports holds the paths of the workspace packages imported, sorted.
+	Imports []string
+}
+
+// Discover returns the packages found below root, sorted by path. When root
+// holds a go.work file, the packages below the modules it uses are found
+// too, even if they live outside of root.
+func Discover(root string) ([]*Package, error) {
+	dirs := []string{root}
+	if uses, ok := lints.ReadWorkFile(filepath.Join(root, "go.work")); ok {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range uses {
+			if rel, err := filepath.Rel(absRoot, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+
+	var pkgs []*Package
+	for _, dir := range dirs {
+		found, err := discoverDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, found...)
+	}
+
+	known := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		known[pkg.Path] = true
+	}
+	for _, pkg := range pkgs {
+		var err error
+		if pkg.Imports, err = workspaceImports(pkg.Files, known); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Path < pkgs[j].Path })
+	return pkgs, nil
+}
+
+// discoverDir returns the packages found below root.
+func discoverDir(root string) ([]*Package, error) {
+	var pkgs []*Package
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		modPath, ok := lints.ReadModulePath(filepath.Join(path, "gno.mod"))
+		if !ok {
+			return nil
+		}
+		files, err := filepath.Glob(filepath.Join(path, "*.gno"))
+		if err != nil {
+			return err
+		}
+		pkgs = append(pkgs, &Package{Dir: path, Path: modPath, Files: files})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking %s: %w", root, err)
+	}
+	return pkgs, nil
+}
+
+func workspaceImports(files []string, known map[string]bool) ([]string, error) {
+	seen := make(map[string]bool)
+	var imports []string
+	for _, file := range files {
+		node, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range node.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil || !known[path] || seen[path] {
+				continue
+			}
+			seen[path] = true
+			imports = append(imports, path)
+		}
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// Order sorts pkgs so that every package comes after the workspace packages
+// it imports. It fails if the imports form a cycle.
+func Order(pkgs []*Package) ([]*Package, error) {
+	byPath := make(map[string]*Package, len(pkgs))
+	for _, pkg := range pkgs {
+		byPath[pkg.Path] = pkg
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(pkgs))
+	ordered := make([]*Package, 0, len(pkgs))
+	var stack []string
+
+	var visit func(pkg *Package) error
+	visit = func(pkg *Package) error {
+		switch state[pkg.Path] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for i, path := range stack {
+				if path == pkg.Path {
+					start = i
+				}
+			}
+			cycle := append(stack[start:len(stack):len(stack)], pkg.Path)
+			return fmt.Errorf("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[pkg.Path] = visiting
+		stack = append(stack, pkg.Path)
+		for _, imp := range pkg.Imports {
+			if dep, ok := byPath[imp]; ok {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[pkg.Path] = done
+		ordered = append(ordered, pkg)
+		return nil
+	}
+
+	for _, pkg := range pkgs {
+		if err := visit(pkg); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Deprecation is an exported function whose documentation starts a
+// paragraph with "Deprecated:".
+type Deprecation struct {
+	Package     string `json:"package"` // import path
+	Function    string `json:"function"`
+	Alternative string `json:"alternative,omitempty"` // qualified replacement, empty if none is named
+}
+
+// Signature is the signature of an exported function or method.
+type Signature struct {
+	Name string `json:"name"` // Func, or Type.Method
+	Type string `json:"type"` // func(x int) error
+}
+
+// Facts holds what the analysis of a package tells about its API.
+type Facts struct {
+	Deprecated []Deprecation `json:"deprecated,omitempty"`
+	// Exports holds the signatures of the exported functions and methods,
+	// in source order.
+	Exports []Signature `json:"exports,omitempty"`
+	// Calls maps the functions and methods of the package, named as in
+	// Exports, to the functions they call, qualified by their import path
+	// and sorted. Calls of methods and function values are not resolved.
+	Calls map[string][]string `json:"calls,omitempty"`
+}
+
+var alternativeRe = regexp.MustCompile(`[Uu]se ([A-Za-z_][\w.]*?)(?:\(\))? instead`)
+
+// CollectFacts parses the non-test files of pkg and returns its facts.
+func CollectFacts(pkg *Package) (*Facts, error) {
+	var nodes []*ast.File
+	for _, file := range sourceFiles(pkg) {
+		node, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	funcs := make(map[string]bool)
+	for _, node := range nodes {
+		for _, decl := range node.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				funcs[fn.Name.Name] = true
+			}
+		}
+	}
+
+	facts := &Facts{Calls: make(map[string][]string)}
+	for _, node := range nodes {
+		imports := importPaths(node)
+		for _, decl := range node.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			name := funcName(fn)
+			if fn.Name.IsExported() {
+				facts.Exports = append(facts.Exports, Signature{Name: name, Type: types.ExprString(fn.Type)})
+			}
+			if calls := calledFuncs(fn, pkg.Path, funcs, imports); len(calls) > 0 {
+				facts.Calls[name] = calls
+			}
+
+			if fn.Recv != nil || !fn.Name.IsExported() || fn.Doc == nil {
+				continue
+			}
+			notice, ok := deprecationNotice(fn.Doc.Text())
+			if !ok {
+				continue
+			}
+			d := Deprecation{Package: pkg.Path, Function: fn.Name.Name}
+			if m := alternativeRe.FindStringSubmatch(notice); m != nil {
+				d.Alternative = m[1]
+				if !strings.Contains(d.Alternative, ".") {
+					d.Alternative = node.Name.Name + "." + d.Alternative
+				}
+			}
+			facts.Deprecated = append(facts.Deprecated, d)
+		}
+	}
+	return facts, nil
+}
+
+// MissingCall is a call of a function of a workspace package that the
+// package does not export, as left by the removal or the renaming of the
+// function.
+type MissingCall struct {
+	Caller string // function of the calling package, named as in Exports
+	Callee string // called function, qualified by its import path
+}
+
+// ExportedFuncs returns the names of the exported functions of f, leaving
+// out the methods.
+func (f *Facts) ExportedFuncs() map[string]bool {
+	funcs := make(map[string]bool, len(f.Exports))
+	for _, sig := range f.Exports {
+		if !strings.Contains(sig.Name, ".") {
+			funcs[sig.Name] = true
+		}
+	}
+	return funcs
+}
+
+// MissingCalls returns the calls of the functions of the package path, as
+// told by its facts, to functions that the packages of exports, keyed by
+// import path, do not export. Calls to other packages are not checked.
+func MissingCalls(path string, facts *Facts, exports map[string]map[string]bool) []MissingCall {
+	callers := make([]string, 0, len(facts.Calls))
+	for caller := range facts.Calls {
+		callers = append(callers, caller)
+	}
+	sort.Strings(callers)
+
+	var missing []MissingCall
+	for _, caller := range callers {
+		for _, callee := range facts.Calls[caller] {
+			dot := strings.LastIndex(callee, ".")
+			pkg, name := callee[:dot], callee[dot+1:]
+			if funcs, ok := exports[pkg]; ok && pkg != path && !funcs[name] {
+				missing = append(missing, MissingCall{Caller: caller, Callee: callee})
+			}
+		}
+	}
+	return missing
+}
+
+// FuncPosition returns the position of the name of the function or method
+// of pkg named as in Exports.
+func FuncPosition(pkg *Package, name string) (token.Position, bool, error) {
+	for _, file := range sourceFiles(pkg) {
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return token.Position{}, false, err
+		}
+		for _, decl := range node.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && funcName(fn) == name {
+				return fset.Position(fn.Name.Pos()), true, nil
+			}
+		}
+	}
+	return token.Position{}, false, nil
+}
+
+// sourceFiles returns the files of pkg that are not tests.
+func sourceFiles(pkg *Package) []string {
+	var files []string
+	for _, file := range pkg.Files {
+		if !strings.HasSuffix(file, "_test.gno") && !strings.HasSuffix(file, "_filetest.gno") {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// funcName returns the name of fn, prefixed with the name of its receiver
+// type for methods.
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	return types.ExprString(typ) + "." + fn.Name.Name
+}
+
+// importPaths maps the names of the packages imported by node to their path.
+func importPaths(node *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, imp := range node.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+// calledFuncs returns the functions of the package path, named in funcs,
+// and of imported packages called by fn, qualified by their import path.
+func calledFuncs(fn *ast.FuncDecl, path string, funcs map[string]bool, imports map[string]string) []string {
+	if fn.Body == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		switch f := call.Fun.(type) {
+		case *ast.Ident:
+			// local declarations shadowing the function are not told apart.
+			if funcs[f.Name] {
+				seen[path+"."+f.Name] = true
+			}
+		case *ast.SelectorExpr:
+			// the parser resolves local variables, but not package names.
+			if x, ok := f.X.(*ast.Ident); ok && x.Obj == nil {
+				if imp, ok := imports[x.Name]; ok {
+					seen[imp+"."+f.Sel.Name] = true
+				}
+			}
+		}
+		return true
+	})
+
+	calls := make([]string, 0, len(seen))
+	for c := range seen {
+		calls = append(calls, c)
+	}
+	sort.Strings(calls)
+	return calls
+}
+
+// deprecationNotice returns the paragraph of doc starting with "Deprecated:".
+func deprecationNotice(doc string) (string, bool) {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		if notice, ok := strings.CutPrefix(strings.TrimSpace(paragraph), "Deprecated:"); ok {
+			return strings.Join(strings.Fields(notice), " "), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,207 @@
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/tlin/lint/ruletest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverAndOrder(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"r/app/gno.mod": "module gno.land/r/demo/app\n",
+		"r/app/app.gno": `package app
+
+import (
+	"std"
+
+	"gno.land/p/demo/avl"
+	"gno.land/p/demo/ufmt"
+)
+`,
+		"p/ufmt/gno.mod":  "module gno.land/p/demo/ufmt\n",
+		"p/ufmt/ufmt.gno": "package ufmt\n\nimport \"gno.land/p/demo/avl\"\n",
+		"p/avl/gno.mod":   "module gno.land/p/demo/avl\n",
+		"p/avl/avl.gno":   "package avl\n",
+		"p/avl/node.gno":  "package avl\n",
+		"notes/readme.md": "not a package\n",
+	})
+
+	pkgs, err := Discover(root)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 3)
+	assert.Equal(t, "gno.land/p/demo/avl", pkgs[0].Path)
+	assert.Len(t, pkgs[0].Files, 2)
+	assert.Equal(t, []string{"gno.land/p/demo/avl", "gno.land/p/demo/ufmt"}, pkgs[2].Imports)
+
+	ordered, err := Order([]*Package{pkgs[2], pkgs[1], pkgs[0]})
+	require.NoError(t, err)
+	var paths []string
+	for _, pkg := range ordered {
+		paths = append(paths, pkg.Path)
+	}
+	assert.Equal(t, []string{"gno.land/p/demo/avl", "gno.land/p/demo/ufmt", "gno.land/r/demo/app"}, paths)
+}
+
+func TestDiscoverWorkFile(t *testing.T) {
+	t.Parallel()
+	outer := ruletest.WriteFiles(t, map[string]string{
+		"repo/go.work":            "go 1.22\n\nuse (\n\t./app\n\t../shared\n)\n",
+		"repo/app/gno.mod":        "module gno.land/r/demo/app\n",
+		"repo/app/app.gno":        "package app\n\nimport \"gno.land/p/demo/shared\"\n",
+		"shared/gno.mod":          "module gno.land/p/demo/shared\n",
+		"shared/shared.gno":       "package shared\n",
+		"unrelated/gno.mod":       "module gno.land/p/demo/unrelated\n",
+		"unrelated/unrelated.gno": "package unrelated\n",
+	})
+
+	pkgs, err := Discover(filepath.Join(outer, "repo"))
+	require.NoError(t, err)
+	require.Len(t, pkgs, 2)
+	assert.Equal(t, "gno.land/p/demo/shared", pkgs[0].Path)
+	assert.Equal(t, []string{"gno.land/p/demo/shared"}, pkgs[1].Imports)
+}
+
+func TestOrderCycle(t *testing.T) {
+	t.Parallel()
+	pkgs := []*Package{
+		{Path: "a", Imports: []string{"b"}},
+		{Path: "b", Imports: []string{"c"}},
+		{Path: "c", Imports: []string{"b"}},
+	}
+	_, err := Order(pkgs)
+	assert.EqualError(t, err, "import cycle: b -> c -> b")
+}
+
+func TestCollectFacts(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"gno.mod": "module gno.land/p/demo/coins\n",
+		"coins.gno": `package coins
+
+// Mint creates coins.
+//
+// Deprecated: use NewCoins instead.
+func Mint() {}
+
+// Burn destroys coins.
+//
+// Deprecated: use bank.Burn() instead.
+func Burn() {}
+
+// Deprecated: no longer needed.
+func Reset() {}
+
+// Deprecated: unexported functions are not part of the API.
+func helper() {}
+
+// NewCoins creates coins.
+func NewCoins() {}
+
+type Coin struct{}
+
+func (c *Coin) Add(n int) error {
+	NewCoins()
+	helper()
+	return wrap()
+}
+`,
+		"errors.gno": `package coins
+
+import "gno.land/p/demo/ufmt"
+
+func report(err error) string {
+	ufmt := err.Error()
+	return ufmt + ufmt.Sprintf("!")
+}
+
+func wrap() error {
+	return ufmt.Errorf("wrapped")
+}
+`,
+		"coins_test.gno": `package coins
+
+// Deprecated: test helpers are ignored.
+func TestHelper() {}
+`,
+	})
+
+	pkgs, err := Discover(root)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+
+	facts, err := CollectFacts(pkgs[0])
+	require.NoError(t, err)
+	assert.Equal(t, []Deprecation{
+		{Package: "gno.land/p/demo/coins", Function: "Mint", Alternative: "coins.NewCoins"},
+		{Package: "gno.land/p/demo/coins", Function: "Burn", Alternative: "bank.Burn"},
+		{Package: "gno.land/p/demo/coins", Function: "Reset"},
+	}, facts.Deprecated)
+	assert.Equal(t, []Signature{
+		{Name: "Mint", Type: "func()"},
+		{Name: "Burn", Type: "func()"},
+		{Name: "Reset", Type: "func()"},
+		{Name: "NewCoins", Type: "func()"},
+		{Name: "Coin.Add", Type: "func(n int) error"},
+	}, facts.Exports)
+	assert.Equal(t, map[string][]string{
+		"Coin.Add": {"gno.land/p/demo/coins.NewCoins", "gno.land/p/demo/coins.helper", "gno.land/p/demo/coins.wrap"},
+		"wrap":     {"gno.land/p/demo/ufmt.Errorf"},
+	}, facts.Calls)
+}
+
+func TestMissingCalls(t *testing.T) {
+	t.Parallel()
+	root := ruletest.WriteFiles(t, map[string]string{
+		"gno.mod": "module gno.land/r/demo/app\n",
+		"app.gno": `package app
+
+import (
+	"gno.land/p/demo/coins"
+	"gno.land/p/demo/ufmt"
+)
+
+func Init() {
+	coins.NewCoins()
+	coins.Mint()
+	ufmt.Sprintf("%d", setup())
+}
+
+func setup() int { return 0 }
+
+type App struct{}
+
+func (a *App) Reset() {
+	coins.Burn()
+}
+`,
+	})
+
+	pkgs, err := Discover(root)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	facts, err := CollectFacts(pkgs[0])
+	require.NoError(t, err)
+
+	exports := map[string]map[string]bool{
+		"gno.land/p/demo/coins": (&Facts{Exports: []Signature{
+			{Name: "NewCoins", Type: "func()"},
+			{Name: "Coin.Burn", Type: "func()"},
+		}}).ExportedFuncs(),
+		"gno.land/r/demo/app": {},
+	}
+	missing := MissingCalls(pkgs[0].Path, facts, exports)
+	assert.Equal(t, []MissingCall{
+		{Caller: "App.Reset", Callee: "gno.land/p/demo/coins.Burn"},
+		{Caller: "Init", Callee: "gno.land/p/demo/coins.Mint"},
+	}, missing, "methods are not exported functions, and packages outside the workspace are not checked")
+
+	pos, ok, err := FuncPosition(pkgs[0], "App.Reset")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(root, "app.gno"), pos.Filename)
+	assert.Equal(t, 18, pos.Line)
+}
@@ -0,0 +1,63 @@
+package lint
+
+import (
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// ExitConfig holds the options deciding which issues fail a run.
+type ExitConfig struct {
+	// FailOn is the lowest severity failing the run. Unset means any issue fails.
+	FailOn *tt.Severity `yaml:"fail-on,omitempty"`
+	// Categories lists the categories, such as "security", whose issues
+	// always fail the run, even when their severity was lowered.
+	Categories []string `yaml:"fail-categories,omitempty"`
+}
+
+// ExitPolicy resolves the exit code of a run from its issues. Every command
+// reporting issues goes through it, so that they all fail on the same issues.
+type ExitPolicy struct {
+	// FailOn is the lowest severity failing the run.
+	FailOn tt.Severity
+	// Categories holds the categories whose issues always fail the run.
+	Categories map[string]bool
+}
+
+// NewExitPolicy builds the exit policy from the configuration file. Without
+// configuration, any issue fails the run.
+func NewExitPolicy(configurationPath string) ExitPolicy {
+	config, _ := parseConfigurationFile(configurationPath)
+	return config.exitPolicy()
+}
+
+func (c Config) exitPolicy() ExitPolicy {
+	policy := ExitPolicy{FailOn: tt.SeverityInfo}
+	if c.Exit.FailOn != nil {
+		policy.FailOn = *c.Exit.FailOn
+	}
+	if len(c.Exit.Categories) > 0 {
+		policy.Categories = make(map[string]bool, len(c.Exit.Categories))
+		for _, category := range c.Exit.Categories {
+			policy.Categories[category] = true
+		}
+	}
+	return policy
+}
+
+// Fails reports whether issue fails the run.
+func (p ExitPolicy) Fails(issue tt.Issue) bool {
+	if issue.Category != "" && p.Categories[issue.Category] {
+		return true
+	}
+	// severities are ordered from error to off.
+	return issue.Severity <= p.FailOn
+}
+
+// ExitCode returns 1 when one of issues fails the run, and 0 otherwise.
+func (p ExitPolicy) ExitCode(issues []tt.Issue) int {
+	for _, issue := range issues {
+		if p.Fails(issue) {
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gnolang/tlin/internal/ignore"
+)
+
+// pathIgnorer is implemented by engines with their own ignored paths.
+type pathIgnorer interface {
+	IsPathIgnored(path string, isDir bool) bool
+}
+
+// pathIncluder is implemented by engines restricting the files walked.
+type pathIncluder interface {
+	IsPathIncluded(path string) bool
+}
+
+// pathFilter decides which paths of a directory walk are skipped, from the
+// .tlinignore files found between the repository root and the walked
+// directories, merged with the paths ignored by the engine. Files must also
+// be included by the engine, if it restricts them.
+type pathFilter struct {
+	matcher  *ignore.Matcher
+	engine   pathIgnorer
+	includer pathIncluder
+}
+
+func newPathFilter(engine LintEngine, root string) (*pathFilter, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &pathFilter{matcher: ignore.New()}
+	f.engine, _ = engine.(pathIgnorer)
+	f.includer, _ = engine.(pathIncluder)
+
+	// the walk root loads its own file when entered.
+	for _, dir := range ancestorsFromRepoRoot(abs) {
+		if err := f.matcher.LoadFile(dir); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// enter loads the ignore file of a directory about to be walked.
+func (f *pathFilter) enter(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	return f.matcher.LoadFile(abs)
+}
+
+func (f *pathFilter) match(path string, isDir bool) bool {
+	if f.engine != nil && f.engine.IsPathIgnored(path, isDir) {
+		return true
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return f.matcher.Match(abs, isDir)
+}
+
+// includes reports whether the file at path is to be linted.
+func (f *pathFilter) includes(path string) bool {
+	return f.includer == nil || f.includer.IsPathIncluded(path)
+}
+
+// ancestorsFromRepoRoot returns the parents of dir, from the root of its
+// repository (the closest directory holding .git) down to the direct parent.
+// Nothing is returned when dir is not inside a repository.
+func ancestorsFromRepoRoot(dir string) []string {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return nil
+	}
+	var parents []string
+	for current := filepath.Dir(dir); current != dir; current = filepath.Dir(current) {
+		parents = append([]string{current}, parents...)
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return parents
+		}
+		dir = current
+	}
+	return nil
+}
@@ -7,12 +7,23 @@ import (
 	"path/filepath"
 
 	"github.com/gnolang/tlin/internal"
+	"github.com/gnolang/tlin/internal/fixer"
 	"github.com/gnolang/tlin/internal/lints"
 	tt "github.com/gnolang/tlin/internal/types"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
 
+// Severity is the severity of a lint rule or issue.
+type Severity = tt.Severity
+
+const (
+	SeverityError   = tt.SeverityError
+	SeverityWarning = tt.SeverityWarning
+	SeverityInfo    = tt.SeverityInfo
+	SeverityOff     = tt.SeverityOff
+)
+
 type LintEngine interface {
 	Run(filePath string) ([]tt.Issue, error)
 	RunSource(source []byte) ([]tt.Issue, error)
@@ -84,11 +95,24 @@ func ProcessPath(
 
 	var issues []tt.Issue
 	if info.IsDir() {
+		ignored, err := newPathFilter(engine, path)
+		if err != nil {
+			return nil, err
+		}
 		err = filepath.Walk(path, func(filePath string, fileInfo os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
-			if !fileInfo.IsDir() && hasDesiredExtension(filePath) {
+			if filePath != path && ignored.match(filePath, fileInfo.IsDir()) {
+				if fileInfo.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if fileInfo.IsDir() {
+				return ignored.enter(filePath)
+			}
+			if hasDesiredExtension(filePath) && ignored.includes(filePath) {
 				fileIssues, err := processor(engine, filePath)
 				if err != nil && logger != nil {
 					logger.Error("Error processing file", zap.String("file", filePath), zap.Error(err))
@@ -130,8 +154,61 @@ func hasDesiredExtension(path string) bool {
 
 // Config represents the overall configuration with a name and a slice of rules.
 type Config struct {
-	Name  string                   `yaml:"name"`
-	Rules map[string]tt.ConfigRule `yaml:"rules"`
+	Name   string                   `yaml:"name"`
+	Rules  map[string]tt.ConfigRule `yaml:"rules"`
+	Fix    FixConfig                `yaml:"fix,omitempty"`
+	Format FormatConfig             `yaml:"format,omitempty"`
+	Exit   ExitConfig               `yaml:"exit,omitempty"`
+}
+
+// FormatConfig holds the options of the text output.
+type FormatConfig struct {
+	// Theme selects a theme among Themes and the built-in ones.
+	Theme string `yaml:"theme,omitempty"`
+	// Template is a built-in template name such as "compact", or the path of
+	// a text/template file relative to the configuration file.
+	Template string `yaml:"template,omitempty"`
+	// Themes maps theme names to the colors of the output elements.
+	Themes map[string]map[string]string `yaml:"themes,omitempty"`
+}
+
+// NewFormatConfig reads the output options from the configuration file.
+func NewFormatConfig(configurationPath string) FormatConfig {
+	config, _ := parseConfigurationFile(configurationPath)
+	return config.Format
+}
+
+// FixConfig holds the options of `tlin -fix`.
+type FixConfig struct {
+	// Rules lists the only rules whose fixes are applied. Empty means all rules.
+	Rules []string `yaml:"rules,omitempty"`
+}
+
+// NewFixPolicy builds the fixer policy from the configuration file: rules
+// marked `fixable: false` are never fixed, and `fix.rules` restricts fixes
+// to the listed rules.
+func NewFixPolicy(configurationPath string) fixer.Policy {
+	config, _ := parseConfigurationFile(configurationPath)
+	return config.fixPolicy()
+}
+
+func (c Config) fixPolicy() fixer.Policy {
+	var policy fixer.Policy
+	for name, rule := range c.Rules {
+		if rule.Fixable != nil && !*rule.Fixable {
+			if policy.Disabled == nil {
+				policy.Disabled = make(map[string]bool)
+			}
+			policy.Disabled[name] = true
+		}
+	}
+	if len(c.Fix.Rules) > 0 {
+		policy.Allowed = make(map[string]bool, len(c.Fix.Rules))
+		for _, name := range c.Fix.Rules {
+			policy.Allowed[name] = true
+		}
+	}
+	return policy
 }
 
 func parseConfigurationFile(configurationPath string) (Config, error) {
@@ -7,9 +7,14 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/gnolang/tlin/internal"
+	"github.com/gnolang/tlin/internal/fixer"
+	"github.com/gnolang/tlin/internal/ignore"
 	"github.com/gnolang/tlin/internal/types"
+	"github.com/gnolang/tlin/lint/ruletest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -219,3 +224,252 @@ func createTempFiles(t *testing.T, dir string, fileNames ...string) []string {
 	}
 	return paths
 }
+
+func TestNewFixPolicy(t *testing.T) {
+	t.Parallel()
+	configPath := filepath.Join(t.TempDir(), ".tlin.yaml")
+	err := os.WriteFile(configPath, []byte(`name: tlin
+rules:
+  early-return-opportunity:
+    severity: WARNING
+    fixable: false
+fix:
+  rules:
+    - simplify-slice-range
+    - early-return-opportunity
+`), 0o644)
+	assert.NoError(t, err)
+
+	policy := NewFixPolicy(configPath)
+	assert.True(t, policy.Allows("simplify-slice-range"))
+	assert.False(t, policy.Allows("early-return-opportunity"), "fixable: false wins over the allowlist")
+	assert.False(t, policy.Allows("emit-format"), "rules outside the allowlist are not fixed")
+
+	assert.True(t, NewFixPolicy(filepath.Join(t.TempDir(), "missing.yaml")).Allows("emit-format"))
+}
+
+func TestFixPolicyUsesConfigRuleName(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".tlin.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  early-return-opportunity:
+    fixable: false
+`), 0o644))
+	src := "package main\n\nfunc f(x int) string {\n\tif x > 10 {\n\t\treturn \"a\"\n\t} else {\n\t\treturn \"b\"\n\t}\n}\n"
+	path := filepath.Join(dir, "a.go")
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+	engine, err := New(dir, nil, configPath)
+	require.NoError(t, err)
+	issues, err := ProcessFile(engine, path)
+	require.NoError(t, err)
+
+	var earlyReturn []types.Issue
+	for _, issue := range issues {
+		if issue.ConfigName() == "early-return-opportunity" {
+			earlyReturn = append(earlyReturn, issue)
+		}
+	}
+	require.Len(t, earlyReturn, 1)
+	assert.Equal(t, "early-return", earlyReturn[0].Rule)
+
+	disabled := fixer.New(false, 0)
+	disabled.Policy = NewFixPolicy(configPath)
+	require.NoError(t, disabled.Fix(path, earlyReturn))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, src, string(content), "fixable: false applies to the issues of the rule")
+
+	allowed := fixer.New(false, 0)
+	require.NoError(t, allowed.Fix(path, earlyReturn))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "else")
+}
+
+func TestNewExitPolicy(t *testing.T) {
+	t.Parallel()
+	configPath := filepath.Join(t.TempDir(), ".tlin.yaml")
+	err := os.WriteFile(configPath, []byte(`name: tlin
+rules:
+  unrestricted-setter:
+    severity: INFO
+exit:
+  fail-on: ERROR
+  fail-categories:
+    - security
+`), 0o644)
+	assert.NoError(t, err)
+
+	policy := NewExitPolicy(configPath)
+	warning := types.Issue{Rule: "emit-format", Severity: types.SeverityWarning}
+	security := types.Issue{Rule: "unrestricted-setter", Category: "security", Severity: types.SeverityInfo}
+	assert.Equal(t, 0, policy.ExitCode([]types.Issue{warning}))
+	assert.Equal(t, 1, policy.ExitCode([]types.Issue{{Severity: types.SeverityError}}))
+	assert.Equal(t, 1, policy.ExitCode([]types.Issue{warning, security}), "security issues fail whatever their severity")
+
+	defaults := NewExitPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Equal(t, 1, defaults.ExitCode([]types.Issue{{Severity: types.SeverityInfo}}))
+	assert.Equal(t, 0, defaults.ExitCode(nil))
+}
+
+func TestModuleEngine(t *testing.T) {
+	t.Parallel()
+	src := "package foo\n\nfunc f(s []int) []int {\n\treturn s[:len(s)]\n}\n"
+	files := map[string]string{
+		"quiet/gno.mod":          "module gno.land/p/demo/quiet\n",
+		"quiet/.tlin.yaml":       "rules:\n  simplify-slice-range:\n    severity: OFF\n",
+		"quiet/foo.gno":          src,
+		"quiet/sub/foo.gno":      src,
+		"loud/gno.mod":           "module gno.land/p/demo/loud\n",
+		"loud/foo.gno":           src,
+		"loud/nested/gno.mod":    "module gno.land/p/demo/loud/nested\n",
+		"loud/nested/.tlin.yaml": "rules:\n  simplify-slice-range:\n    severity: OFF\n",
+		"loud/nested/foo.gno":    src,
+	}
+	root := ruletest.WriteFiles(t, files)
+
+	base, err := New(root, nil, filepath.Join(root, "missing.yaml"))
+	require.NoError(t, err)
+	setups := 0
+	engine := NewModuleEngine(base, filepath.Join(root, "missing.yaml"), func(*internal.Engine) error {
+		setups++
+		return nil
+	})
+
+	reported := make(map[string]bool)
+	issues, err := ProcessFiles(context.Background(), nil, engine, []string{root}, ProcessFile)
+	require.NoError(t, err)
+	for _, issue := range issues {
+		if issue.Rule == "simplify-slice-range" {
+			rel, err := filepath.Rel(root, issue.Filename)
+			require.NoError(t, err)
+			reported[filepath.ToSlash(rel)] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"loud/foo.gno": true}, reported)
+	assert.Equal(t, 2, setups, "one engine is built per configured module")
+}
+
+func TestModuleFixPolicy(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"app/gno.mod":                "module gno.land/r/demo/app\n",
+		"app/app.gno":                "package app\n",
+		"app/nested/gno.mod":         "module gno.land/r/demo/app/nested\n",
+		"app/nested/.tlin.yaml":      "rules:\n  simplify-slice-range:\n    fixable: false\n",
+		"app/nested/nested.gno":      "package nested\n",
+		"app/nested/sub/nested2.gno": "package nested\n",
+	}
+	root := ruletest.WriteFiles(t, files)
+	configPath := filepath.Join(root, ".tlin.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("fix:\n  rules:\n    - simplify-slice-range\n    - emit-format\n"), 0o644))
+
+	policy := NewModuleFixPolicy(configPath)
+	app := policy.For(filepath.Join(root, "app/app.gno"))
+	assert.True(t, app.Allows("simplify-slice-range"))
+	assert.False(t, app.Allows("early-return-opportunity"), "the policy of the configuration applies to modules without one")
+
+	for _, name := range []string{"app/nested/nested.gno", "app/nested/sub/nested2.gno"} {
+		nested := policy.For(filepath.Join(root, name))
+		assert.False(t, nested.Allows("simplify-slice-range"), name)
+		assert.True(t, nested.Allows("early-return-opportunity"), name)
+	}
+}
+
+func TestProcessPathIgnoreFiles(t *testing.T) {
+	t.Parallel()
+	repo := t.TempDir()
+
+	files := map[string]string{
+		".tlinignore":         "gen/\nlegacy.gno\n",
+		"r/.tlinignore":       "*_mock.gno\n",
+		"r/a.gno":             "package r",
+		"r/a_mock.gno":        "package r",
+		"r/legacy.gno":        "package r",
+		"r/gen/b.gno":         "package gen",
+		"r/sub/c.gno":         "package sub",
+		"r/sub/.tlinignore":   "!legacy.gno\n",
+		"r/sub/legacy.gno":    "package sub",
+		"other/d_mock.gno":    "package other",
+		".git/placeholder.go": "package git",
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	var visited []string
+	_, err := ProcessPath(context.Background(), nil, nil, filepath.Join(repo, "r"), func(_ LintEngine, path string) ([]types.Issue, error) {
+		rel, err := filepath.Rel(repo, path)
+		require.NoError(t, err)
+		visited = append(visited, filepath.ToSlash(rel))
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"r/a.gno", "r/sub/c.gno", "r/sub/legacy.gno"}, visited)
+}
+
+// globEngine filters the paths of directory walks like *internal.Engine,
+// with patterns relative to its base directory.
+type globEngine struct {
+	mockLintEngine
+	include *ignore.Matcher
+	exclude *ignore.Matcher
+}
+
+func (e *globEngine) IsPathIgnored(path string, isDir bool) bool {
+	return e.exclude.Match(path, isDir)
+}
+
+func (e *globEngine) IsPathIncluded(path string) bool {
+	return e.include.Match(path, false)
+}
+
+func TestProcessPathGlobs(t *testing.T) {
+	t.Parallel()
+	repo := t.TempDir()
+
+	for _, name := range []string{"a.gno", "a.go", "sub/b.gno", "sub/b_test.gno", "vendor/c.gno", ".git/placeholder"} {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("package a"), 0o644))
+	}
+
+	engine := &globEngine{include: ignore.New(), exclude: ignore.New()}
+	engine.include.Add(repo, "**/*.gno")
+	engine.exclude.Add(repo, "vendor/**")
+	engine.exclude.Add(repo, "*_test.gno")
+
+	var visited []string
+	_, err := ProcessPath(context.Background(), nil, engine, repo, func(_ LintEngine, path string) ([]types.Issue, error) {
+		rel, err := filepath.Rel(repo, path)
+		require.NoError(t, err)
+		visited = append(visited, filepath.ToSlash(rel))
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"a.gno", "sub/b.gno"}, visited)
+}
+
+func TestRegisterBannedCall(t *testing.T) {
+	t.Parallel()
+	engine, err := New(t.TempDir(), nil, "")
+	require.NoError(t, err)
+	require.NoError(t, engine.RegisterBannedCall("os.Exit", "", SeverityWarning))
+
+	issues, err := ProcessSource(engine, []byte("package main\n\nimport \"os\"\n\nfunc main() {\n\tos.Exit(1)\n}\n"))
+	require.NoError(t, err)
+
+	var severities []Severity
+	for _, issue := range issues {
+		if issue.Rule == "banned-call" {
+			severities = append(severities, issue.Severity)
+		}
+	}
+	assert.Equal(t, []Severity{SeverityWarning}, severities)
+}
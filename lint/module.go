@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gnolang/tlin/internal"
+	"github.com/gnolang/tlin/internal/fixer"
+	"github.com/gnolang/tlin/internal/lints"
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// ModuleConfigName is the name of the configuration file looked up at the
+// root of each module by ModuleEngine.
+const ModuleConfigName = ".tlin.yaml"
+
+// ModuleEngine lints the files of a checkout holding several modules, each
+// with the configuration found at the root of its module, as returned by
+// lints.ModuleRoot. Files of modules without a configuration of their own
+// are linted by the embedded engine.
+type ModuleEngine struct {
+	*internal.Engine
+	// configuration is the absolute path of the configuration of the
+	// embedded engine, which is not loaded twice.
+	configuration string
+	// setup applies the options of the embedded engine, such as the
+	// ignored rules, to the engines of the modules.
+	setup func(*internal.Engine) error
+
+	mu      sync.Mutex
+	engines map[string]*internal.Engine // by module root, nil without configuration
+	// deprecated holds the functions registered with RegisterDeprecatedFunc,
+	// for the engines built afterwards.
+	deprecated []deprecatedFunc
+}
+
+type deprecatedFunc struct {
+	pkgPath, funcName, alternative string
+}
+
+// NewModuleEngine returns a ModuleEngine falling back to engine, built from
+// configurationPath. setup, if not nil, is called on each engine built for
+// a module.
+func NewModuleEngine(engine *internal.Engine, configurationPath string, setup func(*internal.Engine) error) *ModuleEngine {
+	configuration, err := filepath.Abs(configurationPath)
+	if err != nil {
+		configuration = configurationPath
+	}
+	return &ModuleEngine{
+		Engine:        engine,
+		configuration: configuration,
+		setup:         setup,
+		engines:       make(map[string]*internal.Engine),
+	}
+}
+
+// Run lints filename with the engine of its module.
+func (m *ModuleEngine) Run(filename string) ([]tt.Issue, error) {
+	engine, err := m.EngineFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return engine.Run(filename)
+}
+
+// EngineFor returns the engine linting filename.
+func (m *ModuleEngine) EngineFor(filename string) (*internal.Engine, error) {
+	root, ok := lints.ModuleRoot(filepath.Dir(filename))
+	if !ok {
+		return m.Engine, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	engine, seen := m.engines[root]
+	if !seen {
+		var err error
+		if engine, err = m.newModuleEngine(root); err != nil {
+			return nil, err
+		}
+		m.engines[root] = engine
+	}
+	if engine == nil {
+		return m.Engine, nil
+	}
+	return engine, nil
+}
+
+// RegisterDeprecatedFunc registers a deprecated function with the engines of
+// all the modules.
+func (m *ModuleEngine) RegisterDeprecatedFunc(pkgPath, funcName, alternative string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Engine.RegisterDeprecatedFunc(pkgPath, funcName, alternative)
+	for _, engine := range m.engines {
+		if engine != nil {
+			engine.RegisterDeprecatedFunc(pkgPath, funcName, alternative)
+		}
+	}
+	m.deprecated = append(m.deprecated, deprecatedFunc{pkgPath, funcName, alternative})
+}
+
+// newModuleEngine builds the engine of the module at root, or returns nil
+// when the module has no configuration of its own.
+func (m *ModuleEngine) newModuleEngine(root string) (*internal.Engine, error) {
+	path := filepath.Join(root, ModuleConfigName)
+	if path == m.configuration {
+		return nil, nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+
+	engine, err := New(root, nil, path)
+	if err != nil {
+		return nil, err
+	}
+	if m.setup != nil {
+		if err := m.setup(engine); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range m.deprecated {
+		engine.RegisterDeprecatedFunc(d.pkgPath, d.funcName, d.alternative)
+	}
+	return engine, nil
+}
+
+// ModuleFixPolicy resolves the fix policy of each file from the
+// configuration at the root of its module, as ModuleEngine does for the
+// rules. Files of modules without a configuration of their own get the
+// policy of the configuration it was built from.
+type ModuleFixPolicy struct {
+	policy        fixer.Policy
+	configuration string
+
+	mu       sync.Mutex
+	policies map[string]*fixer.Policy // by module root, nil without configuration
+}
+
+// NewModuleFixPolicy returns a ModuleFixPolicy falling back to the policy of
+// configurationPath.
+func NewModuleFixPolicy(configurationPath string) *ModuleFixPolicy {
+	configuration, err := filepath.Abs(configurationPath)
+	if err != nil {
+		configuration = configurationPath
+	}
+	return &ModuleFixPolicy{
+		policy:        NewFixPolicy(configurationPath),
+		configuration: configuration,
+		policies:      make(map[string]*fixer.Policy),
+	}
+}
+
+// For returns the fix policy of filename.
+func (p *ModuleFixPolicy) For(filename string) fixer.Policy {
+	root, ok := lints.ModuleRoot(filepath.Dir(filename))
+	if !ok {
+		return p.policy
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	policy, seen := p.policies[root]
+	if !seen {
+		path := filepath.Join(root, ModuleConfigName)
+		if _, err := os.Stat(path); err == nil && path != p.configuration {
+			module := NewFixPolicy(path)
+			policy = &module
+		}
+		p.policies[root] = policy
+	}
+	if policy == nil {
+		return p.policy
+	}
+	return *policy
+}
@@ -0,0 +1,147 @@
+// Package ruletest runs lint rules against annotated source files.
+//
+// Expected issues are written in the source itself, as comments on the line
+// where the issue starts:
+//
+//	x := 1.5 // want "avoid floating-point literals"
+//
+// Each string following `want` is a regular expression that must match the
+// message of one issue starting on that line. Issues without a matching
+// expectation, and expectations without a matching issue, fail the test.
+package ruletest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+)
+
+// Issue is an issue reported by a lint rule.
+type Issue = tt.Issue
+
+// CheckFunc runs a rule on a parsed file. The Check method of a lint rule
+// can be passed as is.
+type CheckFunc func(filename string, node *ast.File, fset *token.FileSet) ([]Issue, error)
+
+// Run writes src to a file named filename in a temporary directory and checks
+// the issues reported on it against its `// want` comments.
+func Run(t testing.TB, check CheckFunc, filename, src string) []Issue {
+	t.Helper()
+	return RunFiles(t, check, map[string]string{filename: src})
+}
+
+// RunFiles writes files, keyed by their slash-separated path, to a temporary
+// directory and runs check on each .go and .gno file among them. Other files,
+// such as gno.mod, are only written. It returns the issues reported.
+func RunFiles(t testing.TB, check CheckFunc, files map[string]string) []Issue {
+	t.Helper()
+	dir := WriteFiles(t, files)
+
+	var sources []string
+	for name := range files {
+		if ext := filepath.Ext(name); ext == ".go" || ext == ".gno" {
+			sources = append(sources, filepath.Join(dir, filepath.FromSlash(name)))
+		}
+	}
+	sort.Strings(sources)
+
+	var all []Issue
+	for _, path := range sources {
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("parsing %s: %v", path, err)
+		}
+		issues, err := check(path, node, fset)
+		if err != nil {
+			t.Fatalf("checking %s: %v", path, err)
+		}
+		compare(t, filepath.Base(path), issues, expectations(t, fset, node))
+		all = append(all, issues...)
+	}
+	return all
+}
+
+// WriteFiles writes files, keyed by their slash-separated path, to a
+// temporary directory and returns the directory.
+func WriteFiles(t testing.TB, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+type expectation struct {
+	line    int
+	pattern *regexp.Regexp
+	matched bool
+}
+
+// expectations parses the `// want` comments of node.
+func expectations(t testing.TB, fset *token.FileSet, node *ast.File) []*expectation {
+	t.Helper()
+	var wants []*expectation
+	for _, group := range node.Comments {
+		for _, c := range group.List {
+			text := strings.TrimPrefix(c.Text, "//")
+			text = strings.TrimSpace(text)
+			if !strings.HasPrefix(text, "want ") {
+				continue
+			}
+			line := fset.Position(c.Pos()).Line
+			rest := strings.TrimSpace(strings.TrimPrefix(text, "want"))
+			for rest != "" {
+				quoted, err := strconv.QuotedPrefix(rest)
+				if err != nil {
+					t.Fatalf("line %d: invalid want comment %q", line, c.Text)
+				}
+				pattern, _ := strconv.Unquote(quoted)
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					t.Fatalf("line %d: invalid pattern %q: %v", line, pattern, err)
+				}
+				wants = append(wants, &expectation{line: line, pattern: re})
+				rest = strings.TrimSpace(rest[len(quoted):])
+			}
+		}
+	}
+	return wants
+}
+
+func compare(t testing.TB, name string, issues []Issue, wants []*expectation) {
+	t.Helper()
+	for _, issue := range issues {
+		found := false
+		for _, w := range wants {
+			if !w.matched && w.line == issue.Start.Line && w.pattern.MatchString(issue.Message) {
+				w.matched, found = true, true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("%s:%d: unexpected issue: %s", name, issue.Start.Line, issue.Message)
+		}
+	}
+	for _, w := range wants {
+		if !w.matched {
+			t.Errorf("%s:%d: no issue matching %q", name, w.line, w.pattern)
+		}
+	}
+}
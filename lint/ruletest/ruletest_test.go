@@ -0,0 +1,74 @@
+package ruletest
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"testing"
+
+	tt "github.com/gnolang/tlin/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder collects the errors reported by the kit instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// reportCalls reports every call to print.
+func reportCalls(filename string, node *ast.File, fset *token.FileSet) ([]tt.Issue, error) {
+	var issues []tt.Issue
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "print" {
+				issues = append(issues, tt.Issue{
+					Rule:     "print-call",
+					Filename: filename,
+					Start:    fset.Position(call.Pos()),
+					End:      fset.Position(call.End()),
+					Message:  "call to print",
+				})
+			}
+		}
+		return true
+	})
+	return issues, nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	issues := Run(t, reportCalls, "a.gno", `package a
+
+func f() {
+	print(1) // want "call to print"
+	print(2); print(3) // want `+"`call to .*`"+` "print$"
+	println(4)
+}
+`)
+	assert.Len(t, issues, 3)
+}
+
+func TestRunMismatches(t *testing.T) {
+	t.Parallel()
+	rec := &recorder{TB: t}
+	RunFiles(rec, reportCalls, map[string]string{
+		"gno.mod": "module gno.land/p/demo/a\n",
+		"a.gno": `package a
+
+func f() {
+	print(1)
+	println(2) // want "call to print"
+}
+`,
+	})
+
+	assert.Equal(t, []string{
+		"a.gno:4: unexpected issue: call to print",
+		`a.gno:5: no issue matching "call to print"`,
+	}, rec.errors)
+}